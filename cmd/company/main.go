@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/iNDicat0r/company/config"
+	"github.com/iNDicat0r/company/internal/app/blobstore"
 	"github.com/iNDicat0r/company/internal/app/handlers"
 	"github.com/iNDicat0r/company/internal/app/infra"
 	"github.com/iNDicat0r/company/internal/app/middlewares"
@@ -26,7 +27,7 @@ func main() {
 	}
 
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", conf.Database.User, conf.Database.Password, conf.Database.Host, conf.Database.Port, conf.Database.Name)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
@@ -45,23 +46,223 @@ func main() {
 		log.Fatalf("failed to setup company repo: %v", err)
 	}
 
+	searchRepo, err := repositories.NewSQLSearchRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup search repo: %v", err)
+	}
+
+	revisionRepo, err := repositories.NewSQLRevisionRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup revision repo: %v", err)
+	}
+
+	idempotencyRepo, err := repositories.NewSQLIdempotencyRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup idempotency repo: %v", err)
+	}
+
+	companyTypeRepo, err := repositories.NewSQLCompanyTypeRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup company type repo: %v", err)
+	}
+
+	transferRepo, err := repositories.NewSQLTransferRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup transfer repo: %v", err)
+	}
+
+	memberRepo, err := repositories.NewSQLMemberRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup member repo: %v", err)
+	}
+
+	tagRepo, err := repositories.NewSQLTagRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup tag repo: %v", err)
+	}
+
+	attributeRepo, err := repositories.NewSQLAttributeRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup attribute repo: %v", err)
+	}
+
+	locationRepo, err := repositories.NewSQLLocationRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup location repo: %v", err)
+	}
+
+	attachmentRepo, err := repositories.NewSQLAttachmentRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup attachment repo: %v", err)
+	}
+
+	employeeCountRepo, err := repositories.NewSQLEmployeeCountRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup employee count repo: %v", err)
+	}
+
+	// setup blob store
+	store, err := blobstore.New(conf.Attachments.Storage.Driver, conf.Attachments.Storage.Root)
+	if err != nil {
+		log.Fatalf("failed to setup blob store: %v", err)
+	}
+
 	// setup services
 	userSvc, err := services.NewUserService(userRepo, conf.Global.JWTSignerKey)
 	if err != nil {
 		log.Fatalf("failed to setup user service: %v", err)
 	}
 
-	companySvc, err := services.NewCompanyService(companyRepo)
+	companySvc, err := services.NewCompanyService(companyRepo, companyTypeRepo, memberRepo, attributeRepo)
 	if err != nil {
 		log.Fatalf("failed to setup company service: %v", err)
 	}
 
+	searchSvc, err := services.NewSearchService(searchRepo)
+	if err != nil {
+		log.Fatalf("failed to setup search service: %v", err)
+	}
+
+	revisionSvc, err := services.NewRevisionService(revisionRepo, memberRepo)
+	if err != nil {
+		log.Fatalf("failed to setup revision service: %v", err)
+	}
+
+	idempotencySvc, err := services.NewIdempotencyService(idempotencyRepo, conf.Idempotency.TTL)
+	if err != nil {
+		log.Fatalf("failed to setup idempotency service: %v", err)
+	}
+
+	companyTypeSvc, err := services.NewCompanyTypeService(companyTypeRepo)
+	if err != nil {
+		log.Fatalf("failed to setup company type service: %v", err)
+	}
+
+	transferSvc, err := services.NewTransferService(transferRepo, companyRepo, userRepo)
+	if err != nil {
+		log.Fatalf("failed to setup transfer service: %v", err)
+	}
+
+	memberSvc, err := services.NewMemberService(companyRepo, memberRepo, userRepo)
+	if err != nil {
+		log.Fatalf("failed to setup member service: %v", err)
+	}
+
+	tagSvc, err := services.NewTagService(tagRepo, companyRepo, memberRepo)
+	if err != nil {
+		log.Fatalf("failed to setup tag service: %v", err)
+	}
+
+	attributeSvc, err := services.NewAttributeService(attributeRepo, companyRepo, memberRepo)
+	if err != nil {
+		log.Fatalf("failed to setup attribute service: %v", err)
+	}
+
+	locationSvc, err := services.NewLocationService(locationRepo, companyRepo, memberRepo)
+	if err != nil {
+		log.Fatalf("failed to setup location service: %v", err)
+	}
+
+	attachmentSvc, err := services.NewAttachmentService(attachmentRepo, companyRepo, memberRepo, store, services.AttachmentSettings{
+		MaxSize:    conf.Attachments.MaxSize,
+		SigningKey: conf.Attachments.SigningKey,
+		LinkTTL:    conf.Attachments.URLTTL,
+	})
+	if err != nil {
+		log.Fatalf("failed to setup attachment service: %v", err)
+	}
+
+	purgeSvc, err := services.NewCompanyPurger(companyRepo, attachmentRepo, store)
+	if err != nil {
+		log.Fatalf("failed to setup purge service: %v", err)
+	}
+
+	employeeHistorySvc, err := services.NewEmployeeHistoryService(employeeCountRepo, companyRepo, memberRepo)
+	if err != nil {
+		log.Fatalf("failed to setup employee history service: %v", err)
+	}
+
 	// setup handlers
 	companyHandler, err := handlers.NewCompanyHandler(companySvc, producer)
 	if err != nil {
 		log.Fatalf("failed to setup company handlers: %v", err)
 	}
 
+	searchHandler, err := handlers.NewSearchHandler(searchSvc)
+	if err != nil {
+		log.Fatalf("failed to setup search handlers: %v", err)
+	}
+
+	trashHandler, err := handlers.NewTrashHandler(companySvc, purgeSvc, producer)
+	if err != nil {
+		log.Fatalf("failed to setup trash handlers: %v", err)
+	}
+
+	exportHandler, err := handlers.NewExportHandler(companySvc)
+	if err != nil {
+		log.Fatalf("failed to setup export handlers: %v", err)
+	}
+
+	importHandler, err := handlers.NewImportHandler(companySvc, producer)
+	if err != nil {
+		log.Fatalf("failed to setup import handlers: %v", err)
+	}
+
+	hierarchyHandler, err := handlers.NewHierarchyHandler(companySvc)
+	if err != nil {
+		log.Fatalf("failed to setup hierarchy handlers: %v", err)
+	}
+
+	lifecycleHandler, err := handlers.NewLifecycleHandler(companySvc, producer)
+	if err != nil {
+		log.Fatalf("failed to setup lifecycle handlers: %v", err)
+	}
+
+	revisionHandler, err := handlers.NewRevisionHandler(revisionSvc)
+	if err != nil {
+		log.Fatalf("failed to setup revision handlers: %v", err)
+	}
+
+	companyTypeHandler, err := handlers.NewCompanyTypeHandler(companyTypeSvc)
+	if err != nil {
+		log.Fatalf("failed to setup company type handlers: %v", err)
+	}
+
+	transferHandler, err := handlers.NewTransferHandler(transferSvc, producer)
+	if err != nil {
+		log.Fatalf("failed to setup transfer handlers: %v", err)
+	}
+
+	memberHandler, err := handlers.NewMemberHandler(memberSvc)
+	if err != nil {
+		log.Fatalf("failed to setup member handlers: %v", err)
+	}
+
+	tagHandler, err := handlers.NewTagHandler(tagSvc)
+	if err != nil {
+		log.Fatalf("failed to setup tag handlers: %v", err)
+	}
+
+	attributeHandler, err := handlers.NewAttributeHandler(attributeSvc)
+	if err != nil {
+		log.Fatalf("failed to setup attribute handlers: %v", err)
+	}
+
+	locationHandler, err := handlers.NewLocationHandler(locationSvc, producer)
+	if err != nil {
+		log.Fatalf("failed to setup location handlers: %v", err)
+	}
+
+	attachmentHandler, err := handlers.NewAttachmentHandler(attachmentSvc)
+	if err != nil {
+		log.Fatalf("failed to setup attachment handlers: %v", err)
+	}
+
+	employeeHistoryHandler, err := handlers.NewEmployeeHistoryHandler(employeeHistorySvc)
+	if err != nil {
+		log.Fatalf("failed to setup employee history handlers: %v", err)
+	}
+
 	userHandler, err := handlers.NewUserHandler(userSvc)
 	if err != nil {
 		log.Fatalf("failed to setup user handlers: %v", err)
@@ -72,11 +273,86 @@ func main() {
 	v1 := router.Group("/v1")
 
 	// company endpoints
-	v1.POST("/companies/", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), companyHandler.HandleCreateCompany)
-	v1.GET("/companies/:companyID", companyHandler.HandleGetCompany)
+	v1.POST("/companies/", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.Idempotency(idempotencySvc), companyHandler.HandleCreateCompany)
+	v1.POST("/companies/batch", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.Idempotency(idempotencySvc), companyHandler.HandleCreateCompanies)
+	v1.POST("/companies/import", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), importHandler.HandleImportCompanies)
+	v1.GET("/companies", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), companyHandler.HandleListCompanies)
+	v1.GET("/companies/search", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), searchHandler.HandleSearchCompanies)
+	v1.GET("/companies/export", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), exportHandler.HandleExportCompanies)
+	v1.GET("/companies/:companyID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), companyHandler.HandleGetCompany)
+	v1.GET("/companies/by-slug/:slug", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), companyHandler.HandleGetCompanyBySlug)
 	v1.DELETE("/companies/:companyID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), companyHandler.HandleDeleteCompany)
 	v1.PATCH("/companies/:companyID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), companyHandler.HandleUpdateCompany)
 
+	// trash endpoints
+	v1.GET("/companies/deleted", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), trashHandler.HandleListDeletedCompanies)
+	v1.POST("/companies/:companyID/restore", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), trashHandler.HandleRestoreCompany)
+	v1.DELETE("/companies/deleted/:companyID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.AdminMiddleware(userSvc), trashHandler.HandlePurgeCompany)
+
+	// corporate group endpoints
+	v1.GET("/companies/:companyID/ancestors", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), hierarchyHandler.HandleListAncestors)
+	v1.GET("/companies/:companyID/children", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), hierarchyHandler.HandleListChildren)
+	v1.GET("/companies/:companyID/subtree", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), hierarchyHandler.HandleGetSubtree)
+
+	// tag endpoints
+	v1.GET("/companies/:companyID/tags", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), tagHandler.HandleListTags)
+	v1.PUT("/companies/:companyID/tags/:tag", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), tagHandler.HandleAddTag)
+	v1.DELETE("/companies/:companyID/tags/:tag", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), tagHandler.HandleRemoveTag)
+	v1.GET("/tags", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), tagHandler.HandleSuggestTags)
+
+	// custom attribute endpoints
+	v1.GET("/companies/:companyID/attributes", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), attributeHandler.HandleGetCompanyAttributes)
+	v1.PUT("/companies/:companyID/attributes", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), attributeHandler.HandleReplaceCompanyAttributes)
+	v1.PATCH("/companies/:companyID/attributes", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), attributeHandler.HandlePatchCompanyAttributes)
+	v1.GET("/attributes", attributeHandler.HandleListAttributeSchemas)
+	v1.GET("/attributes/:name", attributeHandler.HandleGetAttributeSchema)
+	v1.POST("/attributes", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.AdminMiddleware(userSvc), attributeHandler.HandleCreateAttributeSchema)
+	v1.PUT("/attributes/:name", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.AdminMiddleware(userSvc), attributeHandler.HandleUpdateAttributeSchema)
+	v1.DELETE("/attributes/:name", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.AdminMiddleware(userSvc), attributeHandler.HandleDeleteAttributeSchema)
+
+	// location endpoints
+	v1.GET("/companies/:companyID/locations", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), locationHandler.HandleListLocations)
+	v1.GET("/companies/:companyID/locations/:locationID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), locationHandler.HandleGetLocation)
+	v1.POST("/companies/:companyID/locations", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), locationHandler.HandleAddLocation)
+	v1.PUT("/companies/:companyID/locations/:locationID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), locationHandler.HandleUpdateLocation)
+	v1.DELETE("/companies/:companyID/locations/:locationID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), locationHandler.HandleRemoveLocation)
+
+	// attachment endpoints, the content is downloaded with a signed link instead of a token
+	v1.GET("/companies/:companyID/attachments", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), attachmentHandler.HandleListAttachments)
+	v1.GET("/companies/:companyID/attachments/:attachmentID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), attachmentHandler.HandleGetAttachment)
+	v1.GET("/companies/:companyID/attachments/:attachmentID/content", attachmentHandler.HandleDownloadAttachment)
+	v1.POST("/companies/:companyID/attachments", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), attachmentHandler.HandleUploadAttachment)
+	v1.DELETE("/companies/:companyID/attachments/:attachmentID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), attachmentHandler.HandleDeleteAttachment)
+
+	// lifecycle endpoints
+	v1.GET("/companies/:companyID/transitions", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), lifecycleHandler.HandleListTransitions)
+	v1.POST("/companies/:companyID/transitions", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), lifecycleHandler.HandleTransition)
+
+	// employee history endpoints
+	v1.GET("/companies/:companyID/employees/history", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.CacheControl(conf.Cache.Control), employeeHistoryHandler.HandleGetEmployeeHistory)
+
+	// revision endpoints
+	v1.GET("/companies/:companyID/revisions", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), revisionHandler.HandleListRevisions)
+	v1.GET("/companies/:companyID/revisions/:revision", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), revisionHandler.HandleGetRevision)
+
+	// member endpoints
+	v1.GET("/companies/:companyID/members", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), memberHandler.HandleListMembers)
+	v1.POST("/companies/:companyID/members", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), memberHandler.HandleGrantMember)
+	v1.DELETE("/companies/:companyID/members/:userID", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), memberHandler.HandleRevokeMember)
+
+	// ownership transfer endpoints
+	v1.POST("/companies/:companyID/transfers", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), transferHandler.HandleInitiateTransfer)
+	v1.GET("/transfers", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), transferHandler.HandleListTransfers)
+	v1.POST("/transfers/:transferID/accept", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), transferHandler.HandleAcceptTransfer)
+	v1.POST("/transfers/:transferID/cancel", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), transferHandler.HandleCancelTransfer)
+
+	// company type endpoints
+	v1.GET("/company-types", companyTypeHandler.HandleListCompanyTypes)
+	v1.GET("/company-types/:name", companyTypeHandler.HandleGetCompanyType)
+	v1.POST("/company-types", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.AdminMiddleware(userSvc), companyTypeHandler.HandleCreateCompanyType)
+	v1.PUT("/company-types/:name", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.AdminMiddleware(userSvc), companyTypeHandler.HandleUpdateCompanyType)
+	v1.DELETE("/company-types/:name", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), middlewares.AdminMiddleware(userSvc), companyTypeHandler.HandleDeleteCompanyType)
+
 	// auth endpoints
 	v1.POST("/auth/login", userHandler.HandleAuthenticate)
 	v1.GET("/auth/introspect", middlewares.AuthMiddleware(conf.Global.JWTSignerKey), userHandler.HandleIntrospect)
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// runExport writes the companies matching the filter flags to a CSV, NDJSON or JSON file.
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	configFile := flags.String("config", "", "Path to the configuration file")
+	file := flags.String("file", "-", "Path of the exported file, - writes to stdout")
+	formatName := flags.String("format", "", "Format of the file, csv, ndjson or json, guessed from the file extension by default")
+	types := flags.String("type", "", "Comma separated company types to export")
+	registered := flags.String("registered", "", "Only export registered (true) or unregistered (false) companies")
+	minEmployees := flags.Int("min-employees", -1, "Only export companies with at least this many employees")
+	maxEmployees := flags.Int("max-employees", -1, "Only export companies with at most this many employees")
+	owner := flags.String("owner", "", "Only export the companies of the user with this username")
+	_ = flags.Parse(args)
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+	if *formatName == "" {
+		*formatName = string(companyio.FormatJSON)
+	}
+	format, err := companyio.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatalf("failed to pick export format: %v", err)
+	}
+
+	var filter services.CompanyFilter
+	if *types != "" {
+		for _, t := range strings.Split(*types, ",") {
+			filter.Types = append(filter.Types, common.Type(strings.TrimSpace(t)))
+		}
+	}
+	if *registered != "" {
+		b, err := strconv.ParseBool(*registered)
+		if err != nil {
+			log.Fatalf("failed to parse -registered: %v", err)
+		}
+		filter.Registered = &b
+	}
+	if *minEmployees >= 0 {
+		filter.MinEmployees = minEmployees
+	}
+	if *maxEmployees >= 0 {
+		filter.MaxEmployees = maxEmployees
+	}
+
+	db := openDB(*configFile)
+	ctx := context.Background()
+
+	if *owner != "" {
+		userRepo, err := repositories.NewSQLUserRepository(db)
+		if err != nil {
+			log.Fatalf("failed to setup user repo: %v", err)
+		}
+		user, err := userRepo.FindByUserName(ctx, *owner)
+		if err != nil {
+			log.Fatalf("failed to find owner %q: %v", *owner, err)
+		}
+		filter.UserID = &user.ID
+	}
+
+	companyRepo, err := repositories.NewSQLCompanyRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup company repo: %v", err)
+	}
+
+	companyTypeRepo, err := repositories.NewSQLCompanyTypeRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup company type repo: %v", err)
+	}
+
+	memberRepo, err := repositories.NewSQLMemberRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup member repo: %v", err)
+	}
+
+	attributeRepo, err := repositories.NewSQLAttributeRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup attribute repo: %v", err)
+	}
+
+	companySvc, err := services.NewCompanyService(companyRepo, companyTypeRepo, memberRepo, attributeRepo)
+	if err != nil {
+		log.Fatalf("failed to setup company service: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *file != "-" {
+		f, err := os.Create(*file)
+		if err != nil {
+			log.Fatalf("failed to create export file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := companySvc.ExportAll(ctx, filter, w, format); err != nil {
+		log.Fatalf("failed to export companies: %v", err)
+	}
+}
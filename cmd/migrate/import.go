@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// runImport loads the companies of a CSV or NDJSON file and writes a report of the rejected rows.
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	configFile := flags.String("config", "", "Path to the configuration file")
+	file := flags.String("file", "", "Path to the CSV or NDJSON file to import, - reads stdin")
+	formatName := flags.String("format", "", "Format of the file, csv or ndjson, guessed from the file extension by default")
+	owner := flags.String("owner", "", "Username of the user owning the imported companies")
+	columns := flags.String("map", "", "Comma separated column:field pairs for columns not named after a company field")
+	reportFile := flags.String("report", "", "Path of the CSV report of rejected rows, stdout by default")
+	_ = flags.Parse(args)
+
+	if *file == "" || *owner == "" {
+		log.Fatalf("import needs a -file and an -owner")
+	}
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+	format, err := companyio.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatalf("failed to pick import format: %v", err)
+	}
+
+	mapping, err := companyio.ParseMapping(*columns)
+	if err != nil {
+		log.Fatalf("failed to parse column mapping: %v", err)
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("failed to open import file: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	db := openDB(*configFile)
+
+	userRepo, err := repositories.NewSQLUserRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup user repo: %v", err)
+	}
+
+	companyRepo, err := repositories.NewSQLCompanyRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup company repo: %v", err)
+	}
+
+	companyTypeRepo, err := repositories.NewSQLCompanyTypeRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup company type repo: %v", err)
+	}
+
+	memberRepo, err := repositories.NewSQLMemberRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup member repo: %v", err)
+	}
+
+	attributeRepo, err := repositories.NewSQLAttributeRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup attribute repo: %v", err)
+	}
+
+	companySvc, err := services.NewCompanyService(companyRepo, companyTypeRepo, memberRepo, attributeRepo)
+	if err != nil {
+		log.Fatalf("failed to setup company service: %v", err)
+	}
+
+	ctx := context.Background()
+	user, err := userRepo.FindByUserName(ctx, *owner)
+	if err != nil {
+		log.Fatalf("failed to find owner %q: %v", *owner, err)
+	}
+
+	report, err := companySvc.Import(ctx, user.ID, r, format, mapping, nil)
+	if err != nil {
+		log.Fatalf("failed to import companies after %d rows: %v", report.Total, err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *reportFile != "" {
+		f, err := os.Create(*reportFile)
+		if err != nil {
+			log.Fatalf("failed to create report file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeRejectedRows(w, report.Rejected); err != nil {
+		log.Fatalf("failed to write report: %v", err)
+	}
+
+	log.Printf("imported %d of %d rows, %d rejected", report.Imported, report.Total, len(report.Rejected))
+}
+
+// writeRejectedRows writes the rejected rows of an import as CSV.
+func writeRejectedRows(w io.Writer, rows []services.RejectedRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"line", "error"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write([]string{strconv.Itoa(row.Line), row.Error}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -1,36 +1,118 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
 	"github.com/iNDicat0r/company/config"
 	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/iNDicat0r/company/internal/app/services"
 	"github.com/iNDicat0r/company/internal/app/utils"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
 func main() {
-	configFile := flag.String("config", "", "Path to the configuration file")
-	flag.Parse()
+	// the first argument picks the subcommand, migrating the database is the default
+	command, args := "migrate", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "migrate":
+		runMigrate(args)
+	case "import":
+		runImport(args)
+	case "export":
+		runExport(args)
+	default:
+		log.Fatalf("unknown command %q, expected migrate, import or export", command)
+	}
+}
 
-	conf, err := config.NewConfig(*configFile)
+// openDB connects to the database of the configuration file.
+func openDB(configFile string) *gorm.DB {
+	conf, err := config.NewConfig(configFile)
 	if err != nil {
 		log.Fatalf("failed to setup config: %v", err)
 	}
 
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", conf.Database.User, conf.Database.Password, conf.Database.Host, conf.Database.Port, conf.Database.Name)
-	db, err := gorm.Open(mysql.Open(dsn), nil)
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
 
-	err = db.AutoMigrate(&models.User{}, &models.Company{})
+	return db
+}
+
+// runMigrate creates or updates the schema and seeds the default user.
+func runMigrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	configFile := flags.String("config", "", "Path to the configuration file")
+	_ = flags.Parse(args)
+
+	db := openDB(*configFile)
+
+	err := db.AutoMigrate(&models.User{}, &models.Company{}, &models.CompanySearchTerm{}, &models.CompanyRevision{}, &models.IdempotencyKey{}, &models.CompanyType{}, &models.CompanySlug{}, &models.CompanyTransfer{}, &models.CompanyMember{}, &models.Tag{}, &models.CompanyTag{}, &models.AttributeSchema{}, &models.CompanyAttribute{}, &models.CompanyLocation{}, &models.CompanyAttachment{}, &models.CompanyTransition{}, &models.EmployeeCount{})
 	if err != nil {
 		log.Fatalf("failed to migrate database: %v", err)
 	}
+
+	// names used to be unique across deleted companies too, now only live companies hold the key of
+	// their name, which ignores case and Unicode representation, every company has a slug and its
+	// owner is a member of its access control list
+	companyRepo, err := repositories.NewSQLCompanyRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup company repo: %v", err)
+	}
+	if err := companyRepo.Backfill(context.Background()); err != nil {
+		log.Fatalf("failed to backfill company names, slugs and owners: %v", err)
+	}
+	if db.Migrator().HasIndex(&models.Company{}, "name") {
+		if err := db.Migrator().DropIndex(&models.Company{}, "name"); err != nil {
+			log.Fatalf("failed to drop company name index: %v", err)
+		}
+	}
+
+	// company types used to be a check constraint, they are now validated against the catalogue
+	if db.Migrator().HasConstraint(&models.Company{}, "chk_companies_type") {
+		if err := db.Migrator().DropConstraint(&models.Company{}, "chk_companies_type"); err != nil {
+			log.Fatalf("failed to drop company type constraint: %v", err)
+		}
+	}
+	companyTypeRepo, err := repositories.NewSQLCompanyTypeRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup company type repo: %v", err)
+	}
+	if err := companyTypeRepo.Seed(context.Background(), services.DefaultCompanyTypes); err != nil {
+		log.Fatalf("failed to seed company types: %v", err)
+	}
+
+	// employee amounts used to be overwritten, the history of older companies is rebuilt from their revisions
+	employeeCountRepo, err := repositories.NewSQLEmployeeCountRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup employee count repo: %v", err)
+	}
+	if err := employeeCountRepo.Backfill(context.Background()); err != nil {
+		log.Fatalf("failed to backfill employee counts: %v", err)
+	}
+
+	// index the companies created before the search index existed, rebuilding also weighs the terms
+	// indexed before their weight was stored and folds the accents of older terms
+	searchRepo, err := repositories.NewSQLSearchRepository(db)
+	if err != nil {
+		log.Fatalf("failed to setup search repo: %v", err)
+	}
+	if err := searchRepo.Rebuild(context.Background()); err != nil {
+		log.Fatalf("failed to rebuild search index: %v", err)
+	}
 	hashPass, _ := utils.HashPassword("124")
 
 	// user 1
@@ -38,5 +120,6 @@ func main() {
 		Name:     "Mobin",
 		Username: "iNDicat0r",
 		Password: hashPass,
+		Admin:    true,
 	})
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"gopkg.in/yaml.v2"
@@ -27,6 +28,21 @@ type Config struct {
 	Kafka struct {
 		URI string `yaml:"uri" envconfig:"KAFKA_URI"`
 	} `yaml:"kafka"`
+	Cache struct {
+		Control string `yaml:"control" envconfig:"CACHE_CONTROL"` // Cache-Control header of company reads.
+	} `yaml:"cache"`
+	Idempotency struct {
+		TTL time.Duration `yaml:"ttl" envconfig:"IDEMPOTENCY_TTL"` // How long responses to idempotent requests are kept.
+	} `yaml:"idempotency"`
+	Attachments struct {
+		Storage struct {
+			Driver string `yaml:"driver" envconfig:"ATTACHMENTS_STORAGE_DRIVER"` // Blob store of the attachments, only "local" is available.
+			Root   string `yaml:"root" envconfig:"ATTACHMENTS_STORAGE_ROOT"`     // Directory of the local blob store.
+		} `yaml:"storage"`
+		MaxSize    int64         `yaml:"max_size" envconfig:"ATTACHMENTS_MAXSIZE"`       // Largest attachment accepted, in bytes.
+		SigningKey string        `yaml:"signing_key" envconfig:"ATTACHMENTS_SIGNINGKEY"` // Key download URLs are signed with.
+		URLTTL     time.Duration `yaml:"url_ttl" envconfig:"ATTACHMENTS_URLTTL"`         // How long a signed download URL stays valid.
+	} `yaml:"attachments"`
 }
 
 // NewConfig returns a new configuration by parsing yml and env vars.
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -19,6 +20,17 @@ server:
   host: "localhost"
 database:
   name: "testdb"
+cache:
+  control: "private, no-cache"
+idempotency:
+  ttl: 24h
+attachments:
+  storage:
+    driver: "local"
+    root: "/var/lib/company/attachments"
+  max_size: 1048576
+  signing_key: "test_attachment_key"
+  url_ttl: 15m
 `
 
 	// nolint:gofumpt
@@ -42,4 +54,16 @@ database:
 
 	expectedDatabaseName := "testdb"
 	assert.Equal(t, expectedDatabaseName, cfg.Database.Name)
+
+	expectedCacheControl := "private, no-cache"
+	assert.Equal(t, expectedCacheControl, cfg.Cache.Control)
+
+	expectedIdempotencyTTL := 24 * time.Hour
+	assert.Equal(t, expectedIdempotencyTTL, cfg.Idempotency.TTL)
+
+	assert.Equal(t, "local", cfg.Attachments.Storage.Driver)
+	assert.Equal(t, "/var/lib/company/attachments", cfg.Attachments.Storage.Root)
+	assert.Equal(t, int64(1048576), cfg.Attachments.MaxSize)
+	assert.Equal(t, "test_attachment_key", cfg.Attachments.SigningKey)
+	assert.Equal(t, 15*time.Minute, cfg.Attachments.URLTTL)
 }
@@ -0,0 +1,33 @@
+// Package blobstore keeps the content of company attachments. Stores are picked by driver in the
+// configuration, the metadata of the blobs lives in the database.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DriverLocal stores blobs as files under a directory of the local filesystem.
+const DriverLocal = "local"
+
+// ErrNotFound is returned when no blob is stored under a key.
+var ErrNotFound = errors.New("blob not found")
+
+// Store defines the functionality of a blob store. Keys are slash separated paths.
+type Store interface {
+	Put(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the store of a driver, root is the location of the blobs in it.
+func New(driver, root string) (Store, error) {
+	switch driver {
+	case DriverLocal:
+		return NewLocalStore(root)
+	default:
+		return nil, fmt.Errorf("unsupported blob store driver %q", driver)
+	}
+}
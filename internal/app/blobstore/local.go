@@ -0,0 +1,108 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore stores blobs as files under a root directory.
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore creates a local store, the root directory is created when missing.
+func NewLocalStore(root string) (*LocalStore, error) {
+	if root == "" {
+		return nil, errors.New("root is empty")
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create root: %w", err)
+	}
+
+	return &LocalStore{root: root}, nil
+}
+
+// Put stores the content under the key, replacing the blob stored under it. The content is
+// written to a temporary file first so readers never see a partial blob.
+func (s *LocalStore) Put(_ context.Context, key string, content io.Reader) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return fmt.Errorf("failed to put blob: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to put blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to put blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to put blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("failed to put blob: %w", err)
+	}
+
+	return nil
+}
+
+// Open returns the content stored under the key, the caller closes it.
+func (s *LocalStore) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open blob: %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+
+	return f, nil
+}
+
+// Delete removes the blob stored under the key.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the file of a key, keys that would leave the root are rejected.
+func (s *LocalStore) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean != "/"+key || strings.HasSuffix(key, "/") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
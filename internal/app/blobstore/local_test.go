@@ -0,0 +1,70 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	store, err := New("s3", t.TempDir())
+	assert.EqualError(t, err, "unsupported blob store driver \"s3\"")
+	assert.Nil(t, store)
+
+	store, err = New(DriverLocal, t.TempDir())
+	assert.NoError(t, err)
+	assert.IsType(t, &LocalStore{}, store)
+}
+
+func TestNewLocalStore(t *testing.T) {
+	t.Parallel()
+	store, err := NewLocalStore("")
+	assert.EqualError(t, err, "root is empty")
+	assert.Nil(t, store)
+
+	root := filepath.Join(t.TempDir(), "attachments")
+	store, err = NewLocalStore(root)
+	assert.NoError(t, err)
+	assert.NotNil(t, store)
+	assert.DirExists(t, root, "the root is created")
+}
+
+func TestLocalStore(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal("Failed to create store: ", err)
+	}
+
+	ctx := context.Background()
+	key := "companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/logo"
+	assert.NoError(t, store.Put(ctx, key, strings.NewReader("first")))
+	assert.NoError(t, store.Put(ctx, key, strings.NewReader("second")), "putting a key again replaces the blob")
+
+	r, err := store.Open(ctx, key)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Close())
+	assert.Equal(t, "second", string(content))
+
+	entries, err := os.ReadDir(filepath.Join(root, "companies", "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+
+	assert.NoError(t, store.Delete(ctx, key))
+	assert.ErrorIs(t, store.Delete(ctx, key), ErrNotFound)
+	_, err = store.Open(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, key := range []string{"", "../escape", "companies/../../escape", "/absolute", "companies/"} {
+		assert.EqualError(t, store.Put(ctx, key, strings.NewReader("x")), "invalid blob key \""+key+"\"")
+	}
+}
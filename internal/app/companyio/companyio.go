@@ -0,0 +1,150 @@
+// Package companyio reads and writes companies as CSV, NDJSON or JSON files.
+package companyio
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/iNDicat0r/company/common"
+)
+
+// Format is a file format companies are read from or written to.
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatJSON   Format = "json"
+)
+
+// ErrUnknownFormat is returned for a format that is not supported.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatCSV, FormatNDJSON, FormatJSON:
+		return format, nil
+	}
+
+	return "", fmt.Errorf("%w %q", ErrUnknownFormat, name)
+}
+
+// The company fields a column can be mapped to.
+const (
+	FieldName            = "name"
+	FieldDescription     = "description"
+	FieldEmployeesAmount = "employees_amount"
+	FieldRegistered      = "registered"
+	FieldType            = "type"
+)
+
+// AttributePrefix starts the columns holding the value of a custom attribute, e.g. attr.vat_number.
+const AttributePrefix = "attr."
+
+// fields are the importable company fields by their normalized name.
+var fields = map[string]string{
+	"name":            FieldName,
+	"description":     FieldDescription,
+	"employeesamount": FieldEmployeesAmount,
+	"registered":      FieldRegistered,
+	"type":            FieldType,
+}
+
+// normalize lowers a column name and drops everything but letters and digits,
+// so that "EmployeesAmount", "employees_amount" and "Employees Amount" are equal.
+func normalize(column string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, column)
+}
+
+// ParseMapping parses a comma separated list of column:field pairs. It maps the columns
+// of a file whose names do not match the company fields.
+func ParseMapping(value string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		column, field, ok := strings.Cut(pair, ":")
+		column, field = strings.TrimSpace(column), strings.TrimSpace(field)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected column:field", pair)
+		}
+
+		target, ok := fields[normalize(field)]
+		if name, isAttribute := strings.CutPrefix(field, AttributePrefix); isAttribute && name != "" {
+			target, ok = field, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("column %q is mapped to unknown field %q", column, field)
+		}
+		mapping[column] = target
+	}
+
+	return mapping, nil
+}
+
+// resolveField returns the company field a column holds, mapped columns take precedence
+// over columns named after a field. Attribute columns are returned as they are. It returns
+// false for columns that are not imported.
+func resolveField(column string, mapping map[string]string) (string, bool) {
+	if field, ok := mapping[column]; ok {
+		return field, true
+	}
+	if name, ok := strings.CutPrefix(column, AttributePrefix); ok && name != "" {
+		return column, true
+	}
+
+	field, ok := fields[normalize(column)]
+	return field, ok
+}
+
+// setField parses the value of a field into the row, empty values are left unset. The values
+// of attributes are kept as they are written, their schema tells how to parse them.
+func setField(row *Row, field, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	if name, ok := strings.CutPrefix(field, AttributePrefix); ok {
+		if row.Attributes == nil {
+			row.Attributes = make(map[string]string)
+		}
+		row.Attributes[name] = value
+		return nil
+	}
+
+	company := &row.Company
+	switch field {
+	case FieldName:
+		company.Name = value
+	case FieldDescription:
+		company.Description = value
+	case FieldEmployeesAmount:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer", field)
+		}
+		company.EmployeesAmount = n
+	case FieldRegistered:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean", field)
+		}
+		company.Registered = b
+	case FieldType:
+		company.Type = common.Type(value)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package companyio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iNDicat0r/company/internal/app/models"
+)
+
+// maxLineSize is the longest NDJSON line that can be read.
+const maxLineSize = 1 << 20
+
+// Row is a company read from a file. Err is set and Company left empty when the row
+// could not be parsed, the rest of the file can still be read.
+type Row struct {
+	Line       int
+	Company    models.Company
+	Attributes map[string]string // Values of the attr.<name> columns by attribute name.
+	Err        error
+}
+
+// Decoder reads companies from a CSV or NDJSON stream one row at a time.
+type Decoder struct {
+	next func() (Row, error)
+}
+
+// NewDecoder returns a decoder of the stream, the mapping tells which company field a
+// column holds when its name does not match one. A CSV stream must start with a header.
+func NewDecoder(r io.Reader, format Format, mapping map[string]string) (*Decoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVDecoder(r, mapping)
+	case FormatNDJSON:
+		return newNDJSONDecoder(r, mapping), nil
+	}
+
+	return nil, fmt.Errorf("cannot import %w %q", ErrUnknownFormat, format)
+}
+
+// Next returns the next row of the stream, io.EOF is returned once the stream is read.
+func (d *Decoder) Next() (Row, error) {
+	return d.next()
+}
+
+func newCSVDecoder(r io.Reader, mapping map[string]string) (*Decoder, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	hasName := false
+	for i, column := range header {
+		if field, ok := resolveField(strings.TrimSpace(column), mapping); ok {
+			columns[i] = field
+			hasName = hasName || field == FieldName
+		}
+	}
+	if !hasName {
+		return nil, errors.New("csv header has no name column")
+	}
+
+	next := func() (Row, error) {
+		record, err := reader.Read()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Row{Line: parseErr.Line, Err: parseErr.Err}, nil
+		}
+		if err != nil {
+			return Row{}, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := Row{Line: line}
+		for i, value := range record {
+			if columns[i] == "" {
+				continue
+			}
+			if err := setField(&row, columns[i], value); err != nil {
+				return Row{Line: line, Err: err}, nil
+			}
+		}
+
+		return row, nil
+	}
+
+	return &Decoder{next: next}, nil
+}
+
+func newNDJSONDecoder(r io.Reader, mapping map[string]string) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	line := 0
+
+	next := func() (Row, error) {
+		for scanner.Scan() {
+			line++
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			return decodeNDJSONLine(line, scanner.Bytes(), mapping), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return Row{}, err
+		}
+
+		return Row{}, io.EOF
+	}
+
+	return &Decoder{next: next}
+}
+
+// decodeNDJSONLine parses a JSON object into a row, strings, numbers and booleans are accepted as values.
+func decodeNDJSONLine(line int, data []byte, mapping map[string]string) Row {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return Row{Line: line, Err: errors.New("line is not a json object")}
+	}
+
+	row := Row{Line: line}
+
+	for key, raw := range object {
+		field, ok := resolveField(key, mapping)
+		if !ok {
+			continue
+		}
+
+		value := string(raw)
+		var s string
+		switch {
+		case value == "null":
+			continue
+		case json.Unmarshal(raw, &s) == nil:
+			value = s
+		case strings.HasPrefix(value, "{") || strings.HasPrefix(value, "["):
+			return Row{Line: line, Err: fmt.Errorf("%s must not be an object or an array", field)}
+		}
+
+		if err := setField(&row, field, value); err != nil {
+			return Row{Line: line, Err: err}
+		}
+	}
+
+	return row
+}
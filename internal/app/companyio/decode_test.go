@@ -0,0 +1,154 @@
+package companyio
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeAll returns every row of the stream, row errors are replaced by their message.
+func decodeAll(t *testing.T, dec *Decoder) ([]Row, []string) {
+	t.Helper()
+	var rows []Row
+	var errs []string
+	for {
+		row, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, errs
+		}
+		assert.NoError(t, err)
+		if row.Err != nil {
+			errs = append(errs, row.Err.Error())
+			row.Err = nil
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		value      string
+		expMapping map[string]string
+		expErr     string
+	}{
+		"empty": {
+			expMapping: map[string]string{},
+		},
+		"pairs": {
+			value:      "Company Name:name, Staff:EmployeesAmount",
+			expMapping: map[string]string{"Company Name": FieldName, "Staff": FieldEmployeesAmount},
+		},
+		"missing field": {
+			value:  "Company Name",
+			expErr: "invalid column mapping \"Company Name\", expected column:field",
+		},
+		"unknown field": {
+			value:  "Staff:headcount",
+			expErr: "column \"Staff\" is mapped to unknown field \"headcount\"",
+		},
+		"attribute": {
+			value:      "VAT:attr.vat_number",
+			expMapping: map[string]string{"VAT": "attr.vat_number"},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			mapping, err := ParseMapping(tt.value)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expMapping, mapping)
+		})
+	}
+}
+
+func TestDecoder_CSV(t *testing.T) {
+	t.Parallel()
+	file := "Company Name,Employees Amount,registered,type,country\n" +
+		"Acme,12,true,Corporations,NL\n" +
+		"Globex,lots,false,NonProfit,US\n" +
+		"Initech,3\n" +
+		"\"Hooli, Inc\",,,Cooperative,US\n"
+
+	dec, err := NewDecoder(strings.NewReader(file), FormatCSV, map[string]string{"Company Name": FieldName})
+	assert.NoError(t, err)
+
+	rows, errs := decodeAll(t, dec)
+	assert.Equal(t, []Row{
+		{Line: 2, Company: models.Company{Name: "Acme", EmployeesAmount: 12, Registered: true, Type: common.Corporations}},
+		{Line: 3},
+		{Line: 4},
+		{Line: 5, Company: models.Company{Name: "Hooli, Inc", Type: common.Cooperative}},
+	}, rows)
+	assert.Equal(t, []string{"employees_amount must be an integer", "wrong number of fields"}, errs)
+}
+
+func TestDecoder_Attributes(t *testing.T) {
+	t.Parallel()
+	file := "name,attr.founded,VAT,attr.\n" +
+		"Acme,1947,DE123,x\n" +
+		"Globex,,,\n"
+
+	dec, err := NewDecoder(strings.NewReader(file), FormatCSV, map[string]string{"VAT": "attr.vat_number"})
+	assert.NoError(t, err)
+	rows, errs := decodeAll(t, dec)
+	assert.Empty(t, errs)
+	assert.Equal(t, []Row{
+		{Line: 2, Company: models.Company{Name: "Acme"}, Attributes: map[string]string{"founded": "1947", "vat_number": "DE123"}},
+		{Line: 3, Company: models.Company{Name: "Globex"}},
+	}, rows, "empty values are left out")
+
+	dec, err = NewDecoder(strings.NewReader(`{"name":"Acme","attr.founded":1947,"attr.listed":true}`), FormatNDJSON, nil)
+	assert.NoError(t, err)
+	rows, errs = decodeAll(t, dec)
+	assert.Empty(t, errs)
+	assert.Equal(t, []Row{
+		{Line: 1, Company: models.Company{Name: "Acme"}, Attributes: map[string]string{"founded": "1947", "listed": "true"}},
+	}, rows)
+}
+
+func TestDecoder_CSVHeader(t *testing.T) {
+	t.Parallel()
+	_, err := NewDecoder(strings.NewReader(""), FormatCSV, nil)
+	assert.EqualError(t, err, "csv file is empty")
+
+	_, err = NewDecoder(strings.NewReader("title,employees\n"), FormatCSV, nil)
+	assert.EqualError(t, err, "csv header has no name column")
+
+	_, err = NewDecoder(strings.NewReader(""), FormatJSON, nil)
+	assert.EqualError(t, err, "cannot import unknown format \"json\"")
+}
+
+func TestDecoder_NDJSON(t *testing.T) {
+	t.Parallel()
+	file := `{"name":"Acme","employees_amount":12,"registered":true,"type":"Corporations","country":"NL"}` + "\n" +
+		"\n" +
+		`{"Name":"Globex","EmployeesAmount":"7","Description":null}` + "\n" +
+		`["Initech"]` + "\n" +
+		`{"name":"Hooli","registered":"maybe"}` + "\n" +
+		`{"name":{"first":"Hooli"}}`
+
+	dec, err := NewDecoder(strings.NewReader(file), FormatNDJSON, nil)
+	assert.NoError(t, err)
+
+	rows, errs := decodeAll(t, dec)
+	assert.Equal(t, []Row{
+		{Line: 1, Company: models.Company{Name: "Acme", EmployeesAmount: 12, Registered: true, Type: common.Corporations}},
+		{Line: 3, Company: models.Company{Name: "Globex", EmployeesAmount: 7}},
+		{Line: 4},
+		{Line: 5},
+		{Line: 6},
+	}, rows)
+	assert.Equal(t, []string{"line is not a json object", "registered must be a boolean", "name must not be an object or an array"}, errs)
+}
@@ -0,0 +1,126 @@
+package companyio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/iNDicat0r/company/internal/app/models"
+)
+
+// csvHeader names the columns of an exported CSV file, they can be imported back as is.
+var csvHeader = []string{
+	"id", FieldName, FieldDescription, FieldEmployeesAmount, FieldRegistered, FieldType,
+	"user_id", "version", "created_at", "updated_at",
+}
+
+// ContentType returns the media type of files in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// Encoder writes companies to a stream one at a time. Close has to be called once every
+// company is written to complete the file.
+type Encoder struct {
+	format  Format
+	w       io.Writer
+	csv     *csv.Writer
+	written int
+}
+
+// NewEncoder returns an encoder writing to w in the given format.
+func NewEncoder(w io.Writer, format Format) (*Encoder, error) {
+	enc := &Encoder{format: format, w: w}
+	switch format {
+	case FormatCSV:
+		enc.csv = csv.NewWriter(w)
+	case FormatNDJSON, FormatJSON:
+	default:
+		return nil, fmt.Errorf("cannot export %w %q", ErrUnknownFormat, format)
+	}
+
+	return enc, nil
+}
+
+// Encode writes a company, the CSV header or the opening bracket of a JSON array is
+// written along with the first company.
+func (e *Encoder) Encode(company models.Company) error {
+	defer func() { e.written++ }()
+
+	if e.format == FormatCSV {
+		if e.written == 0 {
+			if err := e.csv.Write(csvHeader); err != nil {
+				return err
+			}
+		}
+		return e.csv.Write([]string{
+			company.ID.String(),
+			company.Name,
+			company.Description,
+			strconv.Itoa(company.EmployeesAmount),
+			strconv.FormatBool(company.Registered),
+			string(company.Type),
+			company.UserID.String(),
+			strconv.Itoa(company.Version),
+			company.CreatedAt.Format(time.RFC3339),
+			company.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	data, err := json.Marshal(company)
+	if err != nil {
+		return err
+	}
+
+	prefix, suffix := "", "\n"
+	if e.format == FormatJSON {
+		prefix, suffix = ",", ""
+		if e.written == 0 {
+			prefix = "["
+		}
+	}
+	_, err = fmt.Fprintf(e.w, "%s%s%s", prefix, data, suffix)
+	return err
+}
+
+// Flush writes the buffered data to the stream.
+func (e *Encoder) Flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+
+	return nil
+}
+
+// Close completes the file, an export without companies is a CSV header only or an empty JSON array.
+func (e *Encoder) Close() error {
+	switch e.format {
+	case FormatCSV:
+		if e.written == 0 {
+			if err := e.csv.Write(csvHeader); err != nil {
+				return err
+			}
+		}
+	case FormatJSON:
+		closing := "]"
+		if e.written == 0 {
+			closing = "[]"
+		}
+		if _, err := io.WriteString(e.w, closing); err != nil {
+			return err
+		}
+	}
+
+	return e.Flush()
+}
@@ -0,0 +1,110 @@
+package companyio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoder(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	acme := models.Company{
+		ID:              uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+		CreatedAt:       created,
+		UpdatedAt:       created,
+		Version:         2,
+		Name:            "Acme, Inc",
+		EmployeesAmount: 12,
+		Registered:      true,
+		Type:            common.Corporations,
+		UserID:          uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9"),
+	}
+	acmeJSON := `{"ID":"ca8fc620-509a-40ac-8cc0-525c37c9c4b9","CreatedAt":"2023-05-01T10:00:00Z","UpdatedAt":"2023-05-01T10:00:00Z","DeletedAt":null,"Version":2,"Name":"Acme, Inc","Slug":"","Description":"","EmployeesAmount":12,"Registered":true,"Status":"","Type":"Corporations","UserID":"b6000e46-809f-4684-abd9-dc8f445b5ca9","ParentID":null}`
+	cases := map[string]struct {
+		format    Format
+		companies []models.Company
+		expOutput string
+	}{
+		"empty csv": {
+			format:    FormatCSV,
+			expOutput: "id,name,description,employees_amount,registered,type,user_id,version,created_at,updated_at\n",
+		},
+		"csv": {
+			format:    FormatCSV,
+			companies: []models.Company{acme},
+			expOutput: "id,name,description,employees_amount,registered,type,user_id,version,created_at,updated_at\n" +
+				"ca8fc620-509a-40ac-8cc0-525c37c9c4b9,\"Acme, Inc\",,12,true,Corporations,b6000e46-809f-4684-abd9-dc8f445b5ca9,2,2023-05-01T10:00:00Z,2023-05-01T10:00:00Z\n",
+		},
+		"empty ndjson": {
+			format: FormatNDJSON,
+		},
+		"ndjson": {
+			format:    FormatNDJSON,
+			companies: []models.Company{acme, acme},
+			expOutput: acmeJSON + "\n" + acmeJSON + "\n",
+		},
+		"empty json": {
+			format:    FormatJSON,
+			expOutput: "[]",
+		},
+		"json": {
+			format:    FormatJSON,
+			companies: []models.Company{acme, acme},
+			expOutput: "[" + acmeJSON + "," + acmeJSON + "]",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var out strings.Builder
+			enc, err := NewEncoder(&out, tt.format)
+			assert.NoError(t, err)
+			for _, company := range tt.companies {
+				assert.NoError(t, enc.Encode(company))
+			}
+			assert.NoError(t, enc.Close())
+			assert.Equal(t, tt.expOutput, out.String())
+		})
+	}
+}
+
+func TestEncoder_RoundTrip(t *testing.T) {
+	t.Parallel()
+	acme := models.Company{Name: "Acme", Description: "Anvils", EmployeesAmount: 12, Registered: true, Type: common.Corporations}
+	for _, format := range []Format{FormatCSV, FormatNDJSON} {
+		var out strings.Builder
+		enc, err := NewEncoder(&out, format)
+		assert.NoError(t, err)
+		assert.NoError(t, enc.Encode(acme))
+		assert.NoError(t, enc.Close())
+
+		dec, err := NewDecoder(strings.NewReader(out.String()), format, nil)
+		assert.NoError(t, err)
+		rows, errs := decodeAll(t, dec)
+		assert.Empty(t, errs)
+		if assert.Len(t, rows, 1) {
+			assert.Equal(t, acme, rows[0].Company, "format %s", format)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+	format, err := ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	_, err = ParseFormat("xml")
+	assert.EqualError(t, err, "unknown format \"xml\"")
+
+	_, err = NewEncoder(&strings.Builder{}, Format("xml"))
+	assert.EqualError(t, err, "cannot export unknown format \"xml\"")
+}
@@ -0,0 +1,41 @@
+// Package countries holds the ISO 3166-1 table of countries addresses are validated against.
+package countries
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// Country is an entry of ISO 3166-1, Code is its alpha-2 code.
+type Country struct {
+	Code string
+	Name string
+}
+
+// table is the tab separated list of the alpha-2 codes and short names of the countries.
+//
+//go:embed countries.tsv
+var table string
+
+var byCode = parse(table)
+
+func parse(table string) map[string]Country {
+	countries := map[string]Country{}
+	for _, line := range strings.Split(strings.TrimSpace(table), "\n") {
+		code, name, _ := strings.Cut(line, "\t")
+		countries[code] = Country{Code: code, Name: name}
+	}
+
+	return countries
+}
+
+// Normalize returns the form codes are stored in, codes are matched ignoring case and surrounding spaces.
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Lookup returns the country with the alpha-2 code, ok is false for codes that are not assigned.
+func Lookup(code string) (country Country, ok bool) {
+	country, ok = byCode[Normalize(code)]
+	return country, ok
+}
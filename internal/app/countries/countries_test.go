@@ -0,0 +1,43 @@
+package countries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		code    string
+		expName string
+		expOK   bool
+	}{
+		"assigned code":     {code: "DE", expName: "Germany", expOK: true},
+		"lower case":        {code: "nl", expName: "Netherlands", expOK: true},
+		"surrounding space": {code: " ax ", expName: "Åland Islands", expOK: true},
+		"unassigned code":   {code: "XX"},
+		"alpha-3 code":      {code: "DEU"},
+		"empty":             {code: ""},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			country, ok := Lookup(tt.code)
+			assert.Equal(t, tt.expOK, ok)
+			assert.Equal(t, tt.expName, country.Name)
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+	assert.Len(t, byCode, 249)
+	for code, country := range byCode {
+		assert.Len(t, code, 2)
+		assert.Equal(t, Normalize(code), code)
+		assert.NotEmpty(t, country.Name)
+	}
+}
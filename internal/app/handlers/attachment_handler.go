@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// maxAttachmentRequestSize caps the body of an upload, the size of the file itself is limited
+// by the configuration and checked by the service.
+const maxAttachmentRequestSize = 256 << 20
+
+// maxAttachmentFieldSize is the largest form field accepted along the file of an upload.
+const maxAttachmentFieldSize = 1 << 10
+
+// AttachmentHandler is responsible for handling the routes of company attachments.
+type AttachmentHandler struct {
+	attachmentService services.CompanyAttachments
+}
+
+// NewAttachmentHandler creates a new attachment handler.
+func NewAttachmentHandler(attachmentService services.CompanyAttachments) (*AttachmentHandler, error) {
+	if attachmentService == nil {
+		return nil, errors.New("attachment service is nil")
+	}
+
+	return &AttachmentHandler{attachmentService: attachmentService}, nil
+}
+
+// attachmentResponse represents an attachment of a company. DownloadURL is signed, it gives
+// access to the content until DownloadExpiresAt without authentication.
+type attachmentResponse struct {
+	ID                uuid.UUID             `json:"id"`
+	Kind              models.AttachmentKind `json:"kind"`
+	FileName          string                `json:"file_name"`
+	ContentType       string                `json:"content_type"`
+	Size              int64                 `json:"size"`
+	Checksum          string                `json:"checksum"`
+	UploadedBy        uuid.UUID             `json:"uploaded_by"`
+	CreatedAt         time.Time             `json:"created_at"`
+	DownloadURL       string                `json:"download_url"`
+	DownloadExpiresAt time.Time             `json:"download_expires_at"`
+}
+
+func newAttachmentResponse(signed services.SignedAttachment) attachmentResponse {
+	attachment := signed.Attachment
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(signed.Download.Expires.Unix(), 10))
+	query.Set("signature", signed.Download.Signature)
+
+	return attachmentResponse{
+		ID:                attachment.ID,
+		Kind:              attachment.Kind,
+		FileName:          attachment.FileName,
+		ContentType:       attachment.ContentType,
+		Size:              attachment.Size,
+		Checksum:          attachment.Checksum,
+		UploadedBy:        attachment.UploadedBy,
+		CreatedAt:         attachment.CreatedAt,
+		DownloadURL:       fmt.Sprintf("/v1/companies/%s/attachments/%s/content?%s", attachment.CompanyID, attachment.ID, query.Encode()),
+		DownloadExpiresAt: signed.Download.Expires.UTC(),
+	}
+}
+
+// attachmentListResponse represents the attachments of a company.
+type attachmentListResponse struct {
+	Items []attachmentResponse `json:"items"`
+}
+
+// HandleListAttachments handles listing the attachments of a company.
+func (h *AttachmentHandler) HandleListAttachments(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	attachments, err := h.attachmentService.ListAttachments(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := attachmentListResponse{Items: make([]attachmentResponse, 0, len(attachments))}
+	for _, attachment := range attachments {
+		resp.Items = append(resp.Items, newAttachmentResponse(attachment))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleGetAttachment handles getting a single attachment of a company.
+func (h *AttachmentHandler) HandleGetAttachment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("attachmentID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c, userID, id, attachmentID)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAttachmentResponse(attachment))
+}
+
+// HandleUploadAttachment handles attaching the file of a multipart/form-data body to a company.
+// The body has a kind field and a file part, the kind comes first so the file can be streamed
+// to the blob store.
+func (h *AttachmentHandler) HandleUploadAttachment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type")); mediaType != "multipart/form-data" {
+		problem.WriteStatus(c, http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType, fmt.Errorf("unsupported content type %s", c.ContentType()))
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAttachmentRequestSize)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	var kind string
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, errors.New("the file part is missing"))
+			return
+		}
+		if err != nil {
+			writeMalformedUpload(c, err)
+			return
+		}
+
+		switch part.FormName() {
+		case "kind":
+			value, err := io.ReadAll(io.LimitReader(part, maxAttachmentFieldSize))
+			if err != nil {
+				writeMalformedUpload(c, err)
+				return
+			}
+			kind = string(value)
+		case "file":
+			if kind == "" {
+				problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, errors.New("the kind field has to come before the file part"))
+				return
+			}
+
+			attachment, err := h.attachmentService.UploadAttachment(c, userID, id, services.AttachmentUpload{
+				Kind:     models.AttachmentKind(kind),
+				FileName: part.FileName(),
+				Content:  part,
+			})
+			if err != nil {
+				writeUploadError(c, err)
+				return
+			}
+
+			c.JSON(http.StatusCreated, newAttachmentResponse(attachment))
+			return
+		}
+	}
+}
+
+// writeUploadError responds to an upload that failed, bodies over the size limit are reported as such.
+func writeUploadError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		problem.WriteStatus(c, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge, err)
+		return
+	}
+
+	problem.Write(c, err)
+}
+
+// writeMalformedUpload responds to a multipart body that could not be read.
+func writeMalformedUpload(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		problem.WriteStatus(c, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge, err)
+		return
+	}
+
+	problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+}
+
+// HandleDeleteAttachment handles removing an attachment of a company.
+func (h *AttachmentHandler) HandleDeleteAttachment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("attachmentID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c, userID, id, attachmentID); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleDownloadAttachment handles downloading the content of an attachment with a signed link,
+// the link authorizes the download so no authentication is needed. SVGs are served as plain bytes.
+func (h *AttachmentHandler) HandleDownloadAttachment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("attachmentID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, fmt.Errorf("invalid expires: %w", err))
+		return
+	}
+
+	link := services.DownloadLink{Expires: time.Unix(expires, 0), Signature: c.Query("signature")}
+	attachment, content, err := h.attachmentService.OpenAttachment(c, id, attachmentID, link)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+	defer content.Close()
+
+	contentType := attachment.ContentType
+	if contentType == "image/svg+xml" {
+		// scripts of an SVG would run on our origin if a browser rendered it
+		contentType = "application/octet-stream"
+	}
+
+	c.Header("ETag", `"`+attachment.Checksum+`"`)
+	c.Header("Cache-Control", "private, max-age="+strconv.FormatInt(int64(time.Until(link.Expires).Seconds()), 10))
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, attachment.Size, contentType, content, nil)
+}
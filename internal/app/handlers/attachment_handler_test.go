@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAttachmentHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewAttachmentHandler(nil)
+	assert.EqualError(t, err, "attachment service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewAttachmentHandler(&mockAttachmentService{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleUploadAttachment(t *testing.T) {
+	t.Parallel()
+	logo := services.SignedAttachment{
+		Attachment: models.CompanyAttachment{
+			ID:          uuid.MustParse("3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50"),
+			CreatedAt:   time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC),
+			CompanyID:   uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+			Kind:        models.AttachmentLogo,
+			FileName:    "logo.png",
+			ContentType: "image/png",
+			Size:        4,
+			Checksum:    "0ef1b3c2b4a5c4e0e3f2d8c8e1a3b7f4b5f1b7c3e2d4a6b8c0e1f2a3b4c5d6e7",
+			UploadedBy:  uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9"),
+		},
+		Download: services.DownloadLink{Expires: time.Date(2023, 10, 1, 12, 15, 0, 0, time.UTC), Signature: "c2lnbmF0dXJl"},
+	}
+	cases := map[string]struct {
+		attachmentService *mockAttachmentService
+		fields            [][2]string
+		contentType       string
+		responseStatus    int
+		responseBody      string
+		expUpload         string
+	}{
+		"not multipart": {
+			attachmentService: &mockAttachmentService{},
+			contentType:       "application/json",
+			responseStatus:    http.StatusUnsupportedMediaType,
+			responseBody:      "{\"type\":\"about:blank\",\"title\":\"Unsupported Media Type\",\"status\":415,\"detail\":\"unsupported content type application/json\",\"code\":\"unsupported_media_type\"}",
+		},
+		"no file": {
+			attachmentService: &mockAttachmentService{},
+			fields:            [][2]string{{"kind", "logo"}},
+			responseStatus:    http.StatusBadRequest,
+			responseBody:      "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"the file part is missing\",\"code\":\"malformed_request\"}",
+		},
+		"file before kind": {
+			attachmentService: &mockAttachmentService{},
+			fields:            [][2]string{{"file", "logo"}, {"kind", "logo"}},
+			responseStatus:    http.StatusBadRequest,
+			responseBody:      "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"the kind field has to come before the file part\",\"code\":\"malformed_request\"}",
+		},
+		"too large": {
+			attachmentService: &mockAttachmentService{err: fmt.Errorf("%w: must be at most 1 bytes", services.ErrAttachmentTooLarge)},
+			fields:            [][2]string{{"kind", "logo"}, {"file", "logo"}},
+			responseStatus:    http.StatusUnprocessableEntity,
+			responseBody:      "{\"type\":\"about:blank\",\"title\":\"Unprocessable Entity\",\"status\":422,\"detail\":\"attachment too large: must be at most 1 bytes\",\"code\":\"attachment_too_large\"}",
+			expUpload:         "logo:logo.png:logo",
+		},
+		"success": {
+			attachmentService: &mockAttachmentService{attachment: logo},
+			fields:            [][2]string{{"kind", "logo"}, {"file", "logo"}},
+			responseStatus:    http.StatusCreated,
+			responseBody:      "{\"id\":\"3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50\",\"kind\":\"logo\",\"file_name\":\"logo.png\",\"content_type\":\"image/png\",\"size\":4,\"checksum\":\"0ef1b3c2b4a5c4e0e3f2d8c8e1a3b7f4b5f1b7c3e2d4a6b8c0e1f2a3b4c5d6e7\",\"uploaded_by\":\"b6000e46-809f-4684-abd9-dc8f445b5ca9\",\"created_at\":\"2023-10-01T12:00:00Z\",\"download_url\":\"/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/attachments/3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50/content?expires=1696162500\\u0026signature=c2lnbmF0dXJl\",\"download_expires_at\":\"2023-10-01T12:15:00Z\"}",
+			expUpload:         "logo:logo.png:logo",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			for _, field := range tt.fields {
+				if field[0] == "file" {
+					part, _ := form.CreateFormFile("file", "logo.png")
+					_, _ = part.Write([]byte(field[1]))
+				} else {
+					_ = form.WriteField(field[0], field[1])
+				}
+			}
+			_ = form.Close()
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = form.FormDataContentType()
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("POST", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/attachments", &body)
+			c.Request.Header.Set("Content-Type", contentType)
+
+			handler, _ := NewAttachmentHandler(tt.attachmentService)
+			handler.HandleUploadAttachment(c)
+			assert.Equal(t, tt.responseStatus, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expUpload, tt.attachmentService.uploaded)
+		})
+	}
+}
+
+func TestHandleDownloadAttachment(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		attachmentService *mockAttachmentService
+		query             string
+		responseStatus    int
+		responseBody      string
+		expHeaders        map[string]string
+	}{
+		"no expiry": {
+			attachmentService: &mockAttachmentService{},
+			query:             "signature=c2lnbmF0dXJl",
+			responseStatus:    http.StatusBadRequest,
+			responseBody:      "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid expires: strconv.ParseInt: parsing \\\"\\\": invalid syntax\",\"code\":\"malformed_request\"}",
+		},
+		"expired": {
+			attachmentService: &mockAttachmentService{err: fmt.Errorf("%w: the link expired at 2023-10-01T12:15:00Z", services.ErrInvalidDownloadLink)},
+			query:             "expires=1696162500&signature=c2lnbmF0dXJl",
+			responseStatus:    http.StatusForbidden,
+			responseBody:      "{\"type\":\"about:blank\",\"title\":\"Forbidden\",\"status\":403,\"detail\":\"invalid download link: the link expired at 2023-10-01T12:15:00Z\",\"code\":\"invalid_download_link\"}",
+		},
+		"success": {
+			attachmentService: &mockAttachmentService{attachment: services.SignedAttachment{Attachment: models.CompanyAttachment{
+				FileName:    "annual report.pdf",
+				ContentType: "application/pdf",
+				Size:        8,
+				Checksum:    "abc123",
+			}}, content: "%PDF-1.7"},
+			query:          "expires=1696162500&signature=c2lnbmF0dXJl",
+			responseStatus: http.StatusOK,
+			responseBody:   "%PDF-1.7",
+			expHeaders: map[string]string{
+				"Content-Type":           "application/pdf",
+				"Content-Length":         "8",
+				"Content-Disposition":    "attachment; filename=\"annual report.pdf\"",
+				"ETag":                   "\"abc123\"",
+				"X-Content-Type-Options": "nosniff",
+			},
+		},
+		"svg is not rendered": {
+			attachmentService: &mockAttachmentService{attachment: services.SignedAttachment{Attachment: models.CompanyAttachment{
+				FileName:    "logo.svg",
+				ContentType: "image/svg+xml",
+				Size:        6,
+				Checksum:    "def456",
+			}}, content: "<svg/>"},
+			query:          "expires=1696162500&signature=c2lnbmF0dXJl",
+			responseStatus: http.StatusOK,
+			responseBody:   "<svg/>",
+			expHeaders: map[string]string{
+				"Content-Type":           "application/octet-stream",
+				"Content-Disposition":    "attachment; filename=logo.svg",
+				"X-Content-Type-Options": "nosniff",
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}, {Key: "attachmentID", Value: "3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50"}}
+			c.Request, _ = http.NewRequest("GET", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/attachments/3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50/content?"+tt.query, nil)
+
+			handler, _ := NewAttachmentHandler(tt.attachmentService)
+			handler.HandleDownloadAttachment(c)
+			assert.Equal(t, tt.responseStatus, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			for key, value := range tt.expHeaders {
+				assert.Equal(t, value, w.Header().Get(key), key)
+			}
+		})
+	}
+}
+
+// mockAttachmentService for testing, it records the kind, name and content of an upload.
+type mockAttachmentService struct {
+	attachment services.SignedAttachment
+	content    string
+	uploaded   string
+	err        error
+}
+
+func (m *mockAttachmentService) ListAttachments(_ context.Context, _, _ uuid.UUID) ([]services.SignedAttachment, error) {
+	return []services.SignedAttachment{m.attachment}, m.err
+}
+
+func (m *mockAttachmentService) GetAttachment(_ context.Context, _, _, _ uuid.UUID) (services.SignedAttachment, error) {
+	return m.attachment, m.err
+}
+
+func (m *mockAttachmentService) UploadAttachment(_ context.Context, _, _ uuid.UUID, upload services.AttachmentUpload) (services.SignedAttachment, error) {
+	content, _ := io.ReadAll(upload.Content)
+	m.uploaded = fmt.Sprintf("%s:%s:%s", upload.Kind, upload.FileName, content)
+	return m.attachment, m.err
+}
+
+func (m *mockAttachmentService) DeleteAttachment(_ context.Context, _, _, _ uuid.UUID) error {
+	return m.err
+}
+
+func (m *mockAttachmentService) OpenAttachment(_ context.Context, _, _ uuid.UUID, _ services.DownloadLink) (models.CompanyAttachment, io.ReadCloser, error) {
+	return m.attachment.Attachment, io.NopCloser(strings.NewReader(m.content)), m.err
+}
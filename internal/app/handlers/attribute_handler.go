@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// AttributeHandler is responsible for handling the routes of custom company attributes.
+type AttributeHandler struct {
+	attributeService services.CompanyAttributes
+}
+
+// NewAttributeHandler creates a new attribute handler.
+func NewAttributeHandler(attributeService services.CompanyAttributes) (*AttributeHandler, error) {
+	if attributeService == nil {
+		return nil, errors.New("attribute service is nil")
+	}
+
+	return &AttributeHandler{attributeService: attributeService}, nil
+}
+
+type attributeSchemaRequestPayload struct {
+	Name        string                      `json:"name"`
+	Type        models.AttributeType        `json:"type"`
+	Description string                      `json:"description"`
+	Required    bool                        `json:"required"`
+	Constraints models.AttributeConstraints `json:"constraints"`
+}
+
+func (p attributeSchemaRequestPayload) toServicePayload() services.AttributeSchemaPayload {
+	return services.AttributeSchemaPayload{
+		Type:        p.Type,
+		Description: p.Description,
+		Required:    p.Required,
+		Constraints: p.Constraints,
+	}
+}
+
+// attributeSchemaResponse represents an attribute schema.
+type attributeSchemaResponse struct {
+	Name        string                      `json:"name"`
+	Type        models.AttributeType        `json:"type"`
+	Description string                      `json:"description"`
+	Required    bool                        `json:"required"`
+	Constraints models.AttributeConstraints `json:"constraints"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
+
+func newAttributeSchemaResponse(schema models.AttributeSchema) attributeSchemaResponse {
+	return attributeSchemaResponse{
+		Name:        schema.Name,
+		Type:        schema.Type,
+		Description: schema.Description,
+		Required:    schema.Required,
+		Constraints: schema.Constraints,
+		CreatedAt:   schema.CreatedAt,
+		UpdatedAt:   schema.UpdatedAt,
+	}
+}
+
+// HandleListAttributeSchemas handles listing the attribute schemas.
+func (h *AttributeHandler) HandleListAttributeSchemas(c *gin.Context) {
+	schemas, err := h.attributeService.ListSchemas(c)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	items := make([]attributeSchemaResponse, 0, len(schemas))
+	for _, schema := range schemas {
+		items = append(items, newAttributeSchemaResponse(schema))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// HandleGetAttributeSchema handles getting a single attribute schema.
+func (h *AttributeHandler) HandleGetAttributeSchema(c *gin.Context) {
+	schema, err := h.attributeService.GetSchema(c, c.Param("name"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAttributeSchemaResponse(schema))
+}
+
+// HandleCreateAttributeSchema handles defining a new attribute.
+func (h *AttributeHandler) HandleCreateAttributeSchema(c *gin.Context) {
+	var reqBody attributeSchemaRequestPayload
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	schema, err := h.attributeService.CreateSchema(c, reqBody.Name, reqBody.toServicePayload())
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newAttributeSchemaResponse(schema))
+}
+
+// HandleUpdateAttributeSchema handles replacing the description, required flag and
+// constraints of an attribute, the name and type of an attribute cannot change.
+func (h *AttributeHandler) HandleUpdateAttributeSchema(c *gin.Context) {
+	name := c.Param("name")
+
+	var reqBody attributeSchemaRequestPayload
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+	if reqBody.Name != "" && reqBody.Name != name {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, errors.New("the name of an attribute cannot change"))
+		return
+	}
+
+	schema, err := h.attributeService.UpdateSchema(c, name, reqBody.toServicePayload())
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAttributeSchemaResponse(schema))
+}
+
+// HandleDeleteAttributeSchema handles removing an attribute along with the values companies have for it.
+func (h *AttributeHandler) HandleDeleteAttributeSchema(c *gin.Context) {
+	if err := h.attributeService.DeleteSchema(c, c.Param("name")); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleGetCompanyAttributes handles getting the attribute values of a company.
+func (h *AttributeHandler) HandleGetCompanyAttributes(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	values, err := h.attributeService.GetAttributes(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, values)
+}
+
+// HandleReplaceCompanyAttributes handles replacing every attribute value of a company.
+func (h *AttributeHandler) HandleReplaceCompanyAttributes(c *gin.Context) {
+	h.setAttributes(c, true)
+}
+
+// HandlePatchCompanyAttributes handles merging values into the attribute values of a company,
+// a null value removes an attribute.
+func (h *AttributeHandler) HandlePatchCompanyAttributes(c *gin.Context) {
+	h.setAttributes(c, false)
+}
+
+// setAttributes reads the attribute values of the body and replaces or merges them into the
+// ones of the company, it responds with the resulting values.
+func (h *AttributeHandler) setAttributes(c *gin.Context, replace bool) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	var reqBody map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	values, err := h.attributeService.SetAttributes(c, userID, id, reqBody, replace)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, values)
+}
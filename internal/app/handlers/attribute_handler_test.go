@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAttributeHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewAttributeHandler(nil)
+	assert.EqualError(t, err, "attribute service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewAttributeHandler(&mockAttributeService{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleCreateAttributeSchema(t *testing.T) {
+	t.Parallel()
+	createdAt := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		attributeService *mockAttributeService
+		body             string
+		responseStatus   int
+		responseBody     string
+	}{
+		"malformed body": {
+			attributeService: &mockAttributeService{},
+			body:             "{",
+			responseStatus:   http.StatusBadRequest,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unexpected EOF\",\"code\":\"malformed_request\"}",
+		},
+		"name taken": {
+			attributeService: &mockAttributeService{err: fmt.Errorf("%w: \"segment\"", services.ErrAttributeExists)},
+			body:             `{"name":"segment","type":"enum","constraints":{"options":["smb","enterprise"]}}`,
+			responseStatus:   http.StatusConflict,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"attribute already exists: \\\"segment\\\"\",\"code\":\"attribute_exists\"}",
+		},
+		"success": {
+			attributeService: &mockAttributeService{schema: models.AttributeSchema{
+				Name:        "segment",
+				Type:        models.AttributeEnum,
+				Required:    true,
+				Constraints: models.AttributeConstraints{Options: []string{"smb", "enterprise"}},
+				CreatedAt:   createdAt,
+				UpdatedAt:   createdAt,
+			}},
+			body:           `{"name":"segment","type":"enum","required":true,"constraints":{"options":["smb","enterprise"]}}`,
+			responseStatus: http.StatusCreated,
+			responseBody:   "{\"name\":\"segment\",\"type\":\"enum\",\"description\":\"\",\"required\":true,\"constraints\":{\"options\":[\"smb\",\"enterprise\"]},\"created_at\":\"2023-10-01T12:00:00Z\",\"updated_at\":\"2023-10-01T12:00:00Z\"}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/v1/attributes", strings.NewReader(tt.body))
+
+			handler, _ := NewAttributeHandler(tt.attributeService)
+			handler.HandleCreateAttributeSchema(c)
+			assert.Equal(t, tt.responseStatus, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleUpdateAttributeSchema_NameCannotChange(t *testing.T) {
+	t.Parallel()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "name", Value: "segment"}}
+	c.Request, _ = http.NewRequest("PUT", "/v1/attributes/segment", strings.NewReader(`{"name":"tier"}`))
+
+	handler, _ := NewAttributeHandler(&mockAttributeService{})
+	handler.HandleUpdateAttributeSchema(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"the name of an attribute cannot change\",\"code\":\"malformed_request\"}", w.Body.String())
+}
+
+func TestHandleSetCompanyAttributes(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		attributeService *mockAttributeService
+		method           string
+		body             string
+		responseStatus   int
+		responseBody     string
+		expReplace       bool
+	}{
+		"malformed body": {
+			attributeService: &mockAttributeService{},
+			method:           "PUT",
+			body:             `{"vat_number":`,
+			responseStatus:   http.StatusBadRequest,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unexpected EOF\",\"code\":\"malformed_request\"}",
+		},
+		"invalid values": {
+			attributeService: &mockAttributeService{err: fmt.Errorf("%w: vat_number is required", services.ErrInvalidAttributes)},
+			method:           "PUT",
+			body:             `{}`,
+			responseStatus:   http.StatusUnprocessableEntity,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Unprocessable Entity\",\"status\":422,\"detail\":\"invalid attributes: vat_number is required\",\"code\":\"invalid_attributes\"}",
+			expReplace:       true,
+		},
+		"replace": {
+			attributeService: &mockAttributeService{values: services.AttributeValues{"founded": int64(1947), "vat_number": "DE123"}},
+			method:           "PUT",
+			body:             `{"founded":1947,"vat_number":"DE123"}`,
+			responseStatus:   http.StatusOK,
+			responseBody:     "{\"founded\":1947,\"vat_number\":\"DE123\"}",
+			expReplace:       true,
+		},
+		"merge": {
+			attributeService: &mockAttributeService{values: services.AttributeValues{"vat_number": "DE123"}},
+			method:           "PATCH",
+			body:             `{"founded":null}`,
+			responseStatus:   http.StatusOK,
+			responseBody:     "{\"vat_number\":\"DE123\"}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest(tt.method, "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/attributes", strings.NewReader(tt.body))
+
+			handler, _ := NewAttributeHandler(tt.attributeService)
+			if tt.method == "PUT" {
+				handler.HandleReplaceCompanyAttributes(c)
+			} else {
+				handler.HandlePatchCompanyAttributes(c)
+			}
+			assert.Equal(t, tt.responseStatus, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expReplace, tt.attributeService.replace)
+		})
+	}
+}
+
+func TestParseCompanyFilter_Attributes(t *testing.T) {
+	t.Parallel()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/v1/companies?attr[segment]=smb&attr[listed]=true&attr_min[founded]=1900&attr_max[founded]=2000", nil)
+
+	filter, err := parseCompanyFilter(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []services.AttributeCondition{
+		{Name: "listed", Operator: services.AttributeEqual, Value: "true"},
+		{Name: "segment", Operator: services.AttributeEqual, Value: "smb"},
+		{Name: "founded", Operator: services.AttributeMin, Value: "1900"},
+		{Name: "founded", Operator: services.AttributeMax, Value: "2000"},
+	}, filter.Attributes)
+}
+
+// mockAttributeService for testing.
+type mockAttributeService struct {
+	schema  models.AttributeSchema
+	values  services.AttributeValues
+	replace bool
+	err     error
+}
+
+func (m *mockAttributeService) ListSchemas(_ context.Context) ([]models.AttributeSchema, error) {
+	return []models.AttributeSchema{m.schema}, m.err
+}
+
+func (m *mockAttributeService) GetSchema(_ context.Context, _ string) (models.AttributeSchema, error) {
+	return m.schema, m.err
+}
+
+func (m *mockAttributeService) CreateSchema(_ context.Context, _ string, _ services.AttributeSchemaPayload) (models.AttributeSchema, error) {
+	return m.schema, m.err
+}
+
+func (m *mockAttributeService) UpdateSchema(_ context.Context, _ string, _ services.AttributeSchemaPayload) (models.AttributeSchema, error) {
+	return m.schema, m.err
+}
+
+func (m *mockAttributeService) DeleteSchema(_ context.Context, _ string) error {
+	return m.err
+}
+
+func (m *mockAttributeService) GetAttributes(_ context.Context, _, _ uuid.UUID) (services.AttributeValues, error) {
+	return m.values, m.err
+}
+
+func (m *mockAttributeService) SetAttributes(_ context.Context, _, _ uuid.UUID, _ map[string]json.RawMessage, replace bool) (services.AttributeValues, error) {
+	m.replace = replace
+	return m.values, m.err
+}
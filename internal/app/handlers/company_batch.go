@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "best_effort"
+)
+
+type createCompaniesRequestPayload struct {
+	Mode  string                        `json:"mode"`
+	Items []createCompanyRequestPayload `json:"items"`
+}
+
+// batchItemResponse represents the outcome of a single item of a batch.
+type batchItemResponse struct {
+	Index  int                 `json:"index"`
+	Status string              `json:"status"`
+	ID     *uuid.UUID          `json:"id,omitempty"`
+	Error  string              `json:"error,omitempty"`
+	Errors []problem.Violation `json:"errors,omitempty"`
+}
+
+// batchResponse represents the outcome of a batch, items are in the order of the request.
+type batchResponse struct {
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+	Items   []batchItemResponse `json:"items"`
+}
+
+func newBatchResponse(results []services.BatchItemResult) batchResponse {
+	resp := batchResponse{Items: make([]batchItemResponse, 0, len(results))}
+	for i, result := range results {
+		item := batchItemResponse{Index: i, Status: "skipped"}
+		switch {
+		case result.Created:
+			id := result.Company.ID
+			item.Status, item.ID = "created", &id
+			resp.Created++
+		case result.Err != nil:
+			item.Status, item.Error = "failed", result.Err.Error()
+			item.Errors = problem.Violations(result.Err, fmt.Sprintf("items[%d].", i))
+			resp.Failed++
+		}
+		resp.Items = append(resp.Items, item)
+	}
+
+	return resp
+}
+
+// HandleCreateCompanies handles creating many companies at once. In atomic mode, the default,
+// either every company is created or none, in best_effort mode the valid ones are created.
+// It answers 201 when every company was created, 207 when only some were and 422 otherwise.
+func (h *CompanyHandler) HandleCreateCompanies(c *gin.Context) {
+	var reqBody createCompaniesRequestPayload
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	if reqBody.Mode == "" {
+		reqBody.Mode = batchModeAtomic
+	}
+	if reqBody.Mode != batchModeAtomic && reqBody.Mode != batchModeBestEffort {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, errors.New("mode must be atomic or best_effort"))
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	payloads := make([]services.CreateUpdateCompanyPayload, 0, len(reqBody.Items))
+	for _, item := range reqBody.Items {
+		payloads = append(payloads, services.CreateUpdateCompanyPayload{
+			Name:            item.Name,
+			Description:     item.Description,
+			EmployeesAmount: item.EmployeesAmount,
+			Registered:      item.Registered,
+			Type:            item.Type,
+			ParentID:        item.ParentID,
+			Attributes:      item.Attributes,
+		})
+	}
+
+	results, err := h.CompanyService.CreateBatch(c, userID, payloads, reqBody.Mode == batchModeAtomic)
+	if err != nil && !errors.Is(err, services.ErrBatchAborted) {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := newBatchResponse(results)
+	for _, result := range results {
+		if !result.Created {
+			continue
+		}
+		data, err := json.Marshal(companyEvent{Type: "company.created", Company: result.Company})
+		if err == nil {
+			h.eventProducer.SendMessage(topic, data)
+		}
+	}
+
+	switch {
+	case resp.Created == len(results):
+		c.JSON(http.StatusCreated, resp)
+	case resp.Created > 0:
+		c.JSON(http.StatusMultiStatus, resp)
+	default:
+		c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleCreateCompanies(t *testing.T) {
+	t.Parallel()
+	created := services.BatchItemResult{Company: models.Company{ID: uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9")}, Created: true}
+	alsoCreated := services.BatchItemResult{Company: models.Company{ID: uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d02")}, Created: true}
+	failed := services.BatchItemResult{Err: &services.Error{
+		Kind:       services.ErrValidation,
+		Code:       "invalid_company",
+		Message:    "invalid company: type must not be empty",
+		Violations: []services.Violation{{Field: "type", Message: "must not be empty"}},
+	}}
+	cases := map[string]struct {
+		companyService *mockCompanyService
+		requestBody    string
+		responseStatus int
+		responseBody   string
+		expAtomic      bool
+		expEvents      []uuid.UUID
+	}{
+		"invalid mode": {
+			companyService: &mockCompanyService{},
+			requestBody:    `{"mode":"sometimes","items":[]}`,
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"mode must be atomic or best_effort\",\"code\":\"malformed_request\"}",
+		},
+		"invalid batch": {
+			companyService: &mockCompanyService{err: fmt.Errorf("%w: must contain between 1 and 500 companies", services.ErrInvalidBatch)},
+			requestBody:    `{"items":[]}`,
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid batch: must contain between 1 and 500 companies\",\"code\":\"invalid_batch\"}",
+			expAtomic:      true,
+		},
+		"internal service error": {
+			companyService: &mockCompanyService{err: errors.New("internal error")},
+			requestBody:    `{"items":[{"name":"acme"}]}`,
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+			expAtomic:      true,
+		},
+		"atomic batch aborted": {
+			companyService: &mockCompanyService{
+				batchResults: []services.BatchItemResult{{}, failed},
+				err:          services.ErrBatchAborted,
+			},
+			requestBody:    `{"mode":"atomic","items":[{"name":"acme"},{"name":"globex"}]}`,
+			responseStatus: http.StatusUnprocessableEntity,
+			responseBody:   "{\"created\":0,\"failed\":1,\"items\":[{\"index\":0,\"status\":\"skipped\"},{\"index\":1,\"status\":\"failed\",\"error\":\"invalid company: type must not be empty\",\"errors\":[{\"field\":\"items[1].type\",\"message\":\"must not be empty\"}]}]}",
+			expAtomic:      true,
+		},
+		"atomic batch created": {
+			companyService: &mockCompanyService{batchResults: []services.BatchItemResult{created}},
+			requestBody:    `{"items":[{"name":"acme"}]}`,
+			responseStatus: http.StatusCreated,
+			responseBody:   "{\"created\":1,\"failed\":0,\"items\":[{\"index\":0,\"status\":\"created\",\"id\":\"ca8fc620-509a-40ac-8cc0-525c37c9c4b9\"}]}",
+			expAtomic:      true,
+			expEvents:      []uuid.UUID{created.Company.ID},
+		},
+		"atomic batch of two created": {
+			companyService: &mockCompanyService{batchResults: []services.BatchItemResult{created, alsoCreated}},
+			requestBody:    `{"items":[{"name":"acme"},{"name":"globex"}]}`,
+			responseStatus: http.StatusCreated,
+			responseBody:   "{\"created\":2,\"failed\":0,\"items\":[{\"index\":0,\"status\":\"created\",\"id\":\"ca8fc620-509a-40ac-8cc0-525c37c9c4b9\"},{\"index\":1,\"status\":\"created\",\"id\":\"0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d02\"}]}",
+			expAtomic:      true,
+			expEvents:      []uuid.UUID{created.Company.ID, alsoCreated.Company.ID},
+		},
+		"best effort batch partially created": {
+			companyService: &mockCompanyService{batchResults: []services.BatchItemResult{failed, created}},
+			requestBody:    `{"mode":"best_effort","items":[{"name":"acme"},{"name":"globex"}]}`,
+			responseStatus: http.StatusMultiStatus,
+			responseBody:   "{\"created\":1,\"failed\":1,\"items\":[{\"index\":0,\"status\":\"failed\",\"error\":\"invalid company: type must not be empty\",\"errors\":[{\"field\":\"items[0].type\",\"message\":\"must not be empty\"}]},{\"index\":1,\"status\":\"created\",\"id\":\"ca8fc620-509a-40ac-8cc0-525c37c9c4b9\"}]}",
+			expEvents:      []uuid.UUID{created.Company.ID},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			req, _ := http.NewRequest("POST", "/v1/companies/batch", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			producer := &producerStub{}
+			handler, _ := NewCompanyHandler(tt.companyService, producer)
+			handler.HandleCreateCompanies(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expAtomic, tt.companyService.batchAtomic)
+			if assert.Len(t, producer.sent, len(tt.expEvents), "one event per created company") {
+				for i, id := range tt.expEvents {
+					assert.True(t, strings.HasPrefix(producer.sent[i], "{\"type\":\"company.created\",\"company\":{\"ID\":\""+id.String()+"\""), producer.sent[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+)
+
+// companyETag returns the strong entity tag of a company, it changes with every update.
+func companyETag(comp models.Company) string {
+	return `"` + strconv.Itoa(comp.Version) + `"`
+}
+
+// setCompanyValidators adds the entity tag and the last modification date of the company to the response.
+func setCompanyValidators(c *gin.Context, comp models.Company) {
+	c.Header("ETag", companyETag(comp))
+	if !comp.UpdatedAt.IsZero() {
+		c.Header("Last-Modified", comp.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified evaluates the conditional headers of a read against the company.
+// If-None-Match takes precedence and If-Modified-Since is only used without it.
+func notModified(c *gin.Context, comp models.Company) bool {
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		return noneMatchHit(header, companyETag(comp))
+	}
+
+	header := c.GetHeader("If-Modified-Since")
+	if header == "" || comp.UpdatedAt.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+
+	// http dates have a precision of one second
+	return !comp.UpdatedAt.Truncate(time.Second).After(since)
+}
+
+// noneMatchHit reports whether an If-None-Match header lists the entity tag using the weak comparison.
+func noneMatchHit(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIfMatch returns the company version required by an If-Match header,
+// zero when the header is absent or matches any version.
+func parseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	if strings.Contains(header, ",") {
+		return 0, errors.New("If-Match must contain a single entity tag")
+	}
+
+	// If-Match uses the strong comparison so weak tags never match
+	if strings.HasPrefix(header, "W/") {
+		return 0, errors.New("If-Match does not accept weak entity tags")
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, fmt.Errorf("malformed entity tag %s", header)
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("unknown entity tag %s", header)
+	}
+
+	return version, nil
+}
+
+// writeVersionMismatch answers a request whose change was based on a stale company version,
+// it is a failed precondition when the client asked for a version and a conflict otherwise.
+func writeVersionMismatch(c *gin.Context, err error) {
+	p := problem.New(err)
+	if c.GetHeader("If-Match") != "" {
+		p = problem.NewStatus(http.StatusPreconditionFailed, p.Code, p.Detail)
+	}
+	problem.Render(c, p)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		header     string
+		expVersion int
+		expErr     string
+	}{
+		"absent": {
+			header: "",
+		},
+		"any": {
+			header: "*",
+		},
+		"strong tag": {
+			header:     `"12"`,
+			expVersion: 12,
+		},
+		"weak tag": {
+			header: `W/"12"`,
+			expErr: "If-Match does not accept weak entity tags",
+		},
+		"list of tags": {
+			header: `"1", "2"`,
+			expErr: "If-Match must contain a single entity tag",
+		},
+		"unquoted": {
+			header: `12`,
+			expErr: "malformed entity tag 12",
+		},
+		"foreign tag": {
+			header: `"abc"`,
+			expErr: `unknown entity tag "abc"`,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			version, err := parseIfMatch(tt.header)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expVersion, version)
+			}
+		})
+	}
+}
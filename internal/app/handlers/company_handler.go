@@ -3,11 +3,20 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
 	"github.com/iNDicat0r/company/internal/app/services"
 )
 
@@ -18,6 +27,13 @@ type eventProducer interface {
 	SendMessage(topic string, payload []byte) error
 }
 
+// companyEvent is published when something happens to a company as a whole, Type tells
+// what, e.g. company.restored.
+type companyEvent struct {
+	Type    string         `json:"type"`
+	Company models.Company `json:"company"`
+}
+
 // CompanyHandler is responsible for handling routes for company resources.
 type CompanyHandler struct {
 	CompanyService services.CompanyGetCreateUpdateDeleter
@@ -36,41 +52,258 @@ func NewCompanyHandler(companyService services.CompanyGetCreateUpdateDeleter, ev
 	return &CompanyHandler{CompanyService: companyService, eventProducer: eventProducer}, nil
 }
 
-// HandleGetCompany get a company handler.
+// HandleGetCompany get a company handler, it answers conditional requests with 304 Not Modified.
 func (h *CompanyHandler) HandleGetCompany(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("companyID"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
-	comp, err := h.CompanyService.Get(c, id)
+	comp, err := h.CompanyService.Get(c, userID, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
+		return
+	}
+
+	setCompanyValidators(c, comp)
+	if notModified(c, comp) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 	c.JSON(http.StatusOK, comp)
 }
 
+// HandleGetCompanyBySlug gets a company by slug. Slugs the company had before a rename
+// are redirected to its current one.
+func (h *CompanyHandler) HandleGetCompanyBySlug(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	slug := c.Param("slug")
+	comp, err := h.CompanyService.GetBySlug(c, userID, slug)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	if comp.Slug != slug {
+		c.Redirect(http.StatusMovedPermanently, path.Join(path.Dir(c.Request.URL.Path), url.PathEscape(comp.Slug)))
+		return
+	}
+
+	setCompanyValidators(c, comp)
+	if notModified(c, comp) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, comp)
+}
+
+// companyPageResponse represents a page of companies.
+type companyPageResponse struct {
+	Items      []models.Company `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// HandleListCompanies handles listing the companies of the user page by page.
+func (h *CompanyHandler) HandleListCompanies(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	params, err := parseListCompaniesParams(c)
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	page, err := h.CompanyService.List(c, userID, params)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCompanyPageResponse(page))
+}
+
+// newCompanyPageResponse renders a page of companies, an empty page has an empty list of items.
+func newCompanyPageResponse(page services.CompanyPage) companyPageResponse {
+	resp := companyPageResponse{
+		Items:      page.Companies,
+		NextCursor: page.NextCursor,
+	}
+	if resp.Items == nil {
+		resp.Items = []models.Company{}
+	}
+
+	return resp
+}
+
+// parseListCompaniesParams reads the filter, sort order and pagination of a listing from the query string.
+func parseListCompaniesParams(c *gin.Context) (services.ListCompaniesParams, error) {
+	filter, err := parseCompanyFilter(c)
+	if err != nil {
+		return services.ListCompaniesParams{}, err
+	}
+
+	params := services.ListCompaniesParams{
+		Filter: filter,
+		Cursor: c.Query("cursor"),
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		params.SortBy = strings.TrimPrefix(sort, "-")
+		params.Descending = strings.HasPrefix(sort, "-")
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		params.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return services.ListCompaniesParams{}, fmt.Errorf("invalid limit: %w", err)
+		}
+	}
+
+	return params, nil
+}
+
+// parseCompanyFilter reads the company filter from the query string.
+func parseCompanyFilter(c *gin.Context) (services.CompanyFilter, error) {
+	var filter services.CompanyFilter
+
+	for _, t := range queryList(c, "type") {
+		filter.Types = append(filter.Types, common.Type(t))
+	}
+	filter.TagsAll = queryList(c, "tags_all")
+	filter.TagsAny = queryList(c, "tags_any")
+	filter.TagsNone = queryList(c, "tags_none")
+	filter.Attributes = queryAttributeConditions(c)
+
+	if value := c.Query("registered"); value != "" {
+		registered, err := strconv.ParseBool(value)
+		if err != nil {
+			return services.CompanyFilter{}, fmt.Errorf("invalid registered: %w", err)
+		}
+		filter.Registered = &registered
+	}
+
+	ints := map[string]**int{
+		"min_employees": &filter.MinEmployees,
+		"max_employees": &filter.MaxEmployees,
+	}
+	for key, dst := range ints {
+		if value := c.Query(key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return services.CompanyFilter{}, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			*dst = &n
+		}
+	}
+
+	if value := c.Query("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			return services.CompanyFilter{}, fmt.Errorf("invalid user_id: %w", err)
+		}
+		filter.UserID = &userID
+	}
+
+	times := map[string]**time.Time{
+		"created_after":  &filter.CreatedAfter,
+		"created_before": &filter.CreatedBefore,
+		"updated_after":  &filter.UpdatedAfter,
+		"updated_before": &filter.UpdatedBefore,
+	}
+	for key, dst := range times {
+		if value := c.Query(key); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return services.CompanyFilter{}, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			*dst = &t
+		}
+	}
+
+	return filter, nil
+}
+
+// attributeQueryOperators maps the query string maps holding attribute conditions, e.g.
+// attr_min[founded]=2000-01-01, to the operator they compare with.
+var attributeQueryOperators = []struct {
+	key      string
+	operator services.AttributeOperator
+}{
+	{"attr", services.AttributeEqual},
+	{"attr_min", services.AttributeMin},
+	{"attr_max", services.AttributeMax},
+}
+
+// queryAttributeConditions reads the custom attribute conditions from the query string ordered by attribute name.
+func queryAttributeConditions(c *gin.Context) []services.AttributeCondition {
+	var conditions []services.AttributeCondition
+	for _, q := range attributeQueryOperators {
+		values := c.QueryMap(q.key)
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			conditions = append(conditions, services.AttributeCondition{Name: name, Operator: q.operator, Value: values[name]})
+		}
+	}
+
+	return conditions
+}
+
+// queryList reads a list from the query string, the parameter may be repeated and hold comma separated values.
+func queryList(c *gin.Context, key string) []string {
+	var list []string
+	for _, value := range c.QueryArray(key) {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+	}
+
+	return list
+}
+
 type createCompanyRequestPayload struct {
-	Name            string      `json:"name"`
-	Description     string      `json:"description"`
-	EmployeesAmount int         `json:"employees_amount"`
-	Registered      bool        `json:"registered"`
-	Type            common.Type `json:"type"`
+	Name            string                     `json:"name"`
+	Description     string                     `json:"description"`
+	EmployeesAmount int                        `json:"employees_amount"`
+	Registered      bool                       `json:"registered"`
+	Type            common.Type                `json:"type"`
+	ParentID        *uuid.UUID                 `json:"parent_id"`
+	Attributes      map[string]json.RawMessage `json:"attributes"`
 }
 
-// HandleCreateCompany handles creating a company.
+// HandleCreateCompany handles creating a company, required custom attributes have to be given.
 func (h *CompanyHandler) HandleCreateCompany(c *gin.Context) {
 	var reqBody createCompanyRequestPayload
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
 	userID, err := uuid.Parse(c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
@@ -80,11 +313,13 @@ func (h *CompanyHandler) HandleCreateCompany(c *gin.Context) {
 		EmployeesAmount: reqBody.EmployeesAmount,
 		Registered:      reqBody.Registered,
 		Type:            reqBody.Type,
+		ParentID:        reqBody.ParentID,
+		Attributes:      reqBody.Attributes,
 	}
 
 	comp, err := h.CompanyService.Create(c, userID, payload)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
 		return
 	}
 
@@ -94,63 +329,123 @@ func (h *CompanyHandler) HandleCreateCompany(c *gin.Context) {
 		h.eventProducer.SendMessage(topic, data)
 	}
 
+	setCompanyValidators(c, comp)
 	c.JSON(http.StatusCreated, comp)
 }
 
-// HandleUpdateCompany handles updating a company.
+// HandleUpdateCompany handles updating a company with a JSON merge patch or a JSON patch, the
+// employees_effective_on query parameter tells from which day a changed employees amount applies.
 func (h *CompanyHandler) HandleUpdateCompany(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("companyID"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
-	var reqBody createCompanyRequestPayload
-	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
-	payload := services.CreateUpdateCompanyPayload{
-		Name:            reqBody.Name,
-		Description:     reqBody.Description,
-		EmployeesAmount: reqBody.EmployeesAmount,
-		Registered:      reqBody.Registered,
-		Type:            reqBody.Type,
+	contentType := c.ContentType()
+	if contentType != mimeMergePatch && contentType != mimeJSON && contentType != mimeJSONPatch {
+		problem.WriteStatus(c, http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType, fmt.Errorf("unsupported content type %s", contentType))
+		return
+	}
+
+	version, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusPreconditionFailed, problem.CodePreconditionFailed, err)
+		return
 	}
 
-	comp, err := h.CompanyService.Update(c, id, payload)
+	employeesOn, err := parseQueryDay(c, "employees_effective_on")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
+	body, err := c.GetRawData()
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	var comp models.Company
+	if contentType == mimeJSONPatch {
+		operations, err := parseCompanyJSONPatch(body)
+		if err != nil {
+			problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+			return
+		}
+
+		comp, err = h.CompanyService.Patch(c, userID, id, version, operations, employeesOn)
+		switch {
+		case errors.Is(err, services.ErrVersionMismatch):
+			writeVersionMismatch(c, err)
+			return
+		case err != nil:
+			problem.Write(c, err)
+			return
+		}
+	} else {
+		payload, err := parseCompanyMergePatch(body)
+		if err != nil {
+			problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+			return
+		}
+		payload.EmployeesEffectiveOn = employeesOn
+
+		comp, err = h.CompanyService.Update(c, userID, id, version, payload)
+		if errors.Is(err, services.ErrVersionMismatch) {
+			writeVersionMismatch(c, err)
+			return
+		}
+		if err != nil {
+			problem.Write(c, err)
+			return
+		}
+	}
+
 	// TODO: this is not right, we need an envelope to show the type of the event
 	data, err := json.Marshal(comp)
 	if err == nil {
 		h.eventProducer.SendMessage(topic, data)
 	}
 
+	setCompanyValidators(c, comp)
 	c.JSON(http.StatusOK, comp)
 }
 
-// HandleDeleteCompany handles deleting a company.
+// HandleDeleteCompany handles deleting a company, the children query parameter tells what
+// happens to its subsidiaries.
 func (h *CompanyHandler) HandleDeleteCompany(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("companyID"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
 	userID, err := uuid.Parse(c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
-	err = h.CompanyService.Delete(c, userID, id)
+	version, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusPreconditionFailed, problem.CodePreconditionFailed, err)
+		return
+	}
+
+	err = h.CompanyService.Delete(c, userID, id, version, services.ChildrenPolicy(c.Query("children")))
+	if errors.Is(err, services.ErrVersionMismatch) {
+		writeVersionMismatch(c, err)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
 		return
 	}
 
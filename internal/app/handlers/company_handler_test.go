@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -53,12 +55,16 @@ func TestNewCompanyHandler(t *testing.T) {
 
 func TestHandleGetCompany(t *testing.T) {
 	t.Parallel()
+	modified := models.Company{Version: 2, UpdatedAt: time.Date(2023, 10, 17, 10, 0, 0, 500, time.UTC)}
+	modifiedBody := "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"2023-10-17T10:00:00.0000005Z\",\"DeletedAt\":null,\"Version\":2,\"Name\":\"\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}"
 	cases := map[string]struct {
 		companyService services.CompanyGetCreateUpdateDeleter
 		producer       eventProducer
 		params         gin.Params
+		headers        map[string]string
 		responseStatus int
 		responseBody   string
+		expHeaders     map[string]string
 		expErr         string
 	}{
 		"invalid company id": {
@@ -69,7 +75,7 @@ func TestHandleGetCompany(t *testing.T) {
 				Value: "invalidid2738",
 			}},
 			responseStatus: http.StatusBadRequest,
-			responseBody:   "{\"error\":\"invalid UUID length: 13\"}",
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid UUID length: 13\",\"code\":\"malformed_request\"}",
 		},
 		"internal service error": {
 			companyService: &mockCompanyService{err: errors.New("internal error")},
@@ -79,7 +85,17 @@ func TestHandleGetCompany(t *testing.T) {
 				Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
 			}},
 			responseStatus: http.StatusInternalServerError,
-			responseBody:   "{\"error\":\"internal error\"}",
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+		},
+		"company not found": {
+			companyService: &mockCompanyService{err: services.ErrCompanyNotFound},
+			producer:       &producerStub{},
+			params: gin.Params{gin.Param{
+				Key:   "companyID",
+				Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			}},
+			responseStatus: http.StatusNotFound,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"company not found\",\"code\":\"company_not_found\"}",
 		},
 		"success": {
 			companyService: &mockCompanyService{singleCompany: models.Company{
@@ -91,7 +107,40 @@ func TestHandleGetCompany(t *testing.T) {
 				Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
 			}},
 			responseStatus: http.StatusOK,
-			responseBody:   "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Name\":\"\",\"Description\":\"description 1\",\"EmployeesAmount\":0,\"Registered\":false,\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\"}",
+			responseBody:   "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":0,\"Name\":\"\",\"Slug\":\"\",\"Description\":\"description 1\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}",
+		},
+		"if-none-match hit": {
+			companyService: &mockCompanyService{singleCompany: modified},
+			producer:       &producerStub{},
+			params:         gin.Params{gin.Param{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}},
+			headers:        map[string]string{"If-None-Match": `"1", W/"2"`},
+			responseStatus: http.StatusNotModified,
+			expHeaders:     map[string]string{"ETag": `"2"`, "Last-Modified": "Tue, 17 Oct 2023 10:00:00 GMT"},
+		},
+		"if-none-match takes precedence": {
+			companyService: &mockCompanyService{singleCompany: modified},
+			producer:       &producerStub{},
+			params:         gin.Params{gin.Param{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}},
+			headers:        map[string]string{"If-None-Match": `"1"`, "If-Modified-Since": "Tue, 17 Oct 2023 10:00:00 GMT"},
+			responseStatus: http.StatusOK,
+			responseBody:   modifiedBody,
+			expHeaders:     map[string]string{"ETag": `"2"`},
+		},
+		"not modified since": {
+			companyService: &mockCompanyService{singleCompany: modified},
+			producer:       &producerStub{},
+			params:         gin.Params{gin.Param{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}},
+			headers:        map[string]string{"If-Modified-Since": "Tue, 17 Oct 2023 10:00:00 GMT"},
+			responseStatus: http.StatusNotModified,
+		},
+		"modified since": {
+			companyService: &mockCompanyService{singleCompany: modified},
+			producer:       &producerStub{},
+			params:         gin.Params{gin.Param{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}},
+			headers:        map[string]string{"If-Modified-Since": "Tue, 17 Oct 2023 09:59:59 GMT"},
+			responseStatus: http.StatusOK,
+			responseBody:   modifiedBody,
+			expHeaders:     map[string]string{"Last-Modified": "Tue, 17 Oct 2023 10:00:00 GMT"},
 		},
 	}
 
@@ -101,11 +150,69 @@ func TestHandleGetCompany(t *testing.T) {
 			t.Parallel()
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
 			c.Params = append(c.Params, tt.params...)
+			req, _ := http.NewRequest("GET", "", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			c.Request = req
 			handler, _ := NewCompanyHandler(tt.companyService, tt.producer)
 			handler.HandleGetCompany(c)
 			assert.Equal(t, tt.responseStatus, c.Writer.Status())
 			assert.Equal(t, tt.responseBody, w.Body.String())
+			for key, value := range tt.expHeaders {
+				assert.Equal(t, value, w.Header().Get(key))
+			}
+		})
+	}
+}
+
+func TestHandleGetCompanyBySlug(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyService services.CompanyGetCreateUpdateDeleter
+		slug           string
+		responseStatus int
+		responseBody   string
+		expLocation    string
+	}{
+		"company not found": {
+			companyService: &mockCompanyService{err: fmt.Errorf("%w: initech", services.ErrCompanyNotFound)},
+			slug:           "initech",
+			responseStatus: http.StatusNotFound,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"company not found: initech\",\"code\":\"company_not_found\"}",
+		},
+		"previous slug is redirected": {
+			companyService: &mockCompanyService{singleCompany: models.Company{Slug: "globex"}},
+			slug:           "acme",
+			responseStatus: http.StatusMovedPermanently,
+			expLocation:    "/v1/companies/by-slug/globex",
+		},
+		"success": {
+			companyService: &mockCompanyService{singleCompany: models.Company{Version: 2, Name: "Acme", Slug: "acme"}},
+			slug:           "acme",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":2,\"Name\":\"Acme\",\"Slug\":\"acme\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Params = gin.Params{{Key: "slug", Value: tt.slug}}
+			c.Request, _ = http.NewRequest("GET", "/v1/companies/by-slug/"+tt.slug, nil)
+			handler, _ := NewCompanyHandler(tt.companyService, &producerStub{})
+			handler.HandleGetCompanyBySlug(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.expLocation, w.Header().Get("Location"))
+			if tt.expLocation == "" {
+				assert.Equal(t, tt.responseBody, w.Body.String())
+			}
 		})
 	}
 }
@@ -127,7 +234,23 @@ func TestHandleCreateCompany(t *testing.T) {
 			producer:         &producerStub{},
 			responseStatus:   http.StatusBadRequest,
 			requestBody:      "{",
-			responseBody:     "{\"error\":\"unexpected EOF\"}",
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unexpected EOF\",\"code\":\"malformed_request\"}",
+			setUserIDContext: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+		},
+		"invalid company": {
+			companyService: &mockCompanyService{err: &services.Error{
+				Kind:    services.ErrValidation,
+				Code:    "invalid_company",
+				Message: "invalid company: name must be at most 15 characters long; employees_amount must not be negative",
+				Violations: []services.Violation{
+					{Field: "name", Message: "must be at most 15 characters long"},
+					{Field: "employees_amount", Message: "must not be negative"},
+				},
+			}},
+			producer:         &producerStub{},
+			responseStatus:   http.StatusUnprocessableEntity,
+			requestBody:      `{"name":"a very long company name","employees_amount":-1}`,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Unprocessable Entity\",\"status\":422,\"detail\":\"invalid company: name must be at most 15 characters long; employees_amount must not be negative\",\"code\":\"invalid_company\",\"errors\":[{\"field\":\"name\",\"message\":\"must be at most 15 characters long\"},{\"field\":\"employees_amount\",\"message\":\"must not be negative\"}]}",
 			setUserIDContext: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
 		},
 		"success": {
@@ -135,7 +258,7 @@ func TestHandleCreateCompany(t *testing.T) {
 			producer:         &producerStub{},
 			responseStatus:   http.StatusCreated,
 			requestBody:      `{"name":"company1"}`,
-			responseBody:     "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Name\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\"}",
+			responseBody:     "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":0,\"Name\":\"\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}",
 			setUserIDContext: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
 		},
 	}
@@ -161,31 +284,331 @@ func TestHandleCreateCompany(t *testing.T) {
 	}
 }
 
+func TestHandleUpdateCompany(t *testing.T) {
+	t.Parallel()
+	name := "company1"
+	employees := 12
+	march := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		companyService *mockCompanyService
+		contentType    string
+		ifMatch        string
+		query          string
+		requestBody    string
+		responseStatus int
+		responseBody   string
+		expETag        string
+		expPayload     services.UpdateCompanyPayload
+		expEmployeesOn *time.Time
+	}{
+		"malformed if-match": {
+			companyService: &mockCompanyService{},
+			contentType:    "application/merge-patch+json",
+			ifMatch:        `W/"1"`,
+			requestBody:    `{}`,
+			responseStatus: http.StatusPreconditionFailed,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Precondition Failed\",\"status\":412,\"detail\":\"If-Match does not accept weak entity tags\",\"code\":\"precondition_failed\"}",
+		},
+		"if-match mismatch": {
+			companyService: &mockCompanyService{err: fmt.Errorf("%w: expected 1, current 2", services.ErrVersionMismatch)},
+			contentType:    "application/merge-patch+json",
+			ifMatch:        `"1"`,
+			requestBody:    `{}`,
+			responseStatus: http.StatusPreconditionFailed,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Precondition Failed\",\"status\":412,\"detail\":\"company version mismatch: expected 1, current 2\",\"code\":\"version_mismatch\"}",
+		},
+		"concurrent modification": {
+			companyService: &mockCompanyService{err: fmt.Errorf("%w: company was modified concurrently", services.ErrVersionMismatch)},
+			contentType:    "application/json-patch+json",
+			requestBody:    `[]`,
+			responseStatus: http.StatusConflict,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"company version mismatch: company was modified concurrently\",\"code\":\"version_mismatch\"}",
+		},
+		"unsupported content type": {
+			companyService: &mockCompanyService{},
+			contentType:    "text/plain",
+			requestBody:    "name=company1",
+			responseStatus: http.StatusUnsupportedMediaType,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Unsupported Media Type\",\"status\":415,\"detail\":\"unsupported content type text/plain\",\"code\":\"unsupported_media_type\"}",
+		},
+		"invalid merge patch": {
+			companyService: &mockCompanyService{},
+			contentType:    "application/merge-patch+json",
+			requestBody:    `{"registered":null}`,
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"member \\\"registered\\\" cannot be null\",\"code\":\"malformed_request\"}",
+		},
+		"internal service error": {
+			companyService: &mockCompanyService{err: errors.New("internal error")},
+			contentType:    "application/merge-patch+json",
+			requestBody:    `{"name":"company1"}`,
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+			expPayload:     services.UpdateCompanyPayload{Name: &name},
+		},
+		"success": {
+			companyService: &mockCompanyService{singleCompany: models.Company{Name: "company1", Version: 3}},
+			contentType:    "application/merge-patch+json",
+			ifMatch:        `"2"`,
+			requestBody:    `{"description":null}`,
+			responseStatus: http.StatusOK,
+			expETag:        `"3"`,
+			responseBody:   "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":3,\"Name\":\"company1\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}",
+			expPayload:     services.UpdateCompanyPayload{Description: new(string)},
+		},
+		"invalid json patch": {
+			companyService: &mockCompanyService{},
+			contentType:    "application/json-patch+json",
+			requestBody:    `{"op":"replace"}`,
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"json patch must be an array of operations\",\"code\":\"malformed_request\"}",
+		},
+		"json patch touching an immutable field": {
+			companyService: &mockCompanyService{err: fmt.Errorf("operation 0: %w: path \"/ID\" is immutable", services.ErrInvalidPatch)},
+			contentType:    "application/json-patch+json",
+			requestBody:    `[{"op":"remove","path":"/ID"}]`,
+			responseStatus: http.StatusUnprocessableEntity,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Unprocessable Entity\",\"status\":422,\"detail\":\"operation 0: invalid patch: path \\\"/ID\\\" is immutable\",\"code\":\"invalid_patch\"}",
+		},
+		"json patch test failed": {
+			companyService: &mockCompanyService{err: fmt.Errorf("operation 0: %w: \"/EmployeesAmount\" does not match 40", services.ErrPatchTestFailed)},
+			contentType:    "application/json-patch+json",
+			requestBody:    `[{"op":"test","path":"/EmployeesAmount","value":40}]`,
+			responseStatus: http.StatusConflict,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"operation 0: patch test failed: \\\"/EmployeesAmount\\\" does not match 40\",\"code\":\"patch_test_failed\"}",
+		},
+		"malformed employees effective date": {
+			companyService: &mockCompanyService{},
+			contentType:    "application/merge-patch+json",
+			query:          "employees_effective_on=2023-13-01",
+			requestBody:    `{"employees_amount":12}`,
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid employees_effective_on: parsing time \\\"2023-13-01\\\": month out of range\",\"code\":\"malformed_request\"}",
+		},
+		"merge patch with an employees effective date": {
+			companyService: &mockCompanyService{},
+			contentType:    "application/merge-patch+json",
+			query:          "employees_effective_on=2023-03-01",
+			requestBody:    `{"employees_amount":12}`,
+			responseStatus: http.StatusOK,
+			expETag:        `"0"`,
+			responseBody:   "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":0,\"Name\":\"\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}",
+			expPayload:     services.UpdateCompanyPayload{EmployeesAmount: &employees, EmployeesEffectiveOn: &march},
+		},
+		"json patch with an employees effective date": {
+			companyService: &mockCompanyService{},
+			contentType:    "application/json-patch+json",
+			query:          "employees_effective_on=2023-03-01",
+			requestBody:    `[{"op":"replace","path":"/employees_amount","value":12}]`,
+			responseStatus: http.StatusOK,
+			expETag:        `"0"`,
+			responseBody:   "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":0,\"Name\":\"\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}",
+			expEmployeesOn: &march,
+		},
+		"plain json is a merge patch": {
+			companyService: &mockCompanyService{},
+			contentType:    "application/json",
+			requestBody:    `{}`,
+			responseStatus: http.StatusOK,
+			expETag:        `"0"`,
+			responseBody:   "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":0,\"Name\":\"\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Params = gin.Params{gin.Param{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			req, _ := http.NewRequest("PATCH", "/?"+tt.query, bytes.NewBuffer([]byte(tt.requestBody)))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			c.Request = req
+
+			handler, _ := NewCompanyHandler(tt.companyService, &producerStub{})
+			handler.HandleUpdateCompany(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expETag, w.Header().Get("ETag"))
+			assert.Equal(t, tt.expPayload, tt.companyService.updatePayload)
+			assert.Equal(t, tt.expEmployeesOn, tt.companyService.employeesEffectiveOn)
+		})
+	}
+}
+
+func TestHandleDeleteCompany(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyService *mockCompanyService
+		query          string
+		responseStatus int
+		responseBody   string
+		expChildren    services.ChildrenPolicy
+	}{
+		"has subsidiaries": {
+			companyService: &mockCompanyService{err: fmt.Errorf("%w: delete them first or promote them to the parent company", services.ErrHasSubsidiaries)},
+			responseStatus: http.StatusConflict,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"company has subsidiaries: delete them first or promote them to the parent company\",\"code\":\"company_has_subsidiaries\"}",
+		},
+		"promote subsidiaries": {
+			companyService: &mockCompanyService{},
+			query:          "?children=promote",
+			responseStatus: http.StatusOK,
+			responseBody:   "null",
+			expChildren:    services.ChildrenPromote,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("DELETE", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9"+tt.query, nil)
+
+			handler, _ := NewCompanyHandler(tt.companyService, &producerStub{})
+			handler.HandleDeleteCompany(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expChildren, tt.companyService.deleteChildren)
+		})
+	}
+}
+
+func TestHandleListCompanies(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyService services.CompanyGetCreateUpdateDeleter
+		query          string
+		responseStatus int
+		responseBody   string
+	}{
+		"invalid registered": {
+			companyService: &mockCompanyService{},
+			query:          "registered=maybe",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid registered: strconv.ParseBool: parsing \\\"maybe\\\": invalid syntax\",\"code\":\"malformed_request\"}",
+		},
+		"invalid user id": {
+			companyService: &mockCompanyService{},
+			query:          "user_id=123",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid user_id: invalid UUID length: 3\",\"code\":\"malformed_request\"}",
+		},
+		"invalid limit": {
+			companyService: &mockCompanyService{},
+			query:          "limit=ten",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid limit: strconv.Atoi: parsing \\\"ten\\\": invalid syntax\",\"code\":\"malformed_request\"}",
+		},
+		"invalid list query": {
+			companyService: &mockCompanyService{err: fmt.Errorf("%w: malformed cursor", services.ErrInvalidListQuery)},
+			query:          "cursor=abc",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid list query: malformed cursor\",\"code\":\"invalid_list_query\"}",
+		},
+		"internal service error": {
+			companyService: &mockCompanyService{err: errors.New("internal error")},
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+		},
+		"empty page": {
+			companyService: &mockCompanyService{},
+			query:          "type=Corporations,NonProfit&registered=true&min_employees=1&created_after=2023-10-01T00:00:00Z&sort=-name",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[]}",
+		},
+		"success": {
+			companyService: &mockCompanyService{page: services.CompanyPage{
+				Companies:  []models.Company{{Name: "company1"}},
+				NextCursor: "next",
+			}},
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":0,\"Name\":\"company1\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}],\"next_cursor\":\"next\"}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			req, _ := http.NewRequest("GET", "/v1/companies?"+tt.query, nil)
+			c.Request = req
+
+			handler, _ := NewCompanyHandler(tt.companyService, &producerStub{})
+			handler.HandleListCompanies(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
 type mockCompanyService struct {
-	singleCompany models.Company
-	err           error
+	singleCompany        models.Company
+	page                 services.CompanyPage
+	updatePayload        services.UpdateCompanyPayload
+	patchOperations      []services.PatchOperation
+	employeesEffectiveOn *time.Time
+	batchResults         []services.BatchItemResult
+	batchAtomic          bool
+	deleteChildren       services.ChildrenPolicy
+	err                  error
+}
+
+func (m *mockCompanyService) Get(_ context.Context, _, _ uuid.UUID) (models.Company, error) {
+	return m.singleCompany, m.err
 }
 
-func (m *mockCompanyService) Get(_ context.Context, _ uuid.UUID) (models.Company, error) {
+func (m *mockCompanyService) GetBySlug(_ context.Context, _ uuid.UUID, _ string) (models.Company, error) {
 	return m.singleCompany, m.err
 }
 
+func (m *mockCompanyService) List(_ context.Context, _ uuid.UUID, _ services.ListCompaniesParams) (services.CompanyPage, error) {
+	return m.page, m.err
+}
+
 func (m *mockCompanyService) Create(_ context.Context, _ uuid.UUID, _ services.CreateUpdateCompanyPayload) (models.Company, error) {
 	return m.singleCompany, m.err
 }
 
-func (m *mockCompanyService) Update(_ context.Context, _ uuid.UUID, _ services.CreateUpdateCompanyPayload) (models.Company, error) {
+func (m *mockCompanyService) CreateBatch(_ context.Context, _ uuid.UUID, _ []services.CreateUpdateCompanyPayload, atomic bool) ([]services.BatchItemResult, error) {
+	m.batchAtomic = atomic
+	return m.batchResults, m.err
+}
+
+func (m *mockCompanyService) Update(_ context.Context, _, _ uuid.UUID, _ int, payload services.UpdateCompanyPayload) (models.Company, error) {
+	m.updatePayload = payload
+	return m.singleCompany, m.err
+}
+
+func (m *mockCompanyService) Patch(_ context.Context, _, _ uuid.UUID, _ int, operations []services.PatchOperation, employeesEffectiveOn *time.Time) (models.Company, error) {
+	m.patchOperations = operations
+	m.employeesEffectiveOn = employeesEffectiveOn
 	return m.singleCompany, m.err
 }
 
-func (m *mockCompanyService) Delete(_ context.Context, _, _ uuid.UUID) error {
+func (m *mockCompanyService) Delete(_ context.Context, _, _ uuid.UUID, _ int, children services.ChildrenPolicy) error {
+	m.deleteChildren = children
 	return m.err
 }
 
 type producerStub struct {
-	err error
+	sent []string
+	err  error
 }
 
 func (p *producerStub) SendMessage(topic string, payload []byte) error {
+	p.sent = append(p.sent, string(payload))
 	return p.err
 }
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// media types accepted when patching a company.
+const (
+	mimeJSON       = "application/json"
+	mimeMergePatch = "application/merge-patch+json"
+	mimeJSONPatch  = "application/json-patch+json"
+)
+
+// jsonPatchOperation represents an operation of an RFC 6902 JSON patch document.
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// parseCompanyMergePatch turns an RFC 7396 JSON merge patch into an update payload.
+// Members absent from the patch are left untouched, null clears the optional description and
+// parent and is rejected for the required fields.
+func parseCompanyMergePatch(data []byte) (services.UpdateCompanyPayload, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		return services.UpdateCompanyPayload{}, errors.New("merge patch must be a JSON object")
+	}
+
+	var payload services.UpdateCompanyPayload
+	for member, raw := range members {
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+		var err error
+		switch member {
+		case "name":
+			err = decodeRequired(member, raw, isNull, &payload.Name)
+		case "description":
+			payload.Description = new(string)
+			if !isNull {
+				err = decodeMember(member, raw, payload.Description)
+			}
+		case "employees_amount":
+			err = decodeRequired(member, raw, isNull, &payload.EmployeesAmount)
+		case "registered":
+			err = decodeRequired(member, raw, isNull, &payload.Registered)
+		case "type":
+			err = decodeRequired(member, raw, isNull, &payload.Type)
+		case "parent_id":
+			payload.ParentID = new(uuid.UUID)
+			if !isNull {
+				err = decodeMember(member, raw, payload.ParentID)
+			}
+		default:
+			err = fmt.Errorf("unknown member %q", member)
+		}
+		if err != nil {
+			return services.UpdateCompanyPayload{}, err
+		}
+	}
+
+	return payload, nil
+}
+
+// decodeRequired decodes a member that cannot be removed from a company.
+func decodeRequired[T string | int | bool | common.Type](member string, raw json.RawMessage, isNull bool, dst **T) error {
+	if isNull {
+		return fmt.Errorf("member %q cannot be null", member)
+	}
+
+	*dst = new(T)
+	return decodeMember(member, raw, *dst)
+}
+
+func decodeMember(member string, raw json.RawMessage, dst any) error {
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("invalid member %q: %w", member, err)
+	}
+
+	return nil
+}
+
+// parseCompanyJSONPatch decodes an RFC 6902 JSON patch document into its operations.
+func parseCompanyJSONPatch(data []byte) ([]services.PatchOperation, error) {
+	var document []jsonPatchOperation
+	if err := json.Unmarshal(data, &document); err != nil || document == nil {
+		return nil, errors.New("json patch must be an array of operations")
+	}
+
+	operations := make([]services.PatchOperation, 0, len(document))
+	for i, op := range document {
+		if op.Op == "" || op.Path == "" {
+			return nil, fmt.Errorf("operation %d: op and path are required", i)
+		}
+		operations = append(operations, services.PatchOperation{
+			Op:    op.Op,
+			Path:  op.Path,
+			Value: op.Value,
+		})
+	}
+
+	return operations, nil
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCompanyMergePatch(t *testing.T) {
+	t.Parallel()
+	name, empty, amount, registered, companyType := "company1", "", 0, false, common.NonProfit
+	parentID, noParent := uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), uuid.Nil
+	cases := map[string]struct {
+		patch      string
+		expPayload services.UpdateCompanyPayload
+		expErr     string
+	}{
+		"not an object": {
+			patch:  `["name"]`,
+			expErr: "merge patch must be a JSON object",
+		},
+		"null document": {
+			patch:  `null`,
+			expErr: "merge patch must be a JSON object",
+		},
+		"unknown member": {
+			patch:  `{"id":"ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}`,
+			expErr: "unknown member \"id\"",
+		},
+		"required member set to null": {
+			patch:  `{"name":null}`,
+			expErr: "member \"name\" cannot be null",
+		},
+		"invalid member": {
+			patch:  `{"employees_amount":"ten"}`,
+			expErr: "invalid member \"employees_amount\": json: cannot unmarshal string into Go value of type int",
+		},
+		"empty patch leaves everything untouched": {
+			patch: `{}`,
+		},
+		"zero values are set": {
+			patch: `{"name":"company1","employees_amount":0,"registered":false,"type":"NonProfit"}`,
+			expPayload: services.UpdateCompanyPayload{
+				Name:            &name,
+				EmployeesAmount: &amount,
+				Registered:      &registered,
+				Type:            &companyType,
+			},
+		},
+		"null clears the description": {
+			patch:      `{"description":null}`,
+			expPayload: services.UpdateCompanyPayload{Description: &empty},
+		},
+		"parent is set": {
+			patch:      `{"parent_id":"ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}`,
+			expPayload: services.UpdateCompanyPayload{ParentID: &parentID},
+		},
+		"null detaches from the parent": {
+			patch:      `{"parent_id":null}`,
+			expPayload: services.UpdateCompanyPayload{ParentID: &noParent},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			payload, err := parseCompanyMergePatch([]byte(tt.patch))
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expPayload, payload)
+			}
+		})
+	}
+}
+
+func TestParseCompanyJSONPatch(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		patch         string
+		expOperations []services.PatchOperation
+		expErr        string
+	}{
+		"not an array": {
+			patch:  `{"op":"remove","path":"/description"}`,
+			expErr: "json patch must be an array of operations",
+		},
+		"missing path": {
+			patch:  `[{"op":"remove"}]`,
+			expErr: "operation 0: op and path are required",
+		},
+		"success": {
+			patch: `[{"op":"test","path":"/EmployeesAmount","value":40},{"op":"replace","path":"/Type","value":"NonProfit"}]`,
+			expOperations: []services.PatchOperation{
+				{Op: "test", Path: "/EmployeesAmount", Value: json.RawMessage(`40`)},
+				{Op: "replace", Path: "/Type", Value: json.RawMessage(`"NonProfit"`)},
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			operations, err := parseCompanyJSONPatch([]byte(tt.patch))
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expOperations, operations)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// CompanyTypeHandler is responsible for handling the routes of the company type catalogue.
+type CompanyTypeHandler struct {
+	typeService services.CompanyTypes
+}
+
+// NewCompanyTypeHandler creates a new company type handler.
+func NewCompanyTypeHandler(typeService services.CompanyTypes) (*CompanyTypeHandler, error) {
+	if typeService == nil {
+		return nil, errors.New("company type service is nil")
+	}
+
+	return &CompanyTypeHandler{typeService: typeService}, nil
+}
+
+type companyTypeRequestPayload struct {
+	Name        common.Type `json:"name"`
+	Description string      `json:"description"`
+	Deprecated  bool        `json:"deprecated"`
+}
+
+// companyTypeResponse represents a company type of the catalogue.
+type companyTypeResponse struct {
+	Name        common.Type `json:"name"`
+	Description string      `json:"description"`
+	Deprecated  bool        `json:"deprecated"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+func newCompanyTypeResponse(companyType models.CompanyType) companyTypeResponse {
+	return companyTypeResponse{
+		Name:        companyType.Name,
+		Description: companyType.Description,
+		Deprecated:  companyType.Deprecated,
+		CreatedAt:   companyType.CreatedAt,
+		UpdatedAt:   companyType.UpdatedAt,
+	}
+}
+
+// HandleListCompanyTypes handles listing the company types, deprecated types are only
+// listed with include_deprecated=true.
+func (h *CompanyTypeHandler) HandleListCompanyTypes(c *gin.Context) {
+	includeDeprecated := false
+	if value := c.Query("include_deprecated"); value != "" {
+		var err error
+		includeDeprecated, err = strconv.ParseBool(value)
+		if err != nil {
+			problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, fmt.Errorf("invalid include_deprecated: %w", err))
+			return
+		}
+	}
+
+	companyTypes, err := h.typeService.ListTypes(c, includeDeprecated)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	items := make([]companyTypeResponse, 0, len(companyTypes))
+	for _, companyType := range companyTypes {
+		items = append(items, newCompanyTypeResponse(companyType))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// HandleGetCompanyType handles getting a single company type.
+func (h *CompanyTypeHandler) HandleGetCompanyType(c *gin.Context) {
+	companyType, err := h.typeService.GetType(c, common.Type(c.Param("name")))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCompanyTypeResponse(companyType))
+}
+
+// HandleCreateCompanyType handles adding a company type to the catalogue.
+func (h *CompanyTypeHandler) HandleCreateCompanyType(c *gin.Context) {
+	var reqBody companyTypeRequestPayload
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	companyType, err := h.typeService.CreateType(c, reqBody.Name, services.CompanyTypePayload{
+		Description: reqBody.Description,
+		Deprecated:  reqBody.Deprecated,
+	})
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCompanyTypeResponse(companyType))
+}
+
+// HandleUpdateCompanyType handles replacing the description and deprecation flag of a
+// company type, the name of a type cannot change.
+func (h *CompanyTypeHandler) HandleUpdateCompanyType(c *gin.Context) {
+	name := common.Type(c.Param("name"))
+
+	var reqBody companyTypeRequestPayload
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+	if reqBody.Name != "" && reqBody.Name != name {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, errors.New("the name of a company type cannot change"))
+		return
+	}
+
+	companyType, err := h.typeService.UpdateType(c, name, services.CompanyTypePayload{
+		Description: reqBody.Description,
+		Deprecated:  reqBody.Deprecated,
+	})
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCompanyTypeResponse(companyType))
+}
+
+// HandleDeleteCompanyType handles removing a company type no company uses.
+func (h *CompanyTypeHandler) HandleDeleteCompanyType(c *gin.Context) {
+	if err := h.typeService.DeleteType(c, common.Type(c.Param("name"))); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
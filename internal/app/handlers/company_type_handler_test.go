@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCompanyTypeHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewCompanyTypeHandler(nil)
+	assert.EqualError(t, err, "company type service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewCompanyTypeHandler(&mockCompanyTypeService{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleListCompanyTypes(t *testing.T) {
+	t.Parallel()
+	createdAt := time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		typeService          *mockCompanyTypeService
+		query                string
+		responseStatus       int
+		responseBody         string
+		expIncludeDeprecated bool
+	}{
+		"invalid include_deprecated": {
+			typeService:    &mockCompanyTypeService{},
+			query:          "include_deprecated=maybe",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid include_deprecated: strconv.ParseBool: parsing \\\"maybe\\\": invalid syntax\",\"code\":\"malformed_request\"}",
+		},
+		"internal service error": {
+			typeService:    &mockCompanyTypeService{err: errors.New("internal error")},
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+		},
+		"success": {
+			typeService: &mockCompanyTypeService{types: []models.CompanyType{
+				{Name: "Partnership", Description: "two or more owners", Deprecated: true, CreatedAt: createdAt, UpdatedAt: createdAt},
+			}},
+			query:                "include_deprecated=true",
+			responseStatus:       http.StatusOK,
+			responseBody:         "{\"items\":[{\"name\":\"Partnership\",\"description\":\"two or more owners\",\"deprecated\":true,\"created_at\":\"2023-09-01T10:00:00Z\",\"updated_at\":\"2023-09-01T10:00:00Z\"}]}",
+			expIncludeDeprecated: true,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			req, _ := http.NewRequest("GET", "/v1/company-types?"+tt.query, nil)
+			c.Request = req
+
+			handler, _ := NewCompanyTypeHandler(tt.typeService)
+			handler.HandleListCompanyTypes(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expIncludeDeprecated, tt.typeService.includeDeprecated)
+		})
+	}
+}
+
+func TestHandleCreateCompanyType(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		typeService    *mockCompanyTypeService
+		requestBody    string
+		responseStatus int
+		responseBody   string
+	}{
+		"invalid request": {
+			typeService:    &mockCompanyTypeService{},
+			requestBody:    "{",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unexpected EOF\",\"code\":\"malformed_request\"}",
+		},
+		"already exists": {
+			typeService:    &mockCompanyTypeService{err: fmt.Errorf("%w: \"Corporations\"", services.ErrCompanyTypeExists)},
+			requestBody:    `{"name":"Corporations"}`,
+			responseStatus: http.StatusConflict,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"company type already exists: \\\"Corporations\\\"\",\"code\":\"company_type_exists\"}",
+		},
+		"success": {
+			typeService:    &mockCompanyTypeService{},
+			requestBody:    `{"name":"Partnership","description":"two or more owners"}`,
+			responseStatus: http.StatusCreated,
+			responseBody:   "{\"name\":\"Partnership\",\"description\":\"two or more owners\",\"deprecated\":false,\"created_at\":\"0001-01-01T00:00:00Z\",\"updated_at\":\"0001-01-01T00:00:00Z\"}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			req, _ := http.NewRequest("POST", "/v1/company-types", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			handler, _ := NewCompanyTypeHandler(tt.typeService)
+			handler.HandleCreateCompanyType(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleUpdateCompanyType(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		typeService    *mockCompanyTypeService
+		requestBody    string
+		responseStatus int
+		responseBody   string
+	}{
+		"renaming": {
+			typeService:    &mockCompanyTypeService{},
+			requestBody:    `{"name":"Partnerships"}`,
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"the name of a company type cannot change\",\"code\":\"malformed_request\"}",
+		},
+		"not found": {
+			typeService:    &mockCompanyTypeService{err: fmt.Errorf("%w: \"Partnership\"", services.ErrCompanyTypeNotFound)},
+			requestBody:    `{"deprecated":true}`,
+			responseStatus: http.StatusNotFound,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"company type not found: \\\"Partnership\\\"\",\"code\":\"company_type_not_found\"}",
+		},
+		"success": {
+			typeService:    &mockCompanyTypeService{},
+			requestBody:    `{"name":"Partnership","deprecated":true}`,
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"name\":\"Partnership\",\"description\":\"\",\"deprecated\":true,\"created_at\":\"0001-01-01T00:00:00Z\",\"updated_at\":\"0001-01-01T00:00:00Z\"}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "name", Value: "Partnership"}}
+			req, _ := http.NewRequest("PUT", "/v1/company-types/Partnership", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			handler, _ := NewCompanyTypeHandler(tt.typeService)
+			handler.HandleUpdateCompanyType(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleDeleteCompanyType(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		typeService    *mockCompanyTypeService
+		responseStatus int
+		responseBody   string
+	}{
+		"in use": {
+			typeService:    &mockCompanyTypeService{err: fmt.Errorf("%w: deprecate \"Corporations\" instead", services.ErrCompanyTypeInUse)},
+			responseStatus: http.StatusConflict,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"company type is in use: deprecate \\\"Corporations\\\" instead\",\"code\":\"company_type_in_use\"}",
+		},
+		"success": {
+			typeService:    &mockCompanyTypeService{},
+			responseStatus: http.StatusNoContent,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "name", Value: "Corporations"}}
+			c.Request, _ = http.NewRequest("DELETE", "/v1/company-types/Corporations", nil)
+
+			handler, _ := NewCompanyTypeHandler(tt.typeService)
+			handler.HandleDeleteCompanyType(c)
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.responseStatus, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+// mockCompanyTypeService for testing
+type mockCompanyTypeService struct {
+	types             []models.CompanyType
+	includeDeprecated bool
+	err               error
+}
+
+func (m *mockCompanyTypeService) ListTypes(_ context.Context, includeDeprecated bool) ([]models.CompanyType, error) {
+	m.includeDeprecated = includeDeprecated
+	return m.types, m.err
+}
+
+func (m *mockCompanyTypeService) GetType(_ context.Context, name common.Type) (models.CompanyType, error) {
+	return models.CompanyType{Name: name}, m.err
+}
+
+func (m *mockCompanyTypeService) CreateType(_ context.Context, name common.Type, payload services.CompanyTypePayload) (models.CompanyType, error) {
+	if m.err != nil {
+		return models.CompanyType{}, m.err
+	}
+	return models.CompanyType{Name: name, Description: payload.Description, Deprecated: payload.Deprecated}, nil
+}
+
+func (m *mockCompanyTypeService) UpdateType(_ context.Context, name common.Type, payload services.CompanyTypePayload) (models.CompanyType, error) {
+	if m.err != nil {
+		return models.CompanyType{}, m.err
+	}
+	return models.CompanyType{Name: name, Description: payload.Description, Deprecated: payload.Deprecated}, nil
+}
+
+func (m *mockCompanyTypeService) DeleteType(_ context.Context, _ common.Type) error {
+	return m.err
+}
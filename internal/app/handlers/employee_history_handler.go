@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// EmployeeHistoryHandler is responsible for handling the routes of the headcount history of companies.
+type EmployeeHistoryHandler struct {
+	historyService services.EmployeeHistory
+}
+
+// NewEmployeeHistoryHandler creates a new employee history handler.
+func NewEmployeeHistoryHandler(historyService services.EmployeeHistory) (*EmployeeHistoryHandler, error) {
+	if historyService == nil {
+		return nil, errors.New("employee history service is nil")
+	}
+
+	return &EmployeeHistoryHandler{historyService: historyService}, nil
+}
+
+// employeeHistoryResponse represents the headcount of a company over a range of days, dates are
+// formatted as YYYY-MM-DD and growth rates are fractions, 0.25 being a growth of 25%.
+type employeeHistoryResponse struct {
+	From     string                   `json:"from"`
+	To       string                   `json:"to"`
+	Interval services.HistoryInterval `json:"interval,omitempty"`
+	Items    []employeeHistoryItem    `json:"items"`
+	Summary  employeeHistorySummary   `json:"summary"`
+}
+
+type employeeHistoryItem struct {
+	Date       string   `json:"date"`
+	Employees  int      `json:"employees"`
+	GrowthRate *float64 `json:"growth_rate"`
+}
+
+type employeeHistorySummary struct {
+	Start            int      `json:"start"`
+	End              int      `json:"end"`
+	GrowthRate       *float64 `json:"growth_rate"`
+	AnnualGrowthRate *float64 `json:"annual_growth_rate"`
+}
+
+func newEmployeeHistoryResponse(report services.EmployeeHistoryReport) employeeHistoryResponse {
+	items := make([]employeeHistoryItem, 0, len(report.Points))
+	for _, point := range report.Points {
+		items = append(items, employeeHistoryItem{
+			Date:       point.Date.Format(time.DateOnly),
+			Employees:  point.Employees,
+			GrowthRate: point.GrowthRate,
+		})
+	}
+
+	return employeeHistoryResponse{
+		From:     report.From.Format(time.DateOnly),
+		To:       report.To.Format(time.DateOnly),
+		Interval: report.Interval,
+		Items:    items,
+		Summary: employeeHistorySummary{
+			Start:            report.Summary.Start,
+			End:              report.Summary.End,
+			GrowthRate:       report.Summary.GrowthRate,
+			AnnualGrowthRate: report.Summary.AnnualGrowthRate,
+		},
+	}
+}
+
+// HandleGetEmployeeHistory handles returning how the headcount of a company evolved, the range is
+// given by the from and to query parameters and the interval one downsamples it monthly or quarterly.
+func (h *EmployeeHistoryHandler) HandleGetEmployeeHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	query := services.EmployeeHistoryQuery{Interval: services.HistoryInterval(c.Query("interval"))}
+	if query.From, err = parseQueryDay(c, "from"); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+	if query.To, err = parseQueryDay(c, "to"); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	report, err := h.historyService.GetEmployeeHistory(c, userID, id, query)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newEmployeeHistoryResponse(report))
+}
+
+// parseQueryDay parses an optional YYYY-MM-DD query parameter, nil when it is absent.
+func parseQueryDay(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return &day, nil
+}
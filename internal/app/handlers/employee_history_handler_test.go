@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEmployeeHistoryHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewEmployeeHistoryHandler(nil)
+	assert.EqualError(t, err, "employee history service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewEmployeeHistoryHandler(&mockEmployeeHistoryService{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleGetEmployeeHistory(t *testing.T) {
+	t.Parallel()
+	rate := func(r float64) *float64 { return &r }
+	quarterly := services.EmployeeHistoryReport{
+		From:     time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC),
+		Interval: services.IntervalQuarterly,
+		Points: []services.EmployeeHistoryPoint{
+			{Date: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Employees: 14},
+			{Date: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), Employees: 20, GrowthRate: rate(0.4286)},
+		},
+		Summary: services.EmployeeGrowth{Start: 14, End: 20, GrowthRate: rate(0.4286), AnnualGrowthRate: rate(1.1422)},
+	}
+	cases := map[string]struct {
+		historyService *mockEmployeeHistoryService
+		query          string
+		responseStatus int
+		responseBody   string
+		expQuery       services.EmployeeHistoryQuery
+	}{
+		"malformed from": {
+			historyService: &mockEmployeeHistoryService{},
+			query:          "from=01/01/2023",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid from: parsing time \\\"01/01/2023\\\" as \\\"2006-01-02\\\": cannot parse \\\"01/01/2023\\\" as \\\"2006\\\"\",\"code\":\"malformed_request\"}",
+		},
+		"unknown interval": {
+			historyService: &mockEmployeeHistoryService{err: fmt.Errorf("%w: unknown interval \"weekly\"", services.ErrInvalidHistoryQuery)},
+			query:          "interval=weekly",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid history query: unknown interval \\\"weekly\\\"\",\"code\":\"invalid_history_query\"}",
+			expQuery:       services.EmployeeHistoryQuery{Interval: "weekly"},
+		},
+		"company not found": {
+			historyService: &mockEmployeeHistoryService{err: fmt.Errorf("%w: ca8fc620-509a-40ac-8cc0-525c37c9c4b9", services.ErrCompanyNotFound)},
+			responseStatus: http.StatusNotFound,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9\",\"code\":\"company_not_found\"}",
+		},
+		"quarterly": {
+			historyService: &mockEmployeeHistoryService{report: quarterly},
+			query:          "to=2023-06-30&interval=quarterly",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"from\":\"2023-01-10\",\"to\":\"2023-06-30\",\"interval\":\"quarterly\",\"items\":[{\"date\":\"2023-01-01\",\"employees\":14,\"growth_rate\":null},{\"date\":\"2023-04-01\",\"employees\":20,\"growth_rate\":0.4286}],\"summary\":{\"start\":14,\"end\":20,\"growth_rate\":0.4286,\"annual_growth_rate\":1.1422}}",
+			expQuery:       services.EmployeeHistoryQuery{To: &quarterly.To, Interval: services.IntervalQuarterly},
+		},
+		"no counts yet": {
+			historyService: &mockEmployeeHistoryService{report: services.EmployeeHistoryReport{From: quarterly.From, To: quarterly.From}},
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"from\":\"2023-01-10\",\"to\":\"2023-01-10\",\"items\":[],\"summary\":{\"start\":0,\"end\":0,\"growth_rate\":null,\"annual_growth_rate\":null}}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Request, _ = http.NewRequest("GET", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/employees/history?"+tt.query, nil)
+
+			handler, _ := NewEmployeeHistoryHandler(tt.historyService)
+			handler.HandleGetEmployeeHistory(c)
+			assert.Equal(t, tt.responseStatus, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expQuery, tt.historyService.query)
+		})
+	}
+}
+
+// mockEmployeeHistoryService for testing, it records the query it was called with.
+type mockEmployeeHistoryService struct {
+	report services.EmployeeHistoryReport
+	query  services.EmployeeHistoryQuery
+	err    error
+}
+
+func (m *mockEmployeeHistoryService) GetEmployeeHistory(_ context.Context, _, _ uuid.UUID, query services.EmployeeHistoryQuery) (services.EmployeeHistoryReport, error) {
+	m.query = query
+	return m.report, m.err
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// ExportHandler is responsible for handling the company export route.
+type ExportHandler struct {
+	exportService services.CompanyExporter
+}
+
+// NewExportHandler creates a new export handler.
+func NewExportHandler(exportService services.CompanyExporter) (*ExportHandler, error) {
+	if exportService == nil {
+		return nil, errors.New("export service is nil")
+	}
+
+	return &ExportHandler{exportService: exportService}, nil
+}
+
+// HandleExportCompanies handles streaming every company of the user matching the listing filters
+// as a csv, ndjson or json file, json being the default. Errors after the first company was
+// written cannot be reported anymore and cut the file short.
+func (h *ExportHandler) HandleExportCompanies(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	format := companyio.FormatJSON
+	if value := c.Query("format"); value != "" {
+		format, err = companyio.ParseFormat(value)
+		if err != nil {
+			problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+			return
+		}
+	}
+
+	filter, err := parseCompanyFilter(c)
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="companies.`+string(format)+`"`)
+	err = h.exportService.Export(c, userID, filter, c.Writer, format)
+	if err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			problem.Write(c, err)
+			return
+		}
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExportHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewExportHandler(nil)
+	assert.EqualError(t, err, "export service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewExportHandler(&mockExportService{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleExportCompanies(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		exportService  *mockExportService
+		query          string
+		responseStatus int
+		responseBody   string
+		contentType    string
+		expFormat      companyio.Format
+		expFilter      services.CompanyFilter
+	}{
+		"unknown format": {
+			exportService:  &mockExportService{},
+			query:          "format=xml",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unknown format \\\"xml\\\"\",\"code\":\"malformed_request\"}",
+			contentType:    "application/problem+json",
+		},
+		"invalid filter": {
+			exportService:  &mockExportService{},
+			query:          "format=csv&registered=maybe",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid registered: strconv.ParseBool: parsing \\\"maybe\\\": invalid syntax\",\"code\":\"malformed_request\"}",
+			contentType:    "application/problem+json",
+		},
+		"error before streaming": {
+			exportService:  &mockExportService{err: errors.New("internal error")},
+			query:          "format=csv",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+			contentType:    "application/problem+json",
+			expFormat:      companyio.FormatCSV,
+		},
+		"error while streaming": {
+			exportService:  &mockExportService{output: "[{\"Name\":\"acme\"}", err: errors.New("internal error")},
+			responseStatus: http.StatusOK,
+			responseBody:   "[{\"Name\":\"acme\"}",
+			contentType:    "application/json",
+			expFormat:      companyio.FormatJSON,
+		},
+		"success": {
+			exportService:  &mockExportService{output: "id,name\n"},
+			query:          "format=csv&type=Corporations",
+			responseStatus: http.StatusOK,
+			responseBody:   "id,name\n",
+			contentType:    "text/csv",
+			expFormat:      companyio.FormatCSV,
+			expFilter:      services.CompanyFilter{Types: []common.Type{common.Corporations}},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			req, _ := http.NewRequest("GET", "/v1/companies/export?"+tt.query, nil)
+			c.Request = req
+
+			handler, _ := NewExportHandler(tt.exportService)
+			handler.HandleExportCompanies(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expFormat, tt.exportService.format)
+			assert.Equal(t, tt.expFilter, tt.exportService.filter)
+		})
+	}
+}
+
+type mockExportService struct {
+	output string
+	filter services.CompanyFilter
+	format companyio.Format
+	err    error
+}
+
+func (m *mockExportService) Export(_ context.Context, _ uuid.UUID, filter services.CompanyFilter, w io.Writer, format companyio.Format) error {
+	m.filter, m.format = filter, format
+	if m.output != "" {
+		_, _ = fmt.Fprint(w, m.output)
+	}
+	return m.err
+}
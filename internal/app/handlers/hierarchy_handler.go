@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// HierarchyHandler is responsible for handling the routes of corporate groups.
+type HierarchyHandler struct {
+	hierarchyService services.CompanyHierarchy
+}
+
+// NewHierarchyHandler creates a new hierarchy handler.
+func NewHierarchyHandler(hierarchyService services.CompanyHierarchy) (*HierarchyHandler, error) {
+	if hierarchyService == nil {
+		return nil, errors.New("hierarchy service is nil")
+	}
+
+	return &HierarchyHandler{hierarchyService: hierarchyService}, nil
+}
+
+// companyListResponse represents companies that are not paginated.
+type companyListResponse struct {
+	Items []models.Company `json:"items"`
+}
+
+func newCompanyListResponse(companies []models.Company) companyListResponse {
+	if companies == nil {
+		companies = []models.Company{}
+	}
+
+	return companyListResponse{Items: companies}
+}
+
+// companyNodeResponse represents a company of a subtree.
+type companyNodeResponse struct {
+	Depth   int            `json:"depth"`
+	Company models.Company `json:"company"`
+}
+
+// subtreeResponse represents a company followed by its subsidiaries.
+type subtreeResponse struct {
+	EmployeesTotal int                   `json:"employees_total"`
+	Items          []companyNodeResponse `json:"items"`
+}
+
+// HandleListAncestors handles listing the parents of a company, the direct parent first.
+func (h *HierarchyHandler) HandleListAncestors(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	ancestors, err := h.hierarchyService.Ancestors(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCompanyListResponse(ancestors))
+}
+
+// HandleListChildren handles listing the direct subsidiaries of a company.
+func (h *HierarchyHandler) HandleListChildren(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	children, err := h.hierarchyService.Children(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCompanyListResponse(children))
+}
+
+// HandleGetSubtree handles getting a company and its subsidiaries, the depth query parameter
+// limits how many levels below the company are returned.
+func (h *HierarchyHandler) HandleGetSubtree(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	var depth int
+	if value := c.Query("depth"); value != "" {
+		depth, err = strconv.Atoi(value)
+		if err != nil {
+			problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, fmt.Errorf("invalid depth: %w", err))
+			return
+		}
+	}
+
+	subtree, err := h.hierarchyService.Subtree(c, userID, id, depth)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := subtreeResponse{
+		EmployeesTotal: subtree.EmployeesTotal,
+		Items:          make([]companyNodeResponse, 0, len(subtree.Nodes)),
+	}
+	for _, node := range subtree.Nodes {
+		resp.Items = append(resp.Items, companyNodeResponse{Depth: node.Depth, Company: node.Company})
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCompanyBody = "{\"ID\":\"ca8fc620-509a-40ac-8cc0-525c37c9c4b9\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":1,\"Name\":\"Acme\",\"Slug\":\"acme\",\"Description\":\"\",\"EmployeesAmount\":12,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}"
+
+var testCompany = models.Company{ID: uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), Version: 1, Name: "Acme", Slug: "acme", EmployeesAmount: 12}
+
+func TestNewHierarchyHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewHierarchyHandler(nil)
+	assert.EqualError(t, err, "hierarchy service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewHierarchyHandler(&mockHierarchyService{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleListAncestors(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		hierarchyService *mockHierarchyService
+		responseStatus   int
+		responseBody     string
+	}{
+		"company not found": {
+			hierarchyService: &mockHierarchyService{err: fmt.Errorf("%w: 5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11", services.ErrCompanyNotFound)},
+			responseStatus:   http.StatusNotFound,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"company not found: 5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11\",\"code\":\"company_not_found\"}",
+		},
+		"top of the group": {
+			hierarchyService: &mockHierarchyService{},
+			responseStatus:   http.StatusOK,
+			responseBody:     "{\"items\":[]}",
+		},
+		"success": {
+			hierarchyService: &mockHierarchyService{companies: []models.Company{testCompany}},
+			responseStatus:   http.StatusOK,
+			responseBody:     "{\"items\":[" + testCompanyBody + "]}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Params = gin.Params{{Key: "companyID", Value: "5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11"}}
+			c.Request, _ = http.NewRequest("GET", "/v1/companies/5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11/ancestors", nil)
+
+			handler, _ := NewHierarchyHandler(tt.hierarchyService)
+			handler.HandleListAncestors(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleGetSubtree(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		hierarchyService *mockHierarchyService
+		query            string
+		responseStatus   int
+		responseBody     string
+		expDepth         int
+	}{
+		"invalid depth": {
+			hierarchyService: &mockHierarchyService{},
+			query:            "?depth=all",
+			responseStatus:   http.StatusBadRequest,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid depth: strconv.Atoi: parsing \\\"all\\\": invalid syntax\",\"code\":\"malformed_request\"}",
+		},
+		"negative depth": {
+			hierarchyService: &mockHierarchyService{err: fmt.Errorf("%w: negative depth", services.ErrInvalidHierarchyQuery)},
+			query:            "?depth=-1",
+			responseStatus:   http.StatusBadRequest,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid hierarchy query: negative depth\",\"code\":\"invalid_hierarchy_query\"}",
+			expDepth:         -1,
+		},
+		"success": {
+			hierarchyService: &mockHierarchyService{subtree: services.CompanySubtree{
+				Nodes:          []services.CompanyNode{{Company: testCompany}},
+				EmployeesTotal: 12,
+			}},
+			query:          "?depth=2",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"employees_total\":12,\"items\":[{\"depth\":0,\"company\":" + testCompanyBody + "}]}",
+			expDepth:       2,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Request, _ = http.NewRequest("GET", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/subtree"+tt.query, nil)
+
+			handler, _ := NewHierarchyHandler(tt.hierarchyService)
+			handler.HandleGetSubtree(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expDepth, tt.hierarchyService.depth)
+		})
+	}
+}
+
+// mockHierarchyService for testing.
+type mockHierarchyService struct {
+	companies []models.Company
+	subtree   services.CompanySubtree
+	depth     int
+	err       error
+}
+
+func (m *mockHierarchyService) Ancestors(_ context.Context, _, _ uuid.UUID) ([]models.Company, error) {
+	return m.companies, m.err
+}
+
+func (m *mockHierarchyService) Children(_ context.Context, _, _ uuid.UUID) ([]models.Company, error) {
+	return m.companies, m.err
+}
+
+func (m *mockHierarchyService) Subtree(_ context.Context, _, _ uuid.UUID, depth int) (services.CompanySubtree, error) {
+	m.depth = depth
+	return m.subtree, m.err
+}
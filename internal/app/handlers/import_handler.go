@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// maxImportSize is the largest file accepted by the import endpoint.
+const maxImportSize = 32 << 20
+
+// importFormats are the formats accepted by the import endpoint by content type.
+var importFormats = map[string]companyio.Format{
+	"text/csv":             companyio.FormatCSV,
+	"application/x-ndjson": companyio.FormatNDJSON,
+	"application/ndjson":   companyio.FormatNDJSON,
+}
+
+// ImportHandler is responsible for handling the company import route.
+type ImportHandler struct {
+	importService services.CompanyImporter
+	eventProducer eventProducer
+}
+
+// NewImportHandler creates a new import handler.
+func NewImportHandler(importService services.CompanyImporter, eventProducer eventProducer) (*ImportHandler, error) {
+	if importService == nil {
+		return nil, errors.New("import service is nil")
+	}
+	if eventProducer == nil {
+		return nil, errors.New("eventProducer is nil")
+	}
+
+	return &ImportHandler{importService: importService, eventProducer: eventProducer}, nil
+}
+
+// rejectedRowResponse represents a row of an import that did not become a company.
+type rejectedRowResponse struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// importResponse represents the outcome of an import.
+type importResponse struct {
+	Total    int                   `json:"total"`
+	Imported int                   `json:"imported"`
+	Rejected []rejectedRowResponse `json:"rejected"`
+}
+
+func newImportResponse(report services.ImportReport) importResponse {
+	resp := importResponse{
+		Total:    report.Total,
+		Imported: report.Imported,
+		Rejected: make([]rejectedRowResponse, 0, len(report.Rejected)),
+	}
+	for _, row := range report.Rejected {
+		resp.Rejected = append(resp.Rejected, rejectedRowResponse{Line: row.Line, Error: row.Error})
+	}
+
+	return resp
+}
+
+// HandleImportCompanies handles importing the companies of a CSV or NDJSON body, the format
+// is picked by the content type. Columns whose name is not a company field can be mapped
+// with map=column:field query parameters, attr.<name> columns hold custom attribute values.
+// Invalid rows are reported, they do not fail the import.
+// Every imported company is published like a created one as soon as its chunk is saved.
+func (h *ImportHandler) HandleImportCompanies(c *gin.Context) {
+	format, ok := importFormats[c.ContentType()]
+	if !ok {
+		problem.WriteStatus(c, http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType, fmt.Errorf("unsupported content type %s", c.ContentType()))
+		return
+	}
+
+	mapping, err := companyio.ParseMapping(strings.Join(c.QueryArray("map"), ","))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	report, err := h.importService.Import(c, userID, body, format, mapping, h.publish)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		problem.WriteStatus(c, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge, err)
+		return
+	case err != nil:
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newImportResponse(report))
+}
+
+// publish sends a company.created event for every company of a saved chunk of an import.
+func (h *ImportHandler) publish(companies []models.Company) {
+	for _, comp := range companies {
+		data, err := json.Marshal(companyEvent{Type: "company.created", Company: comp})
+		if err == nil {
+			h.eventProducer.SendMessage(topic, data)
+		}
+	}
+}
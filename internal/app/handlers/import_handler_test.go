@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewImportHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewImportHandler(nil, &producerStub{})
+	assert.EqualError(t, err, "import service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewImportHandler(&mockImportService{}, nil)
+	assert.EqualError(t, err, "eventProducer is nil")
+	assert.Nil(t, h)
+
+	h, err = NewImportHandler(&mockImportService{}, &producerStub{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleImportCompanies(t *testing.T) {
+	t.Parallel()
+	firstImported := uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+	secondImported := uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d02")
+	cases := map[string]struct {
+		importService  *mockImportService
+		contentType    string
+		query          string
+		responseStatus int
+		responseBody   string
+		expFormat      companyio.Format
+		expMapping     map[string]string
+		expEvents      []uuid.UUID
+	}{
+		"unsupported content type": {
+			importService:  &mockImportService{},
+			contentType:    "application/json",
+			responseStatus: http.StatusUnsupportedMediaType,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Unsupported Media Type\",\"status\":415,\"detail\":\"unsupported content type application/json\",\"code\":\"unsupported_media_type\"}",
+		},
+		"invalid mapping": {
+			importService:  &mockImportService{},
+			contentType:    "text/csv",
+			query:          "map=Staff:headcount",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"column \\\"Staff\\\" is mapped to unknown field \\\"headcount\\\"\",\"code\":\"malformed_request\"}",
+		},
+		"invalid import": {
+			importService:  &mockImportService{err: fmt.Errorf("%w: csv header has no name column", services.ErrInvalidImport)},
+			contentType:    "text/csv",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid import: csv header has no name column\",\"code\":\"invalid_import\"}",
+			expFormat:      companyio.FormatCSV,
+			expMapping:     map[string]string{},
+		},
+		"internal service error": {
+			importService: &mockImportService{
+				saved: [][]models.Company{{{ID: firstImported}}},
+				err:   errors.New("internal error"),
+			},
+			contentType:    "application/x-ndjson",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+			expFormat:      companyio.FormatNDJSON,
+			expMapping:     map[string]string{},
+			expEvents:      []uuid.UUID{firstImported},
+		},
+		"success": {
+			importService: &mockImportService{
+				report: services.ImportReport{
+					Total:    3,
+					Imported: 2,
+					Rejected: []services.RejectedRow{{Line: 3, Error: "company type is empty"}},
+				},
+				saved: [][]models.Company{{{ID: firstImported}}, {{ID: secondImported}}},
+			},
+			contentType:    "text/csv",
+			query:          "map=Company%20Name:name&map=Staff:employees_amount",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"total\":3,\"imported\":2,\"rejected\":[{\"line\":3,\"error\":\"company type is empty\"}]}",
+			expFormat:      companyio.FormatCSV,
+			expMapping:     map[string]string{"Company Name": "name", "Staff": "employees_amount"},
+			expEvents:      []uuid.UUID{firstImported, secondImported},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			req, _ := http.NewRequest("POST", "/v1/companies/import?"+tt.query, strings.NewReader("name\nacme\n"))
+			req.Header.Set("Content-Type", tt.contentType)
+			c.Request = req
+
+			producer := &producerStub{}
+			handler, _ := NewImportHandler(tt.importService, producer)
+			handler.HandleImportCompanies(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expFormat, tt.importService.format)
+			assert.Equal(t, tt.expMapping, tt.importService.mapping)
+			if assert.Len(t, producer.sent, len(tt.expEvents)) {
+				for i, id := range tt.expEvents {
+					assert.True(t, strings.HasPrefix(producer.sent[i], "{\"type\":\"company.created\",\"company\":{\"ID\":\""+id.String()+"\""), producer.sent[i])
+				}
+			}
+		})
+	}
+}
+
+type mockImportService struct {
+	report  services.ImportReport
+	saved   [][]models.Company // Chunks handed over as if they were saved.
+	format  companyio.Format
+	mapping map[string]string
+	err     error
+}
+
+func (m *mockImportService) Import(_ context.Context, _ uuid.UUID, r io.Reader, format companyio.Format, mapping map[string]string, saved func([]models.Company)) (services.ImportReport, error) {
+	m.format, m.mapping = format, mapping
+	_, _ = io.ReadAll(r)
+	for _, chunk := range m.saved {
+		saved(chunk)
+	}
+	return m.report, m.err
+}
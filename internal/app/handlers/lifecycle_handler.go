@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// LifecycleHandler is responsible for handling the routes of company status transitions.
+type LifecycleHandler struct {
+	lifecycleService services.CompanyLifecycle
+	eventProducer    eventProducer
+}
+
+// NewLifecycleHandler creates a new lifecycle handler.
+func NewLifecycleHandler(lifecycleService services.CompanyLifecycle, eventProducer eventProducer) (*LifecycleHandler, error) {
+	if lifecycleService == nil {
+		return nil, errors.New("lifecycle service is nil")
+	}
+
+	if eventProducer == nil {
+		return nil, errors.New("eventProducer is nil")
+	}
+
+	return &LifecycleHandler{lifecycleService: lifecycleService, eventProducer: eventProducer}, nil
+}
+
+type transitionRequestPayload struct {
+	To     models.CompanyStatus `json:"to"`
+	Reason string               `json:"reason"`
+}
+
+// transitionResponse represents a change of the status of a company, Version is the
+// version of the company the transition brought it to.
+type transitionResponse struct {
+	ID        uuid.UUID             `json:"id"`
+	Kind      models.TransitionKind `json:"kind"`
+	From      models.CompanyStatus  `json:"from"`
+	To        models.CompanyStatus  `json:"to"`
+	Reason    string                `json:"reason"`
+	ActorID   uuid.UUID             `json:"actor_id"`
+	Version   int                   `json:"version"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+func newTransitionResponse(transition models.CompanyTransition) transitionResponse {
+	return transitionResponse{
+		ID:        transition.ID,
+		Kind:      transition.Kind,
+		From:      transition.From,
+		To:        transition.To,
+		Reason:    transition.Reason,
+		ActorID:   transition.ActorID,
+		Version:   transition.Version,
+		CreatedAt: transition.CreatedAt,
+	}
+}
+
+// transitionListResponse represents the status history of a company.
+type transitionListResponse struct {
+	Items []transitionResponse `json:"items"`
+}
+
+// transitionEvent is published for every transition, Type tells which one it is,
+// e.g. company.suspended.
+type transitionEvent struct {
+	Type       string             `json:"type"`
+	Transition transitionResponse `json:"transition"`
+	Company    models.Company     `json:"company"`
+}
+
+// HandleListTransitions handles listing the status changes of a company, oldest first.
+func (h *LifecycleHandler) HandleListTransitions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	transitions, err := h.lifecycleService.ListTransitions(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := transitionListResponse{Items: make([]transitionResponse, 0, len(transitions))}
+	for _, transition := range transitions {
+		resp.Items = append(resp.Items, newTransitionResponse(transition))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleTransition handles moving a company to another status, an If-Match header makes sure
+// the company did not change since it was read. The transition is published with its own event type.
+func (h *LifecycleHandler) HandleTransition(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	version, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusPreconditionFailed, problem.CodePreconditionFailed, err)
+		return
+	}
+
+	var reqBody transitionRequestPayload
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	transition, comp, err := h.lifecycleService.Transition(c, userID, id, version, services.TransitionPayload(reqBody))
+	if errors.Is(err, services.ErrVersionMismatch) {
+		writeVersionMismatch(c, err)
+		return
+	}
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := newTransitionResponse(transition)
+	data, err := json.Marshal(transitionEvent{Type: "company." + string(transition.Kind), Transition: resp, Company: comp})
+	if err == nil {
+		h.eventProducer.SendMessage(topic, data)
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLifecycleHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewLifecycleHandler(nil, &producerStub{})
+	assert.EqualError(t, err, "lifecycle service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewLifecycleHandler(&mockLifecycleService{}, nil)
+	assert.EqualError(t, err, "eventProducer is nil")
+	assert.Nil(t, h)
+
+	h, err = NewLifecycleHandler(&mockLifecycleService{}, &producerStub{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleTransition(t *testing.T) {
+	t.Parallel()
+	suspension := models.CompanyTransition{
+		ID:        uuid.MustParse("3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50"),
+		CreatedAt: time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC),
+		CompanyID: uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+		Kind:      models.TransitionSuspended,
+		From:      models.StatusActive,
+		To:        models.StatusSuspended,
+		Reason:    "overdue accounts",
+		ActorID:   uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9"),
+		Version:   4,
+	}
+	cases := map[string]struct {
+		lifecycleService *mockLifecycleService
+		ifMatch          string
+		body             string
+		responseStatus   int
+		responseBody     string
+		expVersion       int
+		expEvent         string
+	}{
+		"malformed body": {
+			lifecycleService: &mockLifecycleService{},
+			body:             "{",
+			responseStatus:   http.StatusBadRequest,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unexpected EOF\",\"code\":\"malformed_request\"}",
+		},
+		"not allowed": {
+			lifecycleService: &mockLifecycleService{err: fmt.Errorf("%w: the company is dissolved, its status cannot change anymore", services.ErrTransitionNotAllowed)},
+			body:             `{"to":"active"}`,
+			responseStatus:   http.StatusConflict,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"transition not allowed: the company is dissolved, its status cannot change anymore\",\"code\":\"transition_not_allowed\"}",
+		},
+		"stale version": {
+			lifecycleService: &mockLifecycleService{err: fmt.Errorf("%w: expected 2, current 3", services.ErrVersionMismatch)},
+			ifMatch:          `"2"`,
+			body:             `{"to":"suspended","reason":"overdue accounts"}`,
+			responseStatus:   http.StatusPreconditionFailed,
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Precondition Failed\",\"status\":412,\"detail\":\"company version mismatch: expected 2, current 3\",\"code\":\"version_mismatch\"}",
+			expVersion:       2,
+		},
+		"success": {
+			lifecycleService: &mockLifecycleService{transition: suspension, company: models.Company{Name: "Acme", Status: models.StatusSuspended, Registered: true}},
+			ifMatch:          `"3"`,
+			body:             `{"to":"suspended","reason":"overdue accounts"}`,
+			responseStatus:   http.StatusCreated,
+			responseBody:     "{\"id\":\"3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50\",\"kind\":\"suspended\",\"from\":\"active\",\"to\":\"suspended\",\"reason\":\"overdue accounts\",\"actor_id\":\"b6000e46-809f-4684-abd9-dc8f445b5ca9\",\"version\":4,\"created_at\":\"2023-10-01T12:00:00Z\"}",
+			expVersion:       3,
+			expEvent:         "{\"type\":\"company.suspended\",\"transition\":{\"id\":\"3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50\",\"kind\":\"suspended\"",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("POST", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/transitions", strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			producer := &producerStub{}
+			handler, _ := NewLifecycleHandler(tt.lifecycleService, producer)
+			handler.HandleTransition(c)
+			assert.Equal(t, tt.responseStatus, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expVersion, tt.lifecycleService.version)
+			if tt.expEvent == "" {
+				assert.Empty(t, producer.sent)
+			} else if assert.Len(t, producer.sent, 1) {
+				assert.True(t, strings.HasPrefix(producer.sent[0], tt.expEvent), producer.sent[0])
+				assert.Contains(t, producer.sent[0], "\"company\":{\"ID\":\"00000000-0000-0000-0000-000000000000\"")
+				assert.Contains(t, producer.sent[0], "\"Registered\":true,\"Status\":\"suspended\"")
+			}
+		})
+	}
+}
+
+func TestHandleListTransitions(t *testing.T) {
+	t.Parallel()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+	c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	c.Request, _ = http.NewRequest("GET", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/transitions", nil)
+
+	submitted := models.CompanyTransition{Kind: models.TransitionSubmitted, From: models.StatusDraft, To: models.StatusPendingRegistration, Version: 2}
+	handler, _ := NewLifecycleHandler(&mockLifecycleService{transitions: []models.CompanyTransition{submitted}}, &producerStub{})
+	handler.HandleListTransitions(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\"items\":[{\"id\":\"00000000-0000-0000-0000-000000000000\",\"kind\":\"registration_submitted\",\"from\":\"draft\",\"to\":\"pending_registration\",\"reason\":\"\",\"actor_id\":\"00000000-0000-0000-0000-000000000000\",\"version\":2,\"created_at\":\"0001-01-01T00:00:00Z\"}]}", w.Body.String())
+}
+
+// mockLifecycleService for testing, it records the version a transition was requested at.
+type mockLifecycleService struct {
+	transition  models.CompanyTransition
+	transitions []models.CompanyTransition
+	company     models.Company
+	version     int
+	err         error
+}
+
+func (m *mockLifecycleService) ListTransitions(_ context.Context, _, _ uuid.UUID) ([]models.CompanyTransition, error) {
+	return m.transitions, m.err
+}
+
+func (m *mockLifecycleService) Transition(_ context.Context, _, _ uuid.UUID, version int, _ services.TransitionPayload) (models.CompanyTransition, models.Company, error) {
+	m.version = version
+	return m.transition, m.company, m.err
+}
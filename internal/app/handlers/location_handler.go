@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// LocationHandler is responsible for handling the routes of company locations.
+type LocationHandler struct {
+	locationService services.CompanyLocations
+	eventProducer   eventProducer
+}
+
+// NewLocationHandler creates a new location handler.
+func NewLocationHandler(locationService services.CompanyLocations, eventProducer eventProducer) (*LocationHandler, error) {
+	if locationService == nil {
+		return nil, errors.New("location service is nil")
+	}
+
+	if eventProducer == nil {
+		return nil, errors.New("eventProducer is nil")
+	}
+
+	return &LocationHandler{locationService: locationService, eventProducer: eventProducer}, nil
+}
+
+type locationRequestPayload struct {
+	Name         string   `json:"name"`
+	Headquarters bool     `json:"headquarters"`
+	Street       string   `json:"street"`
+	PostalCode   string   `json:"postal_code"`
+	City         string   `json:"city"`
+	Region       string   `json:"region"`
+	Country      string   `json:"country"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+}
+
+// locationResponse represents a location of a company.
+type locationResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Headquarters bool      `json:"headquarters"`
+	Street       string    `json:"street"`
+	PostalCode   string    `json:"postal_code"`
+	City         string    `json:"city"`
+	Region       string    `json:"region"`
+	Country      string    `json:"country"`
+	Latitude     *float64  `json:"latitude"`
+	Longitude    *float64  `json:"longitude"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func newLocationResponse(location models.CompanyLocation) locationResponse {
+	return locationResponse{
+		ID:           location.ID,
+		Name:         location.Name,
+		Headquarters: location.Headquarters,
+		Street:       location.Street,
+		PostalCode:   location.PostalCode,
+		City:         location.City,
+		Region:       location.Region,
+		Country:      location.Country,
+		Latitude:     location.Latitude,
+		Longitude:    location.Longitude,
+		CreatedAt:    location.CreatedAt,
+		UpdatedAt:    location.UpdatedAt,
+	}
+}
+
+// locationListResponse represents the locations of a company.
+type locationListResponse struct {
+	Items []locationResponse `json:"items"`
+}
+
+// HandleListLocations handles listing the locations of a company, headquarters first.
+func (h *LocationHandler) HandleListLocations(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	locations, err := h.locationService.ListLocations(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := locationListResponse{Items: make([]locationResponse, 0, len(locations))}
+	for _, location := range locations {
+		resp.Items = append(resp.Items, newLocationResponse(location))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleGetLocation handles getting a single location of a company.
+func (h *LocationHandler) HandleGetLocation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("locationID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	location, err := h.locationService.GetLocation(c, userID, id, locationID)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLocationResponse(location))
+}
+
+// HandleAddLocation handles adding a location to a company, the relocated company is published.
+func (h *LocationHandler) HandleAddLocation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	var reqBody locationRequestPayload
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	location, comp, err := h.locationService.AddLocation(c, userID, id, services.LocationPayload(reqBody))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	h.publish(comp)
+	c.JSON(http.StatusCreated, newLocationResponse(location))
+}
+
+// HandleUpdateLocation handles replacing the address of a location, the relocated company is published.
+func (h *LocationHandler) HandleUpdateLocation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("locationID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	var reqBody locationRequestPayload
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	location, comp, err := h.locationService.UpdateLocation(c, userID, id, locationID, services.LocationPayload(reqBody))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	h.publish(comp)
+	c.JSON(http.StatusOK, newLocationResponse(location))
+}
+
+// HandleRemoveLocation handles removing a location of a company, the relocated company is published.
+func (h *LocationHandler) HandleRemoveLocation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	locationID, err := uuid.Parse(c.Param("locationID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	comp, err := h.locationService.RemoveLocation(c, userID, id, locationID)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	h.publish(comp)
+	c.Status(http.StatusNoContent)
+}
+
+// publish sends a company.relocated event with the company and its locations as they are after a change.
+func (h *LocationHandler) publish(comp models.Company) {
+	data, err := json.Marshal(companyEvent{Type: "company.relocated", Company: comp})
+	if err == nil {
+		h.eventProducer.SendMessage(topic, data)
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLocationHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewLocationHandler(nil, &producerStub{})
+	assert.EqualError(t, err, "location service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewLocationHandler(&mockLocationService{}, nil)
+	assert.EqualError(t, err, "eventProducer is nil")
+	assert.Nil(t, h)
+
+	h, err = NewLocationHandler(&mockLocationService{}, &producerStub{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleAddLocation(t *testing.T) {
+	t.Parallel()
+	createdAt := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	lat, long := 52.52, 13.405
+	berlin := models.CompanyLocation{
+		ID:           uuid.MustParse("3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50"),
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+		CompanyID:    uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+		Name:         "Berlin office",
+		Headquarters: true,
+		City:         "Berlin",
+		Country:      "DE",
+		Latitude:     &lat,
+		Longitude:    &long,
+	}
+	cases := map[string]struct {
+		locationService *mockLocationService
+		body            string
+		responseStatus  int
+		responseBody    string
+		expEvents       int
+	}{
+		"malformed body": {
+			locationService: &mockLocationService{},
+			body:            "{",
+			responseStatus:  http.StatusBadRequest,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unexpected EOF\",\"code\":\"malformed_request\"}",
+		},
+		"unknown country": {
+			locationService: &mockLocationService{err: fmt.Errorf("%w: country is unknown", services.ErrInvalidLocation)},
+			body:            `{"name":"Berlin office","city":"Berlin","country":"XX"}`,
+			responseStatus:  http.StatusUnprocessableEntity,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Unprocessable Entity\",\"status\":422,\"detail\":\"invalid location: country is unknown\",\"code\":\"invalid_location\"}",
+		},
+		"success": {
+			locationService: &mockLocationService{location: berlin},
+			body:            `{"name":"Berlin office","headquarters":true,"city":"Berlin","country":"de","latitude":52.52,"longitude":13.405}`,
+			responseStatus:  http.StatusCreated,
+			responseBody:    "{\"id\":\"3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50\",\"name\":\"Berlin office\",\"headquarters\":true,\"street\":\"\",\"postal_code\":\"\",\"city\":\"Berlin\",\"region\":\"\",\"country\":\"DE\",\"latitude\":52.52,\"longitude\":13.405,\"created_at\":\"2023-10-01T12:00:00Z\",\"updated_at\":\"2023-10-01T12:00:00Z\"}",
+			expEvents:       1,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("POST", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/locations", strings.NewReader(tt.body))
+
+			producer := &producerStub{}
+			handler, _ := NewLocationHandler(tt.locationService, producer)
+			handler.HandleAddLocation(c)
+			assert.Equal(t, tt.responseStatus, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Len(t, producer.sent, tt.expEvents)
+		})
+	}
+}
+
+func TestHandleRemoveLocation_PublishesCompany(t *testing.T) {
+	t.Parallel()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}, {Key: "locationID", Value: "3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50"}}
+	c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	c.Request, _ = http.NewRequest("DELETE", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/locations/3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50", nil)
+
+	paris := models.CompanyLocation{ID: uuid.MustParse("5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11"), Name: "Paris office", City: "Paris", Country: "FR"}
+	producer := &producerStub{}
+	handler, _ := NewLocationHandler(&mockLocationService{company: models.Company{Name: "Acme", Locations: []models.CompanyLocation{paris}}}, producer)
+	handler.HandleRemoveLocation(c)
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+	if assert.Len(t, producer.sent, 1) {
+		assert.True(t, strings.HasPrefix(producer.sent[0], "{\"type\":\"company.relocated\",\"company\":{"), producer.sent[0])
+		assert.Contains(t, producer.sent[0], "\"Locations\":[{\"ID\":\"5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11\"")
+		assert.Contains(t, producer.sent[0], "\"City\":\"Paris\",\"Region\":\"\",\"Country\":\"FR\"")
+	}
+}
+
+func TestHandleGetLocation_InvalidLocationID(t *testing.T) {
+	t.Parallel()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}, {Key: "locationID", Value: "hq"}}
+	c.Request, _ = http.NewRequest("GET", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/locations/hq", nil)
+
+	handler, _ := NewLocationHandler(&mockLocationService{}, &producerStub{})
+	handler.HandleGetLocation(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid UUID length: 2\",\"code\":\"malformed_request\"}", w.Body.String())
+}
+
+// mockLocationService for testing.
+type mockLocationService struct {
+	location models.CompanyLocation
+	company  models.Company
+	err      error
+}
+
+func (m *mockLocationService) ListLocations(_ context.Context, _, _ uuid.UUID) ([]models.CompanyLocation, error) {
+	return []models.CompanyLocation{m.location}, m.err
+}
+
+func (m *mockLocationService) GetLocation(_ context.Context, _, _, _ uuid.UUID) (models.CompanyLocation, error) {
+	return m.location, m.err
+}
+
+func (m *mockLocationService) AddLocation(_ context.Context, _, _ uuid.UUID, _ services.LocationPayload) (models.CompanyLocation, models.Company, error) {
+	return m.location, m.company, m.err
+}
+
+func (m *mockLocationService) UpdateLocation(_ context.Context, _, _, _ uuid.UUID, _ services.LocationPayload) (models.CompanyLocation, models.Company, error) {
+	return m.location, m.company, m.err
+}
+
+func (m *mockLocationService) RemoveLocation(_ context.Context, _, _, _ uuid.UUID) (models.Company, error) {
+	return m.company, m.err
+}
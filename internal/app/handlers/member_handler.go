@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// MemberHandler is responsible for handling the routes of company members.
+type MemberHandler struct {
+	memberService services.CompanyMembers
+}
+
+// NewMemberHandler creates a new member handler.
+func NewMemberHandler(memberService services.CompanyMembers) (*MemberHandler, error) {
+	if memberService == nil {
+		return nil, errors.New("member service is nil")
+	}
+
+	return &MemberHandler{memberService: memberService}, nil
+}
+
+type memberRequestPayload struct {
+	Username string            `json:"username"`
+	Role     models.MemberRole `json:"role"`
+}
+
+// memberResponse represents a member of a company.
+type memberResponse struct {
+	UserID    uuid.UUID         `json:"user_id"`
+	Role      models.MemberRole `json:"role"`
+	GrantedBy uuid.UUID         `json:"granted_by"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func newMemberResponse(member models.CompanyMember) memberResponse {
+	return memberResponse{
+		UserID:    member.UserID,
+		Role:      member.Role,
+		GrantedBy: member.GrantedBy,
+		CreatedAt: member.CreatedAt,
+		UpdatedAt: member.UpdatedAt,
+	}
+}
+
+// memberListResponse represents the members of a company.
+type memberListResponse struct {
+	Items []memberResponse `json:"items"`
+}
+
+// HandleListMembers handles listing the members of a company.
+func (h *MemberHandler) HandleListMembers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	members, err := h.memberService.ListMembers(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := memberListResponse{Items: make([]memberResponse, 0, len(members))}
+	for _, member := range members {
+		resp.Items = append(resp.Items, newMemberResponse(member))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleGrantMember handles granting a user a role on a company, the role of an existing member is replaced.
+func (h *MemberHandler) HandleGrantMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	var payload memberRequestPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	member, err := h.memberService.GrantMember(c, userID, id, payload.Username, payload.Role)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newMemberResponse(member))
+}
+
+// HandleRevokeMember handles revoking the access of a member to a company.
+func (h *MemberHandler) HandleRevokeMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	memberID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	if err := h.memberService.RevokeMember(c, userID, id, memberID); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
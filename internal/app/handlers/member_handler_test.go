@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMember = models.CompanyMember{
+	CompanyID: uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+	UserID:    uuid.MustParse("5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11"),
+	Role:      models.RoleEditor,
+	GrantedBy: uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9"),
+	CreatedAt: time.Date(2023, 10, 17, 10, 0, 0, 0, time.UTC),
+	UpdatedAt: time.Date(2023, 10, 17, 10, 0, 0, 0, time.UTC),
+}
+
+const testMemberBody = "{\"user_id\":\"5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11\",\"role\":\"editor\",\"granted_by\":\"b6000e46-809f-4684-abd9-dc8f445b5ca9\",\"created_at\":\"2023-10-17T10:00:00Z\",\"updated_at\":\"2023-10-17T10:00:00Z\"}"
+
+func TestNewMemberHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewMemberHandler(nil)
+	assert.EqualError(t, err, "member service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewMemberHandler(&mockMemberService{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleListMembers(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		memberService  *mockMemberService
+		responseStatus int
+		responseBody   string
+	}{
+		"not a member": {
+			memberService:  &mockMemberService{err: fmt.Errorf("%w: the viewer role is required", services.ErrAccessDenied)},
+			responseStatus: http.StatusForbidden,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Forbidden\",\"status\":403,\"detail\":\"access to the company denied: the viewer role is required\",\"code\":\"company_access_denied\"}",
+		},
+		"success": {
+			memberService:  &mockMemberService{member: testMember},
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[" + testMemberBody + "]}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("GET", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/members", nil)
+
+			handler, _ := NewMemberHandler(tt.memberService)
+			handler.HandleListMembers(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleGrantMember(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		memberService  *mockMemberService
+		requestBody    string
+		responseStatus int
+		responseBody   string
+	}{
+		"malformed body": {
+			memberService:  &mockMemberService{},
+			requestBody:    `{"username":`,
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unexpected EOF\",\"code\":\"malformed_request\"}",
+		},
+		"invalid member": {
+			memberService:  &mockMemberService{err: fmt.Errorf("%w: role must be one of owner, editor or viewer", services.ErrInvalidMember)},
+			requestBody:    `{"username":"recipient","role":"admin"}`,
+			responseStatus: http.StatusUnprocessableEntity,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Unprocessable Entity\",\"status\":422,\"detail\":\"invalid member: role must be one of owner, editor or viewer\",\"code\":\"invalid_member\"}",
+		},
+		"success": {
+			memberService:  &mockMemberService{member: testMember},
+			requestBody:    `{"username":"recipient","role":"editor"}`,
+			responseStatus: http.StatusOK,
+			responseBody:   testMemberBody,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			req, _ := http.NewRequest("POST", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/members", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			handler, _ := NewMemberHandler(tt.memberService)
+			handler.HandleGrantMember(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleRevokeMember(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		memberService  *mockMemberService
+		memberID       string
+		responseStatus int
+		responseBody   string
+	}{
+		"invalid member id": {
+			memberService:  &mockMemberService{},
+			memberID:       "invalid",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid UUID length: 7\",\"code\":\"malformed_request\"}",
+		},
+		"not found": {
+			memberService:  &mockMemberService{err: fmt.Errorf("%w: 5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11", services.ErrMemberNotFound)},
+			memberID:       "5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11",
+			responseStatus: http.StatusNotFound,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"member not found: 5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11\",\"code\":\"member_not_found\"}",
+		},
+		"success": {
+			memberService:  &mockMemberService{},
+			memberID:       "5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11",
+			responseStatus: http.StatusNoContent,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{
+				{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"},
+				{Key: "userID", Value: tt.memberID},
+			}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("DELETE", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/members/"+tt.memberID, nil)
+
+			handler, _ := NewMemberHandler(tt.memberService)
+			handler.HandleRevokeMember(c)
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+// mockMemberService for testing.
+type mockMemberService struct {
+	member models.CompanyMember
+	err    error
+}
+
+func (m *mockMemberService) ListMembers(_ context.Context, _, _ uuid.UUID) ([]models.CompanyMember, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []models.CompanyMember{m.member}, nil
+}
+
+func (m *mockMemberService) GrantMember(_ context.Context, _, _ uuid.UUID, _ string, _ models.MemberRole) (models.CompanyMember, error) {
+	return m.member, m.err
+}
+
+func (m *mockMemberService) RevokeMember(_ context.Context, _, _, _ uuid.UUID) error {
+	return m.err
+}
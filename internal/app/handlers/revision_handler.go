@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// RevisionHandler is responsible for handling the company revision history routes.
+type RevisionHandler struct {
+	revisionService services.CompanyRevisions
+}
+
+// NewRevisionHandler creates a new revision handler.
+func NewRevisionHandler(revisionService services.CompanyRevisions) (*RevisionHandler, error) {
+	if revisionService == nil {
+		return nil, errors.New("revision service is nil")
+	}
+
+	return &RevisionHandler{revisionService: revisionService}, nil
+}
+
+// fieldChangeResponse represents a field that changed in a revision.
+type fieldChangeResponse struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// revisionResponse represents a revision of a company, the snapshot is only rendered for a single revision.
+type revisionResponse struct {
+	Revision  int                   `json:"revision"`
+	Action    models.RevisionAction `json:"action"`
+	ActorID   uuid.UUID             `json:"actor_id"`
+	CreatedAt time.Time             `json:"created_at"`
+	Company   *models.Company       `json:"company,omitempty"`
+	Changes   []fieldChangeResponse `json:"changes"`
+}
+
+func newRevisionResponse(revision services.Revision, withSnapshot bool) revisionResponse {
+	resp := revisionResponse{
+		Revision:  revision.Number,
+		Action:    revision.Action,
+		ActorID:   revision.ActorID,
+		CreatedAt: revision.CreatedAt,
+		Changes:   make([]fieldChangeResponse, 0, len(revision.Changes)),
+	}
+	if withSnapshot {
+		resp.Company = &revision.Company
+	}
+	for _, change := range revision.Changes {
+		resp.Changes = append(resp.Changes, fieldChangeResponse{Field: change.Field, From: change.From, To: change.To})
+	}
+
+	return resp
+}
+
+// HandleListRevisions handles listing the revision history of a company to its members.
+func (h *RevisionHandler) HandleListRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	revisions, err := h.revisionService.ListRevisions(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	items := make([]revisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		items = append(items, newRevisionResponse(revision, false))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// HandleGetRevision handles getting a single revision of a company to its members.
+func (h *RevisionHandler) HandleGetRevision(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || number < 1 {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, errors.New("revision must be a positive integer"))
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	revision, err := h.revisionService.GetRevision(c, userID, id, number)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newRevisionResponse(revision, true))
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRevisionHandler(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		revisionService services.CompanyRevisions
+		expErr          string
+	}{
+		"no revision service": {
+			expErr: "revision service is nil",
+		},
+		"success": {
+			revisionService: &mockRevisionService{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			h, err := NewRevisionHandler(tt.revisionService)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, h)
+			} else {
+				assert.NotNil(t, h)
+			}
+		})
+	}
+}
+
+func TestHandleListRevisions(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		revisionService services.CompanyRevisions
+		companyID       string
+		responseStatus  int
+		responseBody    string
+	}{
+		"invalid company id": {
+			revisionService: &mockRevisionService{},
+			companyID:       "invalidid2738",
+			responseStatus:  http.StatusBadRequest,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid UUID length: 13\",\"code\":\"malformed_request\"}",
+		},
+		"internal service error": {
+			revisionService: &mockRevisionService{err: errors.New("internal error")},
+			companyID:       "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			responseStatus:  http.StatusInternalServerError,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+		},
+		"success": {
+			revisionService: &mockRevisionService{revision: services.Revision{
+				Number:  2,
+				Action:  models.RevisionUpdated,
+				Company: models.Company{Name: "Acme"},
+				Changes: []services.FieldChange{{Field: "EmployeesAmount", From: json.RawMessage(`10`), To: json.RawMessage(`12`)}},
+			}},
+			companyID:      "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[{\"revision\":2,\"action\":\"updated\",\"actor_id\":\"00000000-0000-0000-0000-000000000000\",\"created_at\":\"0001-01-01T00:00:00Z\",\"changes\":[{\"field\":\"EmployeesAmount\",\"from\":10,\"to\":12}]}]}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{gin.Param{Key: "companyID", Value: tt.companyID}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+
+			handler, _ := NewRevisionHandler(tt.revisionService)
+			handler.HandleListRevisions(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleGetRevision(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		revisionService services.CompanyRevisions
+		revision        string
+		responseStatus  int
+		responseBody    string
+	}{
+		"invalid revision": {
+			revisionService: &mockRevisionService{},
+			revision:        "0",
+			responseStatus:  http.StatusBadRequest,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"revision must be a positive integer\",\"code\":\"malformed_request\"}",
+		},
+		"unknown revision": {
+			revisionService: &mockRevisionService{err: fmt.Errorf("%w: company ca8fc620-509a-40ac-8cc0-525c37c9c4b9 has no revision 7", services.ErrRevisionNotFound)},
+			revision:        "7",
+			responseStatus:  http.StatusNotFound,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"revision not found: company ca8fc620-509a-40ac-8cc0-525c37c9c4b9 has no revision 7\",\"code\":\"revision_not_found\"}",
+		},
+		"success": {
+			revisionService: &mockRevisionService{revision: services.Revision{
+				Number:  1,
+				Action:  models.RevisionCreated,
+				Company: models.Company{Name: "Acme", Version: 1},
+			}},
+			revision:       "1",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"revision\":1,\"action\":\"created\",\"actor_id\":\"00000000-0000-0000-0000-000000000000\",\"created_at\":\"0001-01-01T00:00:00Z\",\"company\":{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":1,\"Name\":\"Acme\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null},\"changes\":[]}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{
+				gin.Param{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"},
+				gin.Param{Key: "revision", Value: tt.revision},
+			}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+
+			handler, _ := NewRevisionHandler(tt.revisionService)
+			handler.HandleGetRevision(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+type mockRevisionService struct {
+	revision services.Revision
+	err      error
+}
+
+func (m *mockRevisionService) ListRevisions(_ context.Context, _, _ uuid.UUID) ([]services.Revision, error) {
+	return []services.Revision{m.revision}, m.err
+}
+
+func (m *mockRevisionService) GetRevision(_ context.Context, _, _ uuid.UUID, _ int) (services.Revision, error) {
+	return m.revision, m.err
+}
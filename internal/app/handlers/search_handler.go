@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// SearchHandler is responsible for handling the company search routes.
+type SearchHandler struct {
+	searchService services.CompanySearcher
+}
+
+// NewSearchHandler creates a new search handler.
+func NewSearchHandler(searchService services.CompanySearcher) (*SearchHandler, error) {
+	if searchService == nil {
+		return nil, errors.New("search service is nil")
+	}
+
+	return &SearchHandler{searchService: searchService}, nil
+}
+
+// searchHitResponse represents a company matched by a search.
+type searchHitResponse struct {
+	Company    models.Company    `json:"company"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// searchResponse represents a page of search results.
+type searchResponse struct {
+	Items []searchHitResponse `json:"items"`
+	Total int                 `json:"total"`
+}
+
+// HandleSearchCompanies handles the full-text search over the companies of the user.
+func (h *SearchHandler) HandleSearchCompanies(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	query := c.Query("q")
+	if query == "" {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, errors.New("query parameter q is required"))
+		return
+	}
+
+	pagination := map[string]int{"limit": 0, "offset": 0}
+	for key := range pagination {
+		if value := c.Query(key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, fmt.Errorf("invalid %s: %w", key, err))
+				return
+			}
+			pagination[key] = n
+		}
+	}
+
+	result, err := h.searchService.Search(c, userID, query, pagination["limit"], pagination["offset"])
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := searchResponse{
+		Items: make([]searchHitResponse, 0, len(result.Hits)),
+		Total: result.Total,
+	}
+	for _, hit := range result.Hits {
+		highlights := map[string]string{}
+		if hit.Highlights.Name != "" {
+			highlights["name"] = hit.Highlights.Name
+		}
+		if hit.Highlights.Description != "" {
+			highlights["description"] = hit.Highlights.Description
+		}
+		resp.Items = append(resp.Items, searchHitResponse{
+			Company:    hit.Company,
+			Score:      hit.Score,
+			Highlights: highlights,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
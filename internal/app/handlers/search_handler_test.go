@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSearchHandler(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		searchService services.CompanySearcher
+		expErr        string
+	}{
+		"no search service": {
+			searchService: nil,
+			expErr:        "search service is nil",
+		},
+		"success": {
+			searchService: &mockSearchService{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			h, err := NewSearchHandler(tt.searchService)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, h)
+			} else {
+				assert.NotNil(t, h)
+			}
+		})
+	}
+}
+
+func TestHandleSearchCompanies(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		searchService  services.CompanySearcher
+		query          string
+		responseStatus int
+		responseBody   string
+	}{
+		"missing query": {
+			searchService:  &mockSearchService{},
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"query parameter q is required\",\"code\":\"malformed_request\"}",
+		},
+		"invalid offset": {
+			searchService:  &mockSearchService{},
+			query:          "q=acme&offset=x",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid offset: strconv.Atoi: parsing \\\"x\\\": invalid syntax\",\"code\":\"malformed_request\"}",
+		},
+		"invalid search query": {
+			searchService:  &mockSearchService{err: fmt.Errorf("%w: no searchable terms", services.ErrInvalidSearchQuery)},
+			query:          "q=%3F",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid search query: no searchable terms\",\"code\":\"invalid_search_query\"}",
+		},
+		"internal service error": {
+			searchService:  &mockSearchService{err: errors.New("internal error")},
+			query:          "q=acme",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+		},
+		"success": {
+			searchService: &mockSearchService{result: services.SearchResult{
+				Hits: []services.SearchHit{{
+					Company:    models.Company{Name: "Acme"},
+					Score:      2.5,
+					Highlights: services.SearchHighlights{Name: "<em>Acme</em>"},
+				}},
+				Total: 1,
+			}},
+			query:          "q=acme",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[{\"company\":{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":0,\"Name\":\"Acme\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null},\"score\":2.5,\"highlights\":{\"name\":\"\\u003cem\\u003eAcme\\u003c/em\\u003e\"}}],\"total\":1}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			req, _ := http.NewRequest("GET", "/v1/companies/search?"+tt.query, nil)
+			c.Request = req
+
+			handler, _ := NewSearchHandler(tt.searchService)
+			handler.HandleSearchCompanies(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+type mockSearchService struct {
+	result services.SearchResult
+	err    error
+}
+
+func (m *mockSearchService) Search(_ context.Context, _ uuid.UUID, _ string, _, _ int) (services.SearchResult, error) {
+	return m.result, m.err
+}
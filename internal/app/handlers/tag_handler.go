@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// TagHandler is responsible for handling the routes of company tags.
+type TagHandler struct {
+	tagService services.CompanyTags
+}
+
+// NewTagHandler creates a new tag handler.
+func NewTagHandler(tagService services.CompanyTags) (*TagHandler, error) {
+	if tagService == nil {
+		return nil, errors.New("tag service is nil")
+	}
+
+	return &TagHandler{tagService: tagService}, nil
+}
+
+// tagResponse represents a tag.
+type tagResponse struct {
+	Name string `json:"name"`
+}
+
+// tagListResponse represents the tags of a company.
+type tagListResponse struct {
+	Items []tagResponse `json:"items"`
+}
+
+func newTagListResponse(tags []models.Tag) tagListResponse {
+	resp := tagListResponse{Items: make([]tagResponse, 0, len(tags))}
+	for _, tag := range tags {
+		resp.Items = append(resp.Items, tagResponse{Name: tag.Label})
+	}
+
+	return resp
+}
+
+// tagUsageResponse represents a tag and the number of companies using it.
+type tagUsageResponse struct {
+	Name      string `json:"name"`
+	Companies int    `json:"companies"`
+}
+
+// tagUsageListResponse represents suggested tags.
+type tagUsageListResponse struct {
+	Items []tagUsageResponse `json:"items"`
+}
+
+// HandleListTags handles listing the tags of a company.
+func (h *TagHandler) HandleListTags(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	tags, err := h.tagService.ListTags(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTagListResponse(tags))
+}
+
+// HandleAddTag handles tagging a company, it responds with the tags the company has.
+func (h *TagHandler) HandleAddTag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	tags, err := h.tagService.AddTag(c, userID, id, c.Param("tag"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTagListResponse(tags))
+}
+
+// HandleRemoveTag handles removing a tag from a company.
+func (h *TagHandler) HandleRemoveTag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	if err := h.tagService.RemoveTag(c, userID, id, c.Param("tag")); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleSuggestTags handles autocompleting tags, the most used tags starting with the prefix come first.
+// Only the tags of the companies the user is a member of are suggested.
+func (h *TagHandler) HandleSuggestTags(c *gin.Context) {
+	var limit int
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, fmt.Errorf("invalid limit: %w", err))
+			return
+		}
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	usage, err := h.tagService.SuggestTags(c, userID, c.Query("prefix"), limit)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := tagUsageListResponse{Items: make([]tagUsageResponse, 0, len(usage))}
+	for _, u := range usage {
+		resp.Items = append(resp.Items, tagUsageResponse{Name: u.Tag.Label, Companies: u.Companies})
+	}
+	c.JSON(http.StatusOK, resp)
+}
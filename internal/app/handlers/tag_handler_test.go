@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTagHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewTagHandler(nil)
+	assert.EqualError(t, err, "tag service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewTagHandler(&mockTagService{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleAddTag(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		tagService     *mockTagService
+		responseStatus int
+		responseBody   string
+	}{
+		"invalid tag": {
+			tagService:     &mockTagService{err: fmt.Errorf("%w: must not contain commas or control characters", services.ErrInvalidTag)},
+			responseStatus: http.StatusUnprocessableEntity,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Unprocessable Entity\",\"status\":422,\"detail\":\"invalid tag: must not contain commas or control characters\",\"code\":\"invalid_tag\"}",
+		},
+		"success": {
+			tagService:     &mockTagService{tags: []models.Tag{{Name: "eu", Label: "EU"}, {Name: "priority", Label: "Priority"}}},
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[{\"name\":\"EU\"},{\"name\":\"Priority\"}]}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{
+				{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"},
+				{Key: "tag", Value: "EU"},
+			}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("PUT", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/tags/EU", nil)
+
+			handler, _ := NewTagHandler(tt.tagService)
+			handler.HandleAddTag(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleRemoveTag(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		tagService     *mockTagService
+		responseStatus int
+		responseBody   string
+	}{
+		"not tagged": {
+			tagService:     &mockTagService{err: fmt.Errorf("%w: the company is not tagged \"EU\"", services.ErrTagNotFound)},
+			responseStatus: http.StatusNotFound,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"tag not found: the company is not tagged \\\"EU\\\"\",\"code\":\"tag_not_found\"}",
+		},
+		"success": {
+			tagService:     &mockTagService{},
+			responseStatus: http.StatusNoContent,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{
+				{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"},
+				{Key: "tag", Value: "EU"},
+			}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("DELETE", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/tags/EU", nil)
+
+			handler, _ := NewTagHandler(tt.tagService)
+			handler.HandleRemoveTag(c)
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleSuggestTags(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		tagService     *mockTagService
+		query          string
+		responseStatus int
+		responseBody   string
+	}{
+		"invalid limit": {
+			tagService:     &mockTagService{},
+			query:          "?limit=ten",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid limit: strconv.Atoi: parsing \\\"ten\\\": invalid syntax\",\"code\":\"malformed_request\"}",
+		},
+		"no tags": {
+			tagService:     &mockTagService{},
+			query:          "?prefix=x",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[]}",
+		},
+		"success": {
+			tagService:     &mockTagService{usage: []services.TagUsage{{Tag: models.Tag{Name: "priority", Label: "Priority"}, Companies: 12}}},
+			query:          "?prefix=pr&limit=5",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[{\"name\":\"Priority\",\"companies\":12}]}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("GET", "/v1/tags"+tt.query, nil)
+
+			handler, _ := NewTagHandler(tt.tagService)
+			handler.HandleSuggestTags(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestParseCompanyFilter_Tags(t *testing.T) {
+	t.Parallel()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/v1/companies?tags_all=EU,priority&tags_any=partner&tags_any=vendor&tags_none=,churned", nil)
+
+	filter, err := parseCompanyFilter(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EU", "priority"}, filter.TagsAll)
+	assert.Equal(t, []string{"partner", "vendor"}, filter.TagsAny)
+	assert.Equal(t, []string{"churned"}, filter.TagsNone)
+}
+
+// mockTagService for testing.
+type mockTagService struct {
+	tags  []models.Tag
+	usage []services.TagUsage
+	err   error
+}
+
+func (m *mockTagService) ListTags(_ context.Context, _, _ uuid.UUID) ([]models.Tag, error) {
+	return m.tags, m.err
+}
+
+func (m *mockTagService) AddTag(_ context.Context, _, _ uuid.UUID, _ string) ([]models.Tag, error) {
+	return m.tags, m.err
+}
+
+func (m *mockTagService) RemoveTag(_ context.Context, _, _ uuid.UUID, _ string) error {
+	return m.err
+}
+
+func (m *mockTagService) SuggestTags(_ context.Context, _ uuid.UUID, _ string, _ int) ([]services.TagUsage, error) {
+	return m.usage, m.err
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// TransferHandler is responsible for handling the routes of company ownership transfers.
+type TransferHandler struct {
+	transferService services.CompanyTransfers
+	eventProducer   eventProducer
+}
+
+// NewTransferHandler creates a new transfer handler.
+func NewTransferHandler(transferService services.CompanyTransfers, eventProducer eventProducer) (*TransferHandler, error) {
+	if transferService == nil {
+		return nil, errors.New("transfer service is nil")
+	}
+
+	if eventProducer == nil {
+		return nil, errors.New("eventProducer is nil")
+	}
+
+	return &TransferHandler{transferService: transferService, eventProducer: eventProducer}, nil
+}
+
+type transferRequestPayload struct {
+	ToUsername string `json:"to_username"`
+}
+
+// transferResponse represents a company ownership transfer.
+type transferResponse struct {
+	ID          uuid.UUID             `json:"id"`
+	CompanyID   uuid.UUID             `json:"company_id"`
+	FromUserID  uuid.UUID             `json:"from_user_id"`
+	ToUserID    uuid.UUID             `json:"to_user_id"`
+	InitiatorID uuid.UUID             `json:"initiator_id"`
+	Status      models.TransferStatus `json:"status"`
+	CreatedAt   time.Time             `json:"created_at"`
+	ResolvedBy  *uuid.UUID            `json:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time            `json:"resolved_at,omitempty"`
+}
+
+func newTransferResponse(transfer models.CompanyTransfer) transferResponse {
+	return transferResponse{
+		ID:          transfer.ID,
+		CompanyID:   transfer.CompanyID,
+		FromUserID:  transfer.FromUserID,
+		ToUserID:    transfer.ToUserID,
+		InitiatorID: transfer.InitiatorID,
+		Status:      transfer.Status,
+		CreatedAt:   transfer.CreatedAt,
+		ResolvedBy:  transfer.ResolvedBy,
+		ResolvedAt:  transfer.ResolvedAt,
+	}
+}
+
+// transferListResponse represents the pending transfers of a user.
+type transferListResponse struct {
+	Items []transferResponse `json:"items"`
+}
+
+// HandleInitiateTransfer handles offering a company to another user.
+func (h *TransferHandler) HandleInitiateTransfer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	var payload transferRequestPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	transfer, err := h.transferService.InitiateTransfer(c, userID, id, payload.ToUsername)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newTransferResponse(transfer))
+}
+
+// HandleListTransfers handles listing the pending transfers of the authenticated user.
+func (h *TransferHandler) HandleListTransfers(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	transfers, err := h.transferService.ListTransfers(c, userID)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := transferListResponse{Items: make([]transferResponse, 0, len(transfers))}
+	for _, transfer := range transfers {
+		resp.Items = append(resp.Items, newTransferResponse(transfer))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// transferEvent is published when a transfer is accepted along with the company changing hands.
+type transferEvent struct {
+	Type     string           `json:"type"`
+	Transfer transferResponse `json:"transfer"`
+	Company  models.Company   `json:"company"`
+}
+
+// HandleAcceptTransfer handles the recipient accepting a transfer, the change of ownership is published.
+func (h *TransferHandler) HandleAcceptTransfer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("transferID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	transfer, comp, err := h.transferService.AcceptTransfer(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	resp := newTransferResponse(transfer)
+	data, err := json.Marshal(transferEvent{Type: "company.transferred", Transfer: resp, Company: comp})
+	if err == nil {
+		h.eventProducer.SendMessage(topic, data)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleCancelTransfer handles withdrawing or declining a pending transfer.
+func (h *TransferHandler) HandleCancelTransfer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("transferID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	transfer, err := h.transferService.CancelTransfer(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTransferResponse(transfer))
+}
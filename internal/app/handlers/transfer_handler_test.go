@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTransfer = models.CompanyTransfer{
+	ID:          uuid.MustParse("7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a"),
+	CreatedAt:   time.Date(2023, 10, 17, 10, 0, 0, 0, time.UTC),
+	CompanyID:   uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+	FromUserID:  uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9"),
+	ToUserID:    uuid.MustParse("5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11"),
+	InitiatorID: uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9"),
+	Status:      models.TransferPending,
+}
+
+const testTransferBody = "{\"id\":\"7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a\",\"company_id\":\"ca8fc620-509a-40ac-8cc0-525c37c9c4b9\",\"from_user_id\":\"b6000e46-809f-4684-abd9-dc8f445b5ca9\",\"to_user_id\":\"5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11\",\"initiator_id\":\"b6000e46-809f-4684-abd9-dc8f445b5ca9\",\"status\":\"pending\",\"created_at\":\"2023-10-17T10:00:00Z\"}"
+
+func TestNewTransferHandler(t *testing.T) {
+	t.Parallel()
+	h, err := NewTransferHandler(nil, &producerStub{})
+	assert.EqualError(t, err, "transfer service is nil")
+	assert.Nil(t, h)
+
+	h, err = NewTransferHandler(&mockTransferService{}, nil)
+	assert.EqualError(t, err, "eventProducer is nil")
+	assert.Nil(t, h)
+
+	h, err = NewTransferHandler(&mockTransferService{}, &producerStub{})
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestHandleInitiateTransfer(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		transferService *mockTransferService
+		userID          string
+		requestBody     string
+		responseStatus  int
+		responseBody    string
+	}{
+		"invalid user id": {
+			transferService: &mockTransferService{},
+			userID:          "invalid",
+			requestBody:     `{"to_username":"recipient"}`,
+			responseStatus:  http.StatusBadRequest,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid UUID length: 7\",\"code\":\"malformed_request\"}",
+		},
+		"forbidden": {
+			transferService: &mockTransferService{err: fmt.Errorf("%w: only the owner of the company or an admin can transfer it", services.ErrTransferForbidden)},
+			userID:          "5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11",
+			requestBody:     `{"to_username":"recipient"}`,
+			responseStatus:  http.StatusForbidden,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Forbidden\",\"status\":403,\"detail\":\"transfer not allowed: only the owner of the company or an admin can transfer it\",\"code\":\"transfer_forbidden\"}",
+		},
+		"pending transfer": {
+			transferService: &mockTransferService{err: fmt.Errorf("%w: cancel it before starting another one", services.ErrTransferPending)},
+			userID:          "b6000e46-809f-4684-abd9-dc8f445b5ca9",
+			requestBody:     `{"to_username":"recipient"}`,
+			responseStatus:  http.StatusConflict,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"company has a pending transfer: cancel it before starting another one\",\"code\":\"transfer_pending\"}",
+		},
+		"success": {
+			transferService: &mockTransferService{transfer: testTransfer},
+			userID:          "b6000e46-809f-4684-abd9-dc8f445b5ca9",
+			requestBody:     `{"to_username":"recipient"}`,
+			responseStatus:  http.StatusCreated,
+			responseBody:    testTransferBody,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+			c.Set("userID", tt.userID)
+			req, _ := http.NewRequest("POST", "/v1/companies/ca8fc620-509a-40ac-8cc0-525c37c9c4b9/transfers", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			handler, _ := NewTransferHandler(tt.transferService, &producerStub{})
+			handler.HandleInitiateTransfer(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleListTransfers(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		transferService *mockTransferService
+		responseStatus  int
+		responseBody    string
+	}{
+		"internal service error": {
+			transferService: &mockTransferService{err: errors.New("internal error")},
+			responseStatus:  http.StatusInternalServerError,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+		},
+		"no transfers": {
+			transferService: &mockTransferService{},
+			responseStatus:  http.StatusOK,
+			responseBody:    "{\"items\":[]}",
+		},
+		"success": {
+			transferService: &mockTransferService{transfer: testTransfer},
+			responseStatus:  http.StatusOK,
+			responseBody:    "{\"items\":[" + testTransferBody + "]}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("GET", "/v1/transfers", nil)
+
+			handler, _ := NewTransferHandler(tt.transferService, &producerStub{})
+			handler.HandleListTransfers(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleAcceptTransfer(t *testing.T) {
+	t.Parallel()
+	resolvedAt := time.Date(2023, 10, 18, 9, 30, 0, 0, time.UTC)
+	accepted := testTransfer
+	accepted.Status = models.TransferAccepted
+	accepted.ResolvedBy = &accepted.ToUserID
+	accepted.ResolvedAt = &resolvedAt
+	cases := map[string]struct {
+		transferService *mockTransferService
+		transferID      string
+		responseStatus  int
+		responseBody    string
+		expEvent        string
+	}{
+		"invalid transfer id": {
+			transferService: &mockTransferService{},
+			transferID:      "invalid",
+			responseStatus:  http.StatusBadRequest,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid UUID length: 7\",\"code\":\"malformed_request\"}",
+		},
+		"already resolved": {
+			transferService: &mockTransferService{err: fmt.Errorf("%w: 7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a", services.ErrTransferResolved)},
+			transferID:      "7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a",
+			responseStatus:  http.StatusConflict,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"transfer is no longer pending: 7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a\",\"code\":\"transfer_resolved\"}",
+		},
+		"success": {
+			transferService: &mockTransferService{transfer: accepted},
+			transferID:      "7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a",
+			responseStatus:  http.StatusOK,
+			responseBody:    "{\"id\":\"7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a\",\"company_id\":\"ca8fc620-509a-40ac-8cc0-525c37c9c4b9\",\"from_user_id\":\"b6000e46-809f-4684-abd9-dc8f445b5ca9\",\"to_user_id\":\"5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11\",\"initiator_id\":\"b6000e46-809f-4684-abd9-dc8f445b5ca9\",\"status\":\"accepted\",\"created_at\":\"2023-10-17T10:00:00Z\",\"resolved_by\":\"5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11\",\"resolved_at\":\"2023-10-18T09:30:00Z\"}",
+			expEvent:        "{\"type\":\"company.transferred\",\"transfer\":{\"id\":\"7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a\",",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "transferID", Value: tt.transferID}}
+			c.Set("userID", "5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11")
+			c.Request, _ = http.NewRequest("POST", "/v1/transfers/"+tt.transferID+"/accept", nil)
+
+			producer := &producerStub{}
+			handler, _ := NewTransferHandler(tt.transferService, producer)
+			handler.HandleAcceptTransfer(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			if tt.expEvent == "" {
+				assert.Empty(t, producer.sent)
+			} else if assert.Len(t, producer.sent, 1) {
+				assert.True(t, strings.HasPrefix(producer.sent[0], tt.expEvent), producer.sent[0])
+				assert.Contains(t, producer.sent[0], "\"company\":{\"ID\":\"ca8fc620-509a-40ac-8cc0-525c37c9c4b9\"")
+			}
+		})
+	}
+}
+
+func TestHandleCancelTransfer(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		transferService *mockTransferService
+		responseStatus  int
+		responseBody    string
+	}{
+		"not found": {
+			transferService: &mockTransferService{err: fmt.Errorf("%w: 7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a", services.ErrTransferNotFound)},
+			responseStatus:  http.StatusNotFound,
+			responseBody:    "{\"type\":\"about:blank\",\"title\":\"Not Found\",\"status\":404,\"detail\":\"transfer not found: 7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a\",\"code\":\"transfer_not_found\"}",
+		},
+		"success": {
+			transferService: &mockTransferService{transfer: testTransfer},
+			responseStatus:  http.StatusOK,
+			responseBody:    testTransferBody,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "transferID", Value: "7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a"}}
+			c.Set("userID", "b6000e46-809f-4684-abd9-dc8f445b5ca9")
+			c.Request, _ = http.NewRequest("POST", "/v1/transfers/7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a/cancel", nil)
+
+			handler, _ := NewTransferHandler(tt.transferService, &producerStub{})
+			handler.HandleCancelTransfer(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+// mockTransferService for testing
+type mockTransferService struct {
+	transfer models.CompanyTransfer
+	err      error
+}
+
+func (m *mockTransferService) InitiateTransfer(_ context.Context, _, _ uuid.UUID, _ string) (models.CompanyTransfer, error) {
+	return m.transfer, m.err
+}
+
+func (m *mockTransferService) ListTransfers(_ context.Context, _ uuid.UUID) ([]models.CompanyTransfer, error) {
+	if m.transfer.ID == uuid.Nil {
+		return nil, m.err
+	}
+	return []models.CompanyTransfer{m.transfer}, m.err
+}
+
+func (m *mockTransferService) AcceptTransfer(_ context.Context, _, _ uuid.UUID) (models.CompanyTransfer, models.Company, error) {
+	return m.transfer, models.Company{ID: m.transfer.CompanyID, UserID: m.transfer.ToUserID}, m.err
+}
+
+func (m *mockTransferService) CancelTransfer(_ context.Context, _, _ uuid.UUID) (models.CompanyTransfer, error) {
+	return m.transfer, m.err
+}
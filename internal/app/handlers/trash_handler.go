@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// purgeEvent is published when a deleted company is removed for good, only its id is left.
+type purgeEvent struct {
+	Type      string    `json:"type"`
+	CompanyID uuid.UUID `json:"company_id"`
+}
+
+// TrashHandler is responsible for handling the routes of deleted companies.
+type TrashHandler struct {
+	trashService  services.CompanyTrash
+	purgeService  services.CompanyPurge
+	eventProducer eventProducer
+}
+
+// NewTrashHandler creates a new trash handler.
+func NewTrashHandler(trashService services.CompanyTrash, purgeService services.CompanyPurge, eventProducer eventProducer) (*TrashHandler, error) {
+	if trashService == nil {
+		return nil, errors.New("trash service is nil")
+	}
+
+	if purgeService == nil {
+		return nil, errors.New("purge service is nil")
+	}
+
+	if eventProducer == nil {
+		return nil, errors.New("eventProducer is nil")
+	}
+
+	return &TrashHandler{trashService: trashService, purgeService: purgeService, eventProducer: eventProducer}, nil
+}
+
+// HandleListDeletedCompanies handles listing the deleted companies the authenticated user is a member of.
+func (h *TrashHandler) HandleListDeletedCompanies(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	params, err := parseListCompaniesParams(c)
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	page, err := h.trashService.ListDeleted(c, userID, params)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCompanyPageResponse(page))
+}
+
+// HandleRestoreCompany handles restoring a deleted company.
+func (h *TrashHandler) HandleRestoreCompany(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	comp, err := h.trashService.Restore(c, userID, id)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	data, err := json.Marshal(companyEvent{Type: "company.restored", Company: comp})
+	if err == nil {
+		h.eventProducer.SendMessage(topic, data)
+	}
+
+	setCompanyValidators(c, comp)
+	c.JSON(http.StatusOK, comp)
+}
+
+// HandlePurgeCompany handles permanently removing a deleted company.
+func (h *TrashHandler) HandlePurgeCompany(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("companyID"))
+	if err != nil {
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+		return
+	}
+
+	if err := h.purgeService.Purge(c, id); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	data, err := json.Marshal(purgeEvent{Type: "company.purged", CompanyID: id})
+	if err == nil {
+		h.eventProducer.SendMessage(topic, data)
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTrashHandler(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		trashService services.CompanyTrash
+		purgeService services.CompanyPurge
+		producer     eventProducer
+		expErr       string
+	}{
+		"no trash service": {
+			expErr: "trash service is nil",
+		},
+		"no purge service": {
+			trashService: &mockTrashService{},
+			expErr:       "purge service is nil",
+		},
+		"no event producer": {
+			trashService: &mockTrashService{},
+			purgeService: &mockTrashService{},
+			expErr:       "eventProducer is nil",
+		},
+		"success": {
+			trashService: &mockTrashService{},
+			purgeService: &mockTrashService{},
+			producer:     &producerStub{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			h, err := NewTrashHandler(tt.trashService, tt.purgeService, tt.producer)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, h)
+			} else {
+				assert.NotNil(t, h)
+			}
+		})
+	}
+}
+
+func TestHandleListDeletedCompanies(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		trashService   services.CompanyTrash
+		query          string
+		responseStatus int
+		responseBody   string
+	}{
+		"invalid list query": {
+			trashService:   &mockTrashService{err: fmt.Errorf("%w: negative limit", services.ErrInvalidListQuery)},
+			query:          "limit=-1",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid list query: negative limit\",\"code\":\"invalid_list_query\"}",
+		},
+		"internal service error": {
+			trashService:   &mockTrashService{err: errors.New("internal error")},
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+		},
+		"success": {
+			trashService:   &mockTrashService{},
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"items\":[]}",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+			req, _ := http.NewRequest("GET", "/v1/companies/deleted?"+tt.query, nil)
+			c.Request = req
+
+			handler, _ := NewTrashHandler(tt.trashService, &mockTrashService{}, &producerStub{})
+			handler.HandleListDeletedCompanies(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandleRestoreCompany(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		trashService   services.CompanyTrash
+		companyID      string
+		responseStatus int
+		responseBody   string
+		expEvent       string
+	}{
+		"invalid company id": {
+			trashService:   &mockTrashService{},
+			companyID:      "invalidid2738",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"invalid UUID length: 13\",\"code\":\"malformed_request\"}",
+		},
+		"name taken": {
+			trashService:   &mockTrashService{err: fmt.Errorf("%w: rename the company using it before restoring this one", services.ErrNameTaken)},
+			companyID:      "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			responseStatus: http.StatusConflict,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,\"detail\":\"company name is taken: rename the company using it before restoring this one\",\"code\":\"company_name_taken\"}",
+		},
+		"success": {
+			trashService:   &mockTrashService{company: models.Company{Name: "company1", Version: 3}},
+			companyID:      "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"ID\":\"00000000-0000-0000-0000-000000000000\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Version\":3,\"Name\":\"company1\",\"Slug\":\"\",\"Description\":\"\",\"EmployeesAmount\":0,\"Registered\":false,\"Status\":\"\",\"Type\":\"\",\"UserID\":\"00000000-0000-0000-0000-000000000000\",\"ParentID\":null}",
+			expEvent:       "{\"type\":\"company.restored\",\"company\":{\"ID\":\"00000000-0000-0000-0000-000000000000\",",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", "ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+			c.Params = gin.Params{gin.Param{Key: "companyID", Value: tt.companyID}}
+
+			producer := &producerStub{}
+			handler, _ := NewTrashHandler(tt.trashService, &mockTrashService{}, producer)
+			handler.HandleRestoreCompany(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			if tt.expEvent == "" {
+				assert.Empty(t, producer.sent)
+			} else if assert.Len(t, producer.sent, 1) {
+				assert.True(t, strings.HasPrefix(producer.sent[0], tt.expEvent), producer.sent[0])
+			}
+		})
+	}
+}
+
+func TestHandlePurgeCompany(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		purgeService   *mockTrashService
+		responseStatus int
+		responseBody   string
+		expEvents      []string
+	}{
+		"internal service error": {
+			purgeService:   &mockTrashService{err: errors.New("internal error")},
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
+		},
+		"success": {
+			purgeService:   &mockTrashService{},
+			responseStatus: http.StatusNoContent,
+			expEvents:      []string{"{\"type\":\"company.purged\",\"company_id\":\"ca8fc620-509a-40ac-8cc0-525c37c9c4b9\"}"},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{gin.Param{Key: "companyID", Value: "ca8fc620-509a-40ac-8cc0-525c37c9c4b9"}}
+
+			producer := &producerStub{}
+			handler, _ := NewTrashHandler(&mockTrashService{}, tt.purgeService, producer)
+			handler.HandlePurgeCompany(c)
+			assert.Equal(t, tt.responseStatus, c.Writer.Status())
+			assert.Equal(t, tt.responseBody, w.Body.String())
+			assert.Equal(t, tt.expEvents, producer.sent)
+		})
+	}
+}
+
+// mockTrashService for testing, it also purges companies.
+type mockTrashService struct {
+	company models.Company
+	page    services.CompanyPage
+	err     error
+}
+
+func (m *mockTrashService) ListDeleted(_ context.Context, _ uuid.UUID, _ services.ListCompaniesParams) (services.CompanyPage, error) {
+	return m.page, m.err
+}
+
+func (m *mockTrashService) Restore(_ context.Context, _, _ uuid.UUID) (models.Company, error) {
+	return m.company, m.err
+}
+
+func (m *mockTrashService) Purge(_ context.Context, _ uuid.UUID) error {
+	return m.err
+}
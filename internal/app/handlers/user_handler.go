@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/problem"
 	"github.com/iNDicat0r/company/internal/app/services"
 )
 
@@ -39,13 +40,13 @@ func (uh *UserHandler) HandleAuthenticate(c *gin.Context) {
 	var reqBody authenticateRequestBody
 
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 
 	jwt, err := uh.userService.Authenticate(c, reqBody.Username, reqBody.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
 		return
 	}
 
@@ -59,12 +60,12 @@ func (uh *UserHandler) HandleAuthenticate(c *gin.Context) {
 func (uh *UserHandler) HandleIntrospect(c *gin.Context) {
 	userID, err := uuid.Parse(c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
 		return
 	}
 	user, err := uh.userService.GetUser(c, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
 		return
 	}
 
@@ -57,13 +57,13 @@ func TestHandleAuthenticate(t *testing.T) {
 			userService:    &mockUserService{},
 			responseStatus: http.StatusBadRequest,
 			requestBody:    "{",
-			responseBody:   "{\"error\":\"unexpected EOF\"}",
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"unexpected EOF\",\"code\":\"malformed_request\"}",
 		},
 		"user service error": {
 			userService:    &mockUserService{err: errors.New("internal error")},
 			responseStatus: http.StatusInternalServerError,
 			requestBody:    "{\"username\":\"hello\", \"password\":\"123\"}",
-			responseBody:   "{\"error\":\"internal error\"}",
+			responseBody:   "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
 		},
 		"success": {
 			userService:    &mockUserService{jwt: "jwt123"},
@@ -109,7 +109,7 @@ func TestHandleIntrospect(t *testing.T) {
 				Key:   "userID",
 				Value: "862dedcb-68c5-49f7-a94a-b7190499f16b",
 			}},
-			responseBody:     "{\"error\":\"internal error\"}",
+			responseBody:     "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,\"code\":\"internal_error\"}",
 			setUserIDContext: "862dedcb-68c5-49f7-a94a-b7190499f16b",
 		},
 		"success": {
@@ -125,7 +125,7 @@ func TestHandleIntrospect(t *testing.T) {
 				Key:   "userID",
 				Value: "862dedcb-68c5-49f7-a94a-b7190499f16b",
 			}},
-			responseBody:     "{\"ID\":\"862dedcb-68c5-49f7-a94a-b7190499f16b\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Name\":\"user 1\",\"Username\":\"userx\",\"Companies\":null}",
+			responseBody:     "{\"ID\":\"862dedcb-68c5-49f7-a94a-b7190499f16b\",\"CreatedAt\":\"0001-01-01T00:00:00Z\",\"UpdatedAt\":\"0001-01-01T00:00:00Z\",\"DeletedAt\":null,\"Name\":\"user 1\",\"Username\":\"userx\",\"Admin\":false,\"Companies\":null}",
 			setUserIDContext: "862dedcb-68c5-49f7-a94a-b7190499f16b",
 		},
 	}
@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// AdminMiddleware only lets admins through, it has to run after the AuthMiddleware.
+func AdminMiddleware(userService services.User) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			problem.WriteStatus(c, http.StatusUnauthorized, problem.CodeUnauthenticated, errUnauthenticated)
+			c.Abort()
+			return
+		}
+
+		user, err := userService.GetUser(c, userID)
+		if err != nil || !user.Admin {
+			problem.WriteStatus(c, http.StatusForbidden, problem.CodeForbidden, errors.New("admin privileges are required"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	cases := map[string]struct {
+		userID      string
+		userService *mockUserService
+		expStatus   int
+		expBody     string
+	}{
+		"not authenticated": {
+			userService: &mockUserService{},
+			expStatus:   http.StatusUnauthorized,
+			expBody:     `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"authentication is required","code":"unauthenticated"}`,
+		},
+		"unknown user": {
+			userID:      "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			userService: &mockUserService{err: errors.New("user not found")},
+			expStatus:   http.StatusForbidden,
+			expBody:     `{"type":"about:blank","title":"Forbidden","status":403,"detail":"admin privileges are required","code":"forbidden"}`,
+		},
+		"not an admin": {
+			userID:      "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			userService: &mockUserService{user: models.User{Name: "user"}},
+			expStatus:   http.StatusForbidden,
+			expBody:     `{"type":"about:blank","title":"Forbidden","status":403,"detail":"admin privileges are required","code":"forbidden"}`,
+		},
+		"admin": {
+			userID:      "ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			userService: &mockUserService{user: models.User{Name: "admin", Admin: true}},
+			expStatus:   http.StatusOK,
+			expBody:     `{"message":"purged"}`,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.userID != "" {
+					c.Set("userID", tt.userID)
+				}
+			})
+			router.Use(AdminMiddleware(tt.userService))
+			router.DELETE("/v1/companies/deleted/:companyID", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "purged"})
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/companies/deleted/ca8fc620-509a-40ac-8cc0-525c37c9c4b9", nil))
+			assert.Equal(t, tt.expStatus, w.Code)
+			assert.Equal(t, tt.expBody, w.Body.String())
+		})
+	}
+}
+
+type mockUserService struct {
+	user models.User
+	err  error
+}
+
+func (m *mockUserService) Save(_ context.Context, _, _, _ string) (uuid.UUID, error) {
+	return uuid.UUID{}, m.err
+}
+
+func (m *mockUserService) Authenticate(_ context.Context, _, _ string) (string, error) {
+	return "", m.err
+}
+
+func (m *mockUserService) GetUser(_ context.Context, _ uuid.UUID) (models.User, error) {
+	return m.user, m.err
+}
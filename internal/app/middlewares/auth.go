@@ -1,26 +1,32 @@
 package middlewares
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iNDicat0r/company/internal/app/problem"
 	"github.com/iNDicat0r/company/internal/app/utils"
 )
 
+// errUnauthenticated is reported when a request carries no user.
+var errUnauthenticated = errors.New("authentication is required")
+
 // AuthMiddleware is an authenticator middleware.
 func AuthMiddleware(jwtPrivateKey string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			problem.WriteStatus(c, http.StatusUnauthorized, problem.CodeUnauthenticated, errUnauthenticated)
 			c.Abort()
 			return
 		}
 
 		userID, err := utils.ParseJWT(jwtPrivateKey, authHeader)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+			problem.WriteStatus(c, http.StatusUnauthorized, problem.CodeUnauthenticated, fmt.Errorf("invalid token: %w", err))
 			c.Abort()
 			return
 		}
@@ -0,0 +1,16 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControl is a middleware setting the Cache-Control header of the responses.
+func CacheControl(value string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if value != "" {
+			c.Header("Cache-Control", value)
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expValue string
+	}{
+		"not configured": {
+			value:    "",
+			expValue: "",
+		},
+		"configured": {
+			value:    "private, max-age=60",
+			expValue: "private, max-age=60",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(CacheControl(tt.value))
+			router.GET("/v1/companies", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/companies", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expValue, w.Header().Get("Cache-Control"))
+		})
+	}
+}
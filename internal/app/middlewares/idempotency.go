@@ -0,0 +1,107 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/problem"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// replayedHeaders are the response headers stored along with the body of an idempotent request.
+var replayedHeaders = []string{"Content-Type", "ETag", "Last-Modified", "Location"}
+
+// Idempotency makes requests carrying an Idempotency-Key header safe to retry, it has to run
+// after the AuthMiddleware. The first response given to a key is replayed on retries, except
+// server errors which release the key. Reusing a key for a different request fails with 422.
+func Idempotency(keys services.IdempotencyKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			problem.WriteStatus(c, http.StatusUnauthorized, problem.CodeUnauthenticated, errUnauthenticated)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			problem.WriteStatus(c, http.StatusBadRequest, problem.CodeMalformedRequest, err)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		stored, err := keys.Begin(c, userID, key, requestFingerprint(c.Request, body))
+		if err != nil {
+			problem.Write(c, err)
+			c.Abort()
+			return
+		}
+
+		if stored != nil {
+			for name, value := range stored.Header {
+				c.Header(name, value)
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, stored.Header["Content-Type"], stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			_ = keys.Release(c, userID, key)
+			return
+		}
+
+		header := make(map[string]string, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if value := c.Writer.Header().Get(name); value != "" {
+				header[name] = value
+			}
+		}
+		_ = keys.Complete(c, userID, key, services.StoredResponse{
+			StatusCode: c.Writer.Status(),
+			Header:     header,
+			Body:       recorder.body.Bytes(),
+		})
+	}
+}
+
+// requestFingerprint identifies a request by its method, path and body.
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the body written to the response.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
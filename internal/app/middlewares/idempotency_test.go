@@ -0,0 +1,114 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	keys := &memoryIdempotencyKeys{records: map[string]memoryIdempotencyRecord{}}
+	calls := 0
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", "ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+	})
+	router.Use(Idempotency(keys))
+	router.POST("/v1/companies/", func(c *gin.Context) {
+		calls++
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		c.Header("ETag", `"1"`)
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	post := func(key, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("", "/v1/companies/", `{"name":"acme"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = post("", "/v1/companies/", `{"name":"acme"}`)
+	assert.Equal(t, `{"call":2}`, w.Body.String(), "requests without a key are not deduplicated")
+
+	w = post("create-acme", "/v1/companies/", `{"name":"acme"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"call":3}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+
+	w = post("create-acme", "/v1/companies/", `{"name":"acme"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"call":3}`, w.Body.String())
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+
+	w = post("create-acme", "/v1/companies/", `{"name":"other"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"idempotency key reused with a different request","code":"idempotency_key_reused"}`, w.Body.String())
+
+	w = post("failing", "/v1/companies/?fail=1", `{}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	w = post("failing", "/v1/companies/", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code, "server errors release the key")
+	assert.Equal(t, 5, calls)
+
+	w = post(strings.Repeat("k", 256), "/v1/companies/", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// memoryIdempotencyKeys is an in memory implementation of the idempotency keys service.
+type memoryIdempotencyKeys struct {
+	records map[string]memoryIdempotencyRecord
+}
+
+type memoryIdempotencyRecord struct {
+	fingerprint string
+	response    *services.StoredResponse
+}
+
+func (m *memoryIdempotencyKeys) Begin(_ context.Context, _ uuid.UUID, key, fingerprint string) (*services.StoredResponse, error) {
+	if len(key) > 255 {
+		return nil, services.ErrInvalidIdempotencyKey
+	}
+
+	record, ok := m.records[key]
+	switch {
+	case !ok:
+		m.records[key] = memoryIdempotencyRecord{fingerprint: fingerprint}
+		return nil, nil
+	case record.fingerprint != fingerprint:
+		return nil, services.ErrIdempotencyKeyReused
+	case record.response == nil:
+		return nil, services.ErrIdempotencyKeyInProgress
+	}
+
+	return record.response, nil
+}
+
+func (m *memoryIdempotencyKeys) Complete(_ context.Context, _ uuid.UUID, key string, response services.StoredResponse) error {
+	record := m.records[key]
+	record.response = &response
+	m.records[key] = record
+	return nil
+}
+
+func (m *memoryIdempotencyKeys) Release(_ context.Context, _ uuid.UUID, key string) error {
+	delete(m.records, key)
+	return nil
+}
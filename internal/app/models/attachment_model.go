@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttachmentKind is what a file attached to a company is used for.
+type AttachmentKind string
+
+const (
+	AttachmentLogo     AttachmentKind = "logo"     // Image shown along the company.
+	AttachmentDocument AttachmentKind = "document" // Registration certificates and other paperwork.
+)
+
+// CompanyAttachment is the metadata of a file attached to a company, the content is kept in a blob store.
+type CompanyAttachment struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	CreatedAt   time.Time
+	CompanyID   uuid.UUID      `gorm:"type:char(36);index"`
+	Kind        AttachmentKind `gorm:"size:16"`
+	FileName    string         `gorm:"size:255"`
+	ContentType string         `gorm:"size:127"` // Sniffed from the content, the type sent by the client is ignored.
+	Size        int64
+	Checksum    string    `gorm:"size:64"` // Hex encoded SHA-256 of the content.
+	UploadedBy  uuid.UUID `gorm:"type:char(36)"`
+}
+
+func (a *CompanyAttachment) BeforeCreate(_ *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttributeType is the type of the values of a custom attribute.
+type AttributeType string
+
+const (
+	AttributeString AttributeType = "string"
+	AttributeInt    AttributeType = "int"
+	AttributeBool   AttributeType = "bool"
+	AttributeDate   AttributeType = "date" // A calendar day written as 2006-01-02.
+	AttributeEnum   AttributeType = "enum" // A string out of the options of the schema.
+)
+
+// AttributeConstraints restrict the values of a custom attribute, each constraint applies
+// to a single type of attribute.
+type AttributeConstraints struct {
+	MinLength *int     `json:"min_length,omitempty"` // string
+	MaxLength *int     `json:"max_length,omitempty"` // string
+	Pattern   string   `json:"pattern,omitempty"`    // string, the whole value has to match
+	Min       *int64   `json:"min,omitempty"`        // int
+	Max       *int64   `json:"max,omitempty"`        // int
+	MinDate   string   `json:"min_date,omitempty"`   // date
+	MaxDate   string   `json:"max_date,omitempty"`   // date
+	Options   []string `json:"options,omitempty"`    // enum
+}
+
+// AttributeSchema defines a custom attribute companies can have a value for.
+type AttributeSchema struct {
+	Name        string               `gorm:"primaryKey;size:64"`
+	Type        AttributeType        `gorm:"size:16;not null"`
+	Description string               `gorm:"size:255"`
+	Required    bool                 `gorm:"not null;default:false"`
+	Constraints AttributeConstraints `gorm:"serializer:json;type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CompanyAttribute is the value a company has for a custom attribute. It is kept in the
+// column matching the type of the attribute so values can be compared and sorted.
+type CompanyAttribute struct {
+	CompanyID   uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	Name        string    `gorm:"primaryKey;size:64;index"`
+	StringValue *string   `gorm:"size:255"`
+	IntValue    *int64
+	BoolValue   *bool
+	DateValue   *time.Time
+	UpdatedBy   uuid.UUID `gorm:"type:char(36)"`
+	UpdatedAt   time.Time
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/naming"
 	"gorm.io/gorm"
 )
 
@@ -14,15 +15,36 @@ type Company struct {
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	DeletedAt       gorm.DeletedAt `gorm:"index"`
-	Name            string         `gorm:"size:15;unique"`
+	Version         int            `gorm:"not null;default:1"` // Incremented on every update, used for optimistic locking.
+	Name            string         `gorm:"size:15"`
+	ActiveName      *string        `gorm:"size:255;uniqueIndex" json:"-"` // Key of the name of a live company, NULL once deleted so the name can be reused.
+	Slug            string         `gorm:"size:80;index"`                 // Current slug, the previous ones are kept as CompanySlug.
 	Description     string         `gorm:"size:3000"`
 	EmployeesAmount int
-	Registered      bool
-	Type            common.Type `gorm:"type:enum('Corporations', 'NonProfit', 'Cooperative', 'Sole Proprietorship')"`
-	UserID          uuid.UUID   `gorm:"type:uuid"`
+	Registered      bool               // Follows the status, only set directly for companies created before statuses.
+	Status          CompanyStatus      `gorm:"size:32;index"` // Stage of the lifecycle, only changed by transitions.
+	Type            common.Type        `gorm:"size:32;index"` // Name of an entry of the company type catalogue.
+	UserID          uuid.UUID          `gorm:"type:uuid"`
+	ParentID        *uuid.UUID         `gorm:"type:char(36);index"`                                 // Parent company in a corporate group, NULL for the top of a group.
+	Locations       []CompanyLocation  `gorm:"foreignKey:CompanyID;constraint:-" json:",omitempty"` // Headquarters first, only loaded when reading a single company.
+	Attributes      []CompanyAttribute `gorm:"foreignKey:CompanyID;constraint:-" json:"-"`          // Custom attribute values, only set to create them along with the company.
 }
 
 func (c *Company) BeforeCreate(_ *gorm.DB) (err error) {
 	c.ID = uuid.New()
+	c.Version = 1
+	key := naming.Key(c.Name)
+	c.ActiveName = &key
+	if c.Status == "" {
+		c.Status = InitialStatus(c.Registered)
+	}
 	return
 }
+
+// CompanySlug maps every slug a company ever had to it, so links made before a rename keep working.
+// Slugs are never handed to another company until the one holding them is purged.
+type CompanySlug struct {
+	Slug      string    `gorm:"primaryKey;size:80"`
+	CompanyID uuid.UUID `gorm:"type:char(36);index"`
+	CreatedAt time.Time
+}
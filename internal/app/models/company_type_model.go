@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/iNDicat0r/company/common"
+)
+
+// CompanyType represents an entry of the company type catalogue. Deprecated types stay
+// valid for the companies using them but cannot be picked for new ones.
+type CompanyType struct {
+	Name        common.Type `gorm:"primaryKey;size:32"`
+	Description string      `gorm:"size:255"`
+	Deprecated  bool        `gorm:"not null;default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmployeeCount is the number of employees a company has from a day on, one is recorded
+// every time the EmployeesAmount of a company changes. Counts effective on the same day
+// are told apart by when they were recorded, the last one wins.
+type EmployeeCount struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	CreatedAt   time.Time
+	CompanyID   uuid.UUID `gorm:"type:char(36);index:idx_employee_counts_company_day"`
+	EffectiveOn time.Time `gorm:"index:idx_employee_counts_company_day"` // Midnight UTC of the day the count applies from.
+	Employees   int
+	ActorID     uuid.UUID `gorm:"type:char(36)"`
+}
+
+func (e *EmployeeCount) BeforeCreate(_ *gorm.DB) (err error) {
+	e.ID = uuid.New()
+	return
+}
+
+// Day truncates a time to the midnight UTC starting its day, as EffectiveOn is stored.
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey represents a request made with an Idempotency-Key header and the response it got.
+// StatusCode is zero while the first request with the key is still being processed.
+type IdempotencyKey struct {
+	UserID      uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	Key         string    `gorm:"primaryKey;size:255;column:idempotency_key"`
+	Fingerprint string    `gorm:"size:64"`
+	StatusCode  int
+	Header      map[string]string `gorm:"type:text;serializer:json"`
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CompanyStatus is the stage of its lifecycle a company is at.
+type CompanyStatus string
+
+const (
+	StatusDraft               CompanyStatus = "draft"
+	StatusPendingRegistration CompanyStatus = "pending_registration"
+	StatusActive              CompanyStatus = "active"
+	StatusSuspended           CompanyStatus = "suspended"
+	StatusDissolved           CompanyStatus = "dissolved"
+)
+
+// Registered reports whether a company at the status is on the register, Company.Registered follows it.
+func (s CompanyStatus) Registered() bool {
+	return s == StatusActive || s == StatusSuspended
+}
+
+// InitialStatus is the status a company is created at, registered companies start active.
+func InitialStatus(registered bool) CompanyStatus {
+	if registered {
+		return StatusActive
+	}
+	return StatusDraft
+}
+
+// TransitionKind names a move between two statuses, events of transitions are typed after it.
+type TransitionKind string
+
+const (
+	TransitionSubmitted  TransitionKind = "registration_submitted"
+	TransitionRejected   TransitionKind = "registration_rejected"
+	TransitionRegistered TransitionKind = "registered"
+	TransitionSuspended  TransitionKind = "suspended"
+	TransitionReinstated TransitionKind = "reinstated"
+	TransitionDissolved  TransitionKind = "dissolved"
+)
+
+// CompanyTransition records a change of the status of a company, Version is the version
+// of the company the transition brought it to.
+type CompanyTransition struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	CreatedAt time.Time
+	CompanyID uuid.UUID      `gorm:"type:char(36);index"`
+	Kind      TransitionKind `gorm:"size:32"`
+	From      CompanyStatus  `gorm:"size:32"`
+	To        CompanyStatus  `gorm:"size:32"`
+	Reason    string         `gorm:"size:500"`
+	ActorID   uuid.UUID      `gorm:"type:char(36)"`
+	Version   int
+}
+
+func (t *CompanyTransition) BeforeCreate(_ *gorm.DB) (err error) {
+	t.ID = uuid.New()
+	return
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CompanyLocation is an office of a company with its postal address. A company has at most one
+// headquarters, the other locations are its branches.
+type CompanyLocation struct {
+	ID           uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	CompanyID    uuid.UUID `gorm:"type:char(36);index"`
+	Name         string    `gorm:"size:128"`
+	Headquarters bool
+	Street       string   `gorm:"size:255"`
+	PostalCode   string   `gorm:"size:32"`
+	City         string   `gorm:"size:128"`
+	Region       string   `gorm:"size:128"`     // State, province or county, depending on the country.
+	Country      string   `gorm:"size:2;index"` // ISO 3166-1 alpha-2 code.
+	Latitude     *float64 // Coordinates of the location, both or neither are set.
+	Longitude    *float64
+}
+
+func (l *CompanyLocation) BeforeCreate(_ *gorm.DB) (err error) {
+	l.ID = uuid.New()
+	return
+}
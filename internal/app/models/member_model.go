@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemberRole is the access a user has to a company.
+type MemberRole string
+
+const (
+	RoleOwner  MemberRole = "owner"  // Edits, deletes and manages the members of the company.
+	RoleEditor MemberRole = "editor" // Edits the company.
+	RoleViewer MemberRole = "viewer" // Reads the history and members of the company.
+)
+
+// memberRoleRanks orders the roles, a role grants everything the roles below it grant.
+var memberRoleRanks = map[MemberRole]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// Valid reports whether the role is one of the known roles.
+func (r MemberRole) Valid() bool {
+	return memberRoleRanks[r] > 0
+}
+
+// Grants reports whether the role includes the needed one.
+func (r MemberRole) Grants(need MemberRole) bool {
+	return r.Valid() && memberRoleRanks[r] >= memberRoleRanks[need]
+}
+
+// CompanyMember is an entry of the access control list of a company. The user a company
+// belongs to is always one of its owners.
+type CompanyMember struct {
+	CompanyID uuid.UUID  `gorm:"primaryKey;type:char(36)"`
+	UserID    uuid.UUID  `gorm:"primaryKey;type:char(36);index"`
+	Role      MemberRole `gorm:"size:16"`
+	GrantedBy uuid.UUID  `gorm:"type:char(36)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionAction is the kind of change a company revision records.
+type RevisionAction string
+
+const (
+	RevisionCreated      RevisionAction = "created"
+	RevisionUpdated      RevisionAction = "updated"
+	RevisionDeleted      RevisionAction = "deleted"
+	RevisionRestored     RevisionAction = "restored"
+	RevisionTransferred  RevisionAction = "transferred"
+	RevisionRelocated    RevisionAction = "relocated"    // A location of the company was added, changed or removed.
+	RevisionTransitioned RevisionAction = "transitioned" // The status of the company changed.
+)
+
+// CompanyRevision is an immutable snapshot of a company taken after every change.
+// Revision is the version of the company the snapshot was taken at.
+type CompanyRevision struct {
+	CompanyID uuid.UUID      `gorm:"primaryKey;type:char(36)"`
+	Revision  int            `gorm:"primaryKey;autoIncrement:false"`
+	Action    RevisionAction `gorm:"size:16"`
+	ActorID   uuid.UUID      `gorm:"type:char(36)"`
+	CreatedAt time.Time
+	Snapshot  Company `gorm:"type:text;serializer:json"`
+}
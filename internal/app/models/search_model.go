@@ -0,0 +1,14 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// CompanySearchTerm represents an entry of the company full-text search index.
+type CompanySearchTerm struct {
+	CompanyID uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	Field     string    `gorm:"primaryKey;size:16"`
+	Term      string    `gorm:"primaryKey;size:64;index"`
+	Frequency int
+	Weight    float64 // Weight of the field times the log-scaled frequency, searches sum it up in SQL.
+}
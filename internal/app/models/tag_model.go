@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag represents a label companies are classified with. Tags are matched ignoring case,
+// Name is the key of the label and Label is the label as it was first written.
+type Tag struct {
+	Name      string `gorm:"primaryKey;size:128"`
+	Label     string `gorm:"size:32"`
+	CreatedAt time.Time
+}
+
+// CompanyTag links a tag to a company.
+type CompanyTag struct {
+	CompanyID uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	TagName   string    `gorm:"primaryKey;size:128;index"`
+	CreatedBy uuid.UUID `gorm:"type:char(36)"`
+	CreatedAt time.Time
+}
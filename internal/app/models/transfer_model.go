@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TransferStatus is the state of a company ownership transfer.
+type TransferStatus string
+
+const (
+	TransferPending   TransferStatus = "pending"
+	TransferAccepted  TransferStatus = "accepted"
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+// CompanyTransfer is a request to hand a company over to another user, ownership only
+// changes once the recipient accepts it.
+type CompanyTransfer struct {
+	ID               uuid.UUID `gorm:"primaryKey;type:char(36)"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	CompanyID        uuid.UUID      `gorm:"type:char(36);index"`
+	PendingCompanyID *uuid.UUID     `gorm:"type:char(36);uniqueIndex" json:"-"` // Company of a pending transfer, NULL once resolved so a company has a single pending transfer.
+	FromUserID       uuid.UUID      `gorm:"type:char(36);index"`                // Owner of the company when the transfer was initiated.
+	ToUserID         uuid.UUID      `gorm:"type:char(36);index"`
+	InitiatorID      uuid.UUID      `gorm:"type:char(36)"` // The owner or an admin acting on their behalf.
+	Status           TransferStatus `gorm:"size:16;index"`
+	ResolvedBy       *uuid.UUID     `gorm:"type:char(36)"`
+	ResolvedAt       *time.Time
+}
+
+func (t *CompanyTransfer) BeforeCreate(_ *gorm.DB) (err error) {
+	t.ID = uuid.New()
+	t.Status = TransferPending
+	companyID := t.CompanyID
+	t.PendingCompanyID = &companyID
+	return
+}
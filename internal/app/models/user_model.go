@@ -16,6 +16,7 @@ type User struct {
 	Name      string
 	Username  string    `gorm:"index; unique"` // Unique and Index Username which will be used for auth.
 	Password  string    `json:"-"`             // Hide password when json encoded.
+	Admin     bool      // Admins can run the privileged operations such as purging companies.
 	Companies []Company // Define a one-to-many relationship
 }
 
@@ -0,0 +1,81 @@
+// Package naming derives the unique keys and URL slugs of company names.
+package naming
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength is the longest slug derived from a name, suffixes included.
+const MaxSlugLength = 80
+
+// fallbackSlug is used for names without a single ASCII letter or digit.
+const fallbackSlug = "company"
+
+var folder = cases.Fold()
+
+// Key returns the form of a name uniqueness is checked on. Names that only differ
+// in case or in the Unicode representation of the same characters share a key.
+func Key(name string) string {
+	return norm.NFKC.String(folder.String(norm.NFKC.String(name)))
+}
+
+// Slug returns the URL-safe form of a name: lower-cased ASCII letters and digits separated
+// by single dashes. Accents are dropped, so "Café Müller" becomes "cafe-muller".
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFKD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining marks left over by the decomposition of accented letters
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	slug := b.String()
+	// leave room for the suffix that tells apart companies with the same slug
+	if len(slug) > MaxSlugLength-8 {
+		slug = strings.TrimRight(slug[:MaxSlugLength-8], "-")
+	}
+	if slug == "" {
+		return fallbackSlug
+	}
+
+	return slug
+}
+
+// WithSuffix returns the n-th candidate slug for a base slug, the first one is the base itself.
+func WithSuffix(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+
+	return base + "-" + strconv.Itoa(n)
+}
+
+// HasBase reports whether slug is one of the candidates WithSuffix returns for base.
+func HasBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(suffix)
+	return err == nil && n > 1 && WithSuffix(base, n) == slug
+}
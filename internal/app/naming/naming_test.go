@@ -0,0 +1,82 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		a, b     string
+		expEqual bool
+	}{
+		"case":                {a: "Acme", b: "ACME", expEqual: true},
+		"composed accent":     {a: "Café", b: "Café", expEqual: true},
+		"compatibility forms": {a: "ﬁne", b: "FINE", expEqual: true},
+		"sharp s":             {a: "Straße", b: "STRASSE", expEqual: true},
+		"different names":     {a: "Acme", b: "Acne", expEqual: false},
+		"accents matter":      {a: "Cafe", b: "Café", expEqual: false},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expEqual, Key(tt.a) == Key(tt.b))
+		})
+	}
+}
+
+func TestSlug(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		name    string
+		expSlug string
+	}{
+		"simple":             {name: "Acme", expSlug: "acme"},
+		"punctuation":        {name: "  Acme, Inc. ", expSlug: "acme-inc"},
+		"accents":            {name: "Café Müller", expSlug: "cafe-muller"},
+		"digits":             {name: "7-Eleven", expSlug: "7-eleven"},
+		"no ascii":           {name: "株式会社", expSlug: "company"},
+		"empty":              {name: "", expSlug: "company"},
+		"long name is cut":   {name: strings.Repeat("ab ", 40), expSlug: strings.TrimSuffix(strings.Repeat("ab-", 24), "-")},
+		"compatibility form": {name: "ﬁne", expSlug: "fine"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expSlug, Slug(tt.name))
+		})
+	}
+}
+
+func TestHasBase(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		slug    string
+		base    string
+		expBase bool
+	}{
+		"base":            {slug: "acme", base: "acme", expBase: true},
+		"suffix":          {slug: "acme-3", base: "acme", expBase: true},
+		"first suffix":    {slug: "acme-1", base: "acme", expBase: false},
+		"leading zero":    {slug: "acme-02", base: "acme", expBase: false},
+		"other word":      {slug: "acme-inc", base: "acme", expBase: false},
+		"different base":  {slug: "acne-2", base: "acme", expBase: false},
+		"base has digits": {slug: "acme-2", base: "acme-2", expBase: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expBase, HasBase(tt.slug, tt.base))
+		})
+	}
+	assert.Equal(t, "acme-2", WithSuffix("acme", 2))
+}
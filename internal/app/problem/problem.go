@@ -0,0 +1,122 @@
+// Package problem reports errors to clients as RFC 7807 problem details.
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iNDicat0r/company/internal/app/services"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// The codes of problems detected before reaching a service.
+const (
+	CodeMalformedRequest     = "malformed_request"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodePreconditionFailed   = "precondition_failed"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnauthenticated      = "unauthenticated"
+	CodeForbidden            = "forbidden"
+	CodeInternal             = "internal_error"
+)
+
+// Problem represents the details of an error, Code is a stable identifier clients can switch on.
+type Problem struct {
+	Type   string      `json:"type"`
+	Title  string      `json:"title"`
+	Status int         `json:"status"`
+	Detail string      `json:"detail,omitempty"`
+	Code   string      `json:"code"`
+	Errors []Violation `json:"errors,omitempty"`
+}
+
+// Violation is a validation rule broken by a field of the request, Field is its JSON path.
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// kinds maps the kinds of service errors to their status code and default code.
+var kinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{services.ErrInvalidRequest, http.StatusBadRequest, "invalid_request"},
+	{services.ErrUnauthenticated, http.StatusUnauthorized, CodeUnauthenticated},
+	{services.ErrForbidden, http.StatusForbidden, CodeForbidden},
+	{services.ErrNotFound, http.StatusNotFound, "not_found"},
+	{services.ErrConflict, http.StatusConflict, "conflict"},
+	{services.ErrValidation, http.StatusUnprocessableEntity, "validation_failed"},
+}
+
+// New returns the problem describing a service error. Errors of no known kind become an
+// internal error whose details are not exposed.
+func New(err error) Problem {
+	for _, k := range kinds {
+		if !errors.Is(err, k.kind) {
+			continue
+		}
+
+		code := k.code
+		var domainErr *services.Error
+		if errors.As(err, &domainErr) {
+			code = domainErr.Code
+		}
+		p := NewStatus(k.status, code, err.Error())
+		p.Errors = Violations(err, "")
+		return p
+	}
+
+	return NewStatus(http.StatusInternalServerError, CodeInternal, "")
+}
+
+// Violations returns the violations listed by a validation error, their field paths are
+// prefixed when the validated value is nested in the request. It returns nil for other errors.
+func Violations(err error, prefix string) []Violation {
+	var domainErr *services.Error
+	if !errors.As(err, &domainErr) || len(domainErr.Violations) == 0 {
+		return nil
+	}
+
+	violations := make([]Violation, 0, len(domainErr.Violations))
+	for _, v := range domainErr.Violations {
+		violations = append(violations, Violation{Field: prefix + v.Field, Message: v.Message})
+	}
+
+	return violations
+}
+
+// NewStatus returns a problem with the given status, code and detail.
+func NewStatus(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Write reports a service error as a problem, internal errors are attached to the context to be logged.
+func Write(c *gin.Context, err error) {
+	p := New(err)
+	if p.Status == http.StatusInternalServerError {
+		_ = c.Error(err)
+	}
+	Render(c, p)
+}
+
+// WriteStatus reports an error detected before reaching a service, like a malformed request.
+func WriteStatus(c *gin.Context, status int, code string, err error) {
+	Render(c, NewStatus(status, code, err.Error()))
+}
+
+// Render writes the problem as the response.
+func Render(c *gin.Context, p Problem) {
+	c.Header("Content-Type", ContentType)
+	c.JSON(p.Status, p)
+}
@@ -0,0 +1,72 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iNDicat0r/company/internal/app/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err      error
+		expected Problem
+	}{
+		"not found": {
+			err:      fmt.Errorf("%w: ca8fc620-509a-40ac-8cc0-525c37c9c4b9", services.ErrCompanyNotFound),
+			expected: NewStatus(http.StatusNotFound, "company_not_found", "company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+		},
+		"conflict": {
+			err:      services.ErrNameTaken,
+			expected: NewStatus(http.StatusConflict, "company_name_taken", "company name is taken"),
+		},
+		"validation": {
+			err:      services.ErrInvalidPatch,
+			expected: NewStatus(http.StatusUnprocessableEntity, "invalid_patch", "invalid patch"),
+		},
+		"invalid request": {
+			err:      services.ErrInvalidListQuery,
+			expected: NewStatus(http.StatusBadRequest, "invalid_list_query", "invalid list query"),
+		},
+		"unauthenticated": {
+			err:      services.ErrInvalidCredentials,
+			expected: NewStatus(http.StatusUnauthorized, "invalid_credentials", "wrong username/password combination"),
+		},
+		"kind without code": {
+			err:      fmt.Errorf("%w: not an admin", services.ErrForbidden),
+			expected: NewStatus(http.StatusForbidden, CodeForbidden, "forbidden: not an admin"),
+		},
+		"internal": {
+			err:      errors.New("connection refused"),
+			expected: NewStatus(http.StatusInternalServerError, CodeInternal, ""),
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, New(tt.err))
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	Write(c, errors.New("connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_error"}`, w.Body.String())
+	assert.Len(t, c.Errors, 1, "internal errors are kept to be logged")
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+)
+
+// SQLAttachmentRepository stores the metadata of the files attached to companies.
+type SQLAttachmentRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLAttachmentRepository creates a new sql attachment repository.
+func NewSQLAttachmentRepository(db *gorm.DB) (*SQLAttachmentRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLAttachmentRepository{
+		db: db,
+	}, nil
+}
+
+// List returns the attachments of a company, the latest first.
+func (ar *SQLAttachmentRepository) List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyAttachment, error) {
+	var attachments []models.CompanyAttachment
+	result := ar.db.WithContext(ctx).Where("company_id = ?", companyID).Order("created_at DESC, id ASC").Find(&attachments)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", result.Error)
+	}
+
+	return attachments, nil
+}
+
+// Find returns an attachment of a company.
+func (ar *SQLAttachmentRepository) Find(ctx context.Context, companyID, attachmentID uuid.UUID) (models.CompanyAttachment, error) {
+	var attachment models.CompanyAttachment
+	result := ar.db.WithContext(ctx).Where("id = ? AND company_id = ?", attachmentID, companyID).First(&attachment)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.CompanyAttachment{}, fmt.Errorf("failed to find attachment: %w", ErrNotFound)
+	}
+	if result.Error != nil {
+		return models.CompanyAttachment{}, fmt.Errorf("failed to find attachment: %w", result.Error)
+	}
+
+	return attachment, nil
+}
+
+// Save stores the metadata of an attachment of a live company.
+func (ar *SQLAttachmentRepository) Save(ctx context.Context, attachment models.CompanyAttachment) (models.CompanyAttachment, error) {
+	err := ar.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", attachment.CompanyID).First(&models.Company{}).Error; err != nil {
+			return companyError(err)
+		}
+		return tx.Create(&attachment).Error
+	})
+	if err != nil {
+		return models.CompanyAttachment{}, fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// Delete removes the metadata of an attachment of a company.
+func (ar *SQLAttachmentRepository) Delete(ctx context.Context, companyID, attachmentID uuid.UUID) error {
+	result := ar.db.WithContext(ctx).Where("id = ? AND company_id = ?", attachmentID, companyID).Delete(&models.CompanyAttachment{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete attachment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to delete attachment: %w", ErrNotFound)
+	}
+
+	return nil
+}
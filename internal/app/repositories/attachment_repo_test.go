@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLAttachmentRepository(t *testing.T) {
+	t.Parallel()
+	repo, err := NewSQLAttachmentRepository(nil)
+	assert.EqualError(t, err, "db is nil")
+	assert.Nil(t, repo)
+
+	repo, err = NewSQLAttachmentRepository(&gorm.DB{})
+	assert.NoError(t, err)
+	assert.NotNil(t, repo)
+}
+
+func TestSQLAttachmentRepository(t *testing.T) {
+	db := setupTestDB(t)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	repo, err := NewSQLAttachmentRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	companyID, err := companyRepo.Save(ctx, models.Company{Name: "Acme", Type: common.Corporations, UserID: owner})
+	assert.NoError(t, err)
+
+	logoID := uuid.New()
+	logo, err := repo.Save(ctx, models.CompanyAttachment{ID: logoID, CompanyID: companyID, Kind: models.AttachmentLogo, FileName: "logo.png", ContentType: "image/png", Size: 42, UploadedBy: owner})
+	assert.NoError(t, err)
+	assert.Equal(t, logoID, logo.ID, "the id chosen for the blob is kept")
+	_, err = repo.Save(ctx, models.CompanyAttachment{CompanyID: companyID, Kind: models.AttachmentDocument, FileName: "certificate.pdf", UploadedBy: owner})
+	assert.NoError(t, err)
+	_, err = repo.Save(ctx, models.CompanyAttachment{CompanyID: uuid.New(), Kind: models.AttachmentLogo})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	attachments, err := repo.List(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+
+	found, err := repo.Find(ctx, companyID, logoID)
+	assert.NoError(t, err)
+	assert.Equal(t, "logo.png", found.FileName)
+	_, err = repo.Find(ctx, uuid.New(), logoID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoError(t, repo.Delete(ctx, companyID, logoID))
+	assert.ErrorIs(t, repo.Delete(ctx, companyID, logoID), ErrNotFound)
+
+	// purging the company removes the metadata of its attachments
+	assert.NoError(t, companyRepo.Delete(ctx, owner, companyID, 0, ChildrenRestrict))
+	assert.NoError(t, companyRepo.Purge(ctx, companyID))
+	attachments, err = repo.List(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Empty(t, attachments)
+}
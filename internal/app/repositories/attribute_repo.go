@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+)
+
+// SQLAttributeRepository stores the custom attribute schemas and the values companies have.
+type SQLAttributeRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLAttributeRepository creates a new sql attribute repository.
+func NewSQLAttributeRepository(db *gorm.DB) (*SQLAttributeRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLAttributeRepository{
+		db: db,
+	}, nil
+}
+
+// ListSchemas returns every attribute schema ordered by name.
+func (ar *SQLAttributeRepository) ListSchemas(ctx context.Context) ([]models.AttributeSchema, error) {
+	var schemas []models.AttributeSchema
+	result := ar.db.WithContext(ctx).Order("name ASC").Find(&schemas)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list attribute schemas: %w", result.Error)
+	}
+
+	return schemas, nil
+}
+
+// FindSchema finds an attribute schema by name.
+func (ar *SQLAttributeRepository) FindSchema(ctx context.Context, name string) (models.AttributeSchema, error) {
+	var schema models.AttributeSchema
+	result := ar.db.WithContext(ctx).Where("name = ?", name).First(&schema)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.AttributeSchema{}, fmt.Errorf("failed to find attribute schema: %w", ErrNotFound)
+	}
+	if result.Error != nil {
+		return models.AttributeSchema{}, fmt.Errorf("failed to find attribute schema: %w", result.Error)
+	}
+
+	return schema, nil
+}
+
+// SaveSchema defines a new attribute, it fails with ErrAttributeExists when the name is taken.
+func (ar *SQLAttributeRepository) SaveSchema(ctx context.Context, schema models.AttributeSchema) (models.AttributeSchema, error) {
+	result := ar.db.WithContext(ctx).Create(&schema)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return models.AttributeSchema{}, fmt.Errorf("failed to save attribute schema: %w", ErrAttributeExists)
+	}
+	if result.Error != nil {
+		return models.AttributeSchema{}, fmt.Errorf("failed to save attribute schema: %w", result.Error)
+	}
+
+	return schema, nil
+}
+
+// UpdateSchema stores the description, required flag and constraints of an attribute schema,
+// the type of an attribute never changes.
+func (ar *SQLAttributeRepository) UpdateSchema(ctx context.Context, schema models.AttributeSchema) (models.AttributeSchema, error) {
+	var updated models.AttributeSchema
+	err := ar.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AttributeSchema{}).
+			Where("name = ?", schema.Name).
+			Select("Description", "Required", "Constraints").
+			Updates(&schema)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return tx.Where("name = ?", schema.Name).First(&updated).Error
+	})
+	if err != nil {
+		return models.AttributeSchema{}, fmt.Errorf("failed to update attribute schema: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteSchema removes an attribute schema along with the values companies have for it.
+func (ar *SQLAttributeRepository) DeleteSchema(ctx context.Context, name string) error {
+	err := ar.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("name = ?", name).Delete(&models.AttributeSchema{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return tx.Where("name = ?", name).Delete(&models.CompanyAttribute{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete attribute schema: %w", err)
+	}
+
+	return nil
+}
+
+// ListValues returns the attribute values of a company ordered by name.
+func (ar *SQLAttributeRepository) ListValues(ctx context.Context, companyID uuid.UUID) ([]models.CompanyAttribute, error) {
+	var values []models.CompanyAttribute
+	result := ar.db.WithContext(ctx).Where("company_id = ?", companyID).Order("name ASC").Find(&values)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list attribute values: %w", result.Error)
+	}
+
+	return values, nil
+}
+
+// ReplaceValues replaces every attribute value of a company with the given ones.
+func (ar *SQLAttributeRepository) ReplaceValues(ctx context.Context, companyID uuid.UUID, values []models.CompanyAttribute) error {
+	err := ar.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyAttribute{}).Error; err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+
+		for i := range values {
+			values[i].CompanyID = companyID
+		}
+		return tx.Create(&values).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace attribute values: %w", err)
+	}
+
+	return nil
+}
+
+// attributeColumns maps the attribute types to the column their values are stored in.
+var attributeColumns = map[models.AttributeType]string{
+	models.AttributeString: "string_value",
+	models.AttributeInt:    "int_value",
+	models.AttributeBool:   "bool_value",
+	models.AttributeDate:   "date_value",
+	models.AttributeEnum:   "string_value",
+}
+
+// attributeOperators maps the attribute operators to their sql comparison.
+var attributeOperators = map[AttributeOperator]string{
+	AttributeEqual: "=",
+	AttributeMin:   ">=",
+	AttributeMax:   "<=",
+}
+
+// attributed returns a subquery selecting the ids of the companies having a value for the attribute.
+func attributed(tx *gorm.DB, name string) *gorm.DB {
+	return tx.Session(&gorm.Session{NewDB: true}).Model(&models.CompanyAttribute{}).Select("company_id").Where("name = ?", name)
+}
+
+// attributeMatches returns a subquery selecting the ids of the companies meeting the condition.
+func attributeMatches(tx *gorm.DB, condition AttributeCondition) *gorm.DB {
+	comparison := fmt.Sprintf("%s %s ?", attributeColumns[condition.Type], attributeOperators[condition.Operator])
+	return attributed(tx, condition.Name).Where(comparison, condition.Value)
+}
+
+// attributeSortKey returns the expression ordering companies by the value they have for
+// the attribute and the variables it needs.
+func attributeSortKey(attribute AttributeRef) (string, []any) {
+	column := fmt.Sprintf(
+		"(SELECT %s FROM company_attributes WHERE company_attributes.company_id = companies.id AND company_attributes.name = ?)",
+		attributeColumns[attribute.Type],
+	)
+	return column, []any{attribute.Name}
+}
@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLAttributeRepository(t *testing.T) {
+	t.Parallel()
+	repo, err := NewSQLAttributeRepository(nil)
+	assert.EqualError(t, err, "db is nil")
+	assert.Nil(t, repo)
+
+	repo, err = NewSQLAttributeRepository(&gorm.DB{})
+	assert.NoError(t, err)
+	assert.NotNil(t, repo)
+}
+
+func TestSQLAttributeRepository_Schemas(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewSQLAttributeRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	maxLength := 16
+	vat := models.AttributeSchema{Name: "vat_number", Type: models.AttributeString, Constraints: models.AttributeConstraints{MaxLength: &maxLength}}
+	_, err = repo.SaveSchema(ctx, vat)
+	assert.NoError(t, err)
+	_, err = repo.SaveSchema(ctx, models.AttributeSchema{Name: "segment", Type: models.AttributeEnum, Constraints: models.AttributeConstraints{Options: []string{"smb"}}})
+	assert.NoError(t, err)
+	_, err = repo.SaveSchema(ctx, vat)
+	assert.ErrorIs(t, err, ErrAttributeExists)
+
+	schemas, err := repo.ListSchemas(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, schemas, 2)
+	assert.Equal(t, "segment", schemas[0].Name)
+	assert.Equal(t, []string{"smb"}, schemas[0].Constraints.Options)
+
+	updated, err := repo.UpdateSchema(ctx, models.AttributeSchema{Name: "vat_number", Type: models.AttributeInt, Description: "VAT number", Required: true})
+	assert.NoError(t, err)
+	assert.Equal(t, models.AttributeString, updated.Type, "the type never changes")
+	assert.Equal(t, "VAT number", updated.Description)
+	assert.True(t, updated.Required)
+	assert.Nil(t, updated.Constraints.MaxLength)
+
+	_, err = repo.UpdateSchema(ctx, models.AttributeSchema{Name: "website"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	companyID := uuid.New()
+	number := "DE123"
+	assert.NoError(t, repo.ReplaceValues(ctx, companyID, []models.CompanyAttribute{{Name: "vat_number", StringValue: &number}}))
+	assert.NoError(t, repo.DeleteSchema(ctx, "vat_number"))
+	assert.ErrorIs(t, repo.DeleteSchema(ctx, "vat_number"), ErrNotFound)
+	_, err = repo.FindSchema(ctx, "vat_number")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	values, err := repo.ListValues(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Empty(t, values, "the values of a deleted attribute are removed")
+}
+
+func TestSQLAttributeRepository_Values(t *testing.T) {
+	db := setupTestDB(t)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	repo, err := NewSQLAttributeRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	ids := map[string]uuid.UUID{}
+	for _, name := range []string{"Acme", "Globex", "Initech", "Umbrella"} {
+		id, err := companyRepo.Save(ctx, models.Company{Name: name, Type: common.Corporations, UserID: owner})
+		if err != nil {
+			t.Fatal("Failed to save company: ", err)
+		}
+		ids[name] = id
+	}
+
+	type values struct {
+		founded int64
+		listed  bool
+		yearEnd string
+	}
+	set := map[string]values{
+		"Acme":    {founded: 1947, listed: true, yearEnd: "2023-12-31"},
+		"Globex":  {founded: 1989, listed: false, yearEnd: "2023-06-30"},
+		"Initech": {founded: 1983, listed: true, yearEnd: "2023-03-31"},
+	}
+	for name, v := range set {
+		v := v
+		yearEnd, _ := time.Parse("2006-01-02", v.yearEnd)
+		err := repo.ReplaceValues(ctx, ids[name], []models.CompanyAttribute{
+			{Name: "founded", IntValue: &v.founded, UpdatedBy: owner},
+			{Name: "listed", BoolValue: &v.listed, UpdatedBy: owner},
+			{Name: "year_end", DateValue: &yearEnd, UpdatedBy: owner},
+		})
+		assert.NoError(t, err)
+	}
+
+	stored, err := repo.ListValues(ctx, ids["Acme"])
+	assert.NoError(t, err)
+	assert.Len(t, stored, 3)
+	assert.Equal(t, "founded", stored[0].Name)
+	assert.Equal(t, int64(1947), *stored[0].IntValue)
+
+	// values given to a new company are saved along with it
+	hooliFounded := int64(1999)
+	hooli, err := companyRepo.Save(ctx, models.Company{
+		Name:       "Hooli",
+		Type:       common.Corporations,
+		UserID:     owner,
+		Attributes: []models.CompanyAttribute{{Name: "founded", IntValue: &hooliFounded, UpdatedBy: owner}},
+	})
+	assert.NoError(t, err)
+	stored, err = repo.ListValues(ctx, hooli)
+	assert.NoError(t, err)
+	if assert.Len(t, stored, 1) {
+		assert.Equal(t, hooli, stored[0].CompanyID)
+		assert.Equal(t, int64(1999), *stored[0].IntValue)
+	}
+	assert.NoError(t, companyRepo.Delete(ctx, owner, hooli, 0, ChildrenRestrict))
+
+	founded := AttributeRef{Name: "founded", Type: models.AttributeInt}
+	listed := AttributeRef{Name: "listed", Type: models.AttributeBool}
+	yearEnd := AttributeRef{Name: "year_end", Type: models.AttributeDate}
+	cases := map[string]struct {
+		query    CompanyListQuery
+		expNames []string
+	}{
+		"equal": {
+			query:    CompanyListQuery{SortBy: SortByName, Filter: CompanyFilter{Attributes: []AttributeCondition{{AttributeRef: listed, Operator: AttributeEqual, Value: true}}}},
+			expNames: []string{"Acme", "Initech"},
+		},
+		"range": {
+			query: CompanyListQuery{SortBy: SortByName, Filter: CompanyFilter{Attributes: []AttributeCondition{
+				{AttributeRef: yearEnd, Operator: AttributeMin, Value: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)},
+				{AttributeRef: yearEnd, Operator: AttributeMax, Value: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)},
+			}}},
+			expNames: []string{"Acme", "Globex"},
+		},
+		"sort leaves out companies without a value": {
+			query:    CompanyListQuery{SortAttribute: &founded, Descending: true},
+			expNames: []string{"Globex", "Initech", "Acme"},
+		},
+		"sort and filter": {
+			query: CompanyListQuery{SortAttribute: &founded, Filter: CompanyFilter{Attributes: []AttributeCondition{
+				{AttributeRef: listed, Operator: AttributeEqual, Value: true},
+			}}},
+			expNames: []string{"Acme", "Initech"},
+		},
+	}
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			tt.query.Limit = 10
+			comps, err := companyRepo.List(ctx, tt.query)
+			assert.NoError(t, err)
+			names := make([]string, 0, len(comps))
+			for _, comp := range comps {
+				names = append(names, comp.Name)
+			}
+			assert.Equal(t, tt.expNames, names)
+		})
+	}
+
+	// walk the companies sorted by founding year one page at a time
+	var walked []uuid.UUID
+	query := CompanyListQuery{SortAttribute: &founded, Limit: 1}
+	for {
+		comps, err := companyRepo.List(ctx, query)
+		assert.NoError(t, err)
+		if len(comps) == 0 {
+			break
+		}
+		walked = append(walked, comps[0].ID)
+		values, err := repo.ListValues(ctx, comps[0].ID)
+		assert.NoError(t, err)
+		query.After = &CompanyCursor{SortValue: *values[0].IntValue, ID: comps[0].ID}
+	}
+	assert.Equal(t, []uuid.UUID{ids["Acme"], ids["Initech"], ids["Globex"]}, walked)
+
+	// replacing drops the values left out and purging a company removes its values
+	assert.NoError(t, repo.ReplaceValues(ctx, ids["Globex"], nil))
+	stored, err = repo.ListValues(ctx, ids["Globex"])
+	assert.NoError(t, err)
+	assert.Empty(t, stored)
+
+	assert.NoError(t, companyRepo.Delete(ctx, owner, ids["Acme"], 0, ChildrenRestrict))
+	assert.NoError(t, companyRepo.Purge(ctx, ids["Acme"]))
+	stored, err = repo.ListValues(ctx, ids["Acme"])
+	assert.NoError(t, err)
+	assert.Empty(t, stored)
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Ancestors returns the live parents of a company, the direct parent first, going up at most maxDepth levels.
+func (br *SQLCompanyRepository) Ancestors(ctx context.Context, companyID uuid.UUID, maxDepth int) ([]models.Company, error) {
+	var comp models.Company
+	result := br.db.WithContext(ctx).Where("id = ?", companyID).First(&comp)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find company: %w", companyError(result.Error))
+	}
+
+	var ancestors []models.Company
+	for comp.ParentID != nil && len(ancestors) < maxDepth {
+		var parent models.Company
+		result := br.db.WithContext(ctx).Where("id = ?", *comp.ParentID).Limit(1).Find(&parent)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to find ancestors: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			break
+		}
+		ancestors = append(ancestors, parent)
+		comp = parent
+	}
+
+	return ancestors, nil
+}
+
+// Children returns the live direct subsidiaries of a company ordered by name.
+func (br *SQLCompanyRepository) Children(ctx context.Context, companyID uuid.UUID) ([]models.Company, error) {
+	var children []models.Company
+	result := br.db.WithContext(ctx).Where("parent_id = ?", companyID).Order("name ASC, id ASC").Find(&children)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list children: %w", result.Error)
+	}
+
+	return children, nil
+}
+
+// Subtree returns a live company followed by its subsidiaries down to maxDepth levels below it,
+// level by level and ordered by name within a level. The company itself is at depth zero.
+func (br *SQLCompanyRepository) Subtree(ctx context.Context, companyID uuid.UUID, maxDepth int) ([]CompanyNode, error) {
+	var root models.Company
+	result := br.db.WithContext(ctx).Where("id = ?", companyID).First(&root)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find company: %w", companyError(result.Error))
+	}
+
+	nodes := []CompanyNode{{Company: root}}
+	seen := map[uuid.UUID]bool{root.ID: true}
+	level := []uuid.UUID{root.ID}
+	for depth := 1; depth <= maxDepth && len(level) > 0; depth++ {
+		var children []models.Company
+		result := br.db.WithContext(ctx).Where("parent_id IN ?", level).Order("name ASC, id ASC").Find(&children)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to list subtree: %w", result.Error)
+		}
+
+		level = level[:0]
+		for _, child := range children {
+			// guards against cycles written before parents were checked
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			nodes = append(nodes, CompanyNode{Company: child, Depth: depth})
+			level = append(level, child.ID)
+		}
+	}
+
+	return nodes, nil
+}
+
+// checkParentCycle makes sure the company is not among the ancestors of its parent. The ancestors
+// are locked while walking up so concurrent moves within the same group cannot create a cycle.
+func checkParentCycle(tx *gorm.DB, company models.Company) error {
+	seen := make(map[uuid.UUID]bool)
+	for parentID := company.ParentID; parentID != nil; {
+		if *parentID == company.ID {
+			return ErrParentCycle
+		}
+		// guards against cycles written before parents were checked
+		if seen[*parentID] {
+			return nil
+		}
+		seen[*parentID] = true
+
+		var parent models.Company
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", *parentID).Limit(1).Find(&parent)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		parentID = parent.ParentID
+	}
+
+	return nil
+}
+
+// releaseChildren applies the policy to the live subsidiaries of a company that is being deleted.
+// Promoted subsidiaries are moved to the parent of the company and a revision of each is recorded.
+func releaseChildren(tx *gorm.DB, actorID uuid.UUID, company models.Company, policy ChildrenPolicy) error {
+	var children []models.Company
+	if err := tx.Where("parent_id = ?", company.ID).Order("id ASC").Find(&children).Error; err != nil {
+		return err
+	}
+	if len(children) == 0 {
+		return nil
+	}
+
+	switch policy {
+	case ChildrenPromote:
+	case ChildrenRestrict, "":
+		return ErrHasChildren
+	default:
+		return fmt.Errorf("unknown children policy %q", policy)
+	}
+
+	for _, child := range children {
+		result := tx.Model(&child).Where("version = ?", child.Version).Updates(map[string]any{
+			"parent_id": company.ParentID,
+			"version":   gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if err := tx.Where("id = ?", child.ID).First(&child).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, models.RevisionUpdated, actorID, child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLCompanyRepository_Hierarchy(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	save := func(name string, parentID *uuid.UUID, employees int) uuid.UUID {
+		id, err := repo.Save(ctx, models.Company{Name: name, EmployeesAmount: employees, Type: common.Corporations, UserID: owner, ParentID: parentID})
+		if err != nil {
+			t.Fatal("Failed to save company: ", err)
+		}
+		return id
+	}
+	// Holding ─┬─ Beta ── Delta
+	//          └─ Gamma
+	holding := save("Holding", nil, 10)
+	beta := save("Beta", &holding, 20)
+	gamma := save("Gamma", &holding, 30)
+	delta := save("Delta", &beta, 40)
+
+	ancestors, err := repo.Ancestors(ctx, delta, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{beta, holding}, companyIDs(ancestors))
+	ancestors, err = repo.Ancestors(ctx, delta, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{beta}, companyIDs(ancestors))
+	_, err = repo.Ancestors(ctx, uuid.New(), 10)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	children, err := repo.Children(ctx, holding)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{beta, gamma}, companyIDs(children))
+
+	nodes, err := repo.Subtree(ctx, holding, 10)
+	assert.NoError(t, err)
+	depths := map[uuid.UUID]int{}
+	for _, node := range nodes {
+		depths[node.Company.ID] = node.Depth
+	}
+	assert.Equal(t, map[uuid.UUID]int{holding: 0, beta: 1, gamma: 1, delta: 2}, depths)
+	nodes, err = repo.Subtree(ctx, holding, 1)
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 3)
+
+	// the group is checked again when writing, whatever the caller looked at before
+	top, err := repo.FindByID(ctx, holding)
+	assert.NoError(t, err)
+	top.ParentID = &delta
+	_, err = repo.Update(ctx, owner, top, time.Time{})
+	assert.ErrorIs(t, err, ErrParentCycle)
+	top.ParentID = &holding
+	_, err = repo.Update(ctx, owner, top, time.Time{})
+	assert.ErrorIs(t, err, ErrParentCycle)
+	top, err = repo.FindByID(ctx, holding)
+	assert.NoError(t, err)
+	assert.Nil(t, top.ParentID)
+
+	err = repo.Delete(ctx, owner, beta, 0, ChildrenRestrict)
+	assert.ErrorIs(t, err, ErrHasChildren)
+
+	// promoted subsidiaries move up to the parent of the deleted company
+	err = repo.Delete(ctx, owner, beta, 0, ChildrenPromote)
+	assert.NoError(t, err)
+	moved, err := repo.FindByID(ctx, delta)
+	assert.NoError(t, err)
+	assert.Equal(t, &holding, moved.ParentID)
+	assert.Equal(t, 2, moved.Version)
+	children, err = repo.Children(ctx, holding)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{delta, gamma}, companyIDs(children))
+
+	// a company restored after its parent is gone becomes the top of its own group
+	err = repo.Delete(ctx, owner, gamma, 0, ChildrenRestrict)
+	assert.NoError(t, err)
+	err = repo.Delete(ctx, owner, holding, 0, ChildrenPromote)
+	assert.NoError(t, err)
+	restored, err := repo.Restore(ctx, owner, gamma)
+	assert.NoError(t, err)
+	assert.Nil(t, restored.ParentID)
+	moved, err = repo.FindByID(ctx, delta)
+	assert.NoError(t, err)
+	assert.Nil(t, moved.ParentID)
+
+	// purging a parent detaches the deleted companies still pointing at it
+	err = repo.Purge(ctx, holding)
+	assert.NoError(t, err)
+	var orphans int64
+	db.Unscoped().Model(&models.Company{}).Where("parent_id = ?", holding).Count(&orphans)
+	assert.Zero(t, orphans)
+}
+
+func companyIDs(companies []models.Company) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(companies))
+	for _, company := range companies {
+		ids = append(ids, company.ID)
+	}
+	return ids
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+)
+
+// Transition moves a company to the status the transition leads to and records it, the company
+// has to still be at the version its current status was read at, otherwise ErrVersionConflict
+// is returned. Registered follows the new status.
+func (br *SQLCompanyRepository) Transition(ctx context.Context, version int, transition models.CompanyTransition) (models.CompanyTransition, models.Company, error) {
+	var comp models.Company
+	err := br.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Company{}).
+			Where("id = ?", transition.CompanyID).
+			Where("version = ?", version).
+			Updates(map[string]any{
+				"status":     transition.To,
+				"registered": transition.To.Registered(),
+				"version":    gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		if err := withLocations(tx).Where("id = ?", transition.CompanyID).First(&comp).Error; err != nil {
+			return companyError(err)
+		}
+		if err := recordRevision(tx, models.RevisionTransitioned, transition.ActorID, comp); err != nil {
+			return err
+		}
+
+		transition.Version = comp.Version
+		return tx.Create(&transition).Error
+	})
+	if err != nil {
+		return models.CompanyTransition{}, models.Company{}, fmt.Errorf("failed to transition company: %w", err)
+	}
+
+	return transition, comp, nil
+}
+
+// ListTransitions returns the status changes of a company, oldest first.
+func (br *SQLCompanyRepository) ListTransitions(ctx context.Context, companyID uuid.UUID) ([]models.CompanyTransition, error) {
+	var transitions []models.CompanyTransition
+	result := br.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Order("version ASC").
+		Find(&transitions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list transitions: %w", result.Error)
+	}
+
+	return transitions, nil
+}
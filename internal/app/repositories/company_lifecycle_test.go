@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLCompanyRepository_Transition(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	revisionRepo, err := NewSQLRevisionRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	draftID, err := repo.Save(ctx, models.Company{Name: "Acme", Type: common.Corporations, UserID: owner})
+	assert.NoError(t, err)
+	registeredID, err := repo.Save(ctx, models.Company{Name: "Globex", Registered: true, Type: common.Corporations, UserID: owner})
+	assert.NoError(t, err)
+
+	draft, err := repo.FindByID(ctx, draftID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusDraft, draft.Status)
+	registered, err := repo.FindByID(ctx, registeredID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusActive, registered.Status, "companies created registered start active")
+
+	suspension := models.CompanyTransition{
+		CompanyID: registeredID,
+		Kind:      models.TransitionSuspended,
+		From:      models.StatusActive,
+		To:        models.StatusSuspended,
+		Reason:    "annual accounts overdue",
+		ActorID:   owner,
+	}
+	transition, comp, err := repo.Transition(ctx, 1, suspension)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, transition.ID)
+	assert.False(t, transition.CreatedAt.IsZero())
+	assert.Equal(t, 2, transition.Version)
+	assert.Equal(t, models.StatusSuspended, comp.Status)
+	assert.True(t, comp.Registered, "suspended companies stay on the register")
+	assert.Equal(t, 2, comp.Version)
+
+	_, _, err = repo.Transition(ctx, 1, suspension)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	_, comp, err = repo.Transition(ctx, 2, models.CompanyTransition{CompanyID: registeredID, Kind: models.TransitionDissolved, From: models.StatusSuspended, To: models.StatusDissolved, Reason: "wound up", ActorID: owner})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusDissolved, comp.Status)
+	assert.False(t, comp.Registered)
+
+	transitions, err := repo.ListTransitions(ctx, registeredID)
+	assert.NoError(t, err)
+	if assert.Len(t, transitions, 2) {
+		assert.Equal(t, models.TransitionSuspended, transitions[0].Kind)
+		assert.Equal(t, "annual accounts overdue", transitions[0].Reason)
+		assert.Equal(t, models.TransitionDissolved, transitions[1].Kind)
+		assert.Equal(t, 3, transitions[1].Version)
+	}
+
+	revisions, err := revisionRepo.List(ctx, registeredID)
+	assert.NoError(t, err)
+	if assert.Len(t, revisions, 3) {
+		assert.Equal(t, models.RevisionTransitioned, revisions[2].Action)
+		assert.Equal(t, models.StatusDissolved, revisions[2].Snapshot.Status)
+	}
+
+	transitions, err = repo.ListTransitions(ctx, draftID)
+	assert.NoError(t, err)
+	assert.Empty(t, transitions)
+}
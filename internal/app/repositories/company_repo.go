@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/naming"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// maxSlugClaims is the number of candidates tried when other companies keep claiming them concurrently.
+const maxSlugClaims = 5
+
 // SQLCompanyRepository implements the company storage, querying and db related logic.
 type SQLCompanyRepository struct {
 	db *gorm.DB
@@ -29,44 +35,594 @@ func NewSQLCompanyRepository(db *gorm.DB) (*SQLCompanyRepository, error) {
 // FindByID returns a company by id.
 func (br *SQLCompanyRepository) FindByID(ctx context.Context, id uuid.UUID) (models.Company, error) {
 	var comp models.Company
-	result := br.db.WithContext(ctx).Where("id = ?", id).First(&comp)
+	result := withLocations(br.db.WithContext(ctx)).Where("id = ?", id).First(&comp)
+	if result.Error != nil {
+		return models.Company{}, fmt.Errorf("failed to find company: %w", companyError(result.Error))
+	}
+
+	return comp, nil
+}
+
+// FindBySlug returns the live company that has or used to have the slug.
+// The slug of the returned company differs from the requested one when it was renamed since.
+func (br *SQLCompanyRepository) FindBySlug(ctx context.Context, slug string) (models.Company, error) {
+	var comp models.Company
+	result := withLocations(br.db.WithContext(ctx)).
+		Joins("JOIN company_slugs ON company_slugs.company_id = companies.id").
+		Where("company_slugs.slug = ?", slug).
+		First(&comp)
 	if result.Error != nil {
-		return models.Company{}, fmt.Errorf("failed to find company: %w", result.Error)
+		return models.Company{}, fmt.Errorf("failed to find company: %w", companyError(result.Error))
 	}
 
 	return comp, nil
 }
 
+// List returns a page of companies matching the query using keyset pagination.
+func (br *SQLCompanyRepository) List(ctx context.Context, query CompanyListQuery) ([]models.Company, error) {
+	column, ok := companySortColumns[query.SortBy]
+	var columnVars []any
+	if query.SortAttribute != nil {
+		column, columnVars = attributeSortKey(*query.SortAttribute)
+	} else if !ok {
+		return nil, fmt.Errorf("unknown sort field %q", query.SortBy)
+	}
+
+	direction, cmp := "ASC", ">"
+	if query.Descending {
+		direction, cmp = "DESC", "<"
+	}
+
+	tx := br.db.WithContext(ctx)
+	if query.Deleted {
+		tx = tx.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	tx = applyCompanyFilter(tx, query.Filter)
+	if query.SortAttribute != nil {
+		tx = tx.Where("id IN (?)", attributed(tx, query.SortAttribute.Name))
+	}
+	if query.After != nil {
+		vars := append(append([]any{}, columnVars...), query.After.SortValue)
+		vars = append(append(vars, columnVars...), query.After.SortValue, query.After.ID)
+		tx = tx.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, cmp), vars...)
+	}
+
+	var comps []models.Company
+	order := clause.OrderBy{Expression: clause.Expr{SQL: fmt.Sprintf("%s %s, id %s", column, direction, direction), Vars: columnVars}}
+	result := tx.Clauses(order).Limit(query.Limit).Find(&comps)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", result.Error)
+	}
+
+	return comps, nil
+}
+
+// Export reads every company matching the filter in chunks ordered by id, the callback is
+// called once per chunk and only one chunk is held in memory at a time.
+func (br *SQLCompanyRepository) Export(ctx context.Context, filter CompanyFilter, chunkSize int, fn func([]models.Company) error) error {
+	var chunk []models.Company
+	result := applyCompanyFilter(br.db.WithContext(ctx), filter).FindInBatches(&chunk, chunkSize, func(_ *gorm.DB, _ int) error {
+		return fn(chunk)
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to export companies: %w", result.Error)
+	}
+
+	return nil
+}
+
+// companySortColumns whitelists the columns a listing can be ordered by.
+var companySortColumns = map[CompanySortField]string{
+	SortByCreatedAt:       "created_at",
+	SortByUpdatedAt:       "updated_at",
+	SortByName:            "name",
+	SortByEmployeesAmount: "employees_amount",
+}
+
+// applyCompanyFilter adds the where clauses of the filter to the query.
+func applyCompanyFilter(tx *gorm.DB, filter CompanyFilter) *gorm.DB {
+	if len(filter.Types) > 0 {
+		tx = tx.Where("type IN ?", filter.Types)
+	}
+	if filter.Registered != nil {
+		tx = tx.Where("registered = ?", *filter.Registered)
+	}
+	if filter.MinEmployees != nil {
+		tx = tx.Where("employees_amount >= ?", *filter.MinEmployees)
+	}
+	if filter.MaxEmployees != nil {
+		tx = tx.Where("employees_amount <= ?", *filter.MaxEmployees)
+	}
+	if filter.UserID != nil {
+		tx = tx.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.MemberID != nil {
+		tx = tx.Where("id IN (?)", memberships(tx, *filter.MemberID))
+	}
+	if filter.CreatedAfter != nil {
+		tx = tx.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		tx = tx.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.UpdatedAfter != nil {
+		tx = tx.Where("updated_at >= ?", *filter.UpdatedAfter)
+	}
+	if filter.UpdatedBefore != nil {
+		tx = tx.Where("updated_at < ?", *filter.UpdatedBefore)
+	}
+	if len(filter.TagsAll) > 0 {
+		tags := tagNames(filter.TagsAll)
+		tx = tx.Where("id IN (?)", tagged(tx, tags).Group("company_id").Having("COUNT(*) = ?", len(tags)))
+	}
+	if len(filter.TagsAny) > 0 {
+		tx = tx.Where("id IN (?)", tagged(tx, tagNames(filter.TagsAny)))
+	}
+	if len(filter.TagsNone) > 0 {
+		tx = tx.Where("id NOT IN (?)", tagged(tx, tagNames(filter.TagsNone)))
+	}
+	for _, condition := range filter.Attributes {
+		tx = tx.Where("id IN (?)", attributeMatches(tx, condition))
+	}
+
+	return tx
+}
+
+// memberships returns a subquery selecting the ids of the companies the user is a member of.
+func memberships(tx *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return tx.Session(&gorm.Session{NewDB: true}).Model(&models.CompanyMember{}).Select("company_id").Where("user_id = ?", userID)
+}
+
+// tagged returns a subquery selecting the ids of the companies having one of the tags.
+func tagged(tx *gorm.DB, names []string) *gorm.DB {
+	return tx.Session(&gorm.Session{NewDB: true}).Model(&models.CompanyTag{}).Select("company_id").Where("tag_name IN ?", names)
+}
+
+// tagNames returns the distinct keys of the tags, tags are matched ignoring case.
+func tagNames(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name := naming.Key(tag)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // Save a company into db.
 func (br *SQLCompanyRepository) Save(ctx context.Context, company models.Company) (uuid.UUID, error) {
-	result := br.db.WithContext(ctx).Create(&company)
-	if result.Error != nil {
-		return uuid.UUID{}, fmt.Errorf("failed to save company: %w", result.Error)
+	err := br.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createCompany(tx, &company)
+	})
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("failed to save company: %w", err)
 	}
 
 	return company.ID, nil
 }
 
-// Delete a company from db.
-func (br *SQLCompanyRepository) Delete(ctx context.Context, userID, companyID uuid.UUID) error {
+// SaveBatch saves companies in a single transaction, each one behind its own savepoint.
+// It returns the error of every company that could not be saved. When atomic is set the
+// first failure rolls back the whole batch and ErrBatchAborted is returned.
+func (br *SQLCompanyRepository) SaveBatch(ctx context.Context, companies []models.Company, atomic bool) ([]error, error) {
+	errs := make([]error, len(companies))
+	err := br.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range companies {
+			errs[i] = tx.Transaction(func(tx *gorm.DB) error {
+				return createCompany(tx, &companies[i])
+			})
+			if errs[i] != nil && atomic {
+				return ErrBatchAborted
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errs, fmt.Errorf("failed to save companies: %w", err)
+	}
+
+	return errs, nil
+}
+
+// createCompany inserts a company along with its attribute values, slug, owner, first revision,
+// first employee count and search index entries.
+func createCompany(tx *gorm.DB, company *models.Company) error {
+	slug, _, err := findFreeSlug(tx, uuid.Nil, naming.Slug(company.Name))
+	if err != nil {
+		return err
+	}
+	company.Slug = slug
+
+	if err := tx.Create(company).Error; err != nil {
+		return companyError(err)
+	}
+	claimed, err := claimSlug(tx, company.ID, naming.Slug(company.Name), slug)
+	if err != nil {
+		return err
+	}
+	if claimed != slug {
+		if err := tx.Model(company).UpdateColumn("slug", claimed).Error; err != nil {
+			return fmt.Errorf("failed to update slug: %w", err)
+		}
+	}
+	if err := saveMember(tx, ownerMember(*company)); err != nil {
+		return err
+	}
+	if err := recordRevision(tx, models.RevisionCreated, company.UserID, *company); err != nil {
+		return err
+	}
+	if err := recordEmployeeCount(tx, company.UserID, *company, company.CreatedAt); err != nil {
+		return err
+	}
+	return indexCompany(tx, *company)
+}
+
+// Delete soft deletes a company on behalf of the actor, a non zero version must match the stored one.
+// The name of a deleted company is released so it can be used by another company,
+// its pending ownership transfer is cancelled and its subsidiaries are handled by the policy.
+func (br *SQLCompanyRepository) Delete(ctx context.Context, actorID, companyID uuid.UUID, version int, children ChildrenPolicy) error {
 	var comp models.Company
-	result := br.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", companyID).First(&comp)
+	result := br.db.WithContext(ctx).Where("id = ?", companyID).First(&comp)
 	if result.Error != nil {
-		return fmt.Errorf("failed to find company: %w", result.Error)
+		return fmt.Errorf("failed to find company: %w", companyError(result.Error))
+	}
+
+	if version != 0 && comp.Version != version {
+		return fmt.Errorf("failed to delete a company: %w", ErrVersionConflict)
 	}
 
-	if err := br.db.Delete(&comp).Error; err != nil {
+	err := br.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := releaseChildren(tx, actorID, comp, children); err != nil {
+			return err
+		}
+
+		result := tx.Model(&comp).Where("version = ?", comp.Version).Updates(map[string]any{
+			"deleted_at":  time.Now(),
+			"active_name": nil,
+			"version":     gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		if err := tx.Unscoped().Where("id = ?", comp.ID).First(&comp).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, models.RevisionDeleted, actorID, comp); err != nil {
+			return err
+		}
+		if err := cancelPendingTransfers(tx, comp.ID, actorID); err != nil {
+			return err
+		}
+		return unindexCompany(tx, comp.ID)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to delete a company: %w", err)
 	}
 
 	return nil
 }
 
-// Delete a company from db.
-func (br *SQLCompanyRepository) Update(ctx context.Context, company models.Company) (models.Company, error) {
-	result := br.db.WithContext(ctx).Save(&company)
-	if result.Error != nil {
-		return models.Company{}, fmt.Errorf("failed to update company: %w", result.Error)
+// Restore brings back a soft deleted company on behalf of the actor. It fails with ErrNameTaken
+// when a live company is using the name of the deleted one. A company whose parent
+// is no longer live comes back at the top of its own group.
+func (br *SQLCompanyRepository) Restore(ctx context.Context, actorID, companyID uuid.UUID) (models.Company, error) {
+	var comp models.Company
+	err := br.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Where("id = ?", companyID).
+			Where("deleted_at IS NOT NULL").
+			First(&comp)
+		if result.Error != nil {
+			return fmt.Errorf("failed to find deleted company: %w", companyError(result.Error))
+		}
+
+		var taken int64
+		if err := tx.Model(&models.Company{}).Where("active_name = ?", naming.Key(comp.Name)).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return ErrNameTaken
+		}
+
+		updates := map[string]any{
+			"deleted_at":  nil,
+			"active_name": naming.Key(comp.Name),
+			"version":     gorm.Expr("version + 1"),
+		}
+		if comp.ParentID != nil {
+			var parents int64
+			if err := tx.Model(&models.Company{}).Where("id = ?", *comp.ParentID).Count(&parents).Error; err != nil {
+				return err
+			}
+			if parents == 0 {
+				updates["parent_id"] = nil
+			}
+		}
+
+		result = tx.Unscoped().Model(&comp).Where("version = ?", comp.Version).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		if err := tx.Where("id = ?", comp.ID).First(&comp).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, models.RevisionRestored, actorID, comp); err != nil {
+			return err
+		}
+		return indexCompany(tx, comp)
+	})
+	if err != nil {
+		return models.Company{}, fmt.Errorf("failed to restore company: %w", err)
+	}
+
+	return comp, nil
+}
+
+// Purge permanently removes a soft deleted company along with its revision history, members,
+// transfers, tags, transitions and employee counts, its slugs are released so other companies can use them.
+func (br *SQLCompanyRepository) Purge(ctx context.Context, companyID uuid.UUID) error {
+	err := br.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Where("id = ?", companyID).
+			Where("deleted_at IS NOT NULL").
+			Delete(&models.Company{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanySlug{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyTransfer{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyAttribute{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyLocation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyAttachment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanyTransition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", companyID).Delete(&models.EmployeeCount{}).Error; err != nil {
+			return err
+		}
+		// only deleted companies can still point at it, they are restored without a parent anyway
+		if err := tx.Unscoped().Model(&models.Company{}).Where("parent_id = ?", companyID).UpdateColumn("parent_id", nil).Error; err != nil {
+			return err
+		}
+		return unindexCompany(tx, companyID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge company: %w", err)
+	}
+
+	return nil
+}
+
+// Update a company in db on behalf of the actor. The write only happens if the stored version
+// is still the one the company was read at, otherwise ErrVersionConflict is returned.
+// A rename gives the company a new slug, the previous one keeps pointing at it. A changed employees
+// amount is recorded as effective from the day of employeesOn, the day of the update when it is zero.
+// ErrParentCycle is returned when the company would become a subsidiary of itself.
+func (br *SQLCompanyRepository) Update(ctx context.Context, actorID uuid.UUID, company models.Company, employeesOn time.Time) (models.Company, error) {
+	if employeesOn.IsZero() {
+		employeesOn = time.Now()
+	}
+	expected := company.Version
+	company.Version++
+	key := naming.Key(company.Name)
+	company.ActiveName = &key
+
+	err := br.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkParentCycle(tx, company); err != nil {
+			return err
+		}
+		if err := assignSlug(tx, &company); err != nil {
+			return err
+		}
+
+		result := tx.Model(&company).
+			Where("version = ?", expected).
+			Select("*").
+			Omit("ID", "CreatedAt", "DeletedAt", "Locations", "Attributes").
+			Updates(&company)
+		if result.Error != nil {
+			return companyError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if err := recordRevision(tx, models.RevisionUpdated, actorID, company); err != nil {
+			return err
+		}
+		if err := recordEmployeeCount(tx, actorID, company, employeesOn); err != nil {
+			return err
+		}
+		return indexCompany(tx, company)
+	})
+	if err != nil {
+		return models.Company{}, fmt.Errorf("failed to update company: %w", err)
 	}
 	return company, nil
 }
+
+// assignSlug gives the company a slug derived from its name unless the current one already is.
+func assignSlug(tx *gorm.DB, company *models.Company) error {
+	base := naming.Slug(company.Name)
+	if company.Slug != "" && naming.HasBase(company.Slug, base) {
+		return nil
+	}
+
+	slug, owned, err := findFreeSlug(tx, company.ID, base)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		if slug, err = claimSlug(tx, company.ID, base, slug); err != nil {
+			return err
+		}
+	}
+	company.Slug = slug
+
+	return nil
+}
+
+// findFreeSlug returns the first candidate of the base slug that no other company holds,
+// and whether the company already holds it from before a rename. The skipped candidates are
+// taken as held by another company.
+func findFreeSlug(tx *gorm.DB, companyID uuid.UUID, base string, skipped ...string) (string, bool, error) {
+	var taken []models.CompanySlug
+	// slugs are made of letters, digits and dashes, so the base cannot contain LIKE wildcards
+	result := tx.Where("slug = ? OR slug LIKE ?", base, base+"-%").Find(&taken)
+	if result.Error != nil {
+		return "", false, fmt.Errorf("failed to find slugs: %w", result.Error)
+	}
+
+	holders := make(map[string]uuid.UUID, len(taken)+len(skipped))
+	for _, s := range taken {
+		holders[s.Slug] = s.CompanyID
+	}
+	for _, slug := range skipped {
+		holders[slug] = uuid.Nil
+	}
+	for n := 1; ; n++ {
+		slug := naming.WithSuffix(base, n)
+		holder, ok := holders[slug]
+		if !ok {
+			return slug, false, nil
+		}
+		if companyID != uuid.Nil && holder == companyID {
+			return slug, true, nil
+		}
+	}
+}
+
+// claimSlug records a free candidate of the base slug for a company and returns the slug claimed.
+// When another company claims the candidate concurrently the next free one is tried, after
+// maxSlugClaims attempts ErrVersionConflict is returned.
+func claimSlug(tx *gorm.DB, companyID uuid.UUID, base, slug string) (string, error) {
+	var lost []string
+	for {
+		// not passed through companyError, a duplicate slug is a concurrent claim and not a taken name
+		err := tx.Create(&models.CompanySlug{Slug: slug, CompanyID: companyID}).Error
+		switch {
+		case err == nil:
+			return slug, nil
+		case !errors.Is(err, gorm.ErrDuplicatedKey):
+			return "", fmt.Errorf("failed to claim slug %q: %w", slug, err)
+		}
+
+		lost = append(lost, slug)
+		if len(lost) == maxSlugClaims {
+			return "", fmt.Errorf("failed to claim slug %q: %w", slug, ErrVersionConflict)
+		}
+		var owned bool
+		slug, owned, err = findFreeSlug(tx, companyID, base, lost...)
+		if err != nil {
+			return "", err
+		}
+		if owned {
+			return slug, nil
+		}
+	}
+}
+
+// Backfill brings companies created before names were compared by their key, had slugs,
+// members or a status up to date, the status of those follows whether they are registered.
+// It fails with ErrNameTaken when two live companies have names that now share a key.
+func (br *SQLCompanyRepository) Backfill(ctx context.Context) error {
+	var chunk []models.Company
+	result := br.db.WithContext(ctx).Unscoped().FindInBatches(&chunk, 100, func(_ *gorm.DB, _ int) error {
+		for _, comp := range chunk {
+			err := br.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				owner := ownerMember(comp)
+				result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&owner)
+				if result.Error != nil {
+					return result.Error
+				}
+
+				updates := map[string]any{}
+				if !comp.DeletedAt.Valid {
+					updates["active_name"] = naming.Key(comp.Name)
+				}
+				if comp.Slug == "" {
+					slug, _, err := findFreeSlug(tx, comp.ID, naming.Slug(comp.Name))
+					if err != nil {
+						return err
+					}
+					if slug, err = claimSlug(tx, comp.ID, naming.Slug(comp.Name), slug); err != nil {
+						return err
+					}
+					updates["slug"] = slug
+				}
+				if comp.Status == "" {
+					updates["status"] = models.InitialStatus(comp.Registered)
+				}
+				if len(updates) == 0 {
+					return nil
+				}
+				// written without hooks so neither the version nor the update time changes
+				return companyError(tx.Unscoped().Model(&comp).UpdateColumns(updates).Error)
+			})
+			if err != nil {
+				return fmt.Errorf("company %s: %w", comp.ID, err)
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill companies: %w", result.Error)
+	}
+
+	return nil
+}
+
+// ownerMember returns the membership of the user a company belongs to.
+func ownerMember(company models.Company) models.CompanyMember {
+	return models.CompanyMember{
+		CompanyID: company.ID,
+		UserID:    company.UserID,
+		Role:      models.RoleOwner,
+		GrantedBy: company.UserID,
+	}
+}
+
+// companyError converts the gorm errors of company queries callers have to tell apart.
+func companyError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		// the name of live companies is the only unique column besides the id
+		return ErrNameTaken
+	}
+
+	return err
+}
@@ -2,7 +2,10 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/iNDicat0r/company/common"
@@ -108,8 +111,8 @@ func TestSQLCompanyRepository_Delete(t *testing.T) {
 	db.Create(&company)
 
 	id := company.ID
-	userID := uuid.New()
-	err = repo.Delete(context.Background(), userID, id)
+	userID := company.UserID
+	err = repo.Delete(context.Background(), userID, id, 0, ChildrenRestrict)
 	if err != nil {
 		t.Fatalf("Delete failed: %v", err)
 	}
@@ -133,7 +136,7 @@ func TestSQLCompanyRepository_Update(t *testing.T) {
 	db.Create(&company)
 
 	company.Name = "Updated Company"
-	updatedCompany, err := repo.Update(context.Background(), company)
+	updatedCompany, err := repo.Update(context.Background(), company.UserID, company, time.Time{})
 	if err != nil {
 		t.Fatalf("Update failed: %v", err)
 	}
@@ -141,3 +144,406 @@ func TestSQLCompanyRepository_Update(t *testing.T) {
 		t.Fatalf("Expected updated company name %s, but got %s", company.Name, updatedCompany.Name)
 	}
 }
+
+func TestSQLCompanyRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	ids := make([]uuid.UUID, 0, 5)
+	for i, amount := range []int{10, 20, 30, 40, 50} {
+		company := models.Company{
+			Name:            fmt.Sprintf("Company %d", i),
+			EmployeesAmount: amount,
+			Registered:      i%2 == 0,
+			Type:            common.Corporations,
+			UserID:          owner,
+		}
+		if i == 4 {
+			company.Type = common.NonProfit
+			company.UserID = uuid.New()
+		}
+		if err := db.Create(&company).Error; err != nil {
+			t.Fatalf("Failed to create company: %v", err)
+		}
+		ids = append(ids, company.ID)
+	}
+	viewer := uuid.New()
+	for _, id := range []uuid.UUID{ids[1], ids[3]} {
+		db.Create(&models.CompanyMember{CompanyID: id, UserID: viewer, Role: models.RoleViewer, GrantedBy: owner})
+	}
+
+	registered := true
+	minEmployees, maxEmployees := 20, 50
+	cases := map[string]struct {
+		query    CompanyListQuery
+		expNames []string
+	}{
+		"sorted by employees": {
+			query:    CompanyListQuery{SortBy: SortByEmployeesAmount, Limit: 10},
+			expNames: []string{"Company 0", "Company 1", "Company 2", "Company 3", "Company 4"},
+		},
+		"descending with limit": {
+			query:    CompanyListQuery{SortBy: SortByEmployeesAmount, Descending: true, Limit: 2},
+			expNames: []string{"Company 4", "Company 3"},
+		},
+		"after cursor": {
+			query: CompanyListQuery{
+				SortBy: SortByName,
+				Limit:  10,
+				After:  &CompanyCursor{SortValue: "Company 2", ID: ids[2]},
+			},
+			expNames: []string{"Company 3", "Company 4"},
+		},
+		"filtered": {
+			query: CompanyListQuery{
+				SortBy: SortByName,
+				Limit:  10,
+				Filter: CompanyFilter{
+					Types:        []common.Type{common.Corporations},
+					Registered:   &registered,
+					MinEmployees: &minEmployees,
+					MaxEmployees: &maxEmployees,
+					UserID:       &owner,
+				},
+			},
+			expNames: []string{"Company 2"},
+		},
+		"member of": {
+			query:    CompanyListQuery{SortBy: SortByName, Limit: 10, Filter: CompanyFilter{MemberID: &viewer}},
+			expNames: []string{"Company 1", "Company 3"},
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			comps, err := repo.List(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			names := make([]string, 0, len(comps))
+			for _, comp := range comps {
+				names = append(names, comp.Name)
+			}
+			assert.Equal(t, tt.expNames, names)
+		})
+	}
+}
+
+func TestSQLCompanyRepository_UpdateVersionConflict(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	company := models.Company{
+		Name:            "Test Company",
+		EmployeesAmount: 22,
+		Registered:      true,
+		Type:            common.Corporations,
+		UserID:          uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9"),
+	}
+	db.Create(&company)
+	assert.Equal(t, 1, company.Version)
+
+	// two writers read the same version
+	first, second := company, company
+	first.Name = "First"
+	second.Name = "Second"
+
+	updated, err := repo.Update(context.Background(), first.UserID, first, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+
+	_, err = repo.Update(context.Background(), second.UserID, second, time.Time{})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	stored, err := repo.FindByID(context.Background(), company.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "First", stored.Name)
+	assert.Equal(t, 2, stored.Version)
+
+	err = repo.Delete(context.Background(), company.UserID, company.ID, 1, ChildrenRestrict)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	err = repo.Delete(context.Background(), company.UserID, company.ID, 2, ChildrenRestrict)
+	assert.NoError(t, err)
+}
+
+func TestSQLCompanyRepository_Trash(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	company := models.Company{
+		Name:            "Test Company",
+		EmployeesAmount: 22,
+		Registered:      true,
+		Type:            common.Corporations,
+		UserID:          owner,
+	}
+	id, err := repo.Save(ctx, company)
+	assert.NoError(t, err)
+
+	_, err = repo.Save(ctx, company)
+	assert.Error(t, err, "names of live companies are unique")
+
+	err = repo.Delete(ctx, owner, id, 0, ChildrenRestrict)
+	assert.NoError(t, err)
+
+	deleted, err := repo.List(ctx, CompanyListQuery{Deleted: true, SortBy: SortByName, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, deleted, 1)
+	assert.Equal(t, id, deleted[0].ID)
+
+	// the name of a deleted company can be reused
+	reusedID, err := repo.Save(ctx, company)
+	assert.NoError(t, err)
+
+	_, err = repo.Restore(ctx, owner, id)
+	assert.ErrorIs(t, err, ErrNameTaken)
+
+	err = repo.Delete(ctx, owner, reusedID, 0, ChildrenRestrict)
+	assert.NoError(t, err)
+
+	_, err = repo.Restore(ctx, owner, uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// the service checks the actor is an owner, any of them can restore
+	restored, err := repo.Restore(ctx, uuid.New(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, restored.ID)
+	assert.False(t, restored.DeletedAt.Valid)
+	assert.Equal(t, 3, restored.Version)
+
+	_, err = repo.FindByID(ctx, id)
+	assert.NoError(t, err)
+
+	err = repo.Purge(ctx, id)
+	assert.ErrorIs(t, err, ErrNotFound, "live companies cannot be purged")
+
+	err = repo.Purge(ctx, reusedID)
+	assert.NoError(t, err)
+
+	var count int64
+	db.Unscoped().Model(&models.Company{}).Where("id = ?", reusedID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestSQLCompanyRepository_SaveBatch(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	userID := uuid.New()
+	newCompanies := func(names ...string) []models.Company {
+		companies := make([]models.Company, 0, len(names))
+		for _, name := range names {
+			companies = append(companies, models.Company{Name: name, EmployeesAmount: 1, Type: common.Corporations, UserID: userID})
+		}
+		return companies
+	}
+	count := func() int64 {
+		var n int64
+		db.Model(&models.Company{}).Count(&n)
+		return n
+	}
+
+	errs, err := repo.SaveBatch(ctx, newCompanies("acme", "globex", "acme"), true)
+	assert.ErrorIs(t, err, ErrBatchAborted)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Error(t, errs[2])
+	assert.Zero(t, count(), "an aborted batch saves nothing")
+
+	companies := newCompanies("acme", "globex", "acme", "initech")
+	errs, err = repo.SaveBatch(ctx, companies, false)
+	assert.NoError(t, err)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Error(t, errs[2])
+	assert.NoError(t, errs[3])
+	assert.Equal(t, int64(3), count())
+
+	saved, err := repo.FindByID(ctx, companies[3].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "initech", saved.Name)
+
+	var revisions int64
+	db.Model(&models.CompanyRevision{}).Count(&revisions)
+	assert.Equal(t, int64(3), revisions, "failed companies leave no revision behind")
+}
+
+func TestSQLCompanyRepository_Export(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	userID := uuid.New()
+	for i := 0; i < 7; i++ {
+		companyType := common.Corporations
+		if i%2 == 1 {
+			companyType = common.NonProfit
+		}
+		_, err := repo.Save(ctx, models.Company{Name: fmt.Sprintf("company %d", i), EmployeesAmount: 1, Type: companyType, UserID: userID})
+		assert.NoError(t, err)
+	}
+
+	var chunks []int
+	seen := map[uuid.UUID]bool{}
+	err = repo.Export(ctx, CompanyFilter{Types: []common.Type{common.Corporations}}, 3, func(companies []models.Company) error {
+		chunks = append(chunks, len(companies))
+		for _, company := range companies {
+			assert.Equal(t, common.Corporations, company.Type)
+			seen[company.ID] = true
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1}, chunks)
+	assert.Len(t, seen, 4)
+
+	err = repo.Export(ctx, CompanyFilter{}, 3, func([]models.Company) error {
+		return errors.New("client went away")
+	})
+	assert.EqualError(t, err, "failed to export companies: client went away")
+}
+
+func TestSQLCompanyRepository_Slugs(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	newCompany := func(name string) models.Company {
+		return models.Company{Name: name, Type: common.Corporations, UserID: owner}
+	}
+
+	id, err := repo.Save(ctx, newCompany("Café Acme"))
+	assert.NoError(t, err)
+	acme, err := repo.FindBySlug(ctx, "cafe-acme")
+	assert.NoError(t, err)
+	assert.Equal(t, id, acme.ID)
+	assert.Equal(t, "cafe-acme", acme.Slug)
+
+	_, err = repo.Save(ctx, newCompany("CAFÉ ACME"))
+	assert.ErrorIs(t, err, ErrNameTaken, "names differing in case are taken")
+	_, err = repo.Save(ctx, newCompany("Café acme"))
+	assert.ErrorIs(t, err, ErrNameTaken, "names differing in Unicode representation are taken")
+
+	otherID, err := repo.Save(ctx, newCompany("Cafe Acme!"))
+	assert.NoError(t, err)
+	other, err := repo.FindByID(ctx, otherID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cafe-acme-2", other.Slug, "slugs are unique")
+
+	// a rename gives a new slug and the previous one keeps pointing at the company
+	acme.Name = "Globex"
+	acme, err = repo.Update(ctx, owner, acme, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, "globex", acme.Slug)
+	found, err := repo.FindBySlug(ctx, "cafe-acme")
+	assert.NoError(t, err)
+	assert.Equal(t, id, found.ID)
+	assert.Equal(t, "globex", found.Slug)
+
+	// changing the case keeps the slug, renaming back takes the previous slug again
+	acme.Name = "GLOBEX"
+	acme, err = repo.Update(ctx, owner, acme, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, "globex", acme.Slug)
+	acme.Name = "Café Acme"
+	acme, err = repo.Update(ctx, owner, acme, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, "cafe-acme", acme.Slug)
+
+	_, err = repo.FindBySlug(ctx, "initech")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// deleted companies are not found, purged ones release their slugs
+	err = repo.Delete(ctx, owner, otherID, 0, ChildrenRestrict)
+	assert.NoError(t, err)
+	_, err = repo.FindBySlug(ctx, "cafe-acme-2")
+	assert.ErrorIs(t, err, ErrNotFound)
+	err = repo.Purge(ctx, otherID)
+	assert.NoError(t, err)
+	var count int64
+	db.Model(&models.CompanySlug{}).Where("company_id = ?", otherID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestClaimSlug_ConcurrentClaim(t *testing.T) {
+	db := setupTestDB(t)
+	// another company claimed the slug after it was found free
+	assert.NoError(t, db.Create(&models.CompanySlug{Slug: "acme", CompanyID: uuid.New()}).Error)
+
+	companyID := uuid.New()
+	slug, err := claimSlug(db, companyID, "acme", "acme")
+	assert.NoError(t, err)
+	assert.Equal(t, "acme-2", slug, "the next free candidate is claimed")
+	var claimed models.CompanySlug
+	assert.NoError(t, db.Where("company_id = ?", companyID).First(&claimed).Error)
+	assert.Equal(t, "acme-2", claimed.Slug)
+}
+
+func TestSQLCompanyRepository_Backfill(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	// companies created before slugs, name keys and statuses existed
+	legacy := []models.Company{
+		{Name: "Acme", Type: common.Corporations},
+		{Name: "ACME!", Registered: true, Type: common.Corporations},
+	}
+	for i := range legacy {
+		assert.NoError(t, db.Create(&legacy[i]).Error)
+		name := legacy[i].Name
+		db.Model(&legacy[i]).UpdateColumns(map[string]any{"slug": "", "active_name": name, "status": ""})
+	}
+	db.Exec("DELETE FROM company_slugs")
+
+	err = repo.Backfill(context.Background())
+	assert.NoError(t, err)
+
+	var comps []models.Company
+	db.Order("created_at").Find(&comps)
+	assert.Len(t, comps, 2)
+	assert.Equal(t, "acme", *comps[0].ActiveName)
+	assert.ElementsMatch(t, []string{"acme", "acme-2"}, []string{comps[0].Slug, comps[1].Slug})
+	assert.Equal(t, 1, comps[0].Version, "backfilling is not a change of the company")
+	assert.Equal(t, []models.CompanyStatus{models.StatusDraft, models.StatusActive}, []models.CompanyStatus{comps[0].Status, comps[1].Status})
+
+	// names that now share a key cannot both stay live
+	clash := models.Company{Name: "Initech", Type: common.Corporations}
+	assert.NoError(t, db.Create(&clash).Error)
+	db.Model(&clash).UpdateColumns(map[string]any{"name": "ACME", "active_name": "ACME"})
+	err = repo.Backfill(context.Background())
+	assert.ErrorIs(t, err, ErrNameTaken)
+}
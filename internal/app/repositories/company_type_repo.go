@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLCompanyTypeRepository stores the company type catalogue.
+type SQLCompanyTypeRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLCompanyTypeRepository creates a new sql company type repository.
+func NewSQLCompanyTypeRepository(db *gorm.DB) (*SQLCompanyTypeRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLCompanyTypeRepository{
+		db: db,
+	}, nil
+}
+
+// List returns every company type of the catalogue ordered by name, deprecated ones included.
+func (tr *SQLCompanyTypeRepository) List(ctx context.Context) ([]models.CompanyType, error) {
+	var companyTypes []models.CompanyType
+	result := tr.db.WithContext(ctx).Order("name ASC").Find(&companyTypes)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list company types: %w", result.Error)
+	}
+
+	return companyTypes, nil
+}
+
+// FindByName finds a company type by name.
+func (tr *SQLCompanyTypeRepository) FindByName(ctx context.Context, name common.Type) (models.CompanyType, error) {
+	var companyType models.CompanyType
+	result := tr.db.WithContext(ctx).Where("name = ?", name).First(&companyType)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.CompanyType{}, fmt.Errorf("failed to find company type: %w", ErrNotFound)
+	}
+	if result.Error != nil {
+		return models.CompanyType{}, fmt.Errorf("failed to find company type: %w", result.Error)
+	}
+
+	return companyType, nil
+}
+
+// Save adds a company type to the catalogue, it fails with ErrTypeExists when the name is taken.
+func (tr *SQLCompanyTypeRepository) Save(ctx context.Context, companyType models.CompanyType) (models.CompanyType, error) {
+	result := tr.db.WithContext(ctx).Create(&companyType)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return models.CompanyType{}, fmt.Errorf("failed to save company type: %w", ErrTypeExists)
+	}
+	if result.Error != nil {
+		return models.CompanyType{}, fmt.Errorf("failed to save company type: %w", result.Error)
+	}
+
+	return companyType, nil
+}
+
+// Update stores the description and deprecation flag of a company type.
+func (tr *SQLCompanyTypeRepository) Update(ctx context.Context, companyType models.CompanyType) (models.CompanyType, error) {
+	var updated models.CompanyType
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.CompanyType{}).
+			Where("name = ?", companyType.Name).
+			Select("Description", "Deprecated").
+			Updates(&companyType)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return tx.Where("name = ?", companyType.Name).First(&updated).Error
+	})
+	if err != nil {
+		return models.CompanyType{}, fmt.Errorf("failed to update company type: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a company type from the catalogue. Types used by a company, deleted ones
+// included since they can be restored, fail with ErrTypeInUse and can only be deprecated.
+func (tr *SQLCompanyTypeRepository) Delete(ctx context.Context, name common.Type) error {
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var used int64
+		if err := tx.Unscoped().Model(&models.Company{}).Where("type = ?", name).Count(&used).Error; err != nil {
+			return err
+		}
+		if used > 0 {
+			return ErrTypeInUse
+		}
+
+		result := tx.Where("name = ?", name).Delete(&models.CompanyType{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete company type: %w", err)
+	}
+
+	return nil
+}
+
+// Seed adds the company types missing from the catalogue, existing ones are left untouched.
+func (tr *SQLCompanyTypeRepository) Seed(ctx context.Context, companyTypes []models.CompanyType) error {
+	if len(companyTypes) == 0 {
+		return nil
+	}
+
+	result := tr.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&companyTypes)
+	if result.Error != nil {
+		return fmt.Errorf("failed to seed company types: %w", result.Error)
+	}
+
+	return nil
+}
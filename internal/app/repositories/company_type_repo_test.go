@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLCompanyTypeRepository(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		db     *gorm.DB
+		expErr string
+	}{
+		"no database": {
+			expErr: "db is nil",
+		},
+		"success": {
+			db: &gorm.DB{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			repo, err := NewSQLCompanyTypeRepository(tt.db)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, repo)
+			} else {
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestSQLCompanyTypeRepository(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	repo, err := NewSQLCompanyTypeRepository(db)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	err = repo.Seed(ctx, []models.CompanyType{{Name: common.Corporations}, {Name: common.NonProfit}})
+	assert.NoError(t, err)
+	err = repo.Seed(ctx, []models.CompanyType{{Name: common.Corporations, Description: "seeded again"}})
+	assert.NoError(t, err, "seeding twice keeps the existing types")
+
+	partnership, err := repo.Save(ctx, models.CompanyType{Name: "Partnership", Description: "two or more owners"})
+	assert.NoError(t, err)
+	assert.Equal(t, common.Type("Partnership"), partnership.Name)
+	_, err = repo.Save(ctx, models.CompanyType{Name: "Partnership"})
+	assert.ErrorIs(t, err, ErrTypeExists)
+
+	types, err := repo.List(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, types, 3) {
+		assert.Equal(t, common.Corporations, types[0].Name)
+		assert.Equal(t, "", types[0].Description)
+		assert.Equal(t, common.Type("Partnership"), types[2].Name)
+	}
+
+	updated, err := repo.Update(ctx, models.CompanyType{Name: "Partnership", Description: "legacy", Deprecated: true})
+	assert.NoError(t, err)
+	assert.True(t, updated.Deprecated)
+	assert.Equal(t, "legacy", updated.Description)
+	_, err = repo.Update(ctx, models.CompanyType{Name: "Guild"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	found, err := repo.FindByName(ctx, "Partnership")
+	assert.NoError(t, err)
+	assert.True(t, found.Deprecated)
+	_, err = repo.FindByName(ctx, "Guild")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	assert.NoError(t, err)
+	_, err = companyRepo.Save(ctx, models.Company{Name: "acme", EmployeesAmount: 3, Type: "Partnership", UserID: uuid.New()})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, repo.Delete(ctx, "Partnership"), ErrTypeInUse)
+	assert.ErrorIs(t, repo.Delete(ctx, "Guild"), ErrNotFound)
+	assert.NoError(t, repo.Delete(ctx, common.NonProfit))
+	_, err = repo.FindByName(ctx, common.NonProfit)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+)
+
+// SQLEmployeeCountRepository reads the employee counts recorded by the company repository.
+type SQLEmployeeCountRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLEmployeeCountRepository creates a new sql employee count repository.
+func NewSQLEmployeeCountRepository(db *gorm.DB) (*SQLEmployeeCountRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLEmployeeCountRepository{
+		db: db,
+	}, nil
+}
+
+// List returns the employee counts of a company effective on or before until, oldest first.
+func (er *SQLEmployeeCountRepository) List(ctx context.Context, companyID uuid.UUID, until time.Time) ([]models.EmployeeCount, error) {
+	var counts []models.EmployeeCount
+	result := er.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Where("effective_on <= ?", until).
+		Order("effective_on ASC, created_at ASC").
+		Find(&counts)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list employee counts: %w", result.Error)
+	}
+
+	return counts, nil
+}
+
+// Backfill rebuilds the employee counts of companies changed before counts were recorded from
+// their revisions, a company without revisions gets a single count from its creation day on.
+func (er *SQLEmployeeCountRepository) Backfill(ctx context.Context) error {
+	var chunk []models.Company
+	result := er.db.WithContext(ctx).Unscoped().
+		Where("NOT EXISTS (SELECT 1 FROM employee_counts WHERE employee_counts.company_id = companies.id)").
+		FindInBatches(&chunk, 100, func(_ *gorm.DB, _ int) error {
+			for _, comp := range chunk {
+				var revisions []models.CompanyRevision
+				result := er.db.WithContext(ctx).Where("company_id = ?", comp.ID).Order("revision ASC").Find(&revisions)
+				if result.Error != nil {
+					return fmt.Errorf("company %s: %w", comp.ID, result.Error)
+				}
+
+				counts := backfilledCounts(comp, revisions)
+				if err := er.db.WithContext(ctx).Create(&counts).Error; err != nil {
+					return fmt.Errorf("company %s: %w", comp.ID, err)
+				}
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill employee counts: %w", result.Error)
+	}
+
+	return nil
+}
+
+// backfilledCounts returns a count for every day the revisions of a company changed its employees amount.
+func backfilledCounts(comp models.Company, revisions []models.CompanyRevision) []models.EmployeeCount {
+	var counts []models.EmployeeCount
+	add := func(at time.Time, employees int, actorID uuid.UUID) {
+		day := models.Day(at)
+		if len(counts) > 0 && counts[len(counts)-1].Employees == employees {
+			return
+		}
+		// only the last change of a day is kept
+		if len(counts) > 0 && counts[len(counts)-1].EffectiveOn.Equal(day) {
+			counts = counts[:len(counts)-1]
+			if len(counts) > 0 && counts[len(counts)-1].Employees == employees {
+				return
+			}
+		}
+		counts = append(counts, models.EmployeeCount{CompanyID: comp.ID, EffectiveOn: day, Employees: employees, ActorID: actorID})
+	}
+
+	for _, revision := range revisions {
+		add(revision.CreatedAt, revision.Snapshot.EmployeesAmount, revision.ActorID)
+	}
+	if len(counts) == 0 {
+		add(comp.CreatedAt, comp.EmployeesAmount, comp.UserID)
+	}
+	add(comp.UpdatedAt, comp.EmployeesAmount, comp.UserID)
+
+	return counts
+}
+
+// recordEmployeeCount stores the employees amount of a company as effective from the day of
+// effectiveOn unless it did not change. A count cannot take effect before the latest one.
+func recordEmployeeCount(tx *gorm.DB, actorID uuid.UUID, company models.Company, effectiveOn time.Time) error {
+	day := models.Day(effectiveOn)
+	var latest []models.EmployeeCount
+	result := tx.Where("company_id = ?", company.ID).Order("effective_on DESC, created_at DESC").Limit(1).Find(&latest)
+	if result.Error != nil {
+		return result.Error
+	}
+	if len(latest) > 0 {
+		if latest[0].Employees == company.EmployeesAmount {
+			return nil
+		}
+		if day.Before(latest[0].EffectiveOn) {
+			return fmt.Errorf("%w: the latest count is effective on %s", ErrEmployeeCountOutOfOrder, latest[0].EffectiveOn.Format(time.DateOnly))
+		}
+	}
+
+	return tx.Create(&models.EmployeeCount{
+		CompanyID:   company.ID,
+		EffectiveOn: day,
+		Employees:   company.EmployeesAmount,
+		ActorID:     actorID,
+	}).Error
+}
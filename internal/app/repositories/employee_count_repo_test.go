@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLCompanyRepository_Update_RecordsEmployeeCounts(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	countRepo, err := NewSQLEmployeeCountRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	editor := uuid.MustParse("f0b1c2d3-e4f5-4a6b-8c7d-9e0f1a2b3c4d")
+	today := models.Day(time.Now())
+	id, err := repo.Save(ctx, models.Company{Name: "Acme", EmployeesAmount: 10, Type: common.Corporations, UserID: owner})
+	assert.NoError(t, err)
+	acme, err := repo.FindByID(ctx, id)
+	assert.NoError(t, err)
+
+	acme.Description = "anvils"
+	acme, err = repo.Update(ctx, editor, acme, time.Time{})
+	assert.NoError(t, err)
+	acme.EmployeesAmount = 12
+	acme, err = repo.Update(ctx, editor, acme, time.Time{})
+	assert.NoError(t, err)
+	acme.EmployeesAmount = 15
+	acme, err = repo.Update(ctx, editor, acme, today.Add(20*time.Hour))
+	assert.NoError(t, err)
+
+	counts, err := countRepo.List(ctx, id, today)
+	assert.NoError(t, err)
+	if assert.Len(t, counts, 3, "unchanged amounts are not recorded") {
+		assert.Equal(t, []int{10, 12, 15}, []int{counts[0].Employees, counts[1].Employees, counts[2].Employees})
+		assert.True(t, counts[0].EffectiveOn.Equal(today))
+		assert.True(t, counts[2].EffectiveOn.Equal(today), "counts are effective from the start of their day")
+		assert.Equal(t, owner, counts[0].ActorID)
+		assert.Equal(t, editor, counts[2].ActorID)
+	}
+
+	acme.EmployeesAmount = 9
+	_, err = repo.Update(ctx, editor, acme, today.AddDate(0, 0, -1))
+	assert.ErrorIs(t, err, ErrEmployeeCountOutOfOrder)
+	stored, err := repo.FindByID(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 15, stored.EmployeesAmount, "the company is not updated either")
+
+	counts, err = countRepo.List(ctx, id, today.AddDate(0, 0, -1))
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestSQLEmployeeCountRepository_Backfill(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLEmployeeCountRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	// companies changed before employee counts were recorded
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	acme := models.Company{Name: "Acme", EmployeesAmount: 20, Type: common.Corporations, UserID: owner}
+	globex := models.Company{Name: "Globex", EmployeesAmount: 7, Type: common.Corporations, UserID: owner}
+	assert.NoError(t, db.Create(&acme).Error)
+	assert.NoError(t, db.Create(&globex).Error)
+
+	january := time.Date(2023, 1, 10, 9, 0, 0, 0, time.UTC)
+	for i, change := range []struct {
+		at        time.Time
+		employees int
+	}{
+		{january, 10},
+		{january.AddDate(0, 1, 0), 10},
+		{january.AddDate(0, 2, 0), 12},
+		{january.AddDate(0, 2, 0).Add(time.Hour), 14},
+		{january.AddDate(0, 3, 0), 20},
+	} {
+		snapshot := acme
+		snapshot.EmployeesAmount = change.employees
+		assert.NoError(t, db.Create(&models.CompanyRevision{CompanyID: acme.ID, Revision: i + 1, CreatedAt: change.at, Snapshot: snapshot}).Error)
+	}
+
+	err = repo.Backfill(context.Background())
+	assert.NoError(t, err)
+	err = repo.Backfill(context.Background())
+	assert.NoError(t, err, "backfilling twice is not a change")
+
+	counts, err := repo.List(context.Background(), acme.ID, time.Now())
+	assert.NoError(t, err)
+	var days []string
+	var employees []int
+	for _, count := range counts {
+		days = append(days, count.EffectiveOn.Format(time.DateOnly))
+		employees = append(employees, count.Employees)
+	}
+	assert.Equal(t, []string{"2023-01-10", "2023-03-10", "2023-04-10"}, days)
+	assert.Equal(t, []int{10, 14, 20}, employees)
+
+	counts, err = repo.List(context.Background(), globex.ID, time.Now())
+	assert.NoError(t, err)
+	if assert.Len(t, counts, 1) {
+		assert.Equal(t, 7, counts[0].Employees)
+		assert.True(t, counts[0].EffectiveOn.Equal(models.Day(globex.CreatedAt)))
+	}
+}
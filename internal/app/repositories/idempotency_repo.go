@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLIdempotencyRepository stores the requests made with an idempotency key and their responses.
+type SQLIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLIdempotencyRepository creates a new sql idempotency repository.
+func NewSQLIdempotencyRepository(db *gorm.DB) (*SQLIdempotencyRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLIdempotencyRepository{
+		db: db,
+	}, nil
+}
+
+// Reserve stores the record unless the user already has a live record with the same key.
+// It returns the stored record and whether it is the one that was passed in.
+// Expired records are removed on the way.
+func (ir *SQLIdempotencyRepository) Reserve(ctx context.Context, record models.IdempotencyKey) (models.IdempotencyKey, bool, error) {
+	db := ir.db.WithContext(ctx)
+	if err := db.Where("expires_at <= ?", time.Now()).Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return models.IdempotencyKey{}, false, fmt.Errorf("failed to remove expired idempotency keys: %w", err)
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return models.IdempotencyKey{}, false, fmt.Errorf("failed to reserve idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return record, true, nil
+	}
+
+	var existing models.IdempotencyKey
+	result = db.Where("user_id = ? AND idempotency_key = ?", record.UserID, record.Key).First(&existing)
+	if result.Error != nil {
+		return models.IdempotencyKey{}, false, fmt.Errorf("failed to find idempotency key: %w", result.Error)
+	}
+
+	return existing, false, nil
+}
+
+// Complete stores the response of the request a key was reserved for.
+func (ir *SQLIdempotencyRepository) Complete(ctx context.Context, record models.IdempotencyKey) error {
+	result := ir.db.WithContext(ctx).Model(&record).Select("StatusCode", "Header", "Body").Updates(&record)
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", result.Error)
+	}
+
+	return nil
+}
+
+// Release removes a key that has no response yet so the request can be retried.
+func (ir *SQLIdempotencyRepository) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	result := ir.db.WithContext(ctx).
+		Where("user_id = ? AND idempotency_key = ? AND status_code = 0", userID, key).
+		Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", result.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLIdempotencyRepository(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		db     *gorm.DB
+		expErr string
+	}{
+		"no database": {
+			expErr: "db is nil",
+		},
+		"success": {
+			db: &gorm.DB{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			repo, err := NewSQLIdempotencyRepository(tt.db)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, repo)
+			} else {
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestSQLIdempotencyRepository_Reserve(t *testing.T) {
+	db := setupTestDB(t)
+
+	repo, err := NewSQLIdempotencyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	userID := uuid.New()
+	record := models.IdempotencyKey{
+		UserID:      userID,
+		Key:         "create-acme",
+		Fingerprint: "abc",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	_, reserved, err := repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	// the same key of another user is unrelated
+	other := record
+	other.UserID = uuid.New()
+	_, reserved, err = repo.Reserve(ctx, other)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	retry := record
+	retry.Fingerprint = "def"
+	existing, reserved, err := repo.Reserve(ctx, retry)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "abc", existing.Fingerprint)
+	assert.Zero(t, existing.StatusCode)
+
+	record.StatusCode = 201
+	record.Header = map[string]string{"Content-Type": "application/json; charset=utf-8"}
+	record.Body = []byte(`{"Name":"Acme"}`)
+	err = repo.Complete(ctx, record)
+	assert.NoError(t, err)
+
+	existing, reserved, err = repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, record.Header, existing.Header)
+	assert.Equal(t, record.Body, existing.Body)
+
+	// completed keys are not released
+	err = repo.Release(ctx, userID, record.Key)
+	assert.NoError(t, err)
+	_, reserved, err = repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+
+	err = repo.Release(ctx, other.UserID, other.Key)
+	assert.NoError(t, err)
+	_, reserved, err = repo.Reserve(ctx, other)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	expired := models.IdempotencyKey{UserID: userID, Key: "expired", Fingerprint: "abc", ExpiresAt: time.Now().Add(-time.Second)}
+	assert.NoError(t, db.Create(&expired).Error)
+	expired.Fingerprint = "def"
+	expired.ExpiresAt = time.Now().Add(time.Hour)
+	_, reserved, err = repo.Reserve(ctx, expired)
+	assert.NoError(t, err)
+	assert.True(t, reserved, "expired keys can be reused")
+}
@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+)
+
+// locationOrder lists the headquarters of a company first and its branches in the order they were added.
+const locationOrder = "headquarters DESC, created_at ASC, id ASC"
+
+// locationColumns are the columns of a location an update replaces.
+var locationColumns = []string{"Name", "Headquarters", "Street", "PostalCode", "City", "Region", "Country", "Latitude", "Longitude"}
+
+// SQLLocationRepository stores the locations of companies.
+type SQLLocationRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLLocationRepository creates a new sql location repository.
+func NewSQLLocationRepository(db *gorm.DB) (*SQLLocationRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLLocationRepository{
+		db: db,
+	}, nil
+}
+
+// List returns the locations of a company, headquarters first.
+func (lr *SQLLocationRepository) List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyLocation, error) {
+	var locations []models.CompanyLocation
+	result := lr.db.WithContext(ctx).Where("company_id = ?", companyID).Order(locationOrder).Find(&locations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list locations: %w", result.Error)
+	}
+
+	return locations, nil
+}
+
+// Find returns a location of a company.
+func (lr *SQLLocationRepository) Find(ctx context.Context, companyID, locationID uuid.UUID) (models.CompanyLocation, error) {
+	var location models.CompanyLocation
+	result := lr.db.WithContext(ctx).Where("id = ? AND company_id = ?", locationID, companyID).First(&location)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.CompanyLocation{}, fmt.Errorf("failed to find location: %w", ErrLocationNotFound)
+	}
+	if result.Error != nil {
+		return models.CompanyLocation{}, fmt.Errorf("failed to find location: %w", result.Error)
+	}
+
+	return location, nil
+}
+
+// Save adds a location to a live company on behalf of the actor. A new headquarters turns the
+// previous one into a branch.
+func (lr *SQLLocationRepository) Save(ctx context.Context, actorID uuid.UUID, location models.CompanyLocation) (models.CompanyLocation, models.Company, error) {
+	var comp models.Company
+	err := lr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", location.CompanyID).First(&comp).Error; err != nil {
+			return companyError(err)
+		}
+		if err := tx.Create(&location).Error; err != nil {
+			return err
+		}
+		if err := keepSingleHeadquarters(tx, location); err != nil {
+			return err
+		}
+		return relocateCompany(tx, actorID, &comp)
+	})
+	if err != nil {
+		return models.CompanyLocation{}, models.Company{}, fmt.Errorf("failed to save location: %w", err)
+	}
+
+	return location, comp, nil
+}
+
+// Update replaces the address of a location of a live company on behalf of the actor.
+func (lr *SQLLocationRepository) Update(ctx context.Context, actorID uuid.UUID, location models.CompanyLocation) (models.CompanyLocation, models.Company, error) {
+	var comp models.Company
+	err := lr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", location.CompanyID).First(&comp).Error; err != nil {
+			return companyError(err)
+		}
+
+		result := tx.Model(&models.CompanyLocation{}).
+			Where("id = ? AND company_id = ?", location.ID, location.CompanyID).
+			Select(locationColumns).
+			Updates(&location)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrLocationNotFound
+		}
+		if err := tx.Where("id = ?", location.ID).First(&location).Error; err != nil {
+			return err
+		}
+		if err := keepSingleHeadquarters(tx, location); err != nil {
+			return err
+		}
+		return relocateCompany(tx, actorID, &comp)
+	})
+	if err != nil {
+		return models.CompanyLocation{}, models.Company{}, fmt.Errorf("failed to update location: %w", err)
+	}
+
+	return location, comp, nil
+}
+
+// Delete removes a location of a live company on behalf of the actor.
+func (lr *SQLLocationRepository) Delete(ctx context.Context, actorID, companyID, locationID uuid.UUID) (models.Company, error) {
+	var comp models.Company
+	err := lr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", companyID).First(&comp).Error; err != nil {
+			return companyError(err)
+		}
+
+		result := tx.Where("id = ? AND company_id = ?", locationID, companyID).Delete(&models.CompanyLocation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrLocationNotFound
+		}
+		return relocateCompany(tx, actorID, &comp)
+	})
+	if err != nil {
+		return models.Company{}, fmt.Errorf("failed to delete location: %w", err)
+	}
+
+	return comp, nil
+}
+
+// keepSingleHeadquarters turns the other locations of the company into branches when the location is its headquarters.
+func keepSingleHeadquarters(tx *gorm.DB, location models.CompanyLocation) error {
+	if !location.Headquarters {
+		return nil
+	}
+
+	return tx.Model(&models.CompanyLocation{}).
+		Where("company_id = ? AND id <> ? AND headquarters = ?", location.CompanyID, location.ID, true).
+		Update("headquarters", false).Error
+}
+
+// relocateCompany bumps the version of a company whose locations changed, so cached copies of it
+// are invalidated, and records the change in its history. The company is reloaded with its locations.
+func relocateCompany(tx *gorm.DB, actorID uuid.UUID, comp *models.Company) error {
+	result := tx.Model(comp).Where("version = ?", comp.Version).Update("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+
+	if err := withLocations(tx).Where("id = ?", comp.ID).First(comp).Error; err != nil {
+		return err
+	}
+	return recordRevision(tx, models.RevisionRelocated, actorID, *comp)
+}
+
+// withLocations loads the locations of the companies read by the query.
+func withLocations(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Locations", func(db *gorm.DB) *gorm.DB {
+		return db.Order(locationOrder)
+	})
+}
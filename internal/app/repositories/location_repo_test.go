@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLLocationRepository(t *testing.T) {
+	t.Parallel()
+	repo, err := NewSQLLocationRepository(nil)
+	assert.EqualError(t, err, "db is nil")
+	assert.Nil(t, repo)
+
+	repo, err = NewSQLLocationRepository(&gorm.DB{})
+	assert.NoError(t, err)
+	assert.NotNil(t, repo)
+}
+
+func TestSQLLocationRepository(t *testing.T) {
+	db := setupTestDB(t)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	revisionRepo, err := NewSQLRevisionRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	repo, err := NewSQLLocationRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	companyID, err := companyRepo.Save(ctx, models.Company{Name: "Acme", Type: common.Corporations, UserID: owner})
+	assert.NoError(t, err)
+
+	lat, long := 52.52, 13.405
+	berlin, comp, err := repo.Save(ctx, owner, models.CompanyLocation{CompanyID: companyID, Name: "Berlin", Headquarters: true, City: "Berlin", Country: "DE", Latitude: &lat, Longitude: &long})
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, berlin.ID)
+	assert.Equal(t, 2, comp.Version, "a location change is a change of the company")
+	assert.Len(t, comp.Locations, 1)
+
+	paris, comp, err := repo.Save(ctx, owner, models.CompanyLocation{CompanyID: companyID, Name: "Paris", City: "Paris", Country: "FR"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, comp.Version)
+	assert.Equal(t, []string{"Berlin", "Paris"}, locationNames(comp.Locations))
+
+	// moving the headquarters turns the previous one into a branch
+	paris.Headquarters = true
+	paris.Region = "Île-de-France"
+	paris, comp, err = repo.Update(ctx, owner, paris)
+	assert.NoError(t, err)
+	assert.Equal(t, "Île-de-France", paris.Region)
+	assert.Equal(t, []string{"Paris", "Berlin"}, locationNames(comp.Locations))
+	assert.False(t, comp.Locations[1].Headquarters)
+
+	stored, err := repo.Find(ctx, companyID, berlin.ID)
+	assert.NoError(t, err)
+	assert.False(t, stored.Headquarters)
+	assert.Equal(t, 52.52, *stored.Latitude)
+
+	_, err = repo.Find(ctx, uuid.New(), berlin.ID)
+	assert.ErrorIs(t, err, ErrLocationNotFound)
+	_, _, err = repo.Update(ctx, owner, models.CompanyLocation{ID: uuid.New(), CompanyID: companyID, Name: "Rome"})
+	assert.ErrorIs(t, err, ErrLocationNotFound)
+	_, _, err = repo.Save(ctx, owner, models.CompanyLocation{CompanyID: uuid.New(), Name: "Rome"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	comp, err = repo.Delete(ctx, owner, companyID, berlin.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Paris"}, locationNames(comp.Locations))
+	_, err = repo.Delete(ctx, owner, companyID, berlin.ID)
+	assert.ErrorIs(t, err, ErrLocationNotFound)
+
+	found, err := companyRepo.FindByID(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Paris"}, locationNames(found.Locations))
+
+	// updating the company leaves its locations alone
+	found.Description = "Anvils"
+	_, err = companyRepo.Update(ctx, owner, found, time.Time{})
+	assert.NoError(t, err)
+	locations, err := repo.List(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Paris"}, locationNames(locations))
+
+	revisions, err := revisionRepo.List(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 6)
+	assert.Equal(t, models.RevisionRelocated, revisions[1].Action)
+	assert.Empty(t, revisions[1].Snapshot.Locations)
+
+	// purging the company removes its locations
+	assert.NoError(t, companyRepo.Delete(ctx, owner, companyID, 0, ChildrenRestrict))
+	assert.NoError(t, companyRepo.Purge(ctx, companyID))
+	locations, err = repo.List(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Empty(t, locations)
+}
+
+func locationNames(locations []models.CompanyLocation) []string {
+	names := make([]string, 0, len(locations))
+	for _, location := range locations {
+		names = append(names, location.Name)
+	}
+	return names
+}
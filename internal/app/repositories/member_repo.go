@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLMemberRepository stores the access control lists of companies.
+type SQLMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLMemberRepository creates a new sql member repository.
+func NewSQLMemberRepository(db *gorm.DB) (*SQLMemberRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLMemberRepository{
+		db: db,
+	}, nil
+}
+
+// List returns the members of a company in the order they were granted access.
+func (mr *SQLMemberRepository) List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyMember, error) {
+	var members []models.CompanyMember
+	result := mr.db.WithContext(ctx).Where("company_id = ?", companyID).Order("created_at ASC, user_id ASC").Find(&members)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list members: %w", result.Error)
+	}
+
+	return members, nil
+}
+
+// Find returns the membership of a user in a company.
+func (mr *SQLMemberRepository) Find(ctx context.Context, companyID, userID uuid.UUID) (models.CompanyMember, error) {
+	var member models.CompanyMember
+	result := mr.db.WithContext(ctx).Where("company_id = ? AND user_id = ?", companyID, userID).First(&member)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.CompanyMember{}, fmt.Errorf("failed to find member: %w", ErrNotFound)
+	}
+	if result.Error != nil {
+		return models.CompanyMember{}, fmt.Errorf("failed to find member: %w", result.Error)
+	}
+
+	return member, nil
+}
+
+// MemberOf returns the ids of the given companies the user is a member of.
+func (mr *SQLMemberRepository) MemberOf(ctx context.Context, userID uuid.UUID, companyIDs []uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	if len(companyIDs) == 0 {
+		return ids, nil
+	}
+
+	result := mr.db.WithContext(ctx).Model(&models.CompanyMember{}).
+		Where("user_id = ? AND company_id IN ?", userID, companyIDs).
+		Pluck("company_id", &ids)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find memberships: %w", result.Error)
+	}
+
+	return ids, nil
+}
+
+// Save grants a user a role on a company, the role of an existing member is replaced.
+func (mr *SQLMemberRepository) Save(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error) {
+	err := mr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveMember(tx, member); err != nil {
+			return err
+		}
+		return tx.Where("company_id = ? AND user_id = ?", member.CompanyID, member.UserID).First(&member).Error
+	})
+	if err != nil {
+		return models.CompanyMember{}, fmt.Errorf("failed to save member: %w", err)
+	}
+
+	return member, nil
+}
+
+// Delete revokes the access of a user to a company.
+func (mr *SQLMemberRepository) Delete(ctx context.Context, companyID, userID uuid.UUID) error {
+	result := mr.db.WithContext(ctx).Where("company_id = ? AND user_id = ?", companyID, userID).Delete(&models.CompanyMember{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to delete member: %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// saveMember inserts a member or replaces the role of an existing one.
+func saveMember(tx *gorm.DB, member models.CompanyMember) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "company_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "granted_by", "updated_at"}),
+	}).Create(&member).Error
+}
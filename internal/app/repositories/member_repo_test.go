@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLMemberRepository(t *testing.T) {
+	t.Parallel()
+	repo, err := NewSQLMemberRepository(nil)
+	assert.EqualError(t, err, "db is nil")
+	assert.Nil(t, repo)
+
+	repo, err = NewSQLMemberRepository(&gorm.DB{})
+	assert.NoError(t, err)
+	assert.NotNil(t, repo)
+}
+
+func TestSQLMemberRepository(t *testing.T) {
+	db := setupTestDB(t)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	transferRepo, err := NewSQLTransferRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	repo, err := NewSQLMemberRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	editor := uuid.MustParse("5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11")
+	companyID, err := companyRepo.Save(ctx, models.Company{Name: "Acme", Type: common.Corporations, UserID: owner})
+	assert.NoError(t, err)
+
+	member, err := repo.Find(ctx, companyID, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleOwner, member.Role, "the creator owns the company")
+
+	_, err = repo.Find(ctx, companyID, editor)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	member, err = repo.Save(ctx, models.CompanyMember{CompanyID: companyID, UserID: editor, Role: models.RoleViewer, GrantedBy: owner})
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleViewer, member.Role)
+
+	ids, err := repo.MemberOf(ctx, editor, []uuid.UUID{companyID, uuid.New()})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{companyID}, ids)
+	ids, err = repo.MemberOf(ctx, uuid.New(), []uuid.UUID{companyID})
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+	member, err = repo.Save(ctx, models.CompanyMember{CompanyID: companyID, UserID: editor, Role: models.RoleEditor, GrantedBy: owner})
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleEditor, member.Role, "saving an existing member replaces the role")
+
+	members, err := repo.List(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	// accepting a transfer hands the owner role over
+	transfer, err := transferRepo.Save(ctx, models.CompanyTransfer{CompanyID: companyID, FromUserID: owner, ToUserID: editor, InitiatorID: owner})
+	assert.NoError(t, err)
+	_, _, err = transferRepo.Accept(ctx, transfer.ID)
+	assert.NoError(t, err)
+	member, err = repo.Find(ctx, companyID, editor)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleOwner, member.Role)
+	assert.Equal(t, owner, member.GrantedBy)
+	_, err = repo.Find(ctx, companyID, owner)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.ErrorIs(t, repo.Delete(ctx, companyID, owner), ErrNotFound)
+	assert.NoError(t, repo.Delete(ctx, companyID, editor))
+	members, err = repo.List(ctx, companyID)
+	assert.NoError(t, err)
+	assert.Empty(t, members)
+}
@@ -2,8 +2,11 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
 	"github.com/iNDicat0r/company/internal/app/models"
 )
 
@@ -17,7 +20,232 @@ type UserRepository interface {
 // CompanyRepository defines the functionality of company repository.
 type CompanyRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (models.Company, error)
-	Delete(ctx context.Context, userID, companyID uuid.UUID) error
+	FindBySlug(ctx context.Context, slug string) (models.Company, error)
+	List(ctx context.Context, query CompanyListQuery) ([]models.Company, error)
+	Export(ctx context.Context, filter CompanyFilter, chunkSize int, fn func([]models.Company) error) error
+	Ancestors(ctx context.Context, companyID uuid.UUID, maxDepth int) ([]models.Company, error)
+	Children(ctx context.Context, companyID uuid.UUID) ([]models.Company, error)
+	Subtree(ctx context.Context, companyID uuid.UUID, maxDepth int) ([]CompanyNode, error)
+	Delete(ctx context.Context, userID, companyID uuid.UUID, version int, children ChildrenPolicy) error
 	Save(ctx context.Context, company models.Company) (uuid.UUID, error)
-	Update(ctx context.Context, company models.Company) (models.Company, error)
+	SaveBatch(ctx context.Context, companies []models.Company, atomic bool) ([]error, error)
+	Update(ctx context.Context, actorID uuid.UUID, company models.Company, employeesOn time.Time) (models.Company, error)
+	Restore(ctx context.Context, actorID, companyID uuid.UUID) (models.Company, error)
+	Purge(ctx context.Context, companyID uuid.UUID) error
+	Transition(ctx context.Context, version int, transition models.CompanyTransition) (models.CompanyTransition, models.Company, error)
+	ListTransitions(ctx context.Context, companyID uuid.UUID) ([]models.CompanyTransition, error)
+}
+
+var (
+	// ErrNotFound is returned when the requested record does not exist.
+	ErrNotFound = errors.New("record not found")
+	// ErrVersionConflict is returned when a company changed since the version a write was based on.
+	ErrVersionConflict = errors.New("company version conflict")
+	// ErrNameTaken is returned when a company name is already used by a live company.
+	ErrNameTaken = errors.New("company name is taken")
+	// ErrBatchAborted is returned when a company of an atomic batch failed and nothing was saved.
+	ErrBatchAborted = errors.New("batch aborted")
+	// ErrRevisionNotFound is returned when a company has no revision with the requested number.
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrTypeExists is returned when a company type is already in the catalogue.
+	ErrTypeExists = errors.New("company type already exists")
+	// ErrTypeInUse is returned when removing a company type some companies still use.
+	ErrTypeInUse = errors.New("company type is in use")
+	// ErrTransferPending is returned when a company already has a pending ownership transfer.
+	ErrTransferPending = errors.New("company has a pending transfer")
+	// ErrTransferResolved is returned when a transfer was already accepted or cancelled.
+	ErrTransferResolved = errors.New("transfer is no longer pending")
+	// ErrHasChildren is returned when deleting a company that has subsidiaries under ChildrenRestrict.
+	ErrHasChildren = errors.New("company has subsidiaries")
+	// ErrParentCycle is returned when the parent of a company is the company or one of its subsidiaries.
+	ErrParentCycle = errors.New("company would become its own ancestor")
+	// ErrAttributeExists is returned when an attribute schema with the same name is already defined.
+	ErrAttributeExists = errors.New("attribute already exists")
+	// ErrLocationNotFound is returned when a company has no location with the requested id.
+	ErrLocationNotFound = errors.New("location not found")
+	// ErrEmployeeCountOutOfOrder is returned when an employee count would take effect before the latest one.
+	ErrEmployeeCountOutOfOrder = errors.New("employee count out of order")
+)
+
+// ChildrenPolicy tells what happens to the subsidiaries of a deleted company.
+type ChildrenPolicy string
+
+const (
+	ChildrenRestrict ChildrenPolicy = "restrict" // The company cannot be deleted while it has subsidiaries.
+	ChildrenPromote  ChildrenPolicy = "promote"  // The subsidiaries are moved to the parent of the company.
+)
+
+// CompanyNode is a company of a subtree and its depth below the root of the subtree.
+type CompanyNode struct {
+	Company models.Company
+	Depth   int
+}
+
+// MemberRepository defines the functionality of the company access control lists.
+type MemberRepository interface {
+	List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyMember, error)
+	Find(ctx context.Context, companyID, userID uuid.UUID) (models.CompanyMember, error)
+	MemberOf(ctx context.Context, userID uuid.UUID, companyIDs []uuid.UUID) ([]uuid.UUID, error)
+	Save(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error)
+	Delete(ctx context.Context, companyID, userID uuid.UUID) error
+}
+
+// TagRepository defines the functionality of company tags.
+type TagRepository interface {
+	ListByCompany(ctx context.Context, companyID uuid.UUID) ([]models.Tag, error)
+	Attach(ctx context.Context, userID, companyID uuid.UUID, tag models.Tag) error
+	Detach(ctx context.Context, companyID uuid.UUID, name string) error
+	Usage(ctx context.Context, userID uuid.UUID, prefix string, limit int) ([]TagUsage, error)
+}
+
+// TagUsage represents a tag and the number of live companies using it.
+type TagUsage struct {
+	Tag       models.Tag
+	Companies int
+}
+
+// AttributeRepository defines the functionality of custom attribute schemas and the values companies have.
+type AttributeRepository interface {
+	ListSchemas(ctx context.Context) ([]models.AttributeSchema, error)
+	FindSchema(ctx context.Context, name string) (models.AttributeSchema, error)
+	SaveSchema(ctx context.Context, schema models.AttributeSchema) (models.AttributeSchema, error)
+	UpdateSchema(ctx context.Context, schema models.AttributeSchema) (models.AttributeSchema, error)
+	DeleteSchema(ctx context.Context, name string) error
+	ListValues(ctx context.Context, companyID uuid.UUID) ([]models.CompanyAttribute, error)
+	ReplaceValues(ctx context.Context, companyID uuid.UUID, values []models.CompanyAttribute) error
+}
+
+// LocationRepository defines the functionality of company location repository. Every change to
+// the locations of a company is a change of the company, the updated company is returned with them.
+type LocationRepository interface {
+	List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyLocation, error)
+	Find(ctx context.Context, companyID, locationID uuid.UUID) (models.CompanyLocation, error)
+	Save(ctx context.Context, actorID uuid.UUID, location models.CompanyLocation) (models.CompanyLocation, models.Company, error)
+	Update(ctx context.Context, actorID uuid.UUID, location models.CompanyLocation) (models.CompanyLocation, models.Company, error)
+	Delete(ctx context.Context, actorID, companyID, locationID uuid.UUID) (models.Company, error)
+}
+
+// AttachmentRepository defines the functionality of company attachment repository.
+type AttachmentRepository interface {
+	List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyAttachment, error)
+	Find(ctx context.Context, companyID, attachmentID uuid.UUID) (models.CompanyAttachment, error)
+	Save(ctx context.Context, attachment models.CompanyAttachment) (models.CompanyAttachment, error)
+	Delete(ctx context.Context, companyID, attachmentID uuid.UUID) error
+}
+
+// EmployeeCountRepository defines the functionality of the employee counts of companies,
+// counts are recorded by the company repository whenever the employees amount changes.
+type EmployeeCountRepository interface {
+	List(ctx context.Context, companyID uuid.UUID, until time.Time) ([]models.EmployeeCount, error)
+}
+
+// AttributeRef names a custom attribute and the type its values are stored as.
+type AttributeRef struct {
+	Name string
+	Type models.AttributeType
+}
+
+// AttributeOperator tells how the value of a custom attribute is compared.
+type AttributeOperator string
+
+const (
+	AttributeEqual AttributeOperator = "eq"
+	AttributeMin   AttributeOperator = "min" // The value is greater than or equal.
+	AttributeMax   AttributeOperator = "max" // The value is less than or equal.
+)
+
+// AttributeCondition compares the value companies have for a custom attribute, Value has
+// the Go type the values of the attribute are stored as.
+type AttributeCondition struct {
+	AttributeRef
+	Operator AttributeOperator
+	Value    any
+}
+
+// TransferRepository defines the functionality of company ownership transfers.
+type TransferRepository interface {
+	Save(ctx context.Context, transfer models.CompanyTransfer) (models.CompanyTransfer, error)
+	FindByID(ctx context.Context, id uuid.UUID) (models.CompanyTransfer, error)
+	ListPending(ctx context.Context, userID *uuid.UUID) ([]models.CompanyTransfer, error)
+	Accept(ctx context.Context, transferID uuid.UUID) (models.CompanyTransfer, models.Company, error)
+	Cancel(ctx context.Context, transferID, userID uuid.UUID) (models.CompanyTransfer, error)
+}
+
+// CompanyTypeRepository defines the functionality of the company type catalogue.
+type CompanyTypeRepository interface {
+	List(ctx context.Context) ([]models.CompanyType, error)
+	FindByName(ctx context.Context, name common.Type) (models.CompanyType, error)
+	Save(ctx context.Context, companyType models.CompanyType) (models.CompanyType, error)
+	Update(ctx context.Context, companyType models.CompanyType) (models.CompanyType, error)
+	Delete(ctx context.Context, name common.Type) error
+	Seed(ctx context.Context, companyTypes []models.CompanyType) error
+}
+
+// RevisionRepository defines the functionality of the company revision history.
+type RevisionRepository interface {
+	List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyRevision, error)
+	Find(ctx context.Context, companyID uuid.UUID, revision int) (models.CompanyRevision, *models.CompanyRevision, error)
+}
+
+// IdempotencyRepository defines the functionality of the idempotency key storage.
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record models.IdempotencyKey) (models.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, record models.IdempotencyKey) error
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+}
+
+// SearchRepository defines the functionality of the company search index.
+type SearchRepository interface {
+	Search(ctx context.Context, memberID uuid.UUID, terms []string, limit, offset int) ([]SearchHit, int, error)
+}
+
+// SearchHit represents a company matched by a search and its relevance score.
+type SearchHit struct {
+	Company models.Company
+	Score   float64
+}
+
+// CompanySortField is a column companies can be ordered by.
+type CompanySortField string
+
+const (
+	SortByCreatedAt       CompanySortField = "created_at"
+	SortByUpdatedAt       CompanySortField = "updated_at"
+	SortByName            CompanySortField = "name"
+	SortByEmployeesAmount CompanySortField = "employees_amount"
+)
+
+// CompanyFilter narrows down the companies returned by a query, nil fields are ignored.
+type CompanyFilter struct {
+	Types         []common.Type
+	Registered    *bool
+	MinEmployees  *int
+	MaxEmployees  *int
+	UserID        *uuid.UUID
+	MemberID      *uuid.UUID // Companies the user is a member of.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	TagsAll       []string             // Companies having every one of the tags.
+	TagsAny       []string             // Companies having at least one of the tags.
+	TagsNone      []string             // Companies having none of the tags.
+	Attributes    []AttributeCondition // Companies whose attributes meet every condition.
+}
+
+// CompanyCursor points at the last company of a page, the next page starts right after it.
+type CompanyCursor struct {
+	SortValue any
+	ID        uuid.UUID
+}
+
+// CompanyListQuery represents a single page request over companies.
+type CompanyListQuery struct {
+	Deleted       bool // List the soft deleted companies instead of the live ones.
+	Filter        CompanyFilter
+	SortBy        CompanySortField
+	SortAttribute *AttributeRef // Sort by a custom attribute instead, companies without a value for it are left out.
+	Descending    bool
+	After         *CompanyCursor
+	Limit         int
 }
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+)
+
+// SQLRevisionRepository reads the revision history recorded by the company repository.
+type SQLRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLRevisionRepository creates a new sql revision repository.
+func NewSQLRevisionRepository(db *gorm.DB) (*SQLRevisionRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLRevisionRepository{
+		db: db,
+	}, nil
+}
+
+// List returns the revisions of a company, oldest first.
+func (rr *SQLRevisionRepository) List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyRevision, error) {
+	var revisions []models.CompanyRevision
+	result := rr.db.WithContext(ctx).Where("company_id = ?", companyID).Order("revision ASC").Find(&revisions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", result.Error)
+	}
+
+	return revisions, nil
+}
+
+// Find returns a revision of a company together with the one recorded before it.
+// The previous revision is nil for the first revision of a company.
+func (rr *SQLRevisionRepository) Find(ctx context.Context, companyID uuid.UUID, revision int) (models.CompanyRevision, *models.CompanyRevision, error) {
+	var current models.CompanyRevision
+	result := rr.db.WithContext(ctx).Where("company_id = ? AND revision = ?", companyID, revision).First(&current)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.CompanyRevision{}, nil, fmt.Errorf("failed to find revision: %w", ErrRevisionNotFound)
+	}
+	if result.Error != nil {
+		return models.CompanyRevision{}, nil, fmt.Errorf("failed to find revision: %w", result.Error)
+	}
+
+	var previous []models.CompanyRevision
+	result = rr.db.WithContext(ctx).
+		Where("company_id = ? AND revision < ?", companyID, revision).
+		Order("revision DESC").
+		Limit(1).
+		Find(&previous)
+	if result.Error != nil {
+		return models.CompanyRevision{}, nil, fmt.Errorf("failed to find previous revision: %w", result.Error)
+	}
+	if len(previous) == 0 {
+		return current, nil, nil
+	}
+
+	return current, &previous[0], nil
+}
+
+// recordRevision stores a snapshot of the company as it is after a change made by the actor.
+// Locations are left out, the revisions of their changes only tell when the company moved.
+func recordRevision(tx *gorm.DB, action models.RevisionAction, actorID uuid.UUID, company models.Company) error {
+	company.ActiveName = nil
+	company.Locations = nil
+	return tx.Create(&models.CompanyRevision{
+		CompanyID: company.ID,
+		Revision:  company.Version,
+		Action:    action,
+		ActorID:   actorID,
+		Snapshot:  company,
+	}).Error
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLRevisionRepository(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		db     *gorm.DB
+		expErr string
+	}{
+		"no database": {
+			expErr: "db is nil",
+		},
+		"success": {
+			db: &gorm.DB{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			repo, err := NewSQLRevisionRepository(tt.db)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, repo)
+			} else {
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestSQLRevisionRepository_History(t *testing.T) {
+	db := setupTestDB(t)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	revisionRepo, err := NewSQLRevisionRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner, editor := uuid.New(), uuid.New()
+	id, err := companyRepo.Save(ctx, models.Company{
+		Name:            "Acme",
+		EmployeesAmount: 10,
+		Type:            common.Corporations,
+		UserID:          owner,
+	})
+	assert.NoError(t, err)
+
+	company, err := companyRepo.FindByID(ctx, id)
+	assert.NoError(t, err)
+	company.EmployeesAmount = 12
+	_, err = companyRepo.Update(ctx, editor, company, time.Time{})
+	assert.NoError(t, err)
+
+	err = companyRepo.Delete(ctx, owner, id, 0, ChildrenRestrict)
+	assert.NoError(t, err)
+
+	_, err = companyRepo.Restore(ctx, owner, id)
+	assert.NoError(t, err)
+
+	revisions, err := revisionRepo.List(ctx, id)
+	assert.NoError(t, err)
+	if assert.Len(t, revisions, 4) {
+		expActions := []models.RevisionAction{models.RevisionCreated, models.RevisionUpdated, models.RevisionDeleted, models.RevisionRestored}
+		expActors := []uuid.UUID{owner, editor, owner, owner}
+		for i, revision := range revisions {
+			assert.Equal(t, i+1, revision.Revision)
+			assert.Equal(t, expActions[i], revision.Action)
+			assert.Equal(t, expActors[i], revision.ActorID)
+			assert.Equal(t, id, revision.Snapshot.ID)
+		}
+		assert.Equal(t, 12, revisions[1].Snapshot.EmployeesAmount)
+		assert.True(t, revisions[2].Snapshot.DeletedAt.Valid)
+		assert.False(t, revisions[3].Snapshot.DeletedAt.Valid)
+	}
+
+	current, previous, err := revisionRepo.Find(ctx, id, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, current.Revision)
+	if assert.NotNil(t, previous) {
+		assert.Equal(t, 1, previous.Revision)
+		assert.Equal(t, 10, previous.Snapshot.EmployeesAmount)
+	}
+
+	_, previous, err = revisionRepo.Find(ctx, id, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, previous)
+
+	_, _, err = revisionRepo.Find(ctx, id, 9)
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+
+	err = companyRepo.Delete(ctx, owner, id, 0, ChildrenRestrict)
+	assert.NoError(t, err)
+	err = companyRepo.Purge(ctx, id)
+	assert.NoError(t, err)
+
+	revisions, err = revisionRepo.List(ctx, id)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+}
@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/search"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchable fields of a company and how much a match in them weighs.
+const (
+	searchFieldName        = "name"
+	searchFieldDescription = "description"
+)
+
+var searchFieldWeights = map[string]float64{
+	searchFieldName:        3,
+	searchFieldDescription: 1,
+}
+
+// prefixMatchWeight lowers the score of terms only matched as a prefix.
+const prefixMatchWeight = 0.5
+
+// maxTermExpansions caps the indexed terms a query term matches as a prefix.
+const maxTermExpansions = 50
+
+// SQLSearchRepository implements the company full-text search on top of the search index table.
+type SQLSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLSearchRepository creates a new sql search repository.
+func NewSQLSearchRepository(db *gorm.DB) (*SQLSearchRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLSearchRepository{
+		db: db,
+	}, nil
+}
+
+// Search returns the companies the member belongs to matching all the terms, best matches first,
+// and the total number of matches. Companies are scored and paged in SQL, only the page is loaded.
+func (sr *SQLSearchRepository) Search(ctx context.Context, memberID uuid.UUID, terms []string, limit, offset int) ([]SearchHit, int, error) {
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	tx := sr.db.WithContext(ctx)
+	var total int64
+	if err := tx.Model(&models.Company{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count companies: %w", err)
+	}
+
+	// every query term contributes the weights of the indexed terms it matches scaled by their idf
+	var indexed []string
+	scores := make([]string, 0, len(terms))
+	var args []any
+	for i, term := range terms {
+		expansions, err := sr.expand(tx, term)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(expansions) == 0 {
+			return []SearchHit{}, 0, nil
+		}
+
+		score := "SUM(CASE"
+		for _, expansion := range expansions {
+			score += " WHEN term = ? THEN weight * ?"
+			args = append(args, expansion.Term, scoreFactor(expansion, term, total))
+			indexed = append(indexed, expansion.Term)
+		}
+		scores = append(scores, fmt.Sprintf("%s ELSE 0 END) AS score%d", score, i))
+	}
+
+	scored := tx.Model(&models.CompanySearchTerm{}).
+		Select("company_id, "+strings.Join(scores, ", "), args...).
+		Where("term IN ?", indexed).
+		Where("company_id IN (?)", memberships(tx, memberID)).
+		Group("company_id")
+	matched := make([]string, 0, len(terms))
+	sum := make([]string, 0, len(terms))
+	for i := range terms {
+		matched = append(matched, fmt.Sprintf("score%d > 0", i))
+		sum = append(sum, fmt.Sprintf("score%d", i))
+	}
+	ranked := tx.Table("(?) AS scored", scored).Where(strings.Join(matched, " AND "))
+
+	var count int64
+	if err := ranked.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count matches: %w", err)
+	}
+
+	var page []rankedCompany
+	result := ranked.Session(&gorm.Session{}).
+		Select("company_id, " + strings.Join(sum, " + ") + " AS score").
+		Order("score DESC, company_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&page)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("failed to rank matches: %w", result.Error)
+	}
+	if len(page) == 0 {
+		return []SearchHit{}, int(count), nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(page))
+	for _, hit := range page {
+		ids = append(ids, hit.CompanyID)
+	}
+
+	var comps []models.Company
+	if err := tx.Where("id IN ?", ids).Find(&comps).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to find companies: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]models.Company, len(comps))
+	for _, comp := range comps {
+		byID[comp.ID] = comp
+	}
+
+	hits := make([]SearchHit, 0, len(page))
+	for _, hit := range page {
+		if comp, ok := byID[hit.CompanyID]; ok {
+			hits = append(hits, SearchHit{Company: comp, Score: hit.Score})
+		}
+	}
+
+	return hits, int(count), nil
+}
+
+// Rebuild drops the whole index and indexes every company again.
+func (sr *SQLSearchRepository) Rebuild(ctx context.Context) error {
+	return sr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.CompanySearchTerm{}).Error; err != nil {
+			return fmt.Errorf("failed to clear search index: %w", err)
+		}
+
+		var comps []models.Company
+		result := tx.FindInBatches(&comps, 500, func(_ *gorm.DB, _ int) error {
+			for _, comp := range comps {
+				if err := indexCompany(tx, comp); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to rebuild search index: %w", result.Error)
+		}
+
+		return nil
+	})
+}
+
+// rankedCompany is a company matching a search and its score.
+type rankedCompany struct {
+	CompanyID uuid.UUID
+	Score     float64
+}
+
+// termExpansion is an indexed term matched by a query term and the number of companies having it.
+type termExpansion struct {
+	Term string
+	Docs int64
+}
+
+// expand returns the indexed terms the query term matches, the query term itself and then the
+// most common ones first, at most maxTermExpansions of them.
+func (sr *SQLSearchRepository) expand(tx *gorm.DB, queryTerm string) ([]termExpansion, error) {
+	query := tx.Model(&models.CompanySearchTerm{}).Select("term, COUNT(DISTINCT company_id) AS docs")
+	if len([]rune(queryTerm)) < search.MinPrefixLength {
+		query = query.Where("term = ?", queryTerm)
+	} else {
+		query = query.Where("term LIKE ?", queryTerm+"%")
+	}
+
+	var expansions []termExpansion
+	result := query.Group("term").
+		Clauses(clause.OrderBy{Expression: clause.Expr{SQL: "term = ? DESC, docs DESC, term ASC", Vars: []any{queryTerm}}}).
+		Limit(maxTermExpansions).
+		Scan(&expansions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query search index: %w", result.Error)
+	}
+
+	return expansions, nil
+}
+
+// scoreFactor returns what the weight of an indexed term is multiplied by when matching the query
+// term: its inverse document frequency, lowered when the term is only matched as a prefix.
+func scoreFactor(expansion termExpansion, queryTerm string, totalCompanies int64) float64 {
+	df := float64(expansion.Docs)
+	idf := math.Log(1 + (float64(totalCompanies)-df+0.5)/(df+0.5))
+	if idf < 0.01 {
+		idf = 0.01
+	}
+	if expansion.Term != queryTerm {
+		return idf * prefixMatchWeight
+	}
+
+	return idf
+}
+
+// indexCompany replaces the index entries of a company with its current name and description.
+func indexCompany(tx *gorm.DB, company models.Company) error {
+	if err := unindexCompany(tx, company.ID); err != nil {
+		return err
+	}
+
+	var entries []models.CompanySearchTerm
+	fields := map[string]string{
+		searchFieldName:        company.Name,
+		searchFieldDescription: company.Description,
+	}
+	for field, text := range fields {
+		for term, freq := range search.Frequencies(text) {
+			entries = append(entries, models.CompanySearchTerm{
+				CompanyID: company.ID,
+				Field:     field,
+				Term:      term,
+				Frequency: freq,
+				Weight:    searchFieldWeights[field] * (1 + math.Log(float64(freq))),
+			})
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := tx.CreateInBatches(entries, 200).Error; err != nil {
+		return fmt.Errorf("failed to index company: %w", err)
+	}
+
+	return nil
+}
+
+// unindexCompany removes all the index entries of a company.
+func unindexCompany(tx *gorm.DB, companyID uuid.UUID) error {
+	if err := tx.Where("company_id = ?", companyID).Delete(&models.CompanySearchTerm{}).Error; err != nil {
+		return fmt.Errorf("failed to remove company from index: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLSearchRepository(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		db     *gorm.DB
+		expErr string
+	}{
+		"no database": {
+			db:     nil,
+			expErr: "db is nil",
+		},
+		"success": {
+			db: &gorm.DB{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			repo, err := NewSQLSearchRepository(tt.db)
+			if tt.expErr != "" {
+				assert.Nil(t, repo)
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestSQLSearchRepository_Search(t *testing.T) {
+	db := setupTestDB(t)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create company repository: ", err)
+	}
+	searchRepo, err := NewSQLSearchRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create search repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	companies := map[string]string{
+		"Rocket Labs":  "We build rockets and rocket engines",
+		"Rock Bakery":  "Bread and cakes, baked fresh every morning",
+		"Blue Rockets": "Toy rockets for kids",
+	}
+	ids := make(map[string]uuid.UUID, len(companies))
+	for name, description := range companies {
+		id, err := companyRepo.Save(ctx, models.Company{
+			Name:            name,
+			Description:     description,
+			EmployeesAmount: 10,
+			Type:            common.Corporations,
+			UserID:          owner,
+		})
+		if err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		ids[name] = id
+	}
+
+	names := func(hits []SearchHit) []string {
+		result := make([]string, 0, len(hits))
+		for _, hit := range hits {
+			result = append(result, hit.Company.Name)
+		}
+		return result
+	}
+
+	hits, total, err := searchRepo.Search(ctx, owner, []string{"rocket"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{"Rocket Labs", "Blue Rockets"}, names(hits), "exact and name matches rank first")
+
+	expansions, err := searchRepo.expand(db, "rock")
+	assert.NoError(t, err)
+	assert.Equal(t, []termExpansion{{Term: "rock", Docs: 1}, {Term: "rockets", Docs: 2}, {Term: "rocket", Docs: 1}}, expansions, "the query term itself first, then the most common")
+
+	_, total, err = searchRepo.Search(ctx, uuid.New(), []string{"rocket"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total, "only the companies of the member match")
+
+	hits, total, err = searchRepo.Search(ctx, owner, []string{"roc", "bread"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{"Rock Bakery"}, names(hits), "every term must match")
+
+	hits, total, err = searchRepo.Search(ctx, owner, []string{"roc"}, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, hits, 1)
+
+	// the index follows updates
+	bakery, err := companyRepo.FindByID(ctx, ids["Rock Bakery"])
+	assert.NoError(t, err)
+	bakery.Description = "Sourdough only"
+	_, err = companyRepo.Update(ctx, bakery.UserID, bakery, time.Time{})
+	assert.NoError(t, err)
+
+	_, total, err = searchRepo.Search(ctx, owner, []string{"bread"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	hits, _, err = searchRepo.Search(ctx, owner, []string{"sourdough"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Rock Bakery"}, names(hits))
+
+	// and deletions
+	err = companyRepo.Delete(ctx, owner, ids["Blue Rockets"], 0, ChildrenRestrict)
+	assert.NoError(t, err)
+
+	hits, _, err = searchRepo.Search(ctx, owner, []string{"rockets"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Rocket Labs"}, names(hits))
+
+	// words a case and accent insensitive collation compares equal are indexed as one term
+	cafe, err := companyRepo.Save(ctx, models.Company{
+		Name:            "Corner Café",
+		Description:     "cafe café CAFÉ",
+		EmployeesAmount: 3,
+		Type:            common.Corporations,
+		UserID:          owner,
+	})
+	assert.NoError(t, err)
+	var terms []models.CompanySearchTerm
+	assert.NoError(t, db.Where("company_id = ? AND field = ?", cafe, "description").Find(&terms).Error)
+	if assert.Len(t, terms, 1) {
+		assert.Equal(t, "cafe", terms[0].Term)
+		assert.Equal(t, 3, terms[0].Frequency)
+	}
+	hits, _, err = searchRepo.Search(ctx, owner, []string{"cafe"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Corner Café"}, names(hits))
+}
+
+func TestSQLSearchRepository_Rebuild(t *testing.T) {
+	db := setupTestDB(t)
+
+	searchRepo, err := NewSQLSearchRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create search repository: ", err)
+	}
+
+	// created behind the repository's back so it is not indexed yet
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	company := models.Company{
+		Name:            "Legacy Co",
+		EmployeesAmount: 22,
+		Type:            common.Corporations,
+		UserID:          owner,
+	}
+	db.Create(&company)
+	db.Create(&models.CompanyMember{CompanyID: company.ID, UserID: owner, Role: models.RoleOwner, GrantedBy: owner})
+
+	ctx := context.Background()
+	_, total, err := searchRepo.Search(ctx, owner, []string{"legacy"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	err = searchRepo.Rebuild(ctx)
+	assert.NoError(t, err)
+
+	hits, total, err := searchRepo.Search(ctx, owner, []string{"legacy"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, company.ID, hits[0].Company.ID)
+}
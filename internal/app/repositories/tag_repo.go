@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLTagRepository stores the tags of companies.
+type SQLTagRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLTagRepository creates a new sql tag repository.
+func NewSQLTagRepository(db *gorm.DB) (*SQLTagRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLTagRepository{
+		db: db,
+	}, nil
+}
+
+// ListByCompany returns the tags of a company ordered by name.
+func (tr *SQLTagRepository) ListByCompany(ctx context.Context, companyID uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	result := tr.db.WithContext(ctx).
+		Joins("JOIN company_tags ON company_tags.tag_name = tags.name").
+		Where("company_tags.company_id = ?", companyID).
+		Order("tags.name ASC").
+		Find(&tags)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", result.Error)
+	}
+
+	return tags, nil
+}
+
+// Attach tags a company on behalf of the user, the tag is created the first time it is used.
+// Attaching a tag the company already has does nothing.
+func (tr *SQLTagRepository) Attach(ctx context.Context, userID, companyID uuid.UUID, tag models.Tag) error {
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tag).Error; err != nil {
+			return err
+		}
+		link := models.CompanyTag{CompanyID: companyID, TagName: tag.Name, CreatedBy: userID}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach tag: %w", err)
+	}
+
+	return nil
+}
+
+// Detach removes a tag from a company.
+func (tr *SQLTagRepository) Detach(ctx context.Context, companyID uuid.UUID, name string) error {
+	result := tr.db.WithContext(ctx).Where("company_id = ? AND tag_name = ?", companyID, name).Delete(&models.CompanyTag{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to detach tag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to detach tag: %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// Usage returns the tags starting with the prefix along with the number of live companies of
+// the user using them, the most used first. Tags none of these companies use are left out.
+func (tr *SQLTagRepository) Usage(ctx context.Context, userID uuid.UUID, prefix string, limit int) ([]TagUsage, error) {
+	tx := tr.db.WithContext(ctx).
+		Model(&models.Tag{}).
+		Select("tags.name, tags.label, COUNT(*) AS companies").
+		Joins("JOIN company_tags ON company_tags.tag_name = tags.name").
+		Joins("JOIN companies ON companies.id = company_tags.company_id AND companies.deleted_at IS NULL").
+		Joins("JOIN company_members ON company_members.company_id = companies.id AND company_members.user_id = ?", userID)
+	if prefix != "" {
+		tx = tx.Where("tags.name LIKE ? ESCAPE '!'", escapeLike(prefix)+"%")
+	}
+
+	var rows []struct {
+		Name      string
+		Label     string
+		Companies int
+	}
+	result := tx.Group("tags.name, tags.label").Order("companies DESC, tags.name ASC").Limit(limit).Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count tag usage: %w", result.Error)
+	}
+
+	usage := make([]TagUsage, 0, len(rows))
+	for _, row := range rows {
+		usage = append(usage, TagUsage{Tag: models.Tag{Name: row.Name, Label: row.Label}, Companies: row.Companies})
+	}
+
+	return usage, nil
+}
+
+// likeEscaper escapes the LIKE wildcards with the escape character given to ESCAPE.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
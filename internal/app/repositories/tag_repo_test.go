@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLTagRepository(t *testing.T) {
+	t.Parallel()
+	repo, err := NewSQLTagRepository(nil)
+	assert.EqualError(t, err, "db is nil")
+	assert.Nil(t, repo)
+
+	repo, err = NewSQLTagRepository(&gorm.DB{})
+	assert.NoError(t, err)
+	assert.NotNil(t, repo)
+}
+
+func TestSQLTagRepository(t *testing.T) {
+	db := setupTestDB(t)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	repo, err := NewSQLTagRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	ids := map[string]uuid.UUID{}
+	for _, name := range []string{"Acme", "Globex", "Initech", "Umbrella"} {
+		id, err := companyRepo.Save(ctx, models.Company{Name: name, Type: common.Corporations, UserID: owner})
+		if err != nil {
+			t.Fatal("Failed to save company: ", err)
+		}
+		ids[name] = id
+	}
+	eu, partner, priority := models.Tag{Name: "eu", Label: "EU"}, models.Tag{Name: "partner", Label: "partner"}, models.Tag{Name: "priority", Label: "Priority"}
+	attach := map[string][]models.Tag{
+		"Acme":     {eu, priority},
+		"Globex":   {eu, partner},
+		"Initech":  {priority},
+		"Umbrella": {eu},
+	}
+	for name, tags := range attach {
+		for _, tag := range tags {
+			assert.NoError(t, repo.Attach(ctx, owner, ids[name], tag))
+		}
+	}
+	// attaching twice does nothing and the first label is kept
+	assert.NoError(t, repo.Attach(ctx, owner, ids["Acme"], models.Tag{Name: "eu", Label: "Eu"}))
+
+	tags, err := repo.ListByCompany(ctx, ids["Acme"])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EU", "Priority"}, tagLabels(tags))
+
+	assert.ErrorIs(t, repo.Detach(ctx, ids["Initech"], "eu"), ErrNotFound)
+	assert.NoError(t, repo.Detach(ctx, ids["Umbrella"], "eu"))
+
+	cases := map[string]struct {
+		filter   CompanyFilter
+		expNames []string
+	}{
+		"all of": {
+			filter:   CompanyFilter{TagsAll: []string{"EU", "priority"}},
+			expNames: []string{"Acme"},
+		},
+		"all of a repeated tag": {
+			filter:   CompanyFilter{TagsAll: []string{"eu", "EU"}},
+			expNames: []string{"Acme", "Globex"},
+		},
+		"any of": {
+			filter:   CompanyFilter{TagsAny: []string{"partner", "priority"}},
+			expNames: []string{"Acme", "Globex", "Initech"},
+		},
+		"none of": {
+			filter:   CompanyFilter{TagsNone: []string{"eu"}},
+			expNames: []string{"Initech", "Umbrella"},
+		},
+		"combined": {
+			filter:   CompanyFilter{TagsAny: []string{"eu"}, TagsNone: []string{"partner"}},
+			expNames: []string{"Acme"},
+		},
+	}
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			comps, err := companyRepo.List(ctx, CompanyListQuery{SortBy: SortByName, Limit: 10, Filter: tt.filter})
+			assert.NoError(t, err)
+			names := make([]string, 0, len(comps))
+			for _, comp := range comps {
+				names = append(names, comp.Name)
+			}
+			assert.Equal(t, tt.expNames, names)
+		})
+	}
+
+	// deleted companies are not counted
+	assert.NoError(t, companyRepo.Delete(ctx, owner, ids["Initech"], 0, ChildrenRestrict))
+	usage, err := repo.Usage(ctx, owner, "", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []TagUsage{{Tag: eu, Companies: 2}, {Tag: partner, Companies: 1}, {Tag: priority, Companies: 1}}, usage)
+
+	usage, err = repo.Usage(ctx, owner, "p", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []TagUsage{{Tag: partner, Companies: 1}}, usage)
+
+	usage, err = repo.Usage(ctx, owner, "%", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, usage, "wildcards are matched literally")
+
+	// the tags of companies the user is not a member of are not suggested
+	stranger := uuid.New()
+	hooli, err := companyRepo.Save(ctx, models.Company{Name: "Hooli", Type: common.Corporations, UserID: stranger})
+	if err != nil {
+		t.Fatal("Failed to save company: ", err)
+	}
+	assert.NoError(t, repo.Attach(ctx, stranger, hooli, models.Tag{Name: "secret", Label: "Secret"}))
+	assert.NoError(t, repo.Attach(ctx, stranger, hooli, eu))
+	usage, err = repo.Usage(ctx, owner, "", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []TagUsage{{Tag: eu, Companies: 2}, {Tag: partner, Companies: 1}, {Tag: priority, Companies: 1}}, usage)
+	usage, err = repo.Usage(ctx, stranger, "", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []TagUsage{{Tag: eu, Companies: 1}, {Tag: models.Tag{Name: "secret", Label: "Secret"}, Companies: 1}}, usage)
+}
+
+func tagLabels(tags []models.Tag) []string {
+	labels := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		labels = append(labels, tag.Label)
+	}
+	return labels
+}
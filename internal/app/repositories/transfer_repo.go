@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"gorm.io/gorm"
+)
+
+// SQLTransferRepository stores company ownership transfers and applies the accepted ones.
+type SQLTransferRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLTransferRepository creates a new sql transfer repository.
+func NewSQLTransferRepository(db *gorm.DB) (*SQLTransferRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	return &SQLTransferRepository{
+		db: db,
+	}, nil
+}
+
+// Save stores a new pending transfer, it fails with ErrTransferPending when the company
+// already has one.
+func (tr *SQLTransferRepository) Save(ctx context.Context, transfer models.CompanyTransfer) (models.CompanyTransfer, error) {
+	result := tr.db.WithContext(ctx).Create(&transfer)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to save transfer: %w", ErrTransferPending)
+	}
+	if result.Error != nil {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to save transfer: %w", result.Error)
+	}
+
+	return transfer, nil
+}
+
+// FindByID returns a transfer by id.
+func (tr *SQLTransferRepository) FindByID(ctx context.Context, id uuid.UUID) (models.CompanyTransfer, error) {
+	var transfer models.CompanyTransfer
+	result := tr.db.WithContext(ctx).Where("id = ?", id).First(&transfer)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to find transfer: %w", ErrNotFound)
+	}
+	if result.Error != nil {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to find transfer: %w", result.Error)
+	}
+
+	return transfer, nil
+}
+
+// ListPending returns the pending transfers the user gives or receives, oldest first.
+// The transfers of every user are returned when userID is nil.
+func (tr *SQLTransferRepository) ListPending(ctx context.Context, userID *uuid.UUID) ([]models.CompanyTransfer, error) {
+	tx := tr.db.WithContext(ctx).Where("status = ?", models.TransferPending)
+	if userID != nil {
+		tx = tx.Where("from_user_id = ? OR to_user_id = ?", *userID, *userID)
+	}
+
+	var transfers []models.CompanyTransfer
+	result := tx.Order("created_at ASC, id ASC").Find(&transfers)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list transfers: %w", result.Error)
+	}
+
+	return transfers, nil
+}
+
+// Accept hands the company of a pending transfer over to its recipient and records the change
+// in the company history. The recipient becomes an owner and the previous owner loses access.
+// It fails with ErrTransferResolved when the transfer is no longer pending and with ErrNotFound
+// when the company was deleted in the meantime.
+func (tr *SQLTransferRepository) Accept(ctx context.Context, transferID uuid.UUID) (models.CompanyTransfer, models.Company, error) {
+	var transfer models.CompanyTransfer
+	var comp models.Company
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", transferID).First(&transfer).Error; err != nil {
+			return companyError(err)
+		}
+		if err := resolveTransfer(tx, &transfer, models.TransferAccepted, transfer.ToUserID); err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", transfer.CompanyID).Where("user_id = ?", transfer.FromUserID).First(&comp)
+		if result.Error != nil {
+			return companyError(result.Error)
+		}
+		result = tx.Model(&comp).Where("version = ?", comp.Version).Updates(map[string]any{
+			"user_id": transfer.ToUserID,
+			"version": gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		if err := tx.Where("id = ?", comp.ID).First(&comp).Error; err != nil {
+			return err
+		}
+		if err := handOverMembership(tx, transfer); err != nil {
+			return err
+		}
+		return recordRevision(tx, models.RevisionTransferred, transfer.ToUserID, comp)
+	})
+	if err != nil {
+		return models.CompanyTransfer{}, models.Company{}, fmt.Errorf("failed to accept transfer: %w", err)
+	}
+
+	return transfer, comp, nil
+}
+
+// Cancel withdraws a pending transfer on behalf of the user, it fails with ErrTransferResolved
+// when the transfer is no longer pending.
+func (tr *SQLTransferRepository) Cancel(ctx context.Context, transferID, userID uuid.UUID) (models.CompanyTransfer, error) {
+	var transfer models.CompanyTransfer
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", transferID).First(&transfer).Error; err != nil {
+			return companyError(err)
+		}
+		return resolveTransfer(tx, &transfer, models.TransferCancelled, userID)
+	})
+	if err != nil {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to cancel transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// handOverMembership replaces the previous owner of a transferred company with the recipient.
+func handOverMembership(tx *gorm.DB, transfer models.CompanyTransfer) error {
+	err := tx.Where("company_id = ? AND user_id = ?", transfer.CompanyID, transfer.FromUserID).Delete(&models.CompanyMember{}).Error
+	if err != nil {
+		return err
+	}
+
+	return saveMember(tx, models.CompanyMember{
+		CompanyID: transfer.CompanyID,
+		UserID:    transfer.ToUserID,
+		Role:      models.RoleOwner,
+		GrantedBy: transfer.FromUserID,
+	})
+}
+
+// resolveTransfer moves a pending transfer to its final status.
+func resolveTransfer(tx *gorm.DB, transfer *models.CompanyTransfer, status models.TransferStatus, userID uuid.UUID) error {
+	now := time.Now()
+	result := tx.Model(transfer).Where("status = ?", models.TransferPending).Updates(map[string]any{
+		"status":             status,
+		"pending_company_id": nil,
+		"resolved_by":        userID,
+		"resolved_at":        now,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferResolved
+	}
+
+	return tx.Where("id = ?", transfer.ID).First(transfer).Error
+}
+
+// cancelPendingTransfers cancels the pending transfer of a company that is going away.
+func cancelPendingTransfers(tx *gorm.DB, companyID, userID uuid.UUID) error {
+	return tx.Model(&models.CompanyTransfer{}).
+		Where("company_id = ?", companyID).
+		Where("status = ?", models.TransferPending).
+		Updates(map[string]any{
+			"status":             models.TransferCancelled,
+			"pending_company_id": nil,
+			"resolved_by":        userID,
+			"resolved_at":        time.Now(),
+		}).Error
+}
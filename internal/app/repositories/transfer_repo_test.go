@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLTransferRepository(t *testing.T) {
+	t.Parallel()
+	repo, err := NewSQLTransferRepository(nil)
+	assert.EqualError(t, err, "db is nil")
+	assert.Nil(t, repo)
+
+	repo, err = NewSQLTransferRepository(&gorm.DB{})
+	assert.NoError(t, err)
+	assert.NotNil(t, repo)
+}
+
+func TestSQLTransferRepository(t *testing.T) {
+	db := setupTestDB(t)
+
+	companyRepo, err := NewSQLCompanyRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+	repo, err := NewSQLTransferRepository(db)
+	if err != nil {
+		t.Fatal("Failed to create repository: ", err)
+	}
+
+	ctx := context.Background()
+	owner := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	recipient := uuid.MustParse("5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11")
+	companyID, err := companyRepo.Save(ctx, models.Company{Name: "Acme", Type: common.Corporations, UserID: owner})
+	assert.NoError(t, err)
+
+	offer := models.CompanyTransfer{CompanyID: companyID, FromUserID: owner, ToUserID: recipient, InitiatorID: owner}
+	cancelled, err := repo.Save(ctx, offer)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferPending, cancelled.Status)
+
+	_, err = repo.Save(ctx, offer)
+	assert.ErrorIs(t, err, ErrTransferPending, "a company has a single pending transfer")
+
+	pending, err := repo.ListPending(ctx, &recipient)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	stranger := uuid.New()
+	pending, err = repo.ListPending(ctx, &stranger)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+
+	cancelled, err = repo.Cancel(ctx, cancelled.ID, recipient)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferCancelled, cancelled.Status)
+	assert.Equal(t, &recipient, cancelled.ResolvedBy)
+	assert.NotNil(t, cancelled.ResolvedAt)
+
+	_, _, err = repo.Accept(ctx, cancelled.ID)
+	assert.ErrorIs(t, err, ErrTransferResolved)
+
+	// once resolved the company can be offered again
+	transfer, err := repo.Save(ctx, offer)
+	assert.NoError(t, err)
+	accepted, comp, err := repo.Accept(ctx, transfer.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferAccepted, accepted.Status)
+	assert.Equal(t, recipient, comp.UserID)
+	assert.Equal(t, 2, comp.Version)
+
+	_, err = repo.Cancel(ctx, transfer.ID, owner)
+	assert.ErrorIs(t, err, ErrTransferResolved)
+	_, err = repo.Cancel(ctx, uuid.New(), owner)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	var revision models.CompanyRevision
+	db.Where("company_id = ? AND revision = ?", companyID, 2).First(&revision)
+	assert.Equal(t, models.RevisionTransferred, revision.Action)
+	assert.Equal(t, recipient, revision.ActorID)
+
+	// the new owner can delete the company, which cancels its pending transfer
+	transfer, err = repo.Save(ctx, models.CompanyTransfer{CompanyID: companyID, FromUserID: recipient, ToUserID: owner, InitiatorID: recipient})
+	assert.NoError(t, err)
+	err = companyRepo.Delete(ctx, recipient, companyID, 0, ChildrenRestrict)
+	assert.NoError(t, err)
+	transfer, err = repo.FindByID(ctx, transfer.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferCancelled, transfer.Status)
+
+	all, err := repo.ListPending(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, all)
+}
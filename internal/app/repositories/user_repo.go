@@ -40,6 +40,9 @@ func (u *SQLUserRepository) Save(ctx context.Context, user models.User) (uuid.UU
 func (u *SQLUserRepository) FindByUserName(ctx context.Context, username string) (models.User, error) {
 	var user models.User
 	result := u.db.WithContext(ctx).Where("username = ?", username).First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.User{}, fmt.Errorf("failed to find user: %w", ErrNotFound)
+	}
 	if result.Error != nil {
 		return models.User{}, fmt.Errorf("failed to find user: %w", result.Error)
 	}
@@ -51,6 +54,9 @@ func (u *SQLUserRepository) FindByUserName(ctx context.Context, username string)
 func (u *SQLUserRepository) FindByID(ctx context.Context, id uuid.UUID) (models.User, error) {
 	var user models.User
 	result := u.db.WithContext(ctx).Where("id = ?", id).First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.User{}, fmt.Errorf("failed to find user: %w", ErrNotFound)
+	}
 	if result.Error != nil {
 		return models.User{}, fmt.Errorf("failed to find user: %w", result.Error)
 	}
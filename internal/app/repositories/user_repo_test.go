@@ -43,12 +43,13 @@ func TestNewSQLLiteUserRepository(t *testing.T) {
 
 func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
 	})
 	if err != nil {
 		t.Fatalf("Failed to connect to the database: %v", err)
 	}
-	_ = db.AutoMigrate(&models.User{}, &models.Company{})
+	_ = db.AutoMigrate(&models.User{}, &models.Company{}, &models.CompanySearchTerm{}, &models.CompanyRevision{}, &models.IdempotencyKey{}, &models.CompanyType{}, &models.CompanySlug{}, &models.CompanyTransfer{}, &models.CompanyMember{}, &models.Tag{}, &models.CompanyTag{}, &models.AttributeSchema{}, &models.CompanyAttribute{}, &models.CompanyLocation{}, &models.CompanyAttachment{}, &models.CompanyTransition{}, &models.EmployeeCount{})
 	return db
 }
 
@@ -0,0 +1,180 @@
+// Package search contains the text processing shared by the company search index and its results.
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxTermLength is the longest term stored in the index, longer tokens are truncated.
+const MaxTermLength = 64
+
+// MinPrefixLength is the shortest query term that is also matched as a prefix.
+const MinPrefixLength = 2
+
+var folder = cases.Fold()
+
+// Tokenize splits a text into case-folded terms made of letters and digits. Accents and
+// compatibility forms are dropped, so "Café" and "cafe" are the same term, the way MySQL
+// compares them.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(fold(text), isSeparator)
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		terms = append(terms, truncate(f))
+	}
+
+	return terms
+}
+
+// Frequencies returns how many times each term of the text occurs in it.
+func Frequencies(text string) map[string]int {
+	freqs := make(map[string]int)
+	for _, term := range Tokenize(text) {
+		freqs[term]++
+	}
+
+	return freqs
+}
+
+// Matches reports whether an indexed term is matched by a query term.
+// Query terms match exactly and, when long enough, as a prefix.
+func Matches(term, queryTerm string) bool {
+	if len([]rune(queryTerm)) < MinPrefixLength {
+		return term == queryTerm
+	}
+
+	return strings.HasPrefix(term, queryTerm)
+}
+
+// Highlight HTML-escapes the text and wraps every word matched by the query terms in <em> tags.
+func Highlight(text string, queryTerms []string) string {
+	var b strings.Builder
+	forEachWord(text, func(word string, isWord bool) {
+		escaped := html.EscapeString(word)
+		if isWord && matchesWord(word, queryTerms) {
+			b.WriteString("<em>" + escaped + "</em>")
+			return
+		}
+		b.WriteString(escaped)
+	})
+
+	return b.String()
+}
+
+// Snippet returns a highlighted excerpt of at most maxWords words around the first match.
+// An empty string is returned when nothing in the text matches.
+func Snippet(text string, queryTerms []string, maxWords int) string {
+	type chunk struct {
+		text   string
+		isWord bool
+	}
+
+	var chunks []chunk
+	first, words := -1, 0
+	forEachWord(text, func(word string, isWord bool) {
+		if isWord {
+			if first < 0 && matchesWord(word, queryTerms) {
+				first = words
+			}
+			words++
+		}
+		chunks = append(chunks, chunk{text: word, isWord: isWord})
+	})
+	if first < 0 {
+		return ""
+	}
+
+	// start a few words before the match so it has some context
+	start := first - maxWords/4
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxWords
+	if end > words {
+		end = words
+		start = end - maxWords
+		if start < 0 {
+			start = 0
+		}
+	}
+
+	var b strings.Builder
+	word := 0
+	for _, c := range chunks {
+		if word >= start && word < end {
+			b.WriteString(c.text)
+		}
+		if c.isWord {
+			word++
+		}
+	}
+	snippet := strings.TrimSpace(b.String())
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < words {
+		snippet += "…"
+	}
+
+	return Highlight(snippet, queryTerms)
+}
+
+// forEachWord walks the text calling fn for every word and every run of separators in order.
+func forEachWord(text string, fn func(s string, isWord bool)) {
+	start, inWord := 0, false
+	for i, r := range text {
+		sep := isSeparator(r)
+		if i > start && sep == inWord {
+			fn(text[start:i], inWord)
+			start = i
+		}
+		inWord = !sep
+	}
+	if start < len(text) {
+		fn(text[start:], inWord)
+	}
+}
+
+// matchesWord reports whether a word of a text is matched by one of the query terms.
+// Folding can split a word, "½" becomes "1⁄2", any of its terms may match.
+func matchesWord(word string, queryTerms []string) bool {
+	for _, term := range Tokenize(word) {
+		for _, q := range queryTerms {
+			if Matches(term, q) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fold case-folds the text and drops the combining marks left over by the decomposition
+// of accented letters.
+func fold(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(folder.String(text)) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func truncate(term string) string {
+	if r := []rune(term); len(r) > MaxTermLength {
+		return string(r[:MaxTermLength])
+	}
+
+	return term
+}
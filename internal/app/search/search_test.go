@@ -0,0 +1,148 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		text      string
+		expTokens []string
+	}{
+		"empty": {
+			text:      "",
+			expTokens: []string{},
+		},
+		"punctuation and case": {
+			text:      "Acme, Inc. builds ROCKETS!",
+			expTokens: []string{"acme", "inc", "builds", "rockets"},
+		},
+		"unicode and digits": {
+			text:      "Müller GmbH 2023",
+			expTokens: []string{"muller", "gmbh", "2023"},
+		},
+		"accents and compatibility forms are folded": {
+			text:      "Café cafe CAFÉ ﬁsh Straße",
+			expTokens: []string{"cafe", "cafe", "cafe", "fish", "strasse"},
+		},
+		"long token is truncated": {
+			text:      strings.Repeat("a", MaxTermLength+10),
+			expTokens: []string{strings.Repeat("a", MaxTermLength)},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expTokens, Tokenize(tt.text))
+		})
+	}
+}
+
+func TestFrequencies(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, map[string]int{"to": 2, "be": 2, "or": 1, "not": 1}, Frequencies("To be, or not to be"))
+}
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		term      string
+		queryTerm string
+		expMatch  bool
+	}{
+		"exact":                     {term: "acme", queryTerm: "acme", expMatch: true},
+		"prefix":                    {term: "acme", queryTerm: "ac", expMatch: true},
+		"single char is not prefix": {term: "acme", queryTerm: "a", expMatch: false},
+		"single char exact":         {term: "a", queryTerm: "a", expMatch: true},
+		"no match":                  {term: "acme", queryTerm: "me", expMatch: false},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expMatch, Matches(tt.term, tt.queryTerm))
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		text       string
+		queryTerms []string
+		expText    string
+	}{
+		"no match": {
+			text:       "Acme Corp",
+			queryTerms: []string{"foo"},
+			expText:    "Acme Corp",
+		},
+		"prefix match keeps case": {
+			text:       "Acme Corp",
+			queryTerms: []string{"ac"},
+			expText:    "<em>Acme</em> Corp",
+		},
+		"html is escaped": {
+			text:       "<b>Acme</b> & Co",
+			queryTerms: []string{"acme", "co"},
+			expText:    "&lt;b&gt;<em>Acme</em>&lt;/b&gt; &amp; <em>Co</em>",
+		},
+		"accents are ignored": {
+			text:       "Café Müller",
+			queryTerms: []string{"cafe"},
+			expText:    "<em>Café</em> Müller",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expText, Highlight(tt.text, tt.queryTerms))
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		text       string
+		queryTerms []string
+		maxWords   int
+		expText    string
+	}{
+		"no match": {
+			text:       "we build rockets",
+			queryTerms: []string{"boats"},
+			maxWords:   4,
+			expText:    "",
+		},
+		"short text": {
+			text:       "we build rockets",
+			queryTerms: []string{"rockets"},
+			maxWords:   4,
+			expText:    "we build <em>rockets</em>",
+		},
+		"excerpt around the match": {
+			text:       "one two three four five six seven eight nine ten",
+			queryTerms: []string{"six"},
+			maxWords:   4,
+			expText:    "…five <em>six</em> seven eight…",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expText, Snippet(tt.text, tt.queryTerms, tt.maxWords))
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/blobstore"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// limits of attachments, the file name length matches the size of the database column.
+const (
+	maxFileNameLength = 255
+	sniffLength       = 3072 // Bytes of the content the type is detected from.
+)
+
+// attachmentTypes are the content types accepted for every kind of attachment.
+var attachmentTypes = map[models.AttachmentKind][]string{
+	models.AttachmentLogo:     {"image/png", "image/jpeg", "image/gif", "image/webp", "image/svg+xml"},
+	models.AttachmentDocument: {"application/pdf", "image/png", "image/jpeg", "image/tiff"},
+}
+
+var (
+	// ErrAttachmentNotFound is returned when a company has no attachment with the requested id.
+	ErrAttachmentNotFound = newError(ErrNotFound, "attachment_not_found", "attachment not found")
+	// ErrInvalidAttachment is returned when a file cannot be attached to a company.
+	ErrInvalidAttachment = newError(ErrValidation, "invalid_attachment", "invalid attachment")
+	// ErrAttachmentTooLarge is returned when a file is larger than the configured limit.
+	ErrAttachmentTooLarge = newError(ErrValidation, "attachment_too_large", "attachment too large")
+	// ErrInvalidDownloadLink is returned when a download link was tampered with or expired.
+	ErrInvalidDownloadLink = newError(ErrForbidden, "invalid_download_link", "invalid download link")
+)
+
+// CompanyAttachments defines the functionality related to the files attached to companies.
+// Members read the attachments, the content is downloaded with signed links that expire.
+type CompanyAttachments interface {
+	ListAttachments(ctx context.Context, userID, companyID uuid.UUID) ([]SignedAttachment, error)
+	GetAttachment(ctx context.Context, userID, companyID, attachmentID uuid.UUID) (SignedAttachment, error)
+	UploadAttachment(ctx context.Context, userID, companyID uuid.UUID, upload AttachmentUpload) (SignedAttachment, error)
+	DeleteAttachment(ctx context.Context, userID, companyID, attachmentID uuid.UUID) error
+	OpenAttachment(ctx context.Context, companyID, attachmentID uuid.UUID, link DownloadLink) (models.CompanyAttachment, io.ReadCloser, error)
+}
+
+// AttachmentUpload is a file being attached to a company.
+type AttachmentUpload struct {
+	Kind     models.AttachmentKind
+	FileName string
+	Content  io.Reader
+}
+
+// DownloadLink authorizes whoever holds it to download an attachment until it expires.
+type DownloadLink struct {
+	Expires   time.Time
+	Signature string
+}
+
+// SignedAttachment is an attachment along with a link to download it.
+type SignedAttachment struct {
+	Attachment models.CompanyAttachment
+	Download   DownloadLink
+}
+
+// AttachmentSettings are the limits of attachments and the signing of their download links.
+type AttachmentSettings struct {
+	MaxSize    int64
+	SigningKey string
+	LinkTTL    time.Duration
+}
+
+// AttachmentService represents the company attachment service.
+type AttachmentService struct {
+	attachmentRepo repositories.AttachmentRepository
+	companyRepo    repositories.CompanyRepository
+	store          blobstore.Store
+	settings       AttachmentSettings
+	access         companyAccess
+}
+
+// NewAttachmentService creates a new attachment service, the attachments of a company are changed by its editors.
+func NewAttachmentService(attachmentRepo repositories.AttachmentRepository, companyRepo repositories.CompanyRepository, memberRepo repositories.MemberRepository, store blobstore.Store, settings AttachmentSettings) (*AttachmentService, error) {
+	if attachmentRepo == nil {
+		return nil, errors.New("attachment repository is nil")
+	}
+
+	if companyRepo == nil {
+		return nil, errors.New("company repository is nil")
+	}
+
+	if memberRepo == nil {
+		return nil, errors.New("member repository is nil")
+	}
+
+	if store == nil {
+		return nil, errors.New("blob store is nil")
+	}
+
+	if settings.MaxSize <= 0 {
+		return nil, errors.New("attachment max size must be positive")
+	}
+
+	if settings.SigningKey == "" {
+		return nil, errors.New("attachment signing key is empty")
+	}
+
+	if settings.LinkTTL <= 0 {
+		return nil, errors.New("attachment link ttl must be positive")
+	}
+
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		companyRepo:    companyRepo,
+		store:          store,
+		settings:       settings,
+		access:         companyAccess{memberRepo: memberRepo},
+	}, nil
+}
+
+// ListAttachments lists the attachments of a company to a viewer of it, the latest first.
+func (s *AttachmentService) ListAttachments(ctx context.Context, userID, companyID uuid.UUID) ([]SignedAttachment, error) {
+	if err := s.findAccessible(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.List(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	signed := make([]SignedAttachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		signed = append(signed, s.signed(attachment))
+	}
+
+	return signed, nil
+}
+
+// GetAttachment returns a single attachment of a company to a viewer of it.
+func (s *AttachmentService) GetAttachment(ctx context.Context, userID, companyID, attachmentID uuid.UUID) (SignedAttachment, error) {
+	if err := s.findAccessible(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return SignedAttachment{}, err
+	}
+
+	attachment, err := s.find(ctx, companyID, attachmentID)
+	if err != nil {
+		return SignedAttachment{}, err
+	}
+
+	return s.signed(attachment), nil
+}
+
+// UploadAttachment attaches a file to a company on behalf of an editor of it. The type of the
+// file is detected from its content and has to suit the kind of attachment, the checksum of
+// the content is kept to let clients verify downloads.
+func (s *AttachmentService) UploadAttachment(ctx context.Context, userID, companyID uuid.UUID, upload AttachmentUpload) (SignedAttachment, error) {
+	if err := s.findAccessible(ctx, userID, companyID, models.RoleEditor); err != nil {
+		return SignedAttachment{}, err
+	}
+
+	fileName := path.Base(strings.ReplaceAll(strings.TrimSpace(upload.FileName), `\`, "/"))
+	var violations []Violation
+	if _, ok := attachmentTypes[upload.Kind]; !ok {
+		violations = append(violations, Violation{Field: "kind", Message: fmt.Sprintf("must be one of %s or %s", models.AttachmentLogo, models.AttachmentDocument)})
+	}
+	switch {
+	case fileName == "" || fileName == "." || fileName == "/":
+		violations = append(violations, Violation{Field: "file", Message: "must have a name"})
+	case utf8.RuneCountInString(fileName) > maxFileNameLength:
+		violations = append(violations, Violation{Field: "file", Message: fmt.Sprintf("must have a name at most %d characters long", maxFileNameLength)})
+	}
+	if err := newValidationError(ErrInvalidAttachment, violations); err != nil {
+		return SignedAttachment{}, err
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(upload.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return SignedAttachment{}, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	head = head[:n]
+	if n == 0 {
+		return SignedAttachment{}, fmt.Errorf("%w: the file is empty", ErrInvalidAttachment)
+	}
+	contentType := mimetype.Detect(head)
+	if !acceptsType(upload.Kind, contentType) {
+		return SignedAttachment{}, fmt.Errorf("%w: a %s cannot be of type %s, expected one of %s", ErrInvalidAttachment, upload.Kind, contentType, strings.Join(attachmentTypes[upload.Kind], ", "))
+	}
+
+	attachment := models.CompanyAttachment{
+		ID:          uuid.New(),
+		CompanyID:   companyID,
+		Kind:        upload.Kind,
+		FileName:    fileName,
+		ContentType: contentType.String(),
+		UploadedBy:  userID,
+	}
+	key := attachmentKey(attachment)
+
+	// one byte past the limit is read to tell a file of the maximum size from a larger one
+	var size byteCounter
+	checksum := sha256.New()
+	content := io.TeeReader(io.LimitReader(io.MultiReader(bytes.NewReader(head), upload.Content), s.settings.MaxSize+1), io.MultiWriter(checksum, &size))
+	if err := s.store.Put(ctx, key, content); err != nil {
+		return SignedAttachment{}, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if int64(size) > s.settings.MaxSize {
+		s.discard(ctx, key)
+		return SignedAttachment{}, fmt.Errorf("%w: must be at most %d bytes", ErrAttachmentTooLarge, s.settings.MaxSize)
+	}
+	attachment.Size = int64(size)
+	attachment.Checksum = hex.EncodeToString(checksum.Sum(nil))
+
+	saved, err := s.attachmentRepo.Save(ctx, attachment)
+	if err != nil {
+		s.discard(ctx, key)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return SignedAttachment{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+		}
+		return SignedAttachment{}, fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	return s.signed(saved), nil
+}
+
+// DeleteAttachment removes an attachment of a company and its content on behalf of an editor of it.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID, companyID, attachmentID uuid.UUID) error {
+	if err := s.findAccessible(ctx, userID, companyID, models.RoleEditor); err != nil {
+		return err
+	}
+
+	attachment, err := s.find(ctx, companyID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	err = s.attachmentRepo.Delete(ctx, companyID, attachmentID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachmentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	err = s.store.Delete(ctx, attachmentKey(attachment))
+	if err != nil && !errors.Is(err, blobstore.ErrNotFound) {
+		return fmt.Errorf("failed to delete attachment content: %w", err)
+	}
+
+	return nil
+}
+
+// OpenAttachment returns the content of an attachment to whoever holds a valid download link
+// of it, the caller closes the content.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, companyID, attachmentID uuid.UUID, link DownloadLink) (models.CompanyAttachment, io.ReadCloser, error) {
+	expected := s.sign(companyID, attachmentID, link.Expires)
+	if !hmac.Equal([]byte(expected), []byte(link.Signature)) {
+		return models.CompanyAttachment{}, nil, fmt.Errorf("%w: the signature does not match", ErrInvalidDownloadLink)
+	}
+	if time.Now().After(link.Expires) {
+		return models.CompanyAttachment{}, nil, fmt.Errorf("%w: the link expired at %s", ErrInvalidDownloadLink, link.Expires.UTC().Format(time.RFC3339))
+	}
+
+	attachment, err := s.find(ctx, companyID, attachmentID)
+	if err != nil {
+		return models.CompanyAttachment{}, nil, err
+	}
+
+	content, err := s.store.Open(ctx, attachmentKey(attachment))
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return models.CompanyAttachment{}, nil, fmt.Errorf("%w: %s has no content", ErrAttachmentNotFound, attachmentID)
+	}
+	if err != nil {
+		return models.CompanyAttachment{}, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+// signed returns the attachment with a download link valid for the configured time.
+func (s *AttachmentService) signed(attachment models.CompanyAttachment) SignedAttachment {
+	expires := time.Now().Add(s.settings.LinkTTL).Truncate(time.Second)
+	return SignedAttachment{
+		Attachment: attachment,
+		Download: DownloadLink{
+			Expires:   expires,
+			Signature: s.sign(attachment.CompanyID, attachment.ID, expires),
+		},
+	}
+}
+
+// sign returns the signature of a download link of an attachment, links are valid to the second.
+func (s *AttachmentService) sign(companyID, attachmentID uuid.UUID, expires time.Time) string {
+	mac := hmac.New(sha256.New, []byte(s.settings.SigningKey))
+	fmt.Fprintf(mac, "%s/%s/%d", companyID, attachmentID, expires.Unix())
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// discard removes the content of an attachment that could not be kept, failures leave an
+// orphaned blob behind and are ignored.
+func (s *AttachmentService) discard(ctx context.Context, key string) {
+	_ = s.store.Delete(ctx, key)
+}
+
+// find gets an attachment of a company by id.
+func (s *AttachmentService) find(ctx context.Context, companyID, attachmentID uuid.UUID) (models.CompanyAttachment, error) {
+	attachment, err := s.attachmentRepo.Find(ctx, companyID, attachmentID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CompanyAttachment{}, fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachmentID)
+	}
+	if err != nil {
+		return models.CompanyAttachment{}, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// findAccessible makes sure the company exists and the user has the needed role on it.
+func (s *AttachmentService) findAccessible(ctx context.Context, userID, companyID uuid.UUID, need models.MemberRole) error {
+	_, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return s.access.authorize(ctx, userID, companyID, need)
+}
+
+// acceptsType reports whether the content type suits the kind of attachment.
+func acceptsType(kind models.AttachmentKind, contentType *mimetype.MIME) bool {
+	for _, accepted := range attachmentTypes[kind] {
+		if contentType.Is(accepted) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// attachmentKey returns the key the content of an attachment is stored under.
+func attachmentKey(attachment models.CompanyAttachment) string {
+	return "companies/" + attachment.CompanyID.String() + "/" + attachment.ID.String()
+}
+
+// byteCounter counts the bytes written to it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
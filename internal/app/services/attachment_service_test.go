@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/blobstore"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPNG starts like every PNG file, it is enough for the type to be detected.
+const testPNG = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00"
+
+var testAttachmentSettings = AttachmentSettings{MaxSize: 64, SigningKey: "test_attachment_key", LinkTTL: 15 * time.Minute}
+
+func TestNewAttachmentService(t *testing.T) {
+	t.Parallel()
+	store, _ := blobstore.NewLocalStore(t.TempDir())
+	cases := map[string]struct {
+		attachmentRepo repositories.AttachmentRepository
+		companyRepo    repositories.CompanyRepository
+		memberRepo     repositories.MemberRepository
+		store          blobstore.Store
+		settings       AttachmentSettings
+		expErr         string
+	}{
+		"attachment repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+			store:       store,
+			settings:    testAttachmentSettings,
+			expErr:      "attachment repository is nil",
+		},
+		"store is nil": {
+			attachmentRepo: &mockAttachmentRepository{},
+			companyRepo:    &mockCompanyRepository{},
+			memberRepo:     &mockMemberRepository{},
+			settings:       testAttachmentSettings,
+			expErr:         "blob store is nil",
+		},
+		"no max size": {
+			attachmentRepo: &mockAttachmentRepository{},
+			companyRepo:    &mockCompanyRepository{},
+			memberRepo:     &mockMemberRepository{},
+			store:          store,
+			settings:       AttachmentSettings{SigningKey: "key", LinkTTL: time.Minute},
+			expErr:         "attachment max size must be positive",
+		},
+		"no signing key": {
+			attachmentRepo: &mockAttachmentRepository{},
+			companyRepo:    &mockCompanyRepository{},
+			memberRepo:     &mockMemberRepository{},
+			store:          store,
+			settings:       AttachmentSettings{MaxSize: 1, LinkTTL: time.Minute},
+			expErr:         "attachment signing key is empty",
+		},
+		"success": {
+			attachmentRepo: &mockAttachmentRepository{},
+			companyRepo:    &mockCompanyRepository{},
+			memberRepo:     &mockMemberRepository{},
+			store:          store,
+			settings:       testAttachmentSettings,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewAttachmentService(tt.attachmentRepo, tt.companyRepo, tt.memberRepo, tt.store, tt.settings)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		memberRepo *mockMemberRepository
+		upload     AttachmentUpload
+		expErr     string
+	}{
+		"viewers cannot upload": {
+			memberRepo: &mockMemberRepository{role: models.RoleViewer},
+			upload:     AttachmentUpload{Kind: models.AttachmentLogo, FileName: "logo.png", Content: strings.NewReader(testPNG)},
+			expErr:     "access to the company denied: the editor role is required, you are viewer",
+		},
+		"unknown kind and no name": {
+			upload: AttachmentUpload{Kind: "avatar", Content: strings.NewReader(testPNG)},
+			expErr: "invalid attachment: kind must be one of logo or document; file must have a name",
+		},
+		"empty": {
+			upload: AttachmentUpload{Kind: models.AttachmentLogo, FileName: "logo.png", Content: strings.NewReader("")},
+			expErr: "invalid attachment: the file is empty",
+		},
+		"type does not suit the kind": {
+			upload: AttachmentUpload{Kind: models.AttachmentLogo, FileName: "logo.png", Content: strings.NewReader("just some text")},
+			expErr: "invalid attachment: a logo cannot be of type text/plain; charset=utf-8, expected one of image/png, image/jpeg, image/gif, image/webp, image/svg+xml",
+		},
+		"too large": {
+			upload: AttachmentUpload{Kind: models.AttachmentLogo, FileName: "logo.png", Content: strings.NewReader(testPNG + strings.Repeat("\x00", 64))},
+			expErr: "attachment too large: must be at most 64 bytes",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			root := t.TempDir()
+			store, _ := blobstore.NewLocalStore(root)
+			attachmentRepo := &mockAttachmentRepository{}
+			s, err := NewAttachmentService(attachmentRepo, &mockCompanyRepository{}, memberRepo, store, testAttachmentSettings)
+			assert.NoError(t, err)
+			_, err = s.UploadAttachment(context.TODO(), uuid.New(), uuid.New(), tt.upload)
+			assert.EqualError(t, err, tt.expErr)
+			assert.Equal(t, models.CompanyAttachment{}, attachmentRepo.saved)
+			assert.Zero(t, countFiles(t, root), "the content of a rejected file is not kept")
+		})
+	}
+}
+
+func TestAttachmentService_Download(t *testing.T) {
+	t.Parallel()
+	store, _ := blobstore.NewLocalStore(t.TempDir())
+	attachmentRepo := &mockAttachmentRepository{}
+	s, err := NewAttachmentService(attachmentRepo, &mockCompanyRepository{}, &mockMemberRepository{}, store, testAttachmentSettings)
+	assert.NoError(t, err)
+
+	ctx := context.TODO()
+	companyID := uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+	uploaded, err := s.UploadAttachment(ctx, uuid.New(), companyID, AttachmentUpload{Kind: models.AttachmentLogo, FileName: `C:\logos\acme.png`, Content: strings.NewReader(testPNG)})
+	assert.NoError(t, err)
+	sum := sha256.Sum256([]byte(testPNG))
+	assert.Equal(t, "acme.png", uploaded.Attachment.FileName, "only the base name of the file is kept")
+	assert.Equal(t, "image/png", uploaded.Attachment.ContentType)
+	assert.Equal(t, int64(len(testPNG)), uploaded.Attachment.Size)
+	assert.Equal(t, hex.EncodeToString(sum[:]), uploaded.Attachment.Checksum)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), uploaded.Download.Expires, 2*time.Second)
+
+	attachment, content, err := s.OpenAttachment(ctx, companyID, uploaded.Attachment.ID, uploaded.Download)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	content.Close()
+	assert.Equal(t, testPNG, string(data))
+	assert.Equal(t, uploaded.Attachment, attachment)
+
+	tampered := uploaded.Download
+	tampered.Expires = tampered.Expires.Add(time.Hour)
+	_, _, err = s.OpenAttachment(ctx, companyID, uploaded.Attachment.ID, tampered)
+	assert.EqualError(t, err, "invalid download link: the signature does not match")
+
+	_, _, err = s.OpenAttachment(ctx, uuid.New(), uploaded.Attachment.ID, uploaded.Download)
+	assert.EqualError(t, err, "invalid download link: the signature does not match", "a link is only valid for its attachment")
+
+	expires := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	expired := DownloadLink{Expires: expires, Signature: s.sign(companyID, uploaded.Attachment.ID, expires)}
+	_, _, err = s.OpenAttachment(ctx, companyID, uploaded.Attachment.ID, expired)
+	assert.EqualError(t, err, "invalid download link: the link expired at 2023-10-01T12:00:00Z")
+
+	// deleting the attachment removes its content
+	assert.NoError(t, s.DeleteAttachment(ctx, uuid.New(), companyID, uploaded.Attachment.ID))
+	_, err = store.Open(ctx, attachmentKey(uploaded.Attachment))
+	assert.ErrorIs(t, err, blobstore.ErrNotFound)
+}
+
+func TestAttachmentService_UploadAttachment_CompanyDeleted(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	store, _ := blobstore.NewLocalStore(root)
+	attachmentRepo := &mockAttachmentRepository{err: fmt.Errorf("failed to save attachment: %w", repositories.ErrNotFound)}
+	s, err := NewAttachmentService(attachmentRepo, &mockCompanyRepository{}, &mockMemberRepository{}, store, testAttachmentSettings)
+	assert.NoError(t, err)
+
+	companyID := uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+	_, err = s.UploadAttachment(context.TODO(), uuid.New(), companyID, AttachmentUpload{Kind: models.AttachmentDocument, FileName: "scan.png", Content: strings.NewReader(testPNG)})
+	assert.EqualError(t, err, "company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+	_, err = store.Open(context.TODO(), attachmentKey(attachmentRepo.saved))
+	assert.ErrorIs(t, err, blobstore.ErrNotFound, "the content of an attachment that was not saved is discarded")
+}
+
+// countFiles returns the number of files under a directory.
+func countFiles(t *testing.T, root string) int {
+	files := 0
+	err := filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files++
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	return files
+}
+
+// mockAttachmentRepository for testing, it keeps the last saved attachment.
+type mockAttachmentRepository struct {
+	saved models.CompanyAttachment
+	err   error
+}
+
+func (m *mockAttachmentRepository) List(_ context.Context, _ uuid.UUID) ([]models.CompanyAttachment, error) {
+	return []models.CompanyAttachment{m.saved}, m.err
+}
+
+func (m *mockAttachmentRepository) Find(_ context.Context, _, attachmentID uuid.UUID) (models.CompanyAttachment, error) {
+	if m.saved.ID != attachmentID {
+		return models.CompanyAttachment{}, fmt.Errorf("failed to find attachment: %w", repositories.ErrNotFound)
+	}
+	return m.saved, m.err
+}
+
+func (m *mockAttachmentRepository) Save(_ context.Context, attachment models.CompanyAttachment) (models.CompanyAttachment, error) {
+	m.saved = attachment
+	return attachment, m.err
+}
+
+func (m *mockAttachmentRepository) Delete(_ context.Context, _, _ uuid.UUID) error {
+	return m.err
+}
@@ -0,0 +1,661 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// CompanyAttributes defines the functionality related to custom company attributes.
+type CompanyAttributes interface {
+	ListSchemas(ctx context.Context) ([]models.AttributeSchema, error)
+	GetSchema(ctx context.Context, name string) (models.AttributeSchema, error)
+	CreateSchema(ctx context.Context, name string, payload AttributeSchemaPayload) (models.AttributeSchema, error)
+	UpdateSchema(ctx context.Context, name string, payload AttributeSchemaPayload) (models.AttributeSchema, error)
+	DeleteSchema(ctx context.Context, name string) error
+	GetAttributes(ctx context.Context, userID, companyID uuid.UUID) (AttributeValues, error)
+	SetAttributes(ctx context.Context, userID, companyID uuid.UUID, values map[string]json.RawMessage, replace bool) (AttributeValues, error)
+}
+
+var (
+	// ErrAttributeNotFound is returned when no attribute schema has the requested name.
+	ErrAttributeNotFound = newError(ErrNotFound, "attribute_not_found", "attribute not found")
+	// ErrAttributeExists is returned when defining an attribute whose name is taken.
+	ErrAttributeExists = newError(ErrConflict, "attribute_exists", "attribute already exists")
+	// ErrInvalidAttributeSchema is returned when an attribute schema breaks one or more validation rules.
+	ErrInvalidAttributeSchema = newError(ErrValidation, "invalid_attribute_schema", "invalid attribute schema")
+	// ErrInvalidAttributes is returned when the attribute values of a company do not match their schemas.
+	ErrInvalidAttributes = newError(ErrValidation, "invalid_attributes", "invalid attributes")
+)
+
+// limits of the attribute fields, they match the size of the database columns.
+const (
+	maxAttributeNameLength        = 64
+	maxAttributeDescriptionLength = 255
+	maxAttributeStringLength      = 255
+)
+
+// attributeDateLayout is the way the values of date attributes are written.
+const attributeDateLayout = "2006-01-02"
+
+// attributeNamePattern keeps attribute names usable in query strings, e.g. attr[vat_number]=.
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// attributeTypes lists the types an attribute can have.
+var attributeTypes = []models.AttributeType{
+	models.AttributeString, models.AttributeInt, models.AttributeBool, models.AttributeDate, models.AttributeEnum,
+}
+
+// AttributeSchemaPayload represents the fields of an attribute schema. The type of an
+// attribute is set when it is defined and cannot change afterwards.
+type AttributeSchemaPayload struct {
+	Type        models.AttributeType
+	Description string
+	Required    bool
+	Constraints models.AttributeConstraints
+}
+
+// AttributeValues maps attribute names to the value a company has, values are strings,
+// int64s, bools or dates written as 2006-01-02.
+type AttributeValues map[string]any
+
+// AttributeOperator tells how an attribute condition compares the values of companies.
+type AttributeOperator string
+
+const (
+	AttributeEqual AttributeOperator = "eq"
+	AttributeMin   AttributeOperator = "min" // The value is greater than or equal, int and date attributes only.
+	AttributeMax   AttributeOperator = "max" // The value is less than or equal, int and date attributes only.
+)
+
+// AttributeCondition compares the value companies have for a custom attribute with a value
+// written the way it is in a query string.
+type AttributeCondition struct {
+	Name     string
+	Operator AttributeOperator
+	Value    string
+}
+
+// attributeSortPrefix starts the sort fields ordering companies by a custom attribute, e.g. attr.vat_number.
+const attributeSortPrefix = "attr."
+
+// AttributeService represents the custom attribute service.
+type AttributeService struct {
+	attrRepo    repositories.AttributeRepository
+	companyRepo repositories.CompanyRepository
+	access      companyAccess
+}
+
+// NewAttributeService creates a new attribute service, the attributes of a company are changed by its editors.
+func NewAttributeService(attrRepo repositories.AttributeRepository, companyRepo repositories.CompanyRepository, memberRepo repositories.MemberRepository) (*AttributeService, error) {
+	if attrRepo == nil {
+		return nil, errors.New("attribute repository is nil")
+	}
+
+	if companyRepo == nil {
+		return nil, errors.New("company repository is nil")
+	}
+
+	if memberRepo == nil {
+		return nil, errors.New("member repository is nil")
+	}
+
+	return &AttributeService{
+		attrRepo:    attrRepo,
+		companyRepo: companyRepo,
+		access:      companyAccess{memberRepo: memberRepo},
+	}, nil
+}
+
+// ListSchemas lists the attribute schemas ordered by name.
+func (s *AttributeService) ListSchemas(ctx context.Context) ([]models.AttributeSchema, error) {
+	schemas, err := s.attrRepo.ListSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attribute schemas: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// GetSchema returns an attribute schema.
+func (s *AttributeService) GetSchema(ctx context.Context, name string) (models.AttributeSchema, error) {
+	schema, err := s.attrRepo.FindSchema(ctx, name)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.AttributeSchema{}, fmt.Errorf("%w: %q", ErrAttributeNotFound, name)
+	}
+	if err != nil {
+		return models.AttributeSchema{}, fmt.Errorf("failed to get attribute schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+// CreateSchema defines a new attribute companies can have a value for.
+func (s *AttributeService) CreateSchema(ctx context.Context, name string, payload AttributeSchemaPayload) (models.AttributeSchema, error) {
+	if err := validateAttributeSchema(name, payload); err != nil {
+		return models.AttributeSchema{}, err
+	}
+
+	schema, err := s.attrRepo.SaveSchema(ctx, models.AttributeSchema{
+		Name:        name,
+		Type:        payload.Type,
+		Description: payload.Description,
+		Required:    payload.Required,
+		Constraints: payload.Constraints,
+	})
+	if errors.Is(err, repositories.ErrAttributeExists) {
+		return models.AttributeSchema{}, fmt.Errorf("%w: %q", ErrAttributeExists, name)
+	}
+	if err != nil {
+		return models.AttributeSchema{}, fmt.Errorf("failed to create attribute schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+// UpdateSchema changes the description, required flag and constraints of an attribute. The
+// values companies already have are checked against the new schema the next time they change.
+func (s *AttributeService) UpdateSchema(ctx context.Context, name string, payload AttributeSchemaPayload) (models.AttributeSchema, error) {
+	current, err := s.GetSchema(ctx, name)
+	if err != nil {
+		return models.AttributeSchema{}, err
+	}
+	if payload.Type == "" {
+		payload.Type = current.Type
+	}
+	if payload.Type != current.Type {
+		return models.AttributeSchema{}, newValidationError(ErrInvalidAttributeSchema, []Violation{
+			{Field: "type", Message: "cannot change, define a new attribute instead"},
+		})
+	}
+	if err := validateAttributeSchema(name, payload); err != nil {
+		return models.AttributeSchema{}, err
+	}
+
+	schema, err := s.attrRepo.UpdateSchema(ctx, models.AttributeSchema{
+		Name:        name,
+		Description: payload.Description,
+		Required:    payload.Required,
+		Constraints: payload.Constraints,
+	})
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.AttributeSchema{}, fmt.Errorf("%w: %q", ErrAttributeNotFound, name)
+	}
+	if err != nil {
+		return models.AttributeSchema{}, fmt.Errorf("failed to update attribute schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+// DeleteSchema removes an attribute along with the values companies have for it.
+func (s *AttributeService) DeleteSchema(ctx context.Context, name string) error {
+	err := s.attrRepo.DeleteSchema(ctx, name)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: %q", ErrAttributeNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete attribute schema: %w", err)
+	}
+
+	return nil
+}
+
+// GetAttributes returns the attribute values of a company the user can view.
+func (s *AttributeService) GetAttributes(ctx context.Context, userID, companyID uuid.UUID) (AttributeValues, error) {
+	if err := s.findCompany(ctx, companyID); err != nil {
+		return nil, err
+	}
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.listValues(ctx, companyID)
+}
+
+// SetAttributes changes the attribute values of a company on behalf of an editor of it. With
+// replace the values become the attribute values of the company, otherwise they are merged
+// into the current ones and a null value removes an attribute. The resulting values are
+// checked against the schemas, required attributes included.
+func (s *AttributeService) SetAttributes(ctx context.Context, userID, companyID uuid.UUID, values map[string]json.RawMessage, replace bool) (AttributeValues, error) {
+	if err := s.findCompany(ctx, companyID); err != nil {
+		return nil, err
+	}
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if !replace {
+		current, err := s.listValues(ctx, companyID)
+		if err != nil {
+			return nil, err
+		}
+		merged := make(map[string]json.RawMessage, len(current)+len(values))
+		for name, value := range current {
+			// marshalling the values read from the database cannot fail
+			merged[name], _ = json.Marshal(value)
+		}
+		for name, value := range values {
+			merged[name] = value
+		}
+		values = merged
+	}
+
+	schemas, err := s.attrRepo.ListSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attribute schemas: %w", err)
+	}
+
+	attributes, err := validateAttributes(schemas, values)
+	if err != nil {
+		return nil, err
+	}
+	for i := range attributes {
+		attributes[i].UpdatedBy = userID
+	}
+
+	if err := s.attrRepo.ReplaceValues(ctx, companyID, attributes); err != nil {
+		return nil, fmt.Errorf("failed to set attributes: %w", err)
+	}
+
+	return s.listValues(ctx, companyID)
+}
+
+// listValues returns the attribute values of a company.
+func (s *AttributeService) listValues(ctx context.Context, companyID uuid.UUID) (AttributeValues, error) {
+	attributes, err := s.attrRepo.ListValues(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attributes: %w", err)
+	}
+
+	values := make(AttributeValues, len(attributes))
+	for _, attribute := range attributes {
+		values[attribute.Name] = attributeJSON(attribute)
+	}
+
+	return values, nil
+}
+
+// findCompany makes sure the company exists.
+func (s *AttributeService) findCompany(ctx context.Context, companyID uuid.UUID) error {
+	_, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return nil
+}
+
+// validateAttributeSchema checks the name and payload of an attribute schema and lists every violation.
+func validateAttributeSchema(name string, payload AttributeSchemaPayload) error {
+	var violations []Violation
+	switch {
+	case name == "":
+		violations = append(violations, Violation{Field: "name", Message: "must not be empty"})
+	case len(name) > maxAttributeNameLength:
+		violations = append(violations, Violation{Field: "name", Message: fmt.Sprintf("must be at most %d characters long", maxAttributeNameLength)})
+	case !attributeNamePattern.MatchString(name):
+		violations = append(violations, Violation{Field: "name", Message: "must start with a lowercase letter and hold only lowercase letters, digits and underscores"})
+	}
+
+	known := false
+	for _, t := range attributeTypes {
+		known = known || t == payload.Type
+	}
+	if !known {
+		names := make([]string, 0, len(attributeTypes))
+		for _, t := range attributeTypes {
+			names = append(names, string(t))
+		}
+		violations = append(violations, Violation{Field: "type", Message: "must be one of " + strings.Join(names, ", ")})
+	}
+
+	if utf8.RuneCountInString(payload.Description) > maxAttributeDescriptionLength {
+		violations = append(violations, Violation{Field: "description", Message: fmt.Sprintf("must be at most %d characters long", maxAttributeDescriptionLength)})
+	}
+
+	return newValidationError(ErrInvalidAttributeSchema, append(violations, constraintViolations(payload.Type, payload.Constraints)...))
+}
+
+// constraintViolations checks the constraints apply to the type of the attribute and can be met.
+func constraintViolations(attributeType models.AttributeType, c models.AttributeConstraints) []Violation {
+	var violations []Violation
+	constraints := []struct {
+		field         string
+		set           bool
+		attributeType models.AttributeType
+	}{
+		{"constraints.min_length", c.MinLength != nil, models.AttributeString},
+		{"constraints.max_length", c.MaxLength != nil, models.AttributeString},
+		{"constraints.pattern", c.Pattern != "", models.AttributeString},
+		{"constraints.min", c.Min != nil, models.AttributeInt},
+		{"constraints.max", c.Max != nil, models.AttributeInt},
+		{"constraints.min_date", c.MinDate != "", models.AttributeDate},
+		{"constraints.max_date", c.MaxDate != "", models.AttributeDate},
+		{"constraints.options", len(c.Options) > 0, models.AttributeEnum},
+	}
+	for _, constraint := range constraints {
+		if constraint.set && constraint.attributeType != attributeType {
+			violations = append(violations, Violation{Field: constraint.field, Message: fmt.Sprintf("only applies to %s attributes", constraint.attributeType)})
+		}
+	}
+
+	if c.MinLength != nil && *c.MinLength < 0 {
+		violations = append(violations, Violation{Field: "constraints.min_length", Message: "must not be negative"})
+	}
+	if c.MaxLength != nil && (*c.MaxLength < 1 || *c.MaxLength > maxAttributeStringLength) {
+		violations = append(violations, Violation{Field: "constraints.max_length", Message: fmt.Sprintf("must be between 1 and %d", maxAttributeStringLength)})
+	}
+	if c.MinLength != nil && c.MaxLength != nil && *c.MinLength > *c.MaxLength {
+		violations = append(violations, Violation{Field: "constraints.min_length", Message: "must not be greater than max_length"})
+	}
+	if _, err := attributePattern(c.Pattern); err != nil {
+		violations = append(violations, Violation{Field: "constraints.pattern", Message: "must be a valid regular expression"})
+	}
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		violations = append(violations, Violation{Field: "constraints.min", Message: "must not be greater than max"})
+	}
+
+	minDate, minErr := time.Parse(attributeDateLayout, c.MinDate)
+	if c.MinDate != "" && minErr != nil {
+		violations = append(violations, Violation{Field: "constraints.min_date", Message: "must be a date written as " + attributeDateLayout})
+	}
+	maxDate, maxErr := time.Parse(attributeDateLayout, c.MaxDate)
+	if c.MaxDate != "" && maxErr != nil {
+		violations = append(violations, Violation{Field: "constraints.max_date", Message: "must be a date written as " + attributeDateLayout})
+	}
+	if minErr == nil && maxErr == nil && minDate.After(maxDate) {
+		violations = append(violations, Violation{Field: "constraints.min_date", Message: "must not be after max_date"})
+	}
+
+	if attributeType == models.AttributeEnum && len(c.Options) == 0 {
+		violations = append(violations, Violation{Field: "constraints.options", Message: "must list the values of the attribute"})
+	}
+	seen := make(map[string]bool, len(c.Options))
+	for i, option := range c.Options {
+		field := fmt.Sprintf("constraints.options[%d]", i)
+		switch {
+		case option == "":
+			violations = append(violations, Violation{Field: field, Message: "must not be empty"})
+		case utf8.RuneCountInString(option) > maxAttributeStringLength:
+			violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must be at most %d characters long", maxAttributeStringLength)})
+		case seen[option]:
+			violations = append(violations, Violation{Field: field, Message: "must not be listed twice"})
+		}
+		seen[option] = true
+	}
+
+	return violations
+}
+
+// attributePattern compiles the pattern of a string attribute, the whole value has to match it.
+func attributePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// validateAttributes checks the values against the attribute schemas, lists every violation
+// and returns the values to store. Null values are left out.
+func validateAttributes(schemas []models.AttributeSchema, values map[string]json.RawMessage) ([]models.CompanyAttribute, error) {
+	attributes, violations := attributeViolations(schemas, values)
+	if err := newValidationError(ErrInvalidAttributes, violations); err != nil {
+		return nil, err
+	}
+
+	return attributes, nil
+}
+
+// newCompanyAttributes checks the attribute values of a company about to be created against
+// the schemas, required attributes included, and returns the values to store along with it.
+func newCompanyAttributes(schemas []models.AttributeSchema, userID uuid.UUID, values map[string]json.RawMessage) ([]models.CompanyAttribute, error) {
+	attributes, violations := attributeViolations(schemas, values)
+	for i := range violations {
+		violations[i].Field = "attributes." + violations[i].Field
+	}
+	if err := newValidationError(ErrInvalidCompany, violations); err != nil {
+		return nil, err
+	}
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	for i := range attributes {
+		attributes[i].UpdatedBy = userID
+	}
+
+	return attributes, nil
+}
+
+// attributesFromText converts attribute values read from a file, written the way they are in
+// query strings, into JSON values. Values that do not parse as their type are kept as strings
+// for the validation to report.
+func attributesFromText(schemas []models.AttributeSchema, texts map[string]string) map[string]json.RawMessage {
+	types := make(map[string]models.AttributeType, len(schemas))
+	for _, schema := range schemas {
+		types[schema.Name] = schema.Type
+	}
+
+	values := make(map[string]json.RawMessage, len(texts))
+	for name, text := range texts {
+		var value any = text
+		if t := types[name]; t == models.AttributeInt || t == models.AttributeBool {
+			if parsed, err := parseAttributeText(t, text); err == nil {
+				value = parsed
+			}
+		}
+		// marshalling strings, int64s and bools cannot fail
+		values[name], _ = json.Marshal(value)
+	}
+
+	return values
+}
+
+// attributeViolations checks the values against the attribute schemas, it returns the values
+// to store and every violation.
+func attributeViolations(schemas []models.AttributeSchema, values map[string]json.RawMessage) ([]models.CompanyAttribute, []Violation) {
+	var violations []Violation
+	defined := make(map[string]bool, len(schemas))
+	for _, schema := range schemas {
+		defined[schema.Name] = true
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !defined[name] {
+			violations = append(violations, Violation{Field: name, Message: "is not a defined attribute"})
+		}
+	}
+
+	attributes := make([]models.CompanyAttribute, 0, len(values))
+	for _, schema := range schemas {
+		raw, ok := values[schema.Name]
+		if !ok || string(raw) == "null" {
+			if schema.Required {
+				violations = append(violations, Violation{Field: schema.Name, Message: "is required"})
+			}
+			continue
+		}
+
+		attribute, message := attributeValue(schema, raw)
+		if message != "" {
+			violations = append(violations, Violation{Field: schema.Name, Message: message})
+			continue
+		}
+		attributes = append(attributes, attribute)
+	}
+
+	return attributes, violations
+}
+
+// attributeValue converts a JSON value into the value stored for the attribute, the message
+// tells why the value does not match the schema.
+func attributeValue(schema models.AttributeSchema, raw json.RawMessage) (models.CompanyAttribute, string) {
+	attribute := models.CompanyAttribute{Name: schema.Name}
+	c := schema.Constraints
+
+	switch schema.Type {
+	case models.AttributeString:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return attribute, "must be a string"
+		}
+		length := utf8.RuneCountInString(value)
+		switch {
+		case length > maxAttributeStringLength:
+			return attribute, fmt.Sprintf("must be at most %d characters long", maxAttributeStringLength)
+		case c.MinLength != nil && length < *c.MinLength:
+			return attribute, fmt.Sprintf("must be at least %d characters long", *c.MinLength)
+		case c.MaxLength != nil && length > *c.MaxLength:
+			return attribute, fmt.Sprintf("must be at most %d characters long", *c.MaxLength)
+		}
+		if pattern, err := attributePattern(c.Pattern); err != nil || (pattern != nil && !pattern.MatchString(value)) {
+			return attribute, fmt.Sprintf("must match the pattern %q", c.Pattern)
+		}
+		attribute.StringValue = &value
+	case models.AttributeInt:
+		var value int64
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return attribute, "must be an integer"
+		}
+		switch {
+		case c.Min != nil && value < *c.Min:
+			return attribute, fmt.Sprintf("must be at least %d", *c.Min)
+		case c.Max != nil && value > *c.Max:
+			return attribute, fmt.Sprintf("must be at most %d", *c.Max)
+		}
+		attribute.IntValue = &value
+	case models.AttributeBool:
+		var value bool
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return attribute, "must be true or false"
+		}
+		attribute.BoolValue = &value
+	case models.AttributeDate:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return attribute, "must be a date written as " + attributeDateLayout
+		}
+		value, err := time.Parse(attributeDateLayout, text)
+		if err != nil {
+			return attribute, "must be a date written as " + attributeDateLayout
+		}
+		// the dates of the constraints were checked when the schema was saved and compare as text
+		switch {
+		case c.MinDate != "" && text < c.MinDate:
+			return attribute, "must not be before " + c.MinDate
+		case c.MaxDate != "" && text > c.MaxDate:
+			return attribute, "must not be after " + c.MaxDate
+		}
+		attribute.DateValue = &value
+	case models.AttributeEnum:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return attribute, "must be a string"
+		}
+		allowed := false
+		for _, option := range c.Options {
+			allowed = allowed || option == value
+		}
+		if !allowed {
+			return attribute, "must be one of " + strings.Join(c.Options, ", ")
+		}
+		attribute.StringValue = &value
+	default:
+		return attribute, fmt.Sprintf("has the unknown type %q", schema.Type)
+	}
+
+	return attribute, ""
+}
+
+// attributeJSON returns the value of a company attribute the way it is written in JSON.
+func attributeJSON(attribute models.CompanyAttribute) any {
+	switch {
+	case attribute.StringValue != nil:
+		return *attribute.StringValue
+	case attribute.IntValue != nil:
+		return *attribute.IntValue
+	case attribute.BoolValue != nil:
+		return *attribute.BoolValue
+	case attribute.DateValue != nil:
+		return attribute.DateValue.Format(attributeDateLayout)
+	}
+
+	return nil
+}
+
+// parseAttributeText parses a value written the way it is in query strings and cursors
+// into the Go type the values of the attribute type are stored as.
+func parseAttributeText(attributeType models.AttributeType, text string) (any, error) {
+	switch attributeType {
+	case models.AttributeInt:
+		return strconv.ParseInt(text, 10, 64)
+	case models.AttributeBool:
+		return strconv.ParseBool(text)
+	case models.AttributeDate:
+		return time.Parse(attributeDateLayout, text)
+	}
+
+	return text, nil
+}
+
+// attributeConditions converts the conditions into repository conditions using the schemas,
+// the attributes have to be defined and the values have to be of their type.
+func attributeConditions(schemas map[string]models.AttributeSchema, conditions []AttributeCondition) ([]repositories.AttributeCondition, error) {
+	converted := make([]repositories.AttributeCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		schema, ok := schemas[condition.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown attribute %q", ErrInvalidListQuery, condition.Name)
+		}
+
+		var operator repositories.AttributeOperator
+		switch condition.Operator {
+		case AttributeEqual:
+			operator = repositories.AttributeEqual
+		case AttributeMin, AttributeMax:
+			if schema.Type != models.AttributeInt && schema.Type != models.AttributeDate {
+				return nil, fmt.Errorf("%w: only int and date attributes have a %s, %q is a %s attribute", ErrInvalidListQuery, condition.Operator, condition.Name, schema.Type)
+			}
+			operator = repositories.AttributeMin
+			if condition.Operator == AttributeMax {
+				operator = repositories.AttributeMax
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown attribute operator %q", ErrInvalidListQuery, condition.Operator)
+		}
+
+		value, err := parseAttributeText(schema.Type, condition.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid value for the %s attribute %q: %q", ErrInvalidListQuery, schema.Type, condition.Name, condition.Value)
+		}
+
+		converted = append(converted, repositories.AttributeCondition{
+			AttributeRef: repositories.AttributeRef{Name: schema.Name, Type: schema.Type},
+			Operator:     operator,
+			Value:        value,
+		})
+	}
+
+	return converted, nil
+}
@@ -0,0 +1,444 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAttributeService(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		attrRepo    repositories.AttributeRepository
+		companyRepo repositories.CompanyRepository
+		memberRepo  repositories.MemberRepository
+		expErr      string
+	}{
+		"attribute repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+			expErr:      "attribute repository is nil",
+		},
+		"company repo is nil": {
+			attrRepo:   &mockAttributeRepository{},
+			memberRepo: &mockMemberRepository{},
+			expErr:     "company repository is nil",
+		},
+		"member repo is nil": {
+			attrRepo:    &mockAttributeRepository{},
+			companyRepo: &mockCompanyRepository{},
+			expErr:      "member repository is nil",
+		},
+		"success": {
+			attrRepo:    &mockAttributeRepository{},
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewAttributeService(tt.attrRepo, tt.companyRepo, tt.memberRepo)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestAttributeService_CreateSchema(t *testing.T) {
+	t.Parallel()
+	one, two := 1, 2
+	low, high := int64(1), int64(0)
+	cases := map[string]struct {
+		attrRepo *mockAttributeRepository
+		name     string
+		payload  AttributeSchemaPayload
+		expErr   string
+	}{
+		"invalid name and type": {
+			attrRepo: &mockAttributeRepository{},
+			name:     "VAT number",
+			payload:  AttributeSchemaPayload{Type: "float"},
+			expErr:   "invalid attribute schema: name must start with a lowercase letter and hold only lowercase letters, digits and underscores; type must be one of string, int, bool, date, enum",
+		},
+		"constraints of another type": {
+			attrRepo: &mockAttributeRepository{},
+			name:     "employees_abroad",
+			payload:  AttributeSchemaPayload{Type: models.AttributeInt, Constraints: models.AttributeConstraints{MaxLength: &two}},
+			expErr:   "invalid attribute schema: constraints.max_length only applies to string attributes",
+		},
+		"constraints that cannot be met": {
+			attrRepo: &mockAttributeRepository{},
+			name:     "vat_number",
+			payload: AttributeSchemaPayload{Type: models.AttributeString, Constraints: models.AttributeConstraints{
+				MinLength: &two, MaxLength: &one, Pattern: "[",
+			}},
+			expErr: "invalid attribute schema: constraints.min_length must not be greater than max_length; constraints.pattern must be a valid regular expression",
+		},
+		"min greater than max": {
+			attrRepo: &mockAttributeRepository{},
+			name:     "founded_year",
+			payload:  AttributeSchemaPayload{Type: models.AttributeInt, Constraints: models.AttributeConstraints{Min: &low, Max: &high}},
+			expErr:   "invalid attribute schema: constraints.min must not be greater than max",
+		},
+		"invalid dates": {
+			attrRepo: &mockAttributeRepository{},
+			name:     "fiscal_year_end",
+			payload:  AttributeSchemaPayload{Type: models.AttributeDate, Constraints: models.AttributeConstraints{MinDate: "2020-12-31", MaxDate: "2020-01-01"}},
+			expErr:   "invalid attribute schema: constraints.min_date must not be after max_date",
+		},
+		"enum without options": {
+			attrRepo: &mockAttributeRepository{},
+			name:     "segment",
+			payload:  AttributeSchemaPayload{Type: models.AttributeEnum},
+			expErr:   "invalid attribute schema: constraints.options must list the values of the attribute",
+		},
+		"enum with repeated options": {
+			attrRepo: &mockAttributeRepository{},
+			name:     "segment",
+			payload:  AttributeSchemaPayload{Type: models.AttributeEnum, Constraints: models.AttributeConstraints{Options: []string{"smb", "", "smb"}}},
+			expErr:   "invalid attribute schema: constraints.options[1] must not be empty; constraints.options[2] must not be listed twice",
+		},
+		"name taken": {
+			attrRepo: &mockAttributeRepository{err: fmt.Errorf("failed to save attribute schema: %w", repositories.ErrAttributeExists)},
+			name:     "vat_number",
+			payload:  AttributeSchemaPayload{Type: models.AttributeString},
+			expErr:   "attribute already exists: \"vat_number\"",
+		},
+		"success": {
+			attrRepo: &mockAttributeRepository{},
+			name:     "segment",
+			payload:  AttributeSchemaPayload{Type: models.AttributeEnum, Required: true, Constraints: models.AttributeConstraints{Options: []string{"smb", "enterprise"}}},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewAttributeService(tt.attrRepo, &mockCompanyRepository{}, &mockMemberRepository{})
+			assert.NoError(t, err)
+			schema, err := s.CreateSchema(context.TODO(), tt.name, tt.payload)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.name, schema.Name)
+			assert.Equal(t, tt.payload.Type, tt.attrRepo.saved.Type)
+			assert.Equal(t, tt.payload.Constraints, tt.attrRepo.saved.Constraints)
+		})
+	}
+}
+
+func TestAttributeService_UpdateSchema(t *testing.T) {
+	t.Parallel()
+	schemas := []models.AttributeSchema{{Name: "vat_number", Type: models.AttributeString}}
+	cases := map[string]struct {
+		attrRepo *mockAttributeRepository
+		name     string
+		payload  AttributeSchemaPayload
+		expErr   string
+	}{
+		"not found": {
+			attrRepo: &mockAttributeRepository{schemas: schemas},
+			name:     "website",
+			expErr:   "attribute not found: \"website\"",
+		},
+		"type cannot change": {
+			attrRepo: &mockAttributeRepository{schemas: schemas},
+			name:     "vat_number",
+			payload:  AttributeSchemaPayload{Type: models.AttributeInt},
+			expErr:   "invalid attribute schema: type cannot change, define a new attribute instead",
+		},
+		"success keeps the type": {
+			attrRepo: &mockAttributeRepository{schemas: schemas},
+			name:     "vat_number",
+			payload:  AttributeSchemaPayload{Description: "VAT identification number", Required: true},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewAttributeService(tt.attrRepo, &mockCompanyRepository{}, &mockMemberRepository{})
+			assert.NoError(t, err)
+			_, err = s.UpdateSchema(context.TODO(), tt.name, tt.payload)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.payload.Description, tt.attrRepo.saved.Description)
+			assert.True(t, tt.attrRepo.saved.Required)
+		})
+	}
+}
+
+func TestAttributeService_SetAttributes(t *testing.T) {
+	t.Parallel()
+	minLength, minYear := 4, int64(1800)
+	schemas := []models.AttributeSchema{
+		{Name: "fiscal_year_end", Type: models.AttributeDate, Constraints: models.AttributeConstraints{MinDate: "2000-01-01"}},
+		{Name: "founded_year", Type: models.AttributeInt, Constraints: models.AttributeConstraints{Min: &minYear}},
+		{Name: "listed", Type: models.AttributeBool},
+		{Name: "segment", Type: models.AttributeEnum, Constraints: models.AttributeConstraints{Options: []string{"smb", "enterprise"}}},
+		{Name: "vat_number", Type: models.AttributeString, Required: true, Constraints: models.AttributeConstraints{MinLength: &minLength, Pattern: "[A-Z]{2}[0-9]+"}},
+	}
+	vat, listed := "DE123456", true
+	current := []models.CompanyAttribute{
+		{Name: "listed", BoolValue: &listed},
+		{Name: "vat_number", StringValue: &vat},
+	}
+	cases := map[string]struct {
+		attrRepo   *mockAttributeRepository
+		memberRepo *mockMemberRepository
+		values     string
+		replace    bool
+		expErr     string
+		expValues  AttributeValues
+	}{
+		"viewers cannot change attributes": {
+			attrRepo:   &mockAttributeRepository{schemas: schemas},
+			memberRepo: &mockMemberRepository{role: models.RoleViewer},
+			values:     `{"vat_number":"DE123456"}`,
+			replace:    true,
+			expErr:     "access to the company denied: the editor role is required, you are viewer",
+		},
+		"values not matching their schema": {
+			attrRepo:   &mockAttributeRepository{schemas: schemas},
+			memberRepo: &mockMemberRepository{},
+			values:     `{"fiscal_year_end":"1999-12-31","founded_year":1700,"listed":"yes","segment":"startup","website":"example.com"}`,
+			replace:    true,
+			expErr: "invalid attributes: website is not a defined attribute; fiscal_year_end must not be before 2000-01-01; founded_year must be at least 1800; " +
+				"listed must be true or false; segment must be one of smb, enterprise; vat_number is required",
+		},
+		"strings are checked against the pattern": {
+			attrRepo:   &mockAttributeRepository{schemas: schemas},
+			memberRepo: &mockMemberRepository{},
+			values:     `{"vat_number":"de123456"}`,
+			replace:    true,
+			expErr:     "invalid attributes: vat_number must match the pattern \"[A-Z]{2}[0-9]+\"",
+		},
+		"replace": {
+			attrRepo:   &mockAttributeRepository{schemas: schemas, values: current},
+			memberRepo: &mockMemberRepository{},
+			values:     `{"vat_number":"FR9876","fiscal_year_end":"2023-12-31","founded_year":1901}`,
+			replace:    true,
+			expValues:  AttributeValues{"vat_number": "FR9876", "fiscal_year_end": "2023-12-31", "founded_year": int64(1901)},
+		},
+		"merge removes null values": {
+			attrRepo:   &mockAttributeRepository{schemas: schemas, values: current},
+			memberRepo: &mockMemberRepository{},
+			values:     `{"listed":null,"segment":"smb"}`,
+			expValues:  AttributeValues{"vat_number": "DE123456", "segment": "smb"},
+		},
+		"merge cannot remove required values": {
+			attrRepo:   &mockAttributeRepository{schemas: schemas, values: current},
+			memberRepo: &mockMemberRepository{},
+			values:     `{"vat_number":null}`,
+			expErr:     "invalid attributes: vat_number is required",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var values map[string]json.RawMessage
+			assert.NoError(t, json.Unmarshal([]byte(tt.values), &values))
+			s, err := NewAttributeService(tt.attrRepo, &mockCompanyRepository{}, tt.memberRepo)
+			assert.NoError(t, err)
+			got, err := s.SetAttributes(context.TODO(), uuid.New(), uuid.New(), values, tt.replace)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, tt.attrRepo.replaced)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expValues, got)
+		})
+	}
+}
+
+func TestAttributeService_GetAttributes(t *testing.T) {
+	t.Parallel()
+	founded := time.Date(1998, 9, 4, 0, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		attrRepo    *mockAttributeRepository
+		expErr      string
+		expValues   AttributeValues
+	}{
+		"company not found": {
+			companyRepo: &mockCompanyRepository{err: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound)},
+			attrRepo:    &mockAttributeRepository{},
+			expErr:      "company not found: " + uuid.Nil.String(),
+		},
+		"attribute repo error": {
+			companyRepo: &mockCompanyRepository{},
+			attrRepo:    &mockAttributeRepository{err: errors.New("attribute repo error")},
+			expErr:      "failed to list attributes: attribute repo error",
+		},
+		"success": {
+			companyRepo: &mockCompanyRepository{},
+			attrRepo:    &mockAttributeRepository{values: []models.CompanyAttribute{{Name: "founded", DateValue: &founded}}},
+			expValues:   AttributeValues{"founded": "1998-09-04"},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewAttributeService(tt.attrRepo, tt.companyRepo, &mockMemberRepository{})
+			assert.NoError(t, err)
+			got, err := s.GetAttributes(context.TODO(), uuid.New(), uuid.Nil)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expValues, got)
+		})
+	}
+}
+
+func TestCompanyService_ListByAttributes(t *testing.T) {
+	t.Parallel()
+	schemas := []models.AttributeSchema{
+		{Name: "founded_year", Type: models.AttributeInt},
+		{Name: "listed", Type: models.AttributeBool},
+	}
+	companies := []models.Company{{ID: uuid.New()}, {ID: uuid.New()}}
+	year := int64(1999)
+	cases := map[string]struct {
+		params       ListCompaniesParams
+		expErr       string
+		expFilter    []repositories.AttributeCondition
+		expSort      *repositories.AttributeRef
+		expHasCursor bool
+	}{
+		"unknown attribute": {
+			params: ListCompaniesParams{Filter: CompanyFilter{Attributes: []AttributeCondition{{Name: "website", Operator: AttributeEqual, Value: "example.com"}}}},
+			expErr: "invalid list query: unknown attribute \"website\"",
+		},
+		"value of another type": {
+			params: ListCompaniesParams{Filter: CompanyFilter{Attributes: []AttributeCondition{{Name: "founded_year", Operator: AttributeMin, Value: "old"}}}},
+			expErr: "invalid list query: invalid value for the int attribute \"founded_year\": \"old\"",
+		},
+		"no range on bools": {
+			params: ListCompaniesParams{Filter: CompanyFilter{Attributes: []AttributeCondition{{Name: "listed", Operator: AttributeMax, Value: "true"}}}},
+			expErr: "invalid list query: only int and date attributes have a max, \"listed\" is a bool attribute",
+		},
+		"unknown sort attribute": {
+			params: ListCompaniesParams{SortBy: "attr.website"},
+			expErr: "invalid list query: unknown sort attribute \"website\"",
+		},
+		"filter and sort": {
+			params: ListCompaniesParams{
+				SortBy: "attr.founded_year",
+				Limit:  1,
+				Filter: CompanyFilter{Attributes: []AttributeCondition{
+					{Name: "listed", Operator: AttributeEqual, Value: "true"},
+					{Name: "founded_year", Operator: AttributeMin, Value: "1900"},
+				}},
+			},
+			expFilter: []repositories.AttributeCondition{
+				{AttributeRef: repositories.AttributeRef{Name: "listed", Type: models.AttributeBool}, Operator: repositories.AttributeEqual, Value: true},
+				{AttributeRef: repositories.AttributeRef{Name: "founded_year", Type: models.AttributeInt}, Operator: repositories.AttributeMin, Value: int64(1900)},
+			},
+			expSort:      &repositories.AttributeRef{Name: "founded_year", Type: models.AttributeInt},
+			expHasCursor: true,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			companyRepo := &mockCompanyRepository{companies: companies}
+			attrRepo := &mockAttributeRepository{schemas: schemas, values: []models.CompanyAttribute{{Name: "founded_year", IntValue: &year}}}
+			s, err := NewCompanyService(companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, attrRepo)
+			assert.NoError(t, err)
+			page, err := s.List(context.TODO(), uuid.New(), tt.params)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expFilter, companyRepo.listQuery.Filter.Attributes)
+			assert.Equal(t, tt.expSort, companyRepo.listQuery.SortAttribute)
+			assert.Equal(t, tt.expHasCursor, page.NextCursor != "")
+
+			cursor, err := decodeAttributeCursor(page.NextCursor, companyRepo.listQuery.SortBy, false, models.AttributeInt)
+			assert.NoError(t, err)
+			assert.Equal(t, companies[0].ID, cursor.ID)
+			assert.Equal(t, year, cursor.SortValue)
+		})
+	}
+}
+
+// mockAttributeRepository for testing
+type mockAttributeRepository struct {
+	schemas  []models.AttributeSchema
+	values   []models.CompanyAttribute
+	saved    models.AttributeSchema
+	replaced []models.CompanyAttribute
+	err      error
+}
+
+func (m *mockAttributeRepository) ListSchemas(_ context.Context) ([]models.AttributeSchema, error) {
+	return m.schemas, m.err
+}
+
+func (m *mockAttributeRepository) FindSchema(_ context.Context, name string) (models.AttributeSchema, error) {
+	if m.err != nil {
+		return models.AttributeSchema{}, m.err
+	}
+	for _, schema := range m.schemas {
+		if schema.Name == name {
+			return schema, nil
+		}
+	}
+	return models.AttributeSchema{}, fmt.Errorf("failed to find attribute schema: %w", repositories.ErrNotFound)
+}
+
+func (m *mockAttributeRepository) SaveSchema(_ context.Context, schema models.AttributeSchema) (models.AttributeSchema, error) {
+	m.saved = schema
+	return schema, m.err
+}
+
+func (m *mockAttributeRepository) UpdateSchema(_ context.Context, schema models.AttributeSchema) (models.AttributeSchema, error) {
+	m.saved = schema
+	return schema, m.err
+}
+
+func (m *mockAttributeRepository) DeleteSchema(_ context.Context, _ string) error {
+	return m.err
+}
+
+func (m *mockAttributeRepository) ListValues(_ context.Context, _ uuid.UUID) ([]models.CompanyAttribute, error) {
+	return m.values, m.err
+}
+
+func (m *mockAttributeRepository) ReplaceValues(_ context.Context, _ uuid.UUID, values []models.CompanyAttribute) error {
+	m.replaced, m.values = values, values
+	return m.err
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// ErrAccessDenied is returned when a user lacks the role an operation on a company needs.
+var ErrAccessDenied = newError(ErrForbidden, "company_access_denied", "access to the company denied")
+
+// companyAccess checks the roles users have on companies against their access control lists.
+type companyAccess struct {
+	memberRepo repositories.MemberRepository
+}
+
+// authorize makes sure the user has at least the needed role on the company.
+func (a companyAccess) authorize(ctx context.Context, userID, companyID uuid.UUID, need models.MemberRole) error {
+	member, err := a.memberRepo.Find(ctx, companyID, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: the %s role is required", ErrAccessDenied, need)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check access: %w", err)
+	}
+
+	if !member.Role.Grants(need) {
+		return fmt.Errorf("%w: the %s role is required, you are %s", ErrAccessDenied, need, member.Role)
+	}
+
+	return nil
+}
+
+// viewable returns the set of the companies the user can view among the given ones.
+func (a companyAccess) viewable(ctx context.Context, userID uuid.UUID, companyIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	ids, err := a.memberRepo.MemberOf(ctx, userID, companyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+
+	visible := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		visible[id] = true
+	}
+
+	return visible, nil
+}
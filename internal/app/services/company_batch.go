@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+const maxBatchSize = 500
+
+var (
+	// ErrInvalidBatch is returned when a batch is empty or too large to be processed.
+	ErrInvalidBatch = newError(ErrInvalidRequest, "invalid_batch", "invalid batch")
+	// ErrBatchAborted is returned when an item of an atomic batch failed and no company was created.
+	ErrBatchAborted = newError(ErrValidation, "batch_aborted", "batch aborted, no company was created")
+)
+
+// BatchItemResult represents the outcome of creating a single company of a batch.
+// A result that is neither created nor failed was skipped because its batch was aborted.
+type BatchItemResult struct {
+	Company models.Company
+	Created bool
+	Err     error
+}
+
+// CreateBatch creates the companies of the user in a single transaction. When atomic is set
+// either every company is created or none, otherwise the valid companies are created and the
+// others are reported as failed.
+func (s *CompanyService) CreateBatch(ctx context.Context, userID uuid.UUID, payloads []CreateUpdateCompanyPayload, atomic bool) ([]BatchItemResult, error) {
+	if len(payloads) == 0 || len(payloads) > maxBatchSize {
+		return nil, fmt.Errorf("%w: must contain between 1 and %d companies", ErrInvalidBatch, maxBatchSize)
+	}
+
+	types, err := s.availableTypes(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := s.attrRepo.ListSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attribute schemas: %w", err)
+	}
+
+	results := make([]BatchItemResult, len(payloads))
+	companies := make([]models.Company, 0, len(payloads))
+	indexes := make([]int, 0, len(payloads))
+	for i, payload := range payloads {
+		company := payload.company(userID)
+		if err := validateCompany(company, types); err != nil {
+			results[i].Err = err
+			continue
+		}
+		company.Attributes, err = newCompanyAttributes(schemas, userID, payload.Attributes)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if err := s.checkParent(ctx, userID, nil, company); err != nil {
+			results[i].Err = err
+			continue
+		}
+		companies = append(companies, company)
+		indexes = append(indexes, i)
+	}
+
+	if len(companies) == 0 || (atomic && len(companies) != len(payloads)) {
+		return results, ErrBatchAborted
+	}
+
+	errs, err := s.companyRepo.SaveBatch(ctx, companies, atomic)
+	for j, i := range indexes {
+		if errs[j] != nil {
+			results[i].Err = fmt.Errorf("failed to save company: %w", errs[j])
+		}
+	}
+	if errors.Is(err, repositories.ErrBatchAborted) {
+		return results, ErrBatchAborted
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create companies: %w", err)
+	}
+
+	for j, i := range indexes {
+		if results[i].Err == nil {
+			results[i].Company = companies[j]
+			results[i].Created = true
+		}
+	}
+
+	return results, nil
+}
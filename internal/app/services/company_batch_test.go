@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyService_CreateBatch(t *testing.T) {
+	t.Parallel()
+	valid := CreateUpdateCompanyPayload{Name: "acme", EmployeesAmount: 3, Type: common.Corporations}
+	invalid := CreateUpdateCompanyPayload{Name: "acme"}
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		payloads    []CreateUpdateCompanyPayload
+		atomic      bool
+		expStatuses []string
+		expSaved    int
+		expErr      string
+	}{
+		"empty batch": {
+			companyRepo: &mockCompanyRepository{},
+			expErr:      "invalid batch: must contain between 1 and 500 companies",
+		},
+		"too large batch": {
+			companyRepo: &mockCompanyRepository{},
+			payloads:    make([]CreateUpdateCompanyPayload, 501),
+			expErr:      "invalid batch: must contain between 1 and 500 companies",
+		},
+		"atomic batch with an invalid company": {
+			companyRepo: &mockCompanyRepository{},
+			payloads:    []CreateUpdateCompanyPayload{valid, invalid},
+			atomic:      true,
+			expStatuses: []string{"skipped", "invalid company: employees_amount must not be empty; type must not be empty"},
+			expErr:      "batch aborted, no company was created",
+		},
+		"atomic batch failing to save": {
+			companyRepo: &mockCompanyRepository{
+				batchErrs: []error{nil, errors.New("duplicate name")},
+				err:       fmt.Errorf("failed to save companies: %w", repositories.ErrBatchAborted),
+			},
+			payloads:    []CreateUpdateCompanyPayload{valid, valid},
+			atomic:      true,
+			expStatuses: []string{"skipped", "failed to save company: duplicate name"},
+			expSaved:    2,
+			expErr:      "batch aborted, no company was created",
+		},
+		"company repo error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			payloads:    []CreateUpdateCompanyPayload{valid},
+			expSaved:    1,
+			expErr:      "failed to create companies: company repo error",
+		},
+		"best effort batch": {
+			companyRepo: &mockCompanyRepository{batchErrs: []error{errors.New("duplicate name"), nil}},
+			payloads:    []CreateUpdateCompanyPayload{valid, invalid, valid},
+			expStatuses: []string{"failed to save company: duplicate name", "invalid company: employees_amount must not be empty; type must not be empty", "created"},
+			expSaved:    2,
+		},
+		"best effort batch without valid companies": {
+			companyRepo: &mockCompanyRepository{},
+			payloads:    []CreateUpdateCompanyPayload{invalid},
+			expStatuses: []string{"invalid company: employees_amount must not be empty; type must not be empty"},
+			expErr:      "batch aborted, no company was created",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+			assert.NoError(t, err)
+
+			userID := uuid.New()
+			results, err := s.CreateBatch(context.TODO(), userID, tt.payloads, tt.atomic)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, tt.companyRepo.batch, tt.expSaved)
+			for _, company := range tt.companyRepo.batch {
+				assert.Equal(t, userID, company.UserID)
+			}
+
+			var statuses []string
+			for _, result := range results {
+				switch {
+				case result.Created:
+					statuses = append(statuses, "created")
+				case result.Err != nil:
+					statuses = append(statuses, result.Err.Error())
+				default:
+					statuses = append(statuses, "skipped")
+				}
+			}
+			assert.Equal(t, tt.expStatuses, statuses)
+		})
+	}
+}
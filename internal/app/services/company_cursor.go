@@ -0,0 +1,122 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// cursorToken is the decoded form of the opaque pagination token handed to clients.
+type cursorToken struct {
+	SortBy     repositories.CompanySortField `json:"s"`
+	Descending bool                          `json:"d"`
+	Value      string                        `json:"v"`
+	ID         uuid.UUID                     `json:"id"`
+}
+
+// isSortable reports whether companies can be listed by the given field.
+func isSortable(field repositories.CompanySortField) bool {
+	switch field {
+	case repositories.SortByCreatedAt, repositories.SortByUpdatedAt, repositories.SortByName, repositories.SortByEmployeesAmount:
+		return true
+	}
+	return false
+}
+
+// encodeCursor builds the token pointing right after the given company.
+func encodeCursor(sortBy repositories.CompanySortField, descending bool, last models.Company) string {
+	token := cursorToken{SortBy: sortBy, Descending: descending, ID: last.ID}
+	switch sortBy {
+	case repositories.SortByCreatedAt:
+		token.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	case repositories.SortByUpdatedAt:
+		token.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	case repositories.SortByName:
+		token.Value = last.Name
+	case repositories.SortByEmployeesAmount:
+		token.Value = strconv.Itoa(last.EmployeesAmount)
+	}
+
+	// marshalling a struct of strings and bools cannot fail
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// encodeAttributeCursor builds the token pointing right after the company with the given id
+// when companies are sorted by the custom attribute the value is for.
+func encodeAttributeCursor(sortBy repositories.CompanySortField, descending bool, id uuid.UUID, value models.CompanyAttribute) string {
+	token := cursorToken{SortBy: sortBy, Descending: descending, ID: id, Value: fmt.Sprint(attributeJSON(value))}
+
+	// marshalling a struct of strings and bools cannot fail
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a token and makes sure it was issued for the same ordering.
+func decodeCursor(raw string, sortBy repositories.CompanySortField, descending bool) (repositories.CompanyCursor, error) {
+	token, err := parseCursorToken(raw, sortBy, descending)
+	if err != nil {
+		return repositories.CompanyCursor{}, err
+	}
+
+	cursor := repositories.CompanyCursor{ID: token.ID}
+	switch sortBy {
+	case repositories.SortByCreatedAt, repositories.SortByUpdatedAt:
+		t, err := time.Parse(time.RFC3339Nano, token.Value)
+		if err != nil {
+			return repositories.CompanyCursor{}, fmt.Errorf("malformed cursor value: %w", err)
+		}
+		cursor.SortValue = t
+	case repositories.SortByName:
+		cursor.SortValue = token.Value
+	case repositories.SortByEmployeesAmount:
+		n, err := strconv.Atoi(token.Value)
+		if err != nil {
+			return repositories.CompanyCursor{}, fmt.Errorf("malformed cursor value: %w", err)
+		}
+		cursor.SortValue = n
+	}
+
+	return cursor, nil
+}
+
+// decodeAttributeCursor parses a token issued for companies sorted by a custom attribute of the given type.
+func decodeAttributeCursor(raw string, sortBy repositories.CompanySortField, descending bool, attributeType models.AttributeType) (repositories.CompanyCursor, error) {
+	token, err := parseCursorToken(raw, sortBy, descending)
+	if err != nil {
+		return repositories.CompanyCursor{}, err
+	}
+
+	value, err := parseAttributeText(attributeType, token.Value)
+	if err != nil {
+		return repositories.CompanyCursor{}, fmt.Errorf("malformed cursor value: %w", err)
+	}
+
+	return repositories.CompanyCursor{SortValue: value, ID: token.ID}, nil
+}
+
+// parseCursorToken decodes a token and makes sure it was issued for the same ordering.
+func parseCursorToken(raw string, sortBy repositories.CompanySortField, descending bool) (cursorToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursorToken{}, errors.New("malformed cursor")
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return cursorToken{}, errors.New("malformed cursor")
+	}
+
+	if token.SortBy != sortBy || token.Descending != descending {
+		return cursorToken{}, errors.New("cursor was issued for a different sort order")
+	}
+
+	return token, nil
+}
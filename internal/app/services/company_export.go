@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/models"
+)
+
+// CompanyExporter defines the functionality related to exporting companies to files.
+type CompanyExporter interface {
+	Export(ctx context.Context, userID uuid.UUID, filter CompanyFilter, w io.Writer, format companyio.Format) error
+}
+
+// exportChunkSize is the number of companies read from the database at once.
+const exportChunkSize = 500
+
+// Export writes every company of the user matching the filter to w in the given format.
+func (s *CompanyService) Export(ctx context.Context, userID uuid.UUID, filter CompanyFilter, w io.Writer, format companyio.Format) error {
+	filter.MemberID = &userID
+	return s.ExportAll(ctx, filter, w, format)
+}
+
+// ExportAll writes every company matching the filter to w in the given format whoever its members
+// are, it backs the export command of the operators. Companies are read in chunks so the memory
+// used does not depend on the number of companies.
+func (s *CompanyService) ExportAll(ctx context.Context, filter CompanyFilter, w io.Writer, format companyio.Format) error {
+	var schemas map[string]models.AttributeSchema
+	if len(filter.Attributes) > 0 {
+		var err error
+		if schemas, err = s.attributeSchemas(ctx); err != nil {
+			return err
+		}
+	}
+	repoFilter, err := repositoryFilter(schemas, filter)
+	if err != nil {
+		return err
+	}
+
+	enc, err := companyio.NewEncoder(w, format)
+	if err != nil {
+		return err
+	}
+
+	err = s.companyRepo.Export(ctx, repoFilter, exportChunkSize, func(companies []models.Company) error {
+		for _, company := range companies {
+			if err := enc.Encode(company); err != nil {
+				return fmt.Errorf("failed to encode company %s: %w", company.ID, err)
+			}
+		}
+		return enc.Flush()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export companies: %w", err)
+	}
+
+	return enc.Close()
+}
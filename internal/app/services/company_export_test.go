@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyService_Export(t *testing.T) {
+	t.Parallel()
+	registered := true
+	companies := make([]models.Company, exportChunkSize+1)
+	for i := range companies {
+		companies[i] = models.Company{Name: fmt.Sprintf("company %d", i)}
+	}
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		format      companyio.Format
+		expLines    int
+		expErr      string
+	}{
+		"unknown format": {
+			companyRepo: &mockCompanyRepository{},
+			format:      companyio.Format("xml"),
+			expErr:      "cannot export unknown format \"xml\"",
+		},
+		"company repo error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			format:      companyio.FormatNDJSON,
+			expErr:      "failed to export companies: company repo error",
+		},
+		"success over several chunks": {
+			companyRepo: &mockCompanyRepository{companies: companies},
+			format:      companyio.FormatNDJSON,
+			expLines:    exportChunkSize + 1,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+			assert.NoError(t, err)
+
+			var out strings.Builder
+			filter := CompanyFilter{Types: []common.Type{common.Corporations}, Registered: &registered}
+			userID := uuid.New()
+			err = s.Export(context.TODO(), userID, filter, &out, tt.format)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, repositories.CompanyFilter{Types: filter.Types, Registered: filter.Registered, MemberID: &userID}, tt.companyRepo.listQuery.Filter)
+			assert.Equal(t, tt.expLines, strings.Count(out.String(), "\n"))
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// maxHierarchyDepth is the number of levels a corporate group can have below its top company.
+const maxHierarchyDepth = 10
+
+var (
+	// ErrInvalidHierarchyQuery is returned when the depth of a subtree cannot be used.
+	ErrInvalidHierarchyQuery = newError(ErrInvalidRequest, "invalid_hierarchy_query", "invalid hierarchy query")
+	// ErrInvalidChildrenPolicy is returned when deleting a company with an unknown children policy.
+	ErrInvalidChildrenPolicy = newError(ErrInvalidRequest, "invalid_children_policy", "invalid children policy")
+	// ErrHasSubsidiaries is returned when deleting a company that has subsidiaries without promoting them.
+	ErrHasSubsidiaries = newError(ErrConflict, "company_has_subsidiaries", "company has subsidiaries")
+)
+
+// CompanyHierarchy defines the functionality related to corporate groups.
+type CompanyHierarchy interface {
+	Ancestors(ctx context.Context, userID, companyID uuid.UUID) ([]models.Company, error)
+	Children(ctx context.Context, userID, companyID uuid.UUID) ([]models.Company, error)
+	Subtree(ctx context.Context, userID, companyID uuid.UUID, depth int) (CompanySubtree, error)
+}
+
+// ChildrenPolicy tells what happens to the subsidiaries of a deleted company.
+type ChildrenPolicy string
+
+const (
+	// ChildrenRestrict refuses to delete a company that has subsidiaries, it is the default.
+	ChildrenRestrict ChildrenPolicy = "restrict"
+	// ChildrenPromote moves the subsidiaries to the parent of the deleted company.
+	ChildrenPromote ChildrenPolicy = "promote"
+)
+
+// CompanyNode represents a company of a subtree and its depth below the root of the subtree.
+type CompanyNode struct {
+	Company models.Company
+	Depth   int
+}
+
+// CompanySubtree represents a company and its subsidiaries, EmployeesTotal adds up the
+// employees of every company of the subtree listed in Nodes.
+type CompanySubtree struct {
+	Nodes          []CompanyNode
+	EmployeesTotal int
+}
+
+// Ancestors returns the parents of a company the user can view up to the top of its group, the
+// direct parent first. The parents the user cannot view are left out.
+func (s *CompanyService) Ancestors(ctx context.Context, userID, companyID uuid.UUID) ([]models.Company, error) {
+	ancestors, err := s.companyRepo.Ancestors(ctx, companyID, maxHierarchyDepth)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ancestors: %w", err)
+	}
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.viewableCompanies(ctx, userID, ancestors)
+}
+
+// Children returns the direct subsidiaries of a company the user can view, the subsidiaries the
+// user cannot view are left out.
+func (s *CompanyService) Children(ctx context.Context, userID, companyID uuid.UUID) ([]models.Company, error) {
+	if _, err := s.Get(ctx, userID, companyID); err != nil {
+		return nil, err
+	}
+
+	children, err := s.companyRepo.Children(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list children: %w", err)
+	}
+
+	return s.viewableCompanies(ctx, userID, children)
+}
+
+// viewableCompanies returns the companies the user can view keeping their order.
+func (s *CompanyService) viewableCompanies(ctx context.Context, userID uuid.UUID, companies []models.Company) ([]models.Company, error) {
+	ids := make([]uuid.UUID, 0, len(companies))
+	for _, company := range companies {
+		ids = append(ids, company.ID)
+	}
+	visible, err := s.access.viewable(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	viewable := make([]models.Company, 0, len(companies))
+	for _, company := range companies {
+		if visible[company.ID] {
+			viewable = append(viewable, company)
+		}
+	}
+
+	return viewable, nil
+}
+
+// Subtree returns a company the user can view and its subsidiaries down to depth levels below it,
+// zero returns the whole subtree. The subsidiaries the user cannot view are left out.
+func (s *CompanyService) Subtree(ctx context.Context, userID, companyID uuid.UUID, depth int) (CompanySubtree, error) {
+	switch {
+	case depth < 0:
+		return CompanySubtree{}, fmt.Errorf("%w: negative depth", ErrInvalidHierarchyQuery)
+	case depth == 0 || depth > maxHierarchyDepth:
+		depth = maxHierarchyDepth
+	}
+
+	nodes, err := s.companyRepo.Subtree(ctx, companyID, depth)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return CompanySubtree{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return CompanySubtree{}, fmt.Errorf("failed to find subtree: %w", err)
+	}
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return CompanySubtree{}, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.Company.ID)
+	}
+	visible, err := s.access.viewable(ctx, userID, ids)
+	if err != nil {
+		return CompanySubtree{}, err
+	}
+
+	subtree := CompanySubtree{Nodes: make([]CompanyNode, 0, len(nodes))}
+	for _, node := range nodes {
+		if !visible[node.Company.ID] {
+			continue
+		}
+		subtree.Nodes = append(subtree.Nodes, CompanyNode(node))
+		subtree.EmployeesTotal += node.Company.EmployeesAmount
+	}
+
+	return subtree, nil
+}
+
+// checkParent makes sure the company can be attached to its parent when the parent differs from
+// the previous one: the parent is a live company the user can edit, it is not the company or one
+// of its subsidiaries and the group does not grow deeper than maxHierarchyDepth.
+func (s *CompanyService) checkParent(ctx context.Context, userID uuid.UUID, previous *uuid.UUID, company models.Company) error {
+	if company.ParentID == nil || (previous != nil && *previous == *company.ParentID) {
+		return nil
+	}
+	parentID := *company.ParentID
+
+	ancestors, err := s.companyRepo.Ancestors(ctx, parentID, maxHierarchyDepth)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return parentViolation("must be an existing company")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find ancestors: %w", err)
+	}
+
+	height := 0
+	if company.ID != uuid.Nil {
+		if parentID == company.ID {
+			return parentViolation("must not be the company itself")
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == company.ID {
+				return parentViolation("must not be a subsidiary of the company")
+			}
+		}
+
+		nodes, err := s.companyRepo.Subtree(ctx, company.ID, maxHierarchyDepth)
+		if err != nil {
+			return fmt.Errorf("failed to find subtree: %w", err)
+		}
+		for _, node := range nodes {
+			if node.Depth > height {
+				height = node.Depth
+			}
+		}
+	}
+
+	if len(ancestors)+1+height > maxHierarchyDepth {
+		return parentViolation(fmt.Sprintf("must not make the group deeper than %d levels", maxHierarchyDepth))
+	}
+
+	if err := s.access.authorize(ctx, userID, parentID, models.RoleEditor); err != nil {
+		return fmt.Errorf("parent company: %w", err)
+	}
+
+	return nil
+}
+
+// parentViolation returns the validation error of a company that cannot be attached to its parent.
+func parentViolation(message string) error {
+	return newValidationError(ErrInvalidCompany, []Violation{{Field: "parent_id", Message: message}})
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyService_Subtree(t *testing.T) {
+	t.Parallel()
+	root := models.Company{ID: uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d01"), EmployeesAmount: 10}
+	child := models.Company{ID: uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d02"), EmployeesAmount: 25, ParentID: &root.ID}
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		memberRepo  *mockMemberRepository
+		depth       int
+		expSubtree  CompanySubtree
+		expErr      string
+	}{
+		"negative depth": {
+			companyRepo: &mockCompanyRepository{},
+			depth:       -1,
+			expErr:      "invalid hierarchy query: negative depth",
+		},
+		"company not found": {
+			companyRepo: &mockCompanyRepository{hierarchyErr: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound)},
+			expErr:      "company not found: 0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d01",
+		},
+		"company repo error": {
+			companyRepo: &mockCompanyRepository{hierarchyErr: errors.New("company repo error")},
+			expErr:      "failed to find subtree: company repo error",
+		},
+		"success": {
+			companyRepo: &mockCompanyRepository{subtree: []repositories.CompanyNode{{Company: root}, {Company: child, Depth: 1}}},
+			expSubtree: CompanySubtree{
+				Nodes:          []CompanyNode{{Company: root}, {Company: child, Depth: 1}},
+				EmployeesTotal: 35,
+			},
+		},
+		"not a member": {
+			companyRepo: &mockCompanyRepository{subtree: []repositories.CompanyNode{{Company: root}, {Company: child, Depth: 1}}},
+			memberRepo:  &mockMemberRepository{err: fmt.Errorf("failed to find member: %w", repositories.ErrNotFound)},
+			expErr:      "access to the company denied: the viewer role is required",
+		},
+		"subsidiary the user cannot view": {
+			companyRepo: &mockCompanyRepository{subtree: []repositories.CompanyNode{{Company: root}, {Company: child, Depth: 1}}},
+			memberRepo:  &mockMemberRepository{hidden: []uuid.UUID{child.ID}},
+			expSubtree: CompanySubtree{
+				Nodes:          []CompanyNode{{Company: root}},
+				EmployeesTotal: 10,
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, memberRepo, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			subtree, err := s.Subtree(context.TODO(), uuid.New(), root.ID, tt.depth)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expSubtree, subtree)
+			}
+		})
+	}
+}
+
+func TestCompanyService_UpdateParent(t *testing.T) {
+	t.Parallel()
+	stored := models.Company{
+		ID:              uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d01"),
+		Version:         1,
+		Name:            "company1",
+		EmployeesAmount: 40,
+		Type:            common.Corporations,
+	}
+	parentID := uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d02")
+	ancestors := make([]models.Company, maxHierarchyDepth-1)
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		memberRepo  repositories.MemberRepository
+		parentID    uuid.UUID
+		expParentID *uuid.UUID
+		expErr      string
+	}{
+		"parent not found": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored, hierarchyErr: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound)},
+			parentID:    parentID,
+			expErr:      "invalid company: parent_id must be an existing company",
+		},
+		"company itself": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			parentID:    stored.ID,
+			expErr:      "invalid company: parent_id must not be the company itself",
+		},
+		"subsidiary of the company": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored, ancestors: []models.Company{stored}},
+			parentID:    parentID,
+			expErr:      "invalid company: parent_id must not be a subsidiary of the company",
+		},
+		"group too deep": {
+			companyRepo: &mockCompanyRepository{
+				singleCompany: stored,
+				ancestors:     ancestors,
+				subtree:       []repositories.CompanyNode{{Company: stored}, {Depth: 1}},
+			},
+			parentID: parentID,
+			expErr:   "invalid company: parent_id must not make the group deeper than 10 levels",
+		},
+		"parent not editable": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			memberRepo:  &parentMemberRepository{parentID: parentID, role: models.RoleViewer},
+			parentID:    parentID,
+			expErr:      "parent company: access to the company denied: the editor role is required, you are viewer",
+		},
+		"parent moved under the company meanwhile": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored, updateErr: fmt.Errorf("failed to update company: %w", repositories.ErrParentCycle)},
+			parentID:    parentID,
+			expErr:      "invalid company: parent_id must not be a subsidiary of the company",
+		},
+		"attach": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored, ancestors: ancestors[1:]},
+			parentID:    parentID,
+			expParentID: &parentID,
+		},
+		"detach": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{
+				ID: stored.ID, Version: 1, Name: "company1", EmployeesAmount: 40, Type: common.Corporations, ParentID: &parentID,
+			}},
+			parentID: uuid.Nil,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, memberRepo, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			_, err = s.Update(context.TODO(), uuid.New(), stored.ID, 0, UpdateCompanyPayload{ParentID: &tt.parentID})
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expParentID, tt.companyRepo.updated.ParentID)
+			}
+		})
+	}
+}
+
+// parentMemberRepository gives the user the role on the parent company and makes them owner of every other company.
+type parentMemberRepository struct {
+	mockMemberRepository
+	parentID uuid.UUID
+	role     models.MemberRole
+}
+
+func (m *parentMemberRepository) Find(_ context.Context, companyID, userID uuid.UUID) (models.CompanyMember, error) {
+	role := models.RoleOwner
+	if companyID == m.parentID {
+		role = m.role
+	}
+	return models.CompanyMember{CompanyID: companyID, UserID: userID, Role: role}, nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/models"
+)
+
+// CompanyImporter defines the functionality related to importing companies from files.
+type CompanyImporter interface {
+	Import(ctx context.Context, userID uuid.UUID, r io.Reader, format companyio.Format, mapping map[string]string, saved func([]models.Company)) (ImportReport, error)
+}
+
+// ErrInvalidImport is returned when a file cannot be imported at all.
+var ErrInvalidImport = newError(ErrInvalidRequest, "invalid_import", "invalid import")
+
+// importChunkSize is the number of rows saved at once.
+const importChunkSize = 100
+
+// ImportReport represents the outcome of an import, rejected rows are ordered by line.
+type ImportReport struct {
+	Total    int
+	Imported int
+	Rejected []RejectedRow
+}
+
+// RejectedRow represents a row of an import that did not become a company.
+type RejectedRow struct {
+	Line  int
+	Error string
+}
+
+// Import reads companies of the user from a CSV or NDJSON stream and saves the valid ones.
+// The attr.<name> columns hold the values of custom attributes, written the way they are
+// in query strings. Rows that cannot be parsed, fail the validation of Create or cannot be
+// saved are reported as rejected without stopping the import. Unless nil, saved is called
+// with the companies of every chunk once they are saved, before the next chunk is read.
+func (s *CompanyService) Import(ctx context.Context, userID uuid.UUID, r io.Reader, format companyio.Format, mapping map[string]string, saved func([]models.Company)) (ImportReport, error) {
+	dec, err := companyio.NewDecoder(r, format, mapping)
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	types, err := s.availableTypes(ctx, "")
+	if err != nil {
+		return ImportReport{}, err
+	}
+	schemas, err := s.attrRepo.ListSchemas(ctx)
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("failed to list attribute schemas: %w", err)
+	}
+
+	report := ImportReport{Rejected: []RejectedRow{}}
+	companies := make([]models.Company, 0, importChunkSize)
+	lines := make([]int, 0, importChunkSize)
+	flush := func() error {
+		if len(companies) == 0 {
+			return nil
+		}
+
+		errs, err := s.companyRepo.SaveBatch(ctx, companies, false)
+		if err != nil {
+			return fmt.Errorf("failed to save imported companies: %w", err)
+		}
+		chunk := make([]models.Company, 0, len(companies))
+		for i, err := range errs {
+			if err != nil {
+				report.Rejected = append(report.Rejected, RejectedRow{Line: lines[i], Error: fmt.Sprintf("failed to save company: %v", err)})
+				continue
+			}
+			report.Imported++
+			chunk = append(chunk, companies[i])
+		}
+		if saved != nil && len(chunk) > 0 {
+			saved(chunk)
+		}
+
+		companies, lines = companies[:0], lines[:0]
+		return nil
+	}
+
+	for {
+		row, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to read import: %w", err)
+		}
+
+		report.Total++
+		if row.Err != nil {
+			report.Rejected = append(report.Rejected, RejectedRow{Line: row.Line, Error: row.Err.Error()})
+			continue
+		}
+
+		payload := CreateUpdateCompanyPayload{
+			Name:            row.Company.Name,
+			Description:     row.Company.Description,
+			EmployeesAmount: row.Company.EmployeesAmount,
+			Registered:      row.Company.Registered,
+			Type:            row.Company.Type,
+		}
+		company := payload.company(userID)
+		if err := validateCompany(company, types); err != nil {
+			report.Rejected = append(report.Rejected, RejectedRow{Line: row.Line, Error: err.Error()})
+			continue
+		}
+		company.Attributes, err = newCompanyAttributes(schemas, userID, attributesFromText(schemas, row.Attributes))
+		if err != nil {
+			report.Rejected = append(report.Rejected, RejectedRow{Line: row.Line, Error: err.Error()})
+			continue
+		}
+
+		companies = append(companies, company)
+		lines = append(lines, row.Line)
+		if len(companies) == importChunkSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	sort.Slice(report.Rejected, func(i, j int) bool {
+		return report.Rejected[i].Line < report.Rejected[j].Line
+	})
+
+	return report, nil
+}
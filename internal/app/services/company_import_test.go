@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/companyio"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyService_Import(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		file        string
+		format      companyio.Format
+		expReport   ImportReport
+		expSaved    []models.Company
+		expErr      string
+	}{
+		"unknown format": {
+			companyRepo: &mockCompanyRepository{},
+			format:      companyio.FormatJSON,
+			expErr:      "invalid import: cannot import unknown format \"json\"",
+		},
+		"missing name column": {
+			companyRepo: &mockCompanyRepository{},
+			file:        "title,employees\n",
+			format:      companyio.FormatCSV,
+			expErr:      "invalid import: csv header has no name column",
+		},
+		"company repo error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			file:        "name,employees_amount,type\nacme,3,Corporations\n",
+			format:      companyio.FormatCSV,
+			expReport:   ImportReport{Total: 1, Rejected: []RejectedRow{}},
+			expErr:      "failed to save imported companies: company repo error",
+		},
+		"rejected rows": {
+			companyRepo: &mockCompanyRepository{batchErrs: []error{nil, errors.New("duplicate name")}},
+			file: "name,employees_amount,type\n" +
+				"acme,3,Corporations\n" +
+				"globex,many,NonProfit\n" +
+				"initech,0,Cooperative\n" +
+				"acme,5,Corporations\n",
+			format: companyio.FormatCSV,
+			expReport: ImportReport{
+				Total:    4,
+				Imported: 1,
+				Rejected: []RejectedRow{
+					{Line: 3, Error: "employees_amount must be an integer"},
+					{Line: 4, Error: "invalid company: employees_amount must not be empty"},
+					{Line: 5, Error: "failed to save company: duplicate name"},
+				},
+			},
+			expSaved: []models.Company{
+				{Name: "acme", EmployeesAmount: 3, Type: common.Corporations},
+				{Name: "acme", EmployeesAmount: 5, Type: common.Corporations},
+			},
+		},
+		"ndjson": {
+			companyRepo: &mockCompanyRepository{},
+			file:        `{"name":"acme","employees_amount":3,"type":"Corporations","registered":true}`,
+			format:      companyio.FormatNDJSON,
+			expReport:   ImportReport{Total: 1, Imported: 1, Rejected: []RejectedRow{}},
+			expSaved: []models.Company{
+				{Name: "acme", EmployeesAmount: 3, Type: common.Corporations, Registered: true},
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+			assert.NoError(t, err)
+
+			userID := uuid.New()
+			var saved []models.Company
+			report, err := s.Import(context.TODO(), userID, strings.NewReader(tt.file), tt.format, nil, func(companies []models.Company) {
+				saved = append(saved, companies...)
+			})
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expReport, report)
+			assert.Len(t, saved, tt.expReport.Imported, "only the saved companies are handed over")
+
+			for i := range tt.expSaved {
+				tt.expSaved[i].UserID = userID
+			}
+			if tt.expSaved != nil {
+				assert.Equal(t, tt.expSaved, tt.companyRepo.batch)
+			}
+		})
+	}
+}
+
+func TestCompanyService_ImportChunks(t *testing.T) {
+	t.Parallel()
+	companyRepo := &mockCompanyRepository{}
+	s, err := NewCompanyService(companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+	assert.NoError(t, err)
+
+	var file strings.Builder
+	file.WriteString("name,employees_amount,type\n")
+	for i := 0; i < importChunkSize+1; i++ {
+		fmt.Fprintf(&file, "company %d,1,Corporations\n", i)
+	}
+
+	var chunks []int
+	saved := func(companies []models.Company) {
+		chunks = append(chunks, len(companies))
+	}
+	report, err := s.Import(context.TODO(), uuid.New(), strings.NewReader(file.String()), companyio.FormatCSV, nil, saved)
+	assert.NoError(t, err)
+	assert.Equal(t, importChunkSize+1, report.Imported)
+	assert.Len(t, companyRepo.batch, 1, "the last chunk holds the remaining row")
+	assert.Equal(t, []int{importChunkSize, 1}, chunks, "every saved chunk is handed over")
+}
+
+func TestCompanyService_ImportAttributes(t *testing.T) {
+	t.Parallel()
+	companyRepo := &mockCompanyRepository{}
+	attrRepo := &mockAttributeRepository{schemas: []models.AttributeSchema{
+		{Name: "founded", Type: models.AttributeInt},
+		{Name: "vat_number", Type: models.AttributeString, Required: true},
+	}}
+	s, err := NewCompanyService(companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, attrRepo)
+	assert.NoError(t, err)
+
+	file := "name,employees_amount,type,attr.founded,VAT\n" +
+		"acme,3,Corporations,1947,DE123\n" +
+		"globex,5,Corporations,1989,\n" +
+		"initech,7,Corporations,old,US456\n"
+	report, err := s.Import(context.TODO(), uuid.New(), strings.NewReader(file), companyio.FormatCSV, map[string]string{"VAT": "attr.vat_number"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Imported)
+	assert.Equal(t, []RejectedRow{
+		{Line: 3, Error: "invalid company: attributes.vat_number is required"},
+		{Line: 4, Error: "invalid company: attributes.founded must be an integer"},
+	}, report.Rejected)
+	if assert.Len(t, companyRepo.batch, 1) && assert.Len(t, companyRepo.batch[0].Attributes, 2) {
+		assert.Equal(t, int64(1947), *companyRepo.batch[0].Attributes[0].IntValue)
+		assert.Equal(t, "DE123", *companyRepo.batch[0].Attributes[1].StringValue)
+	}
+}
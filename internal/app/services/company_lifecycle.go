@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// maxReasonLength matches the size of the reason column of transitions.
+const maxReasonLength = 500
+
+var (
+	// ErrInvalidTransition is returned when a transition request breaks one or more validation rules.
+	ErrInvalidTransition = newError(ErrValidation, "invalid_transition", "invalid transition")
+	// ErrTransitionNotAllowed is returned when a company cannot go from its status to the requested one.
+	ErrTransitionNotAllowed = newError(ErrConflict, "transition_not_allowed", "transition not allowed")
+)
+
+// CompanyLifecycle defines the functionality related to the status of companies.
+type CompanyLifecycle interface {
+	ListTransitions(ctx context.Context, userID, companyID uuid.UUID) ([]models.CompanyTransition, error)
+	Transition(ctx context.Context, userID, companyID uuid.UUID, version int, payload TransitionPayload) (models.CompanyTransition, models.Company, error)
+}
+
+// TransitionPayload represents a request to move a company to another status.
+type TransitionPayload struct {
+	To     models.CompanyStatus
+	Reason string
+}
+
+// lifecycleRule is a move a company is allowed to make, moves that take a company off
+// the register or turn it down have to be explained.
+type lifecycleRule struct {
+	from        models.CompanyStatus
+	to          models.CompanyStatus
+	kind        models.TransitionKind
+	needsReason bool
+}
+
+// lifecycleRules are the only transitions companies can make, dissolved companies stay dissolved.
+var lifecycleRules = []lifecycleRule{
+	{models.StatusDraft, models.StatusPendingRegistration, models.TransitionSubmitted, false},
+	{models.StatusPendingRegistration, models.StatusDraft, models.TransitionRejected, true},
+	{models.StatusPendingRegistration, models.StatusActive, models.TransitionRegistered, false},
+	{models.StatusActive, models.StatusSuspended, models.TransitionSuspended, true},
+	{models.StatusSuspended, models.StatusActive, models.TransitionReinstated, false},
+	{models.StatusActive, models.StatusDissolved, models.TransitionDissolved, true},
+	{models.StatusSuspended, models.StatusDissolved, models.TransitionDissolved, true},
+}
+
+// lifecycleStatuses are the statuses in lifecycle order.
+var lifecycleStatuses = []models.CompanyStatus{
+	models.StatusDraft,
+	models.StatusPendingRegistration,
+	models.StatusActive,
+	models.StatusSuspended,
+	models.StatusDissolved,
+}
+
+// ListTransitions returns the status changes of a company to a viewer of it, oldest first.
+func (s *CompanyService) ListTransitions(ctx context.Context, userID, companyID uuid.UUID) ([]models.CompanyTransition, error) {
+	if _, err := s.findVersion(ctx, userID, companyID, 0, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.companyRepo.ListTransitions(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transitions: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// Transition moves a company to another status on behalf of an owner of it, a non zero version
+// must match the current version of the company. Only the moves of lifecycleRules are allowed.
+func (s *CompanyService) Transition(ctx context.Context, userID, companyID uuid.UUID, version int, payload TransitionPayload) (models.CompanyTransition, models.Company, error) {
+	payload.Reason = strings.TrimSpace(payload.Reason)
+	if err := validateTransition(payload); err != nil {
+		return models.CompanyTransition{}, models.Company{}, err
+	}
+
+	company, err := s.findVersion(ctx, userID, companyID, version, models.RoleOwner)
+	if err != nil {
+		return models.CompanyTransition{}, models.Company{}, err
+	}
+
+	from := company.Status
+	if from == "" {
+		from = models.InitialStatus(company.Registered)
+	}
+	rule, err := findLifecycleRule(from, payload.To)
+	if err != nil {
+		return models.CompanyTransition{}, models.Company{}, err
+	}
+	if rule.needsReason && payload.Reason == "" {
+		return models.CompanyTransition{}, models.Company{}, newValidationError(ErrInvalidTransition, []Violation{
+			{Field: "reason", Message: fmt.Sprintf("must not be empty when going from %s to %s", rule.from, rule.to)},
+		})
+	}
+
+	transition, updated, err := s.companyRepo.Transition(ctx, company.Version, models.CompanyTransition{
+		CompanyID: companyID,
+		Kind:      rule.kind,
+		From:      from,
+		To:        payload.To,
+		Reason:    payload.Reason,
+		ActorID:   userID,
+	})
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		return models.CompanyTransition{}, models.Company{}, fmt.Errorf("%w: company was modified concurrently", ErrVersionMismatch)
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CompanyTransition{}, models.Company{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return models.CompanyTransition{}, models.Company{}, fmt.Errorf("failed to transition company: %w", err)
+	}
+
+	return transition, updated, nil
+}
+
+// validateTransition checks the parts of a transition request that do not depend on the company.
+func validateTransition(payload TransitionPayload) error {
+	var violations []Violation
+	known := false
+	for _, status := range lifecycleStatuses {
+		known = known || status == payload.To
+	}
+	if !known {
+		violations = append(violations, Violation{Field: "to", Message: "must be one of " + joinStatuses(lifecycleStatuses)})
+	}
+	if utf8.RuneCountInString(payload.Reason) > maxReasonLength {
+		violations = append(violations, Violation{Field: "reason", Message: fmt.Sprintf("must be at most %d characters long", maxReasonLength)})
+	}
+
+	return newValidationError(ErrInvalidTransition, violations)
+}
+
+// findLifecycleRule returns the rule of a move, the error lists the statuses the company can go to instead.
+func findLifecycleRule(from, to models.CompanyStatus) (lifecycleRule, error) {
+	var next []models.CompanyStatus
+	for _, rule := range lifecycleRules {
+		if rule.from != from {
+			continue
+		}
+		if rule.to == to {
+			return rule, nil
+		}
+		next = append(next, rule.to)
+	}
+
+	if len(next) == 0 {
+		return lifecycleRule{}, fmt.Errorf("%w: the company is %s, its status cannot change anymore", ErrTransitionNotAllowed, from)
+	}
+	return lifecycleRule{}, fmt.Errorf("%w: the company is %s and cannot become %s, it can only become %s", ErrTransitionNotAllowed, from, to, joinStatuses(next))
+}
+
+// joinStatuses lists statuses for error messages.
+func joinStatuses(statuses []models.CompanyStatus) string {
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		names = append(names, string(status))
+	}
+
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
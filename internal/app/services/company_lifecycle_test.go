@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyService_Transition(t *testing.T) {
+	t.Parallel()
+	companyID := uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+	userID := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	active := models.Company{ID: companyID, Version: 3, Name: "Acme", Registered: true, Status: models.StatusActive}
+	cases := map[string]struct {
+		companyRepo   *mockCompanyRepository
+		memberRepo    *mockMemberRepository
+		version       int
+		payload       TransitionPayload
+		expTransition models.CompanyTransition
+		expErr        string
+	}{
+		"unknown status and long reason": {
+			companyRepo: &mockCompanyRepository{singleCompany: active},
+			payload:     TransitionPayload{To: "closed", Reason: strings.Repeat("x", maxReasonLength+1)},
+			expErr:      "invalid transition: to must be one of draft, pending_registration, active, suspended or dissolved; reason must be at most 500 characters long",
+		},
+		"editors cannot transition": {
+			companyRepo: &mockCompanyRepository{singleCompany: active},
+			memberRepo:  &mockMemberRepository{role: models.RoleEditor},
+			payload:     TransitionPayload{To: models.StatusSuspended, Reason: "overdue accounts"},
+			expErr:      "access to the company denied: the owner role is required, you are editor",
+		},
+		"version mismatch": {
+			companyRepo: &mockCompanyRepository{singleCompany: active},
+			version:     2,
+			payload:     TransitionPayload{To: models.StatusSuspended, Reason: "overdue accounts"},
+			expErr:      "company version mismatch: expected 2, current 3",
+		},
+		"skipping the registration": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{Status: models.StatusDraft}},
+			payload:     TransitionPayload{To: models.StatusActive},
+			expErr:      "transition not allowed: the company is draft and cannot become active, it can only become pending_registration",
+		},
+		"same status": {
+			companyRepo: &mockCompanyRepository{singleCompany: active},
+			payload:     TransitionPayload{To: models.StatusActive},
+			expErr:      "transition not allowed: the company is active and cannot become active, it can only become suspended or dissolved",
+		},
+		"dissolved is final": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{Status: models.StatusDissolved}},
+			payload:     TransitionPayload{To: models.StatusActive},
+			expErr:      "transition not allowed: the company is dissolved, its status cannot change anymore",
+		},
+		"suspending needs a reason": {
+			companyRepo: &mockCompanyRepository{singleCompany: active},
+			payload:     TransitionPayload{To: models.StatusSuspended, Reason: "  "},
+			expErr:      "invalid transition: reason must not be empty when going from active to suspended",
+		},
+		"modified concurrently": {
+			companyRepo: &mockCompanyRepository{singleCompany: active, updateErr: fmt.Errorf("failed to transition company: %w", repositories.ErrVersionConflict)},
+			payload:     TransitionPayload{To: models.StatusSuspended, Reason: "overdue accounts"},
+			expTransition: models.CompanyTransition{
+				CompanyID: companyID, Kind: models.TransitionSuspended, From: models.StatusActive, To: models.StatusSuspended, Reason: "overdue accounts", ActorID: userID,
+			},
+			expErr: "company version mismatch: company was modified concurrently",
+		},
+		"company created before statuses": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{ID: companyID, Version: 3, Registered: true}},
+			version:     3,
+			payload:     TransitionPayload{To: models.StatusSuspended, Reason: " overdue accounts "},
+			expTransition: models.CompanyTransition{
+				CompanyID: companyID, Kind: models.TransitionSuspended, From: models.StatusActive, To: models.StatusSuspended, Reason: "overdue accounts", ActorID: userID,
+			},
+		},
+		"submitting without a reason": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{ID: companyID, Version: 1, Status: models.StatusDraft}},
+			payload:     TransitionPayload{To: models.StatusPendingRegistration},
+			expTransition: models.CompanyTransition{
+				CompanyID: companyID, Kind: models.TransitionSubmitted, From: models.StatusDraft, To: models.StatusPendingRegistration, ActorID: userID,
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, memberRepo, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			transition, comp, err := s.Transition(context.TODO(), userID, companyID, tt.version, tt.payload)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.companyRepo.singleCompany.Version+1, transition.Version)
+				assert.Equal(t, tt.payload.To, comp.Status)
+				assert.Equal(t, tt.payload.To.Registered(), comp.Registered)
+			}
+			assert.Equal(t, tt.expTransition, tt.companyRepo.transition)
+		})
+	}
+}
+
+func TestCompanyService_ListTransitions(t *testing.T) {
+	t.Parallel()
+	companyRepo := &mockCompanyRepository{transitions: []models.CompanyTransition{{Kind: models.TransitionSubmitted}}}
+	s, err := NewCompanyService(companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{err: fmt.Errorf("failed to find member: %w", repositories.ErrNotFound)}, &mockAttributeRepository{})
+	assert.NoError(t, err)
+	_, err = s.ListTransitions(context.TODO(), uuid.New(), uuid.New())
+	assert.EqualError(t, err, "access to the company denied: the viewer role is required")
+
+	s, err = NewCompanyService(companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{role: models.RoleViewer}, &mockAttributeRepository{})
+	assert.NoError(t, err)
+	transitions, err := s.ListTransitions(context.TODO(), uuid.New(), uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, companyRepo.transitions, transitions)
+}
+
+func TestCompanyService_Update_RegisteredFollowsStatus(t *testing.T) {
+	t.Parallel()
+	stored := models.Company{Version: 1, Name: "Acme", EmployeesAmount: 4, Registered: true, Status: models.StatusActive, Type: common.Corporations}
+	s, err := NewCompanyService(&mockCompanyRepository{singleCompany: stored}, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+	assert.NoError(t, err)
+
+	registered := false
+	_, err = s.Update(context.TODO(), uuid.New(), uuid.New(), 0, UpdateCompanyPayload{Registered: &registered})
+	assert.EqualError(t, err, "invalid company: registered follows the status of the company, change it with a transition")
+
+	registered = true
+	_, err = s.Update(context.TODO(), uuid.New(), uuid.New(), 0, UpdateCompanyPayload{Registered: &registered})
+	assert.NoError(t, err, "sending the current value back is not a change")
+}
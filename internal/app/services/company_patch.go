@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+)
+
+var (
+	// ErrInvalidPatch is returned when a JSON patch cannot be applied to a company.
+	ErrInvalidPatch = newError(ErrValidation, "invalid_patch", "invalid patch")
+	// ErrPatchTestFailed is returned when a test operation of a JSON patch does not hold.
+	ErrPatchTestFailed = newError(ErrConflict, "patch_test_failed", "patch test failed")
+)
+
+// PatchOperation represents a single RFC 6902 JSON patch operation.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	Value json.RawMessage
+}
+
+// companyPatchFields maps the JSON pointers accepted in a patch to the company fields,
+// both the response and the request member names are accepted.
+var companyPatchFields = map[string]string{
+	"/ID": "id", "/id": "id",
+	"/CreatedAt": "created_at", "/created_at": "created_at",
+	"/UpdatedAt": "updated_at", "/updated_at": "updated_at",
+	"/Version": "version", "/version": "version",
+	"/UserID": "user_id", "/user_id": "user_id",
+	"/Name": "name", "/name": "name",
+	"/Description": "description", "/description": "description",
+	"/EmployeesAmount": "employees_amount", "/employees_amount": "employees_amount",
+	"/Registered": "registered", "/registered": "registered",
+	"/Type": "type", "/type": "type",
+	"/ParentID": "parent_id", "/parent_id": "parent_id",
+}
+
+// immutableFields can be tested but never modified by a patch.
+var immutableFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"version":    true,
+	"user_id":    true,
+}
+
+// Patch applies a JSON patch to a company on behalf of an editor of it, either every operation is
+// applied or none. A non zero version must match the current version of the company, a changed
+// employees amount applies from employeesEffectiveOn when it is set.
+func (s *CompanyService) Patch(ctx context.Context, userID, companyID uuid.UUID, version int, operations []PatchOperation, employeesEffectiveOn *time.Time) (models.Company, error) {
+	company, err := s.findVersion(ctx, userID, companyID, version, models.RoleEditor)
+	if err != nil {
+		return models.Company{}, err
+	}
+
+	types, err := s.availableTypes(ctx, company.Type)
+	if err != nil {
+		return models.Company{}, err
+	}
+
+	parentID := company.ParentID
+	for i, op := range operations {
+		if err := applyPatchOperation(&company, op); err != nil {
+			return models.Company{}, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	if err := validateCompany(company, types); err != nil {
+		return models.Company{}, err
+	}
+	if err := s.checkParent(ctx, userID, parentID, company); err != nil {
+		return models.Company{}, err
+	}
+
+	return s.update(ctx, userID, company, employeesEffectiveOn)
+}
+
+// applyPatchOperation applies a single operation to the company.
+func applyPatchOperation(company *models.Company, op PatchOperation) error {
+	field, ok := companyPatchFields[op.Path]
+	if !ok {
+		return fmt.Errorf("%w: unknown path %q", ErrInvalidPatch, op.Path)
+	}
+
+	if op.Op != "test" && immutableFields[field] {
+		return fmt.Errorf("%w: path %q is immutable", ErrInvalidPatch, op.Path)
+	}
+
+	switch op.Op {
+	case "test":
+		if op.Value == nil {
+			return fmt.Errorf("%w: test of %q has no value", ErrInvalidPatch, op.Path)
+		}
+		equal, err := jsonEqual(companyFieldValue(*company, field), op.Value)
+		if err != nil {
+			return fmt.Errorf("%w: invalid value for %q: %v", ErrInvalidPatch, op.Path, err)
+		}
+		if !equal {
+			return fmt.Errorf("%w: %q does not match %s", ErrPatchTestFailed, op.Path, op.Value)
+		}
+		return nil
+	case "add", "replace":
+		payload, err := replacePayload(field, op.Value)
+		if err != nil {
+			return fmt.Errorf("%w: invalid value for %q: %v", ErrInvalidPatch, op.Path, err)
+		}
+		payload.apply(company)
+		return nil
+	case "remove":
+		switch field {
+		case "description":
+			UpdateCompanyPayload{Description: new(string)}.apply(company)
+		case "parent_id":
+			UpdateCompanyPayload{ParentID: new(uuid.UUID)}.apply(company)
+		default:
+			return fmt.Errorf("%w: path %q is required and cannot be removed", ErrInvalidPatch, op.Path)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported operation %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// replacePayload decodes the value of an add or replace operation into an update payload.
+func replacePayload(field string, value json.RawMessage) (UpdateCompanyPayload, error) {
+	if value == nil || strings.TrimSpace(string(value)) == "null" {
+		return UpdateCompanyPayload{}, errors.New("value is missing")
+	}
+
+	var payload UpdateCompanyPayload
+	var dst any
+	switch field {
+	case "name":
+		payload.Name = new(string)
+		dst = payload.Name
+	case "description":
+		payload.Description = new(string)
+		dst = payload.Description
+	case "employees_amount":
+		payload.EmployeesAmount = new(int)
+		dst = payload.EmployeesAmount
+	case "registered":
+		payload.Registered = new(bool)
+		dst = payload.Registered
+	case "type":
+		payload.Type = new(common.Type)
+		dst = payload.Type
+	case "parent_id":
+		payload.ParentID = new(uuid.UUID)
+		dst = payload.ParentID
+	}
+
+	if err := json.Unmarshal(value, dst); err != nil {
+		return UpdateCompanyPayload{}, err
+	}
+
+	return payload, nil
+}
+
+// companyFieldValue returns the value of a company field as it is represented in responses.
+func companyFieldValue(company models.Company, field string) any {
+	switch field {
+	case "id":
+		return company.ID
+	case "created_at":
+		return company.CreatedAt
+	case "updated_at":
+		return company.UpdatedAt
+	case "version":
+		return company.Version
+	case "user_id":
+		return company.UserID
+	case "name":
+		return company.Name
+	case "description":
+		return company.Description
+	case "employees_amount":
+		return company.EmployeesAmount
+	case "registered":
+		return company.Registered
+	case "parent_id":
+		return company.ParentID
+	default:
+		return company.Type
+	}
+}
+
+// jsonEqual reports whether the value and the raw JSON represent the same JSON value.
+func jsonEqual(value any, raw json.RawMessage) (bool, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	var left, right any
+	if err := json.Unmarshal(encoded, &left); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(raw, &right); err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(left, right), nil
+}
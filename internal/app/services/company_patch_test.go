@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyService_Patch(t *testing.T) {
+	t.Parallel()
+	stored := models.Company{
+		ID:              uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+		Name:            "company1",
+		Description:     "description 1",
+		EmployeesAmount: 40,
+		Registered:      true,
+		Type:            common.Corporations,
+	}
+	op := func(op, path, value string) PatchOperation {
+		o := PatchOperation{Op: op, Path: path}
+		if value != "" {
+			o.Value = json.RawMessage(value)
+		}
+		return o
+	}
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		operations  []PatchOperation
+		expCompany  models.Company
+		expUpdated  bool
+		expErr      string
+		expErrIs    error
+	}{
+		"company repo find error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			expErr:      "failed to find company: company repo error",
+		},
+		"unknown path": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			operations:  []PatchOperation{op("replace", "/address", `"main street"`)},
+			expErr:      "operation 0: invalid patch: unknown path \"/address\"",
+			expErrIs:    ErrInvalidPatch,
+		},
+		"immutable field": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			operations:  []PatchOperation{op("replace", "/UserID", `"b6000e46-809f-4684-abd9-dc8f445b5ca9"`)},
+			expErr:      "operation 0: invalid patch: path \"/UserID\" is immutable",
+			expErrIs:    ErrInvalidPatch,
+		},
+		"remove required field": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			operations:  []PatchOperation{op("remove", "/type", "")},
+			expErr:      "operation 0: invalid patch: path \"/type\" is required and cannot be removed",
+			expErrIs:    ErrInvalidPatch,
+		},
+		"unsupported operation": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			operations:  []PatchOperation{op("move", "/name", "")},
+			expErr:      "operation 0: invalid patch: unsupported operation \"move\"",
+			expErrIs:    ErrInvalidPatch,
+		},
+		"wrong value type": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			operations:  []PatchOperation{op("replace", "/employees_amount", `"forty"`)},
+			expErr:      "operation 0: invalid patch: invalid value for \"/employees_amount\": json: cannot unmarshal string into Go value of type int",
+			expErrIs:    ErrInvalidPatch,
+		},
+		"failed test leaves the company untouched": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			operations: []PatchOperation{
+				op("replace", "/name", `"company2"`),
+				op("test", "/EmployeesAmount", `41`),
+				op("replace", "/Type", `"NonProfit"`),
+			},
+			expErr:   "operation 1: patch test failed: \"/EmployeesAmount\" does not match 41",
+			expErrIs: ErrPatchTestFailed,
+		},
+		"conditional replace": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			operations: []PatchOperation{
+				op("test", "/EmployeesAmount", `40`),
+				op("test", "/ID", `"ca8fc620-509a-40ac-8cc0-525c37c9c4b9"`),
+				op("replace", "/Type", `"NonProfit"`),
+				op("remove", "/description", ""),
+				op("test", "/description", `""`),
+			},
+			expUpdated: true,
+			expCompany: models.Company{
+				ID:              stored.ID,
+				Name:            "company1",
+				EmployeesAmount: 40,
+				Registered:      true,
+				Type:            common.NonProfit,
+			},
+		},
+		"attach and detach": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			operations: []PatchOperation{
+				op("add", "/parent_id", `"0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d02"`),
+				op("test", "/ParentID", `"0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d02"`),
+				op("remove", "/parent_id", ""),
+				op("test", "/ParentID", `null`),
+			},
+			expUpdated: true,
+			expCompany: stored,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			_, err = s.Patch(context.TODO(), uuid.New(), stored.ID, 0, tt.operations, nil)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				if tt.expErrIs != nil {
+					assert.ErrorIs(t, err, tt.expErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expUpdated {
+				assert.Equal(t, tt.expCompany, tt.companyRepo.updated)
+			} else {
+				assert.Equal(t, models.Company{}, tt.companyRepo.updated)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/iNDicat0r/company/common"
@@ -13,70 +16,337 @@ import (
 
 // CompanyGetCreateUpdateDeleter defines the functionality related to company service.
 type CompanyGetCreateUpdateDeleter interface {
-	Get(ctx context.Context, companyID uuid.UUID) (models.Company, error)
+	Get(ctx context.Context, userID, companyID uuid.UUID) (models.Company, error)
+	GetBySlug(ctx context.Context, userID uuid.UUID, slug string) (models.Company, error)
+	List(ctx context.Context, userID uuid.UUID, params ListCompaniesParams) (CompanyPage, error)
 	Create(ctx context.Context, userID uuid.UUID, payload CreateUpdateCompanyPayload) (models.Company, error)
-	Update(ctx context.Context, companyID uuid.UUID, payload CreateUpdateCompanyPayload) (models.Company, error)
-	Delete(ctx context.Context, userID, companyID uuid.UUID) error
+	CreateBatch(ctx context.Context, userID uuid.UUID, payloads []CreateUpdateCompanyPayload, atomic bool) ([]BatchItemResult, error)
+	Update(ctx context.Context, userID, companyID uuid.UUID, version int, payload UpdateCompanyPayload) (models.Company, error)
+	Patch(ctx context.Context, userID, companyID uuid.UUID, version int, operations []PatchOperation, employeesEffectiveOn *time.Time) (models.Company, error)
+	Delete(ctx context.Context, userID, companyID uuid.UUID, version int, children ChildrenPolicy) error
 }
 
-// CreateUpdateCompanyPayload represents the payload for creating or updating a company.
+var (
+	// ErrVersionMismatch is returned when a company is not at the version a change was based on.
+	ErrVersionMismatch = newError(ErrConflict, "version_mismatch", "company version mismatch")
+	// ErrCompanyNotFound is returned when a company does not exist or was deleted.
+	ErrCompanyNotFound = newError(ErrNotFound, "company_not_found", "company not found")
+)
+
+// CreateUpdateCompanyPayload represents the payload for creating a company.
 type CreateUpdateCompanyPayload struct {
 	Name            string
 	Description     string
 	EmployeesAmount int
 	Registered      bool
 	Type            common.Type
+	ParentID        *uuid.UUID
+	Attributes      map[string]json.RawMessage // Values of the custom attributes, required ones included.
+}
+
+// UpdateCompanyPayload represents a partial update of a company, nil fields are left untouched.
+// A nil UUID as ParentID detaches the company from its parent.
+type UpdateCompanyPayload struct {
+	Name            *string
+	Description     *string
+	EmployeesAmount *int
+	Registered      *bool
+	Type            *common.Type
+	ParentID        *uuid.UUID
+	// EmployeesEffectiveOn is the day a changed employees amount applies from, the day of the update when nil.
+	EmployeesEffectiveOn *time.Time
+}
+
+// CompanyFilter represents the criteria companies are filtered by, nil fields are ignored.
+type CompanyFilter struct {
+	Types         []common.Type
+	Registered    *bool
+	MinEmployees  *int
+	MaxEmployees  *int
+	UserID        *uuid.UUID
+	MemberID      *uuid.UUID
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	TagsAll       []string
+	TagsAny       []string
+	TagsNone      []string
+	Attributes    []AttributeCondition
+}
+
+// ListCompaniesParams represents the parameters for listing companies. SortBy is a column or
+// a custom attribute prefixed with attr., e.g. attr.vat_number.
+type ListCompaniesParams struct {
+	Filter     CompanyFilter
+	SortBy     string
+	Descending bool
+	Limit      int
+	Cursor     string
 }
 
+// CompanyPage represents a page of companies and the cursor to fetch the next one.
+type CompanyPage struct {
+	Companies  []models.Company
+	NextCursor string
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ErrInvalidListQuery is returned when the listing parameters cannot be used.
+var ErrInvalidListQuery = newError(ErrInvalidRequest, "invalid_list_query", "invalid list query")
+
 // CompanyService represents the company service.
 type CompanyService struct {
 	companyRepo repositories.CompanyRepository
+	typeRepo    repositories.CompanyTypeRepository
+	attrRepo    repositories.AttributeRepository
+	access      companyAccess
 }
 
-// NewCompanyService creates a new company service.
-func NewCompanyService(companyRepo repositories.CompanyRepository) (*CompanyService, error) {
+// NewCompanyService creates a new company service, company types are checked against the catalogue
+// of typeRepo, changes to a company against its members in memberRepo and listings are filtered
+// and sorted on the custom attributes of attrRepo.
+func NewCompanyService(companyRepo repositories.CompanyRepository, typeRepo repositories.CompanyTypeRepository, memberRepo repositories.MemberRepository, attrRepo repositories.AttributeRepository) (*CompanyService, error) {
 	if companyRepo == nil {
 		return nil, errors.New("company repository is nil")
 	}
 
+	if typeRepo == nil {
+		return nil, errors.New("company type repository is nil")
+	}
+
+	if memberRepo == nil {
+		return nil, errors.New("member repository is nil")
+	}
+
+	if attrRepo == nil {
+		return nil, errors.New("attribute repository is nil")
+	}
+
 	return &CompanyService{
 		companyRepo: companyRepo,
+		typeRepo:    typeRepo,
+		attrRepo:    attrRepo,
+		access:      companyAccess{memberRepo: memberRepo},
 	}, nil
 }
 
-// Get a company.
-func (s *CompanyService) Get(ctx context.Context, companyID uuid.UUID) (models.Company, error) {
+// Get a company the user can view.
+func (s *CompanyService) Get(ctx context.Context, userID, companyID uuid.UUID) (models.Company, error) {
 	comp, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.Company{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
 	if err != nil {
 		return models.Company{}, fmt.Errorf("failed to get company: %w", err)
 	}
+	if err := s.access.authorize(ctx, userID, comp.ID, models.RoleViewer); err != nil {
+		return models.Company{}, err
+	}
 	return comp, nil
 }
 
-// Create a company.
-func (s *CompanyService) Create(ctx context.Context, userID uuid.UUID, payload CreateUpdateCompanyPayload) (models.Company, error) {
-	if payload.Name == "" {
-		return models.Company{}, errors.New("company name is empty")
+// GetBySlug gets a company the user can view by its current slug or by one it had before being
+// renamed, the slug of the returned company tells which one it is.
+func (s *CompanyService) GetBySlug(ctx context.Context, userID uuid.UUID, slug string) (models.Company, error) {
+	comp, err := s.companyRepo.FindBySlug(ctx, slug)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.Company{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, slug)
 	}
+	if err != nil {
+		return models.Company{}, fmt.Errorf("failed to get company: %w", err)
+	}
+	if err := s.access.authorize(ctx, userID, comp.ID, models.RoleViewer); err != nil {
+		return models.Company{}, err
+	}
+	return comp, nil
+}
 
-	if payload.EmployeesAmount == 0 {
-		return models.Company{}, errors.New("company employees amount is empty")
+// List a page of the companies the user is a member of.
+func (s *CompanyService) List(ctx context.Context, userID uuid.UUID, params ListCompaniesParams) (CompanyPage, error) {
+	params.Filter.MemberID = &userID
+	query, err := s.listQuery(ctx, params)
+	if err != nil {
+		return CompanyPage{}, err
 	}
 
-	if payload.Type == "" {
-		return models.Company{}, errors.New("company type is empty")
+	return s.listPage(ctx, query)
+}
+
+// listPage runs a listing query and builds the cursor of the next page.
+func (s *CompanyService) listPage(ctx context.Context, query repositories.CompanyListQuery) (CompanyPage, error) {
+	// fetch one extra row to know whether there is a next page
+	limit := query.Limit
+	query.Limit++
+
+	comps, err := s.companyRepo.List(ctx, query)
+	if err != nil {
+		return CompanyPage{}, fmt.Errorf("failed to list companies: %w", err)
 	}
 
-	b := models.Company{
-		Name:            payload.Name,
-		Description:     payload.Description,
-		EmployeesAmount: payload.EmployeesAmount,
-		Registered:      payload.Registered,
-		Type:            payload.Type,
-		UserID:          userID,
+	page := CompanyPage{Companies: comps}
+	if len(comps) > limit {
+		page.Companies = comps[:limit]
+		last := page.Companies[limit-1]
+		if query.SortAttribute == nil {
+			page.NextCursor = encodeCursor(query.SortBy, query.Descending, last)
+			return page, nil
+		}
+
+		attributes, err := s.attrRepo.ListValues(ctx, last.ID)
+		if err != nil {
+			return CompanyPage{}, fmt.Errorf("failed to list attributes: %w", err)
+		}
+		for _, attribute := range attributes {
+			if attribute.Name == query.SortAttribute.Name {
+				page.NextCursor = encodeAttributeCursor(query.SortBy, query.Descending, last.ID, attribute)
+			}
+		}
+	}
+
+	return page, nil
+}
+
+// listQuery validates the params and converts them into a repository query.
+func (s *CompanyService) listQuery(ctx context.Context, p ListCompaniesParams) (repositories.CompanyListQuery, error) {
+	limit := p.Limit
+	switch {
+	case limit < 0:
+		return repositories.CompanyListQuery{}, fmt.Errorf("%w: negative limit", ErrInvalidListQuery)
+	case limit == 0:
+		limit = defaultPageSize
+	case limit > maxPageSize:
+		limit = maxPageSize
+	}
+
+	query := repositories.CompanyListQuery{
+		SortBy:     repositories.SortByCreatedAt,
+		Descending: p.Descending,
+		Limit:      limit,
+	}
+	if p.SortBy != "" {
+		query.SortBy = repositories.CompanySortField(p.SortBy)
+	}
+
+	sortAttribute, byAttribute := strings.CutPrefix(p.SortBy, attributeSortPrefix)
+	if !byAttribute && !isSortable(query.SortBy) {
+		return repositories.CompanyListQuery{}, fmt.Errorf("%w: unknown sort field %q", ErrInvalidListQuery, p.SortBy)
+	}
+
+	var schemas map[string]models.AttributeSchema
+	if byAttribute || len(p.Filter.Attributes) > 0 {
+		var err error
+		if schemas, err = s.attributeSchemas(ctx); err != nil {
+			return repositories.CompanyListQuery{}, err
+		}
+	}
+
+	if byAttribute {
+		schema, ok := schemas[sortAttribute]
+		if !ok {
+			return repositories.CompanyListQuery{}, fmt.Errorf("%w: unknown sort attribute %q", ErrInvalidListQuery, sortAttribute)
+		}
+		query.SortAttribute = &repositories.AttributeRef{Name: schema.Name, Type: schema.Type}
+	}
+
+	filter, err := repositoryFilter(schemas, p.Filter)
+	if err != nil {
+		return repositories.CompanyListQuery{}, err
+	}
+	query.Filter = filter
+
+	if p.Cursor != "" {
+		var cursor repositories.CompanyCursor
+		if query.SortAttribute != nil {
+			cursor, err = decodeAttributeCursor(p.Cursor, query.SortBy, p.Descending, query.SortAttribute.Type)
+		} else {
+			cursor, err = decodeCursor(p.Cursor, query.SortBy, p.Descending)
+		}
+		if err != nil {
+			return repositories.CompanyListQuery{}, fmt.Errorf("%w: %v", ErrInvalidListQuery, err)
+		}
+		query.After = &cursor
+	}
+
+	return query, nil
+}
+
+// attributeSchemas returns the attribute schemas by name.
+func (s *CompanyService) attributeSchemas(ctx context.Context) (map[string]models.AttributeSchema, error) {
+	schemas, err := s.attrRepo.ListSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attribute schemas: %w", err)
+	}
+
+	byName := make(map[string]models.AttributeSchema, len(schemas))
+	for _, schema := range schemas {
+		byName[schema.Name] = schema
+	}
+
+	return byName, nil
+}
+
+// repositoryFilter converts the filter into a repository filter, attribute conditions are
+// checked against the schemas.
+func repositoryFilter(schemas map[string]models.AttributeSchema, f CompanyFilter) (repositories.CompanyFilter, error) {
+	filter := repositories.CompanyFilter{
+		Types:         f.Types,
+		Registered:    f.Registered,
+		MinEmployees:  f.MinEmployees,
+		MaxEmployees:  f.MaxEmployees,
+		UserID:        f.UserID,
+		MemberID:      f.MemberID,
+		CreatedAfter:  f.CreatedAfter,
+		CreatedBefore: f.CreatedBefore,
+		UpdatedAfter:  f.UpdatedAfter,
+		UpdatedBefore: f.UpdatedBefore,
+		TagsAll:       f.TagsAll,
+		TagsAny:       f.TagsAny,
+		TagsNone:      f.TagsNone,
+	}
+	if len(f.Attributes) > 0 {
+		conditions, err := attributeConditions(schemas, f.Attributes)
+		if err != nil {
+			return repositories.CompanyFilter{}, err
+		}
+		filter.Attributes = conditions
+	}
+
+	return filter, nil
+}
+
+// Create a company along with the values of its custom attributes.
+func (s *CompanyService) Create(ctx context.Context, userID uuid.UUID, payload CreateUpdateCompanyPayload) (models.Company, error) {
+	types, err := s.availableTypes(ctx, "")
+	if err != nil {
+		return models.Company{}, err
+	}
+
+	schemas, err := s.attrRepo.ListSchemas(ctx)
+	if err != nil {
+		return models.Company{}, fmt.Errorf("failed to list attribute schemas: %w", err)
 	}
 
-	id, err := s.companyRepo.Save(ctx, b)
+	company := payload.company(userID)
+	if err := validateCompany(company, types); err != nil {
+		return models.Company{}, err
+	}
+	company.Attributes, err = newCompanyAttributes(schemas, userID, payload.Attributes)
+	if err != nil {
+		return models.Company{}, err
+	}
+	if err := s.checkParent(ctx, userID, nil, company); err != nil {
+		return models.Company{}, err
+	}
+
+	id, err := s.companyRepo.Save(ctx, company)
+	if errors.Is(err, repositories.ErrNameTaken) {
+		return models.Company{}, fmt.Errorf("%w: another company is already named %q, ignoring case", ErrNameTaken, payload.Name)
+	}
 	if err != nil {
 		return models.Company{}, fmt.Errorf("failed to save company: %w", err)
 	}
@@ -86,26 +356,94 @@ func (s *CompanyService) Create(ctx context.Context, userID uuid.UUID, payload C
 	return retrievedCompany, nil
 }
 
-// Update a company.
-func (s *CompanyService) Update(ctx context.Context, companyID uuid.UUID, payload CreateUpdateCompanyPayload) (models.Company, error) {
+// company builds the company of the user described by the payload.
+func (p CreateUpdateCompanyPayload) company(userID uuid.UUID) models.Company {
+	return models.Company{
+		Name:            p.Name,
+		Description:     p.Description,
+		EmployeesAmount: p.EmployeesAmount,
+		Registered:      p.Registered,
+		Type:            p.Type,
+		UserID:          userID,
+		ParentID:        p.ParentID,
+	}
+}
+
+// Update a company on behalf of an editor of it, a non zero version must match the current version of the company.
+func (s *CompanyService) Update(ctx context.Context, userID, companyID uuid.UUID, version int, payload UpdateCompanyPayload) (models.Company, error) {
+	company, err := s.findVersion(ctx, userID, companyID, version, models.RoleEditor)
+	if err != nil {
+		return models.Company{}, err
+	}
+
+	types, err := s.availableTypes(ctx, company.Type)
+	if err != nil {
+		return models.Company{}, err
+	}
+
+	parentID := company.ParentID
+	payload.apply(&company)
+	if err := validateCompany(company, types); err != nil {
+		return models.Company{}, err
+	}
+	if err := s.checkParent(ctx, userID, parentID, company); err != nil {
+		return models.Company{}, err
+	}
+
+	return s.update(ctx, userID, company, payload.EmployeesEffectiveOn)
+}
+
+// findVersion returns a company the user has the needed role on making sure it is at the given
+// version, zero accepts any version.
+func (s *CompanyService) findVersion(ctx context.Context, userID, companyID uuid.UUID, version int, need models.MemberRole) (models.Company, error) {
 	company, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.Company{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
 	if err != nil {
 		return models.Company{}, fmt.Errorf("failed to find company: %w", err)
 	}
 
-	if payload.Name != "" {
-		company.Name = payload.Name
+	if err := s.access.authorize(ctx, userID, companyID, need); err != nil {
+		return models.Company{}, err
 	}
 
-	if payload.EmployeesAmount != 0 {
-		company.EmployeesAmount = payload.EmployeesAmount
+	if version != 0 && company.Version != version {
+		return models.Company{}, fmt.Errorf("%w: expected %d, current %d", ErrVersionMismatch, version, company.Version)
 	}
 
-	if payload.Type == "" {
-		company.EmployeesAmount = payload.EmployeesAmount
+	return company, nil
+}
+
+// update stores a company read by findVersion unless it was changed in the meantime, a changed
+// employees amount applies from employeesOn when it is set.
+func (s *CompanyService) update(ctx context.Context, userID uuid.UUID, company models.Company, employeesOn *time.Time) (models.Company, error) {
+	var effectiveOn time.Time
+	if employeesOn != nil {
+		if models.Day(*employeesOn).After(models.Day(time.Now())) {
+			return models.Company{}, newValidationError(ErrInvalidCompany, []Violation{
+				{Field: "employees_effective_on", Message: "must not be in the future"},
+			})
+		}
+		effectiveOn = *employeesOn
 	}
 
-	updated, err := s.companyRepo.Update(ctx, company)
+	updated, err := s.companyRepo.Update(ctx, userID, company, effectiveOn)
+	if errors.Is(err, repositories.ErrEmployeeCountOutOfOrder) {
+		return models.Company{}, newValidationError(ErrInvalidCompany, []Violation{
+			{Field: "employees_effective_on", Message: "must not be before the day of the latest employee count"},
+		})
+	}
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		return models.Company{}, fmt.Errorf("%w: company was modified concurrently", ErrVersionMismatch)
+	}
+	if errors.Is(err, repositories.ErrNameTaken) {
+		return models.Company{}, fmt.Errorf("%w: another company is already named %q, ignoring case", ErrNameTaken, company.Name)
+	}
+	// the group changed since checkParent looked at it
+	if errors.Is(err, repositories.ErrParentCycle) {
+		return models.Company{}, parentViolation("must not be a subsidiary of the company")
+	}
 	if err != nil {
 		return models.Company{}, fmt.Errorf("failed to update company: %w", err)
 	}
@@ -113,9 +451,62 @@ func (s *CompanyService) Update(ctx context.Context, companyID uuid.UUID, payloa
 	return updated, nil
 }
 
-// Delete a company.
-func (s *CompanyService) Delete(ctx context.Context, userID, companyID uuid.UUID) error {
-	err := s.companyRepo.Delete(ctx, userID, companyID)
+// apply copies the set fields of the payload into the company.
+func (p UpdateCompanyPayload) apply(company *models.Company) {
+	if p.Name != nil {
+		company.Name = *p.Name
+	}
+
+	if p.Description != nil {
+		company.Description = *p.Description
+	}
+
+	if p.EmployeesAmount != nil {
+		company.EmployeesAmount = *p.EmployeesAmount
+	}
+
+	if p.Registered != nil {
+		company.Registered = *p.Registered
+	}
+
+	if p.Type != nil {
+		company.Type = *p.Type
+	}
+
+	if p.ParentID != nil {
+		company.ParentID = nil
+		if *p.ParentID != uuid.Nil {
+			parentID := *p.ParentID
+			company.ParentID = &parentID
+		}
+	}
+}
+
+// Delete a company on behalf of an owner of it, a non zero version must match the current version of the company.
+// The policy tells what happens to its subsidiaries, an empty one restricts the deletion to companies without any.
+func (s *CompanyService) Delete(ctx context.Context, userID, companyID uuid.UUID, version int, children ChildrenPolicy) error {
+	switch children {
+	case "":
+		children = ChildrenRestrict
+	case ChildrenRestrict, ChildrenPromote:
+	default:
+		return fmt.Errorf("%w: %q, must be %s or %s", ErrInvalidChildrenPolicy, children, ChildrenRestrict, ChildrenPromote)
+	}
+
+	if _, err := s.findVersion(ctx, userID, companyID, 0, models.RoleOwner); err != nil {
+		return err
+	}
+
+	err := s.companyRepo.Delete(ctx, userID, companyID, version, repositories.ChildrenPolicy(children))
+	if errors.Is(err, repositories.ErrHasChildren) {
+		return fmt.Errorf("%w: delete them first or promote them to the parent company", ErrHasSubsidiaries)
+	}
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		return fmt.Errorf("%w: company was modified concurrently or is not at version %d", ErrVersionMismatch, version)
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete company: %w", err)
 	}
@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
 	"github.com/iNDicat0r/company/internal/app/models"
 	"github.com/iNDicat0r/company/internal/app/repositories"
 	"github.com/stretchr/testify/assert"
@@ -15,13 +18,40 @@ func TestNewCompanyService(t *testing.T) {
 	t.Parallel()
 	cases := map[string]struct {
 		companyRepo repositories.CompanyRepository
+		typeRepo    repositories.CompanyTypeRepository
+		memberRepo  repositories.MemberRepository
+		attrRepo    repositories.AttributeRepository
 		expErr      string
 	}{
 		"company repo is nil": {
-			expErr: "company repository is nil",
+			typeRepo:   &mockCompanyTypeRepository{},
+			memberRepo: &mockMemberRepository{},
+			attrRepo:   &mockAttributeRepository{},
+			expErr:     "company repository is nil",
+		},
+		"company type repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+			attrRepo:    &mockAttributeRepository{},
+			expErr:      "company type repository is nil",
+		},
+		"member repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			typeRepo:    &mockCompanyTypeRepository{},
+			attrRepo:    &mockAttributeRepository{},
+			expErr:      "member repository is nil",
+		},
+		"attribute repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			typeRepo:    &mockCompanyTypeRepository{},
+			memberRepo:  &mockMemberRepository{},
+			expErr:      "attribute repository is nil",
 		},
 		"success": {
 			companyRepo: &mockCompanyRepository{},
+			typeRepo:    &mockCompanyTypeRepository{},
+			memberRepo:  &mockMemberRepository{},
+			attrRepo:    &mockAttributeRepository{},
 		},
 	}
 
@@ -29,7 +59,7 @@ func TestNewCompanyService(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			s, err := NewCompanyService(tt.companyRepo)
+			s, err := NewCompanyService(tt.companyRepo, tt.typeRepo, tt.memberRepo, tt.attrRepo)
 			if tt.expErr != "" {
 				assert.EqualError(t, err, tt.expErr)
 				assert.Nil(t, s)
@@ -44,6 +74,7 @@ func TestCompanyService_GetCompany(t *testing.T) {
 	t.Parallel()
 	cases := map[string]struct {
 		companyRepo repositories.CompanyRepository
+		memberRepo  repositories.MemberRepository
 		companyID   uuid.UUID
 		expErr      string
 	}{
@@ -54,6 +85,18 @@ func TestCompanyService_GetCompany(t *testing.T) {
 
 			expErr: "failed to find company 0: company repo error",
 		},
+		"company not found": {
+			companyRepo: &mockCompanyRepository{
+				err: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound),
+			},
+
+			expErr: "company not found: 00000000-0000-0000-0000-000000000000",
+		},
+		"not a member": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{err: fmt.Errorf("failed to find member: %w", repositories.ErrNotFound)},
+			expErr:      "access to the company denied: the viewer role is required",
+		},
 		"success": {
 			companyRepo: &mockCompanyRepository{
 				singleCompany: models.Company{
@@ -67,9 +110,13 @@ func TestCompanyService_GetCompany(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			s, err := NewCompanyService(tt.companyRepo)
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, memberRepo, &mockAttributeRepository{})
 			assert.NoError(t, err)
-			comp, err := s.Get(context.TODO(), tt.companyID)
+			comp, err := s.Get(context.TODO(), uuid.New(), tt.companyID)
 			if tt.expErr != "" {
 				assert.EqualError(t, err, tt.expErr)
 			} else {
@@ -79,25 +126,422 @@ func TestCompanyService_GetCompany(t *testing.T) {
 	}
 }
 
+func TestCompanyService_GetBySlug(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyRepo repositories.CompanyRepository
+		memberRepo  repositories.MemberRepository
+		expErr      string
+		expSlug     string
+	}{
+		"company repo error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			expErr:      "failed to get company: company repo error",
+		},
+		"company not found": {
+			companyRepo: &mockCompanyRepository{err: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound)},
+			expErr:      "company not found: acme",
+		},
+		"not a member": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{Name: "Acme", Slug: "acme"}},
+			memberRepo:  &mockMemberRepository{err: fmt.Errorf("failed to find member: %w", repositories.ErrNotFound)},
+			expErr:      "access to the company denied: the viewer role is required",
+		},
+		"renamed company": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{Name: "Globex", Slug: "globex"}},
+			expSlug:     "globex",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, memberRepo, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			comp, err := s.GetBySlug(context.TODO(), uuid.New(), "acme")
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expSlug, comp.Slug)
+			}
+		})
+	}
+}
+
+func TestCompanyService_Update(t *testing.T) {
+	t.Parallel()
+	stored := models.Company{
+		Version:         1,
+		Name:            "company1",
+		Description:     "description 1",
+		EmployeesAmount: 40,
+		Registered:      true,
+		Type:            common.Corporations,
+	}
+	name, empty, zero, registered, companyType := "company2", "", 0, false, common.Cooperative
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		version     int
+		payload     UpdateCompanyPayload
+		expCompany  models.Company
+		expErr      string
+	}{
+		"company repo find error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			expErr:      "failed to find company: company repo error",
+		},
+		"version mismatch": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			version:     2,
+			payload:     UpdateCompanyPayload{Name: &name},
+			expErr:      "company version mismatch: expected 2, current 1",
+		},
+		"modified concurrently": {
+			companyRepo: &mockCompanyRepository{
+				singleCompany: stored,
+				updateErr:     fmt.Errorf("failed to update company: %w", repositories.ErrVersionConflict),
+			},
+			version: 1,
+			payload: UpdateCompanyPayload{Name: &name},
+			expErr:  "company version mismatch: company was modified concurrently",
+		},
+		"empty name": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			payload:     UpdateCompanyPayload{Name: &empty},
+			expErr:      "invalid company: name must not be empty",
+		},
+		"zero employees": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			payload:     UpdateCompanyPayload{EmployeesAmount: &zero},
+			expErr:      "invalid company: employees_amount must not be empty",
+		},
+		"nothing changes": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			expCompany:  stored,
+		},
+		"every field changes": {
+			companyRepo: &mockCompanyRepository{singleCompany: stored},
+			payload: UpdateCompanyPayload{
+				Name:        &name,
+				Description: &empty,
+				Registered:  &registered,
+				Type:        &companyType,
+			},
+			expCompany: models.Company{
+				Version:         1,
+				Name:            "company2",
+				EmployeesAmount: 40,
+				Type:            common.Cooperative,
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			_, err = s.Update(context.TODO(), uuid.New(), uuid.New(), tt.version, tt.payload)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expCompany, tt.companyRepo.updated)
+			}
+		})
+	}
+}
+
+func TestCompanyService_Delete(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		memberRepo  *mockMemberRepository
+		children    ChildrenPolicy
+		expPolicy   repositories.ChildrenPolicy
+		expErr      string
+	}{
+		"company repo find error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			expErr:      "failed to find company: company repo error",
+		},
+		"company repo error": {
+			companyRepo: &mockCompanyRepository{deleteErr: errors.New("company repo error")},
+			expErr:      "failed to delete company: company repo error",
+		},
+		"version mismatch": {
+			companyRepo: &mockCompanyRepository{deleteErr: fmt.Errorf("failed to delete a company: %w", repositories.ErrVersionConflict)},
+			expErr:      "company version mismatch: company was modified concurrently or is not at version 3",
+		},
+		"editors cannot delete": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{role: models.RoleEditor},
+			expErr:      "access to the company denied: the owner role is required, you are editor",
+		},
+		"not a member": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{err: fmt.Errorf("failed to find member: %w", repositories.ErrNotFound)},
+			expErr:      "access to the company denied: the owner role is required",
+		},
+		"unknown children policy": {
+			companyRepo: &mockCompanyRepository{},
+			children:    "cascade",
+			expErr:      "invalid children policy: \"cascade\", must be restrict or promote",
+		},
+		"has subsidiaries": {
+			companyRepo: &mockCompanyRepository{deleteErr: fmt.Errorf("failed to delete a company: %w", repositories.ErrHasChildren)},
+			expErr:      "company has subsidiaries: delete them first or promote them to the parent company",
+		},
+		"success": {
+			companyRepo: &mockCompanyRepository{},
+			expPolicy:   repositories.ChildrenRestrict,
+		},
+		"promote subsidiaries": {
+			companyRepo: &mockCompanyRepository{},
+			children:    ChildrenPromote,
+			expPolicy:   repositories.ChildrenPromote,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, memberRepo, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			err = s.Delete(context.TODO(), uuid.New(), uuid.New(), 3, tt.children)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expPolicy, tt.companyRepo.deletePolicy)
+			}
+		})
+	}
+}
+
+func TestCompanyService_List(t *testing.T) {
+	t.Parallel()
+	companies := []models.Company{
+		{ID: uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d01"), Name: "company 1", EmployeesAmount: 10},
+		{ID: uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d02"), Name: "company 2", EmployeesAmount: 20},
+		{ID: uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d03"), Name: "company 3", EmployeesAmount: 30},
+	}
+	cases := map[string]struct {
+		companyRepo   repositories.CompanyRepository
+		params        ListCompaniesParams
+		expCompanies  int
+		expNextCursor bool
+		expErr        string
+	}{
+		"unknown sort field": {
+			companyRepo: &mockCompanyRepository{},
+			params:      ListCompaniesParams{SortBy: "description"},
+			expErr:      "invalid list query: unknown sort field \"description\"",
+		},
+		"negative limit": {
+			companyRepo: &mockCompanyRepository{},
+			params:      ListCompaniesParams{Limit: -1},
+			expErr:      "invalid list query: negative limit",
+		},
+		"malformed cursor": {
+			companyRepo: &mockCompanyRepository{},
+			params:      ListCompaniesParams{Cursor: "%%%"},
+			expErr:      "invalid list query: malformed cursor",
+		},
+		"cursor for another sort order": {
+			companyRepo: &mockCompanyRepository{},
+			params: ListCompaniesParams{
+				SortBy: "name",
+				Cursor: encodeCursor(repositories.SortByEmployeesAmount, false, companies[0]),
+			},
+			expErr: "invalid list query: cursor was issued for a different sort order",
+		},
+		"company repo error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			expErr:      "failed to list companies: company repo error",
+		},
+		"last page": {
+			companyRepo:  &mockCompanyRepository{companies: companies},
+			params:       ListCompaniesParams{Limit: 3},
+			expCompanies: 3,
+		},
+		"has next page": {
+			companyRepo:   &mockCompanyRepository{companies: companies},
+			params:        ListCompaniesParams{Limit: 2, SortBy: "employees_amount"},
+			expCompanies:  2,
+			expNextCursor: true,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			userID := uuid.New()
+			page, err := s.List(context.TODO(), userID, tt.params)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, page.Companies, tt.expCompanies)
+				assert.Equal(t, tt.expNextCursor, page.NextCursor != "")
+				assert.Equal(t, &userID, tt.companyRepo.(*mockCompanyRepository).listQuery.Filter.MemberID, "only the companies of the user are listed")
+			}
+		})
+	}
+}
+
+func TestCompanyCursor_RoundTrip(t *testing.T) {
+	t.Parallel()
+	last := models.Company{
+		ID:              uuid.MustParse("0b6ae2e3-9b5f-4a44-8f50-5a1e8a8e2d01"),
+		CreatedAt:       time.Date(2023, 10, 1, 12, 30, 0, 123000000, time.UTC),
+		Name:            "company 1",
+		EmployeesAmount: 42,
+	}
+	cases := map[repositories.CompanySortField]any{
+		repositories.SortByCreatedAt:       last.CreatedAt,
+		repositories.SortByName:            last.Name,
+		repositories.SortByEmployeesAmount: last.EmployeesAmount,
+	}
+
+	for sortBy, expValue := range cases {
+		sortBy, expValue := sortBy, expValue
+		t.Run(string(sortBy), func(t *testing.T) {
+			t.Parallel()
+			cursor, err := decodeCursor(encodeCursor(sortBy, true, last), sortBy, true)
+			assert.NoError(t, err)
+			assert.Equal(t, last.ID, cursor.ID)
+			assert.Equal(t, expValue, cursor.SortValue)
+		})
+	}
+}
+
 // mockCompanyRepository for testing
 type mockCompanyRepository struct {
 	err           error
 	singleCompany models.Company
+	companies     []models.Company
+	updated       models.Company
+	updateErr     error
+	deleteErr     error
+	deletePolicy  repositories.ChildrenPolicy
+	ancestors     []models.Company
+	subtree       []repositories.CompanyNode
+	hierarchyErr  error
+	listQuery     repositories.CompanyListQuery
 	id            uuid.UUID
+	batchErrs     []error
+	batch         []models.Company
+	saved         models.Company
+	transition    models.CompanyTransition
+	transitions   []models.CompanyTransition
+	employeesOn   time.Time
 }
 
 func (m *mockCompanyRepository) FindByID(_ context.Context, _ uuid.UUID) (models.Company, error) {
 	return m.singleCompany, m.err
 }
 
-func (m *mockCompanyRepository) Delete(_ context.Context, _, _ uuid.UUID) error {
+func (m *mockCompanyRepository) FindBySlug(_ context.Context, _ string) (models.Company, error) {
+	return m.singleCompany, m.err
+}
+
+func (m *mockCompanyRepository) List(_ context.Context, query repositories.CompanyListQuery) ([]models.Company, error) {
+	m.listQuery = query
+	if len(m.companies) > query.Limit {
+		return m.companies[:query.Limit], m.err
+	}
+	return m.companies, m.err
+}
+
+func (m *mockCompanyRepository) Export(_ context.Context, filter repositories.CompanyFilter, chunkSize int, fn func([]models.Company) error) error {
+	m.listQuery.Filter = filter
+	for start := 0; start < len(m.companies); start += chunkSize {
+		end := start + chunkSize
+		if end > len(m.companies) {
+			end = len(m.companies)
+		}
+		if err := fn(m.companies[start:end]); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+func (m *mockCompanyRepository) Restore(_ context.Context, _, _ uuid.UUID) (models.Company, error) {
+	return m.singleCompany, m.err
+}
+
+func (m *mockCompanyRepository) Purge(_ context.Context, _ uuid.UUID) error {
 	return m.err
 }
 
-func (m *mockCompanyRepository) Save(_ context.Context, _ models.Company) (uuid.UUID, error) {
+func (m *mockCompanyRepository) Transition(_ context.Context, version int, transition models.CompanyTransition) (models.CompanyTransition, models.Company, error) {
+	m.transition = transition
+	transition.Version = version + 1
+	comp := m.singleCompany
+	comp.Status, comp.Registered, comp.Version = transition.To, transition.To.Registered(), version+1
+	return transition, comp, m.updateErr
+}
+
+func (m *mockCompanyRepository) ListTransitions(_ context.Context, _ uuid.UUID) ([]models.CompanyTransition, error) {
+	return m.transitions, m.err
+}
+
+func (m *mockCompanyRepository) Ancestors(_ context.Context, _ uuid.UUID, _ int) ([]models.Company, error) {
+	return m.ancestors, m.hierarchyErr
+}
+
+func (m *mockCompanyRepository) Children(_ context.Context, _ uuid.UUID) ([]models.Company, error) {
+	return m.companies, m.hierarchyErr
+}
+
+func (m *mockCompanyRepository) Subtree(_ context.Context, id uuid.UUID, _ int) ([]repositories.CompanyNode, error) {
+	if m.subtree == nil {
+		return []repositories.CompanyNode{{Company: models.Company{ID: id}}}, m.hierarchyErr
+	}
+	return m.subtree, m.hierarchyErr
+}
+
+func (m *mockCompanyRepository) Delete(_ context.Context, _, _ uuid.UUID, _ int, children repositories.ChildrenPolicy) error {
+	m.deletePolicy = children
+	return m.deleteErr
+}
+
+func (m *mockCompanyRepository) Save(_ context.Context, company models.Company) (uuid.UUID, error) {
+	m.saved = company
 	return m.id, m.err
 }
 
-func (m *mockCompanyRepository) Update(_ context.Context, _ models.Company) (models.Company, error) {
+func (m *mockCompanyRepository) SaveBatch(_ context.Context, companies []models.Company, _ bool) ([]error, error) {
+	m.batch = companies
+	errs := m.batchErrs
+	if errs == nil {
+		errs = make([]error, len(companies))
+	}
+	return errs, m.err
+}
+
+func (m *mockCompanyRepository) Update(_ context.Context, _ uuid.UUID, company models.Company, employeesOn time.Time) (models.Company, error) {
+	m.updated = company
+	m.employeesOn = employeesOn
+	if m.updateErr != nil {
+		return models.Company{}, m.updateErr
+	}
 	return m.singleCompany, m.err
 }
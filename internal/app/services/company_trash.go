@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/blobstore"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// ErrNameTaken is returned when restoring a company whose name is now used by another company.
+var ErrNameTaken = newError(ErrConflict, "company_name_taken", "company name is taken")
+
+// CompanyTrash defines the functionality related to deleted companies.
+type CompanyTrash interface {
+	ListDeleted(ctx context.Context, userID uuid.UUID, params ListCompaniesParams) (CompanyPage, error)
+	Restore(ctx context.Context, userID, companyID uuid.UUID) (models.Company, error)
+}
+
+// CompanyPurge defines the functionality of permanently removing deleted companies.
+type CompanyPurge interface {
+	Purge(ctx context.Context, companyID uuid.UUID) error
+}
+
+// ListDeleted lists a page of the deleted companies the user is a member of.
+func (s *CompanyService) ListDeleted(ctx context.Context, userID uuid.UUID, params ListCompaniesParams) (CompanyPage, error) {
+	params.Filter.MemberID = &userID
+	query, err := s.listQuery(ctx, params)
+	if err != nil {
+		return CompanyPage{}, err
+	}
+	query.Deleted = true
+
+	return s.listPage(ctx, query)
+}
+
+// Restore a deleted company, the user must be one of its owners. Deleted companies release
+// their name, so a company cannot be restored while another company uses its name.
+func (s *CompanyService) Restore(ctx context.Context, userID, companyID uuid.UUID) (models.Company, error) {
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleOwner); err != nil {
+		return models.Company{}, err
+	}
+
+	comp, err := s.companyRepo.Restore(ctx, userID, companyID)
+	if errors.Is(err, repositories.ErrNameTaken) {
+		return models.Company{}, fmt.Errorf("%w: rename the company using it before restoring this one", ErrNameTaken)
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.Company{}, fmt.Errorf("%w: no deleted company %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return models.Company{}, fmt.Errorf("failed to restore company: %w", err)
+	}
+
+	return comp, nil
+}
+
+// CompanyPurger permanently removes deleted companies along with the content of their attachments,
+// which lives outside the database.
+type CompanyPurger struct {
+	companyRepo    repositories.CompanyRepository
+	attachmentRepo repositories.AttachmentRepository
+	store          blobstore.Store
+}
+
+// NewCompanyPurger creates a new company purger.
+func NewCompanyPurger(companyRepo repositories.CompanyRepository, attachmentRepo repositories.AttachmentRepository, store blobstore.Store) (*CompanyPurger, error) {
+	if companyRepo == nil {
+		return nil, errors.New("company repository is nil")
+	}
+
+	if attachmentRepo == nil {
+		return nil, errors.New("attachment repository is nil")
+	}
+
+	if store == nil {
+		return nil, errors.New("blob store is nil")
+	}
+
+	return &CompanyPurger{
+		companyRepo:    companyRepo,
+		attachmentRepo: attachmentRepo,
+		store:          store,
+	}, nil
+}
+
+// Purge permanently removes a deleted company. The content of its attachments is removed once the
+// company is gone, deleted companies cannot get new attachments in the meantime. Content that
+// cannot be removed does not fail the purge.
+func (p *CompanyPurger) Purge(ctx context.Context, companyID uuid.UUID) error {
+	attachments, err := p.attachmentRepo.List(ctx, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	err = p.companyRepo.Purge(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: no deleted company %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to purge company: %w", err)
+	}
+
+	// the company is gone for good, failures leave orphaned blobs behind and are ignored
+	for _, attachment := range attachments {
+		_ = p.store.Delete(ctx, attachmentKey(attachment))
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/blobstore"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCompanyService_ListDeleted(t *testing.T) {
+	t.Parallel()
+	userID := uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9")
+	otherID := uuid.New()
+	repo := &mockCompanyRepository{companies: []models.Company{{Name: "company1"}}}
+
+	s, err := NewCompanyService(repo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+	assert.NoError(t, err)
+
+	page, err := s.ListDeleted(context.TODO(), userID, ListCompaniesParams{Filter: CompanyFilter{UserID: &otherID}})
+	assert.NoError(t, err)
+	assert.Len(t, page.Companies, 1)
+	assert.True(t, repo.listQuery.Deleted)
+	assert.Equal(t, &userID, repo.listQuery.Filter.MemberID, "only the companies the user is a member of are listed")
+	assert.Equal(t, &otherID, repo.listQuery.Filter.UserID, "the owner of record is an ordinary filter")
+
+	_, err = s.ListDeleted(context.TODO(), userID, ListCompaniesParams{SortBy: "description"})
+	assert.ErrorIs(t, err, ErrInvalidListQuery)
+}
+
+func TestCompanyService_Restore(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		memberRepo  *mockMemberRepository
+		expErr      string
+		expErrIs    error
+	}{
+		"not an owner": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{Name: "company1"}},
+			memberRepo:  &mockMemberRepository{role: models.RoleEditor},
+			expErr:      "access to the company denied: the owner role is required, you are editor",
+			expErrIs:    ErrAccessDenied,
+		},
+		"not a member": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{Name: "company1"}},
+			memberRepo:  &mockMemberRepository{err: fmt.Errorf("failed to find member: %w", repositories.ErrNotFound)},
+			expErr:      "access to the company denied: the owner role is required",
+			expErrIs:    ErrAccessDenied,
+		},
+		"name taken": {
+			companyRepo: &mockCompanyRepository{err: fmt.Errorf("failed to restore company: %w", repositories.ErrNameTaken)},
+			expErr:      "company name is taken: rename the company using it before restoring this one",
+			expErrIs:    ErrNameTaken,
+		},
+		"company repo error": {
+			companyRepo: &mockCompanyRepository{err: errors.New("company repo error")},
+			expErr:      "failed to restore company: company repo error",
+		},
+		"success": {
+			companyRepo: &mockCompanyRepository{singleCompany: models.Company{Name: "company1"}},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewCompanyService(tt.companyRepo, &mockCompanyTypeRepository{}, memberRepo, &mockAttributeRepository{})
+			assert.NoError(t, err)
+			comp, err := s.Restore(context.TODO(), uuid.New(), uuid.New())
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				if tt.expErrIs != nil {
+					assert.ErrorIs(t, err, tt.expErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "company1", comp.Name)
+			}
+		})
+	}
+}
+
+func TestNewCompanyPurger(t *testing.T) {
+	t.Parallel()
+	store, _ := blobstore.NewLocalStore(t.TempDir())
+	p, err := NewCompanyPurger(nil, &mockAttachmentRepository{}, store)
+	assert.EqualError(t, err, "company repository is nil")
+	assert.Nil(t, p)
+
+	p, err = NewCompanyPurger(&mockCompanyRepository{}, nil, store)
+	assert.EqualError(t, err, "attachment repository is nil")
+	assert.Nil(t, p)
+
+	p, err = NewCompanyPurger(&mockCompanyRepository{}, &mockAttachmentRepository{}, nil)
+	assert.EqualError(t, err, "blob store is nil")
+	assert.Nil(t, p)
+}
+
+func TestCompanyPurger_Purge(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		expErr      string
+		expFiles    int
+	}{
+		"not in the trash": {
+			companyRepo: &mockCompanyRepository{err: gorm.ErrRecordNotFound},
+			expErr:      "failed to purge company: record not found",
+			expFiles:    1,
+		},
+		"success": {
+			companyRepo: &mockCompanyRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			store, err := blobstore.NewLocalStore(root)
+			assert.NoError(t, err)
+			attachment := models.CompanyAttachment{ID: uuid.New(), CompanyID: uuid.New()}
+			assert.NoError(t, store.Put(context.TODO(), attachmentKey(attachment), strings.NewReader(testPNG)))
+			assert.Equal(t, 1, countFiles(t, root))
+
+			p, err := NewCompanyPurger(tt.companyRepo, &mockAttachmentRepository{saved: attachment}, store)
+			assert.NoError(t, err)
+			err = p.Purge(context.TODO(), attachment.CompanyID)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expFiles, countFiles(t, root), "the content of attachments goes with the company")
+		})
+	}
+}
+
+func TestCompanyPurger_Purge_ContentNotDeleted(t *testing.T) {
+	t.Parallel()
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	attachment := models.CompanyAttachment{ID: uuid.New(), CompanyID: uuid.New()}
+	p, err := NewCompanyPurger(&mockCompanyRepository{}, &mockAttachmentRepository{saved: attachment}, undeletableStore{store})
+	assert.NoError(t, err)
+	assert.NoError(t, p.Purge(context.TODO(), attachment.CompanyID), "the company is purged even if its content stays")
+}
+
+// undeletableStore is a blob store that fails to delete anything.
+type undeletableStore struct {
+	blobstore.Store
+}
+
+func (undeletableStore) Delete(_ context.Context, _ string) error {
+	return errors.New("blob store error")
+}
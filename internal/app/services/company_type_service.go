@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// CompanyTypes defines the functionality related to the company type catalogue.
+type CompanyTypes interface {
+	ListTypes(ctx context.Context, includeDeprecated bool) ([]models.CompanyType, error)
+	GetType(ctx context.Context, name common.Type) (models.CompanyType, error)
+	CreateType(ctx context.Context, name common.Type, payload CompanyTypePayload) (models.CompanyType, error)
+	UpdateType(ctx context.Context, name common.Type, payload CompanyTypePayload) (models.CompanyType, error)
+	DeleteType(ctx context.Context, name common.Type) error
+}
+
+var (
+	// ErrCompanyTypeNotFound is returned when a company type is not in the catalogue.
+	ErrCompanyTypeNotFound = newError(ErrNotFound, "company_type_not_found", "company type not found")
+	// ErrCompanyTypeExists is returned when creating a company type already in the catalogue.
+	ErrCompanyTypeExists = newError(ErrConflict, "company_type_exists", "company type already exists")
+	// ErrCompanyTypeInUse is returned when deleting a company type some companies use.
+	ErrCompanyTypeInUse = newError(ErrConflict, "company_type_in_use", "company type is in use")
+	// ErrInvalidCompanyType is returned when a company type breaks one or more validation rules.
+	ErrInvalidCompanyType = newError(ErrValidation, "invalid_company_type", "invalid company type")
+)
+
+// limits of the company type fields, they match the size of the database columns.
+const (
+	maxTypeNameLength        = 32
+	maxTypeDescriptionLength = 255
+)
+
+// DefaultCompanyTypes are the company types the catalogue starts with.
+var DefaultCompanyTypes = []models.CompanyType{
+	{Name: common.Corporations, Description: "Company owned by its shareholders"},
+	{Name: common.NonProfit, Description: "Organisation using its revenue to pursue its mission"},
+	{Name: common.Cooperative, Description: "Company owned and run by its members"},
+	{Name: common.SoleProprietorship, Description: "Company owned and run by a single person"},
+}
+
+// CompanyTypePayload represents the editable fields of a company type.
+type CompanyTypePayload struct {
+	Description string
+	Deprecated  bool
+}
+
+// CompanyTypeService represents the company type catalogue service.
+type CompanyTypeService struct {
+	typeRepo repositories.CompanyTypeRepository
+}
+
+// NewCompanyTypeService creates a new company type service.
+func NewCompanyTypeService(typeRepo repositories.CompanyTypeRepository) (*CompanyTypeService, error) {
+	if typeRepo == nil {
+		return nil, errors.New("company type repository is nil")
+	}
+
+	return &CompanyTypeService{
+		typeRepo: typeRepo,
+	}, nil
+}
+
+// ListTypes lists the company types of the catalogue ordered by name, deprecated
+// types are left out unless asked for.
+func (s *CompanyTypeService) ListTypes(ctx context.Context, includeDeprecated bool) ([]models.CompanyType, error) {
+	companyTypes, err := s.typeRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list company types: %w", err)
+	}
+
+	listed := make([]models.CompanyType, 0, len(companyTypes))
+	for _, t := range companyTypes {
+		if includeDeprecated || !t.Deprecated {
+			listed = append(listed, t)
+		}
+	}
+
+	return listed, nil
+}
+
+// GetType returns a company type of the catalogue.
+func (s *CompanyTypeService) GetType(ctx context.Context, name common.Type) (models.CompanyType, error) {
+	companyType, err := s.typeRepo.FindByName(ctx, name)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CompanyType{}, fmt.Errorf("%w: %q", ErrCompanyTypeNotFound, name)
+	}
+	if err != nil {
+		return models.CompanyType{}, fmt.Errorf("failed to get company type: %w", err)
+	}
+
+	return companyType, nil
+}
+
+// CreateType adds a company type to the catalogue.
+func (s *CompanyTypeService) CreateType(ctx context.Context, name common.Type, payload CompanyTypePayload) (models.CompanyType, error) {
+	if err := validateCompanyType(name, payload); err != nil {
+		return models.CompanyType{}, err
+	}
+
+	companyType, err := s.typeRepo.Save(ctx, models.CompanyType{
+		Name:        name,
+		Description: payload.Description,
+		Deprecated:  payload.Deprecated,
+	})
+	if errors.Is(err, repositories.ErrTypeExists) {
+		return models.CompanyType{}, fmt.Errorf("%w: %q", ErrCompanyTypeExists, name)
+	}
+	if err != nil {
+		return models.CompanyType{}, fmt.Errorf("failed to create company type: %w", err)
+	}
+
+	return companyType, nil
+}
+
+// UpdateType changes the description of a company type and deprecates or reinstates it.
+func (s *CompanyTypeService) UpdateType(ctx context.Context, name common.Type, payload CompanyTypePayload) (models.CompanyType, error) {
+	if err := validateCompanyType(name, payload); err != nil {
+		return models.CompanyType{}, err
+	}
+
+	companyType, err := s.typeRepo.Update(ctx, models.CompanyType{
+		Name:        name,
+		Description: payload.Description,
+		Deprecated:  payload.Deprecated,
+	})
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CompanyType{}, fmt.Errorf("%w: %q", ErrCompanyTypeNotFound, name)
+	}
+	if err != nil {
+		return models.CompanyType{}, fmt.Errorf("failed to update company type: %w", err)
+	}
+
+	return companyType, nil
+}
+
+// DeleteType removes a company type no company uses from the catalogue, types in use can only be deprecated.
+func (s *CompanyTypeService) DeleteType(ctx context.Context, name common.Type) error {
+	err := s.typeRepo.Delete(ctx, name)
+	switch {
+	case errors.Is(err, repositories.ErrTypeInUse):
+		return fmt.Errorf("%w: deprecate %q instead", ErrCompanyTypeInUse, name)
+	case errors.Is(err, repositories.ErrNotFound):
+		return fmt.Errorf("%w: %q", ErrCompanyTypeNotFound, name)
+	case err != nil:
+		return fmt.Errorf("failed to delete company type: %w", err)
+	}
+
+	return nil
+}
+
+// validateCompanyType checks the name and payload of a company type and lists every violation.
+func validateCompanyType(name common.Type, payload CompanyTypePayload) error {
+	var violations []Violation
+	switch {
+	case name == "":
+		violations = append(violations, Violation{Field: "name", Message: "must not be empty"})
+	case strings.TrimSpace(string(name)) != string(name):
+		violations = append(violations, Violation{Field: "name", Message: "must not start or end with spaces"})
+	case utf8.RuneCountInString(string(name)) > maxTypeNameLength:
+		violations = append(violations, Violation{Field: "name", Message: fmt.Sprintf("must be at most %d characters long", maxTypeNameLength)})
+	}
+
+	if utf8.RuneCountInString(payload.Description) > maxTypeDescriptionLength {
+		violations = append(violations, Violation{Field: "description", Message: fmt.Sprintf("must be at most %d characters long", maxTypeDescriptionLength)})
+	}
+
+	return newValidationError(ErrInvalidCompanyType, violations)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCompanyTypeService(t *testing.T) {
+	t.Parallel()
+	s, err := NewCompanyTypeService(nil)
+	assert.EqualError(t, err, "company type repository is nil")
+	assert.Nil(t, s)
+
+	s, err = NewCompanyTypeService(&mockCompanyTypeRepository{})
+	assert.NoError(t, err)
+	assert.NotNil(t, s)
+}
+
+func TestCompanyTypeService_ListTypes(t *testing.T) {
+	t.Parallel()
+	repo := &mockCompanyTypeRepository{types: []models.CompanyType{
+		{Name: common.Corporations},
+		{Name: "Partnership", Deprecated: true},
+	}}
+	s, err := NewCompanyTypeService(repo)
+	assert.NoError(t, err)
+
+	active, err := s.ListTypes(context.TODO(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CompanyType{{Name: common.Corporations}}, active)
+
+	all, err := s.ListTypes(context.TODO(), true)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestCompanyTypeService_CreateType(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		typeRepo *mockCompanyTypeRepository
+		name     common.Type
+		payload  CompanyTypePayload
+		expErr   string
+		expIs    error
+	}{
+		"invalid company type": {
+			typeRepo: &mockCompanyTypeRepository{},
+			name:     " Partnership",
+			payload:  CompanyTypePayload{Description: strings.Repeat("d", 256)},
+			expErr:   "invalid company type: name must not start or end with spaces; description must be at most 255 characters long",
+			expIs:    ErrInvalidCompanyType,
+		},
+		"empty name": {
+			typeRepo: &mockCompanyTypeRepository{},
+			expErr:   "invalid company type: name must not be empty",
+			expIs:    ErrInvalidCompanyType,
+		},
+		"already exists": {
+			typeRepo: &mockCompanyTypeRepository{err: fmt.Errorf("failed to save company type: %w", repositories.ErrTypeExists)},
+			name:     common.Corporations,
+			expErr:   "company type already exists: \"Corporations\"",
+			expIs:    ErrConflict,
+		},
+		"company type repo error": {
+			typeRepo: &mockCompanyTypeRepository{err: errors.New("company type repo error")},
+			name:     "Partnership",
+			expErr:   "failed to create company type: company type repo error",
+		},
+		"success": {
+			typeRepo: &mockCompanyTypeRepository{},
+			name:     "Partnership",
+			payload:  CompanyTypePayload{Description: "two or more owners"},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewCompanyTypeService(tt.typeRepo)
+			assert.NoError(t, err)
+			companyType, err := s.CreateType(context.TODO(), tt.name, tt.payload)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				if tt.expIs != nil {
+					assert.ErrorIs(t, err, tt.expIs)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, models.CompanyType{Name: tt.name, Description: tt.payload.Description}, companyType)
+		})
+	}
+}
+
+func TestCompanyTypeService_DeleteType(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		typeRepo *mockCompanyTypeRepository
+		expErr   string
+	}{
+		"in use": {
+			typeRepo: &mockCompanyTypeRepository{err: fmt.Errorf("failed to delete company type: %w", repositories.ErrTypeInUse)},
+			expErr:   "company type is in use: deprecate \"Corporations\" instead",
+		},
+		"not found": {
+			typeRepo: &mockCompanyTypeRepository{err: fmt.Errorf("failed to delete company type: %w", repositories.ErrNotFound)},
+			expErr:   "company type not found: \"Corporations\"",
+		},
+		"success": {
+			typeRepo: &mockCompanyTypeRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewCompanyTypeService(tt.typeRepo)
+			assert.NoError(t, err)
+			err = s.DeleteType(context.TODO(), common.Corporations)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// mockCompanyTypeRepository for testing, it holds the default catalogue unless types are given.
+type mockCompanyTypeRepository struct {
+	types []models.CompanyType
+	err   error
+}
+
+func (m *mockCompanyTypeRepository) List(_ context.Context) ([]models.CompanyType, error) {
+	if m.types == nil {
+		return DefaultCompanyTypes, m.err
+	}
+	return m.types, m.err
+}
+
+func (m *mockCompanyTypeRepository) FindByName(_ context.Context, name common.Type) (models.CompanyType, error) {
+	return models.CompanyType{Name: name}, m.err
+}
+
+func (m *mockCompanyTypeRepository) Save(_ context.Context, companyType models.CompanyType) (models.CompanyType, error) {
+	if m.err != nil {
+		return models.CompanyType{}, m.err
+	}
+	return companyType, nil
+}
+
+func (m *mockCompanyTypeRepository) Update(_ context.Context, companyType models.CompanyType) (models.CompanyType, error) {
+	if m.err != nil {
+		return models.CompanyType{}, m.err
+	}
+	return companyType, nil
+}
+
+func (m *mockCompanyTypeRepository) Delete(_ context.Context, _ common.Type) error {
+	return m.err
+}
+
+func (m *mockCompanyTypeRepository) Seed(_ context.Context, _ []models.CompanyType) error {
+	return m.err
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+)
+
+// limits of the company fields, they match the size of the database columns.
+const (
+	maxNameLength        = 15
+	maxDescriptionLength = 3000
+)
+
+// ErrInvalidCompany is returned when a company breaks one or more validation rules,
+// the returned error lists every violation.
+var ErrInvalidCompany = newError(ErrValidation, "invalid_company", "invalid company")
+
+// typeSet holds the company types a company can be given.
+type typeSet map[common.Type]bool
+
+// companyRule is a rule a field of a company has to follow. Field is the JSON path of the
+// field in requests and valid reports whether the company follows the rule.
+type companyRule struct {
+	field   string
+	message string
+	valid   func(company models.Company, types typeSet) bool
+}
+
+// companyRules are the rules every created or updated company follows, the rules of a
+// field are checked in order and only the first one it breaks is reported.
+var companyRules = []companyRule{
+	{"name", "must not be empty", func(c models.Company, _ typeSet) bool {
+		return c.Name != ""
+	}},
+	{"name", fmt.Sprintf("must be at most %d characters long", maxNameLength), func(c models.Company, _ typeSet) bool {
+		return utf8.RuneCountInString(c.Name) <= maxNameLength
+	}},
+	{"description", fmt.Sprintf("must be at most %d characters long", maxDescriptionLength), func(c models.Company, _ typeSet) bool {
+		return utf8.RuneCountInString(c.Description) <= maxDescriptionLength
+	}},
+	{"employees_amount", "must not be empty", func(c models.Company, _ typeSet) bool {
+		return c.EmployeesAmount != 0
+	}},
+	{"employees_amount", "must not be negative", func(c models.Company, _ typeSet) bool {
+		return c.EmployeesAmount > 0
+	}},
+	{"registered", "follows the status of the company, change it with a transition", func(c models.Company, _ typeSet) bool {
+		return c.Status == "" || c.Registered == c.Status.Registered()
+	}},
+	{"type", "must not be empty", func(c models.Company, _ typeSet) bool {
+		return c.Type != ""
+	}},
+	{"type", "must be an available company type", func(c models.Company, types typeSet) bool {
+		return types[c.Type]
+	}},
+}
+
+// validateCompany checks the company against every rule and returns an error listing all
+// the violations, nil when there are none.
+func validateCompany(company models.Company, types typeSet) error {
+	var violations []Violation
+	broken := map[string]bool{}
+	for _, rule := range companyRules {
+		if broken[rule.field] || rule.valid(company, types) {
+			continue
+		}
+		broken[rule.field] = true
+		violations = append(violations, Violation{Field: rule.field, Message: rule.message})
+	}
+
+	return newValidationError(ErrInvalidCompany, violations)
+}
+
+// availableTypes returns the company types of the catalogue that are not deprecated. The
+// current type of an updated company stays available even when it was deprecated since.
+func (s *CompanyService) availableTypes(ctx context.Context, current common.Type) (typeSet, error) {
+	companyTypes, err := s.typeRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load company types: %w", err)
+	}
+
+	types := make(typeSet, len(companyTypes))
+	for _, t := range companyTypes {
+		if !t.Deprecated || t.Name == current {
+			types[t.Name] = true
+		}
+	}
+
+	return types, nil
+}
+
+// newValidationError returns a detailed copy of a validation sentinel listing the
+// violations, nil when there are none.
+func newValidationError(sentinel *Error, violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.String())
+	}
+
+	return &Error{
+		Kind:       sentinel.Kind,
+		Code:       sentinel.Code,
+		Message:    sentinel.Message + ": " + strings.Join(messages, "; "),
+		Violations: violations,
+	}
+}
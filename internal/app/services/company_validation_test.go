@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCompany(t *testing.T) {
+	t.Parallel()
+	valid := models.Company{
+		Name:            "ÄÖÜ company 123",
+		Description:     strings.Repeat("d", maxDescriptionLength),
+		EmployeesAmount: 1,
+		Type:            common.SoleProprietorship,
+	}
+	types := typeSet{common.Corporations: true, common.SoleProprietorship: true}
+	cases := map[string]struct {
+		company       models.Company
+		expViolations []Violation
+	}{
+		"valid company": {
+			company: valid,
+		},
+		"empty company": {
+			expViolations: []Violation{
+				{Field: "name", Message: "must not be empty"},
+				{Field: "employees_amount", Message: "must not be empty"},
+				{Field: "type", Message: "must not be empty"},
+			},
+		},
+		"every limit exceeded": {
+			company: models.Company{
+				Name:            "a name longer than fifteen characters",
+				Description:     strings.Repeat("d", maxDescriptionLength+1),
+				EmployeesAmount: -3,
+				Type:            "Partnership",
+			},
+			expViolations: []Violation{
+				{Field: "name", Message: "must be at most 15 characters long"},
+				{Field: "description", Message: "must be at most 3000 characters long"},
+				{Field: "employees_amount", Message: "must not be negative"},
+				{Field: "type", Message: "must be an available company type"},
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := validateCompany(tt.company, types)
+			if tt.expViolations == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidCompany)
+			assert.ErrorIs(t, err, ErrValidation)
+			var domainErr *Error
+			assert.True(t, errors.As(err, &domainErr))
+			assert.Equal(t, tt.expViolations, domainErr.Violations)
+		})
+	}
+}
+
+func TestCompanyService_AvailableTypes(t *testing.T) {
+	t.Parallel()
+	typeRepo := &mockCompanyTypeRepository{types: []models.CompanyType{
+		{Name: common.Corporations},
+		{Name: "Partnership", Deprecated: true},
+	}}
+	stored := models.Company{Name: "acme", EmployeesAmount: 3, Type: "Partnership"}
+	s, err := NewCompanyService(&mockCompanyRepository{singleCompany: stored}, typeRepo, &mockMemberRepository{}, &mockAttributeRepository{})
+	assert.NoError(t, err)
+
+	_, err = s.Create(context.TODO(), uuid.New(), CreateUpdateCompanyPayload{Name: "globex", EmployeesAmount: 1, Type: "Partnership"})
+	assert.EqualError(t, err, "invalid company: type must be an available company type", "deprecated types cannot be picked")
+
+	name := "acme 2"
+	_, err = s.Update(context.TODO(), uuid.New(), uuid.New(), 0, UpdateCompanyPayload{Name: &name})
+	assert.NoError(t, err, "companies keep their deprecated type")
+
+	typeRepo.err = errors.New("company type repo error")
+	_, err = s.Update(context.TODO(), uuid.New(), uuid.New(), 0, UpdateCompanyPayload{Name: &name})
+	assert.EqualError(t, err, "failed to load company types: company type repo error")
+}
+
+func TestCompanyService_CreateAttributes(t *testing.T) {
+	t.Parallel()
+	schemas := []models.AttributeSchema{
+		{Name: "founded", Type: models.AttributeInt},
+		{Name: "vat_number", Type: models.AttributeString, Required: true},
+	}
+	cases := map[string]struct {
+		attrRepo      *mockAttributeRepository
+		attributes    map[string]json.RawMessage
+		expAttributes []string
+		expErr        string
+	}{
+		"required attribute missing": {
+			attrRepo:   &mockAttributeRepository{schemas: schemas},
+			attributes: map[string]json.RawMessage{"founded": json.RawMessage(`1947`)},
+			expErr:     "invalid company: attributes.vat_number is required",
+		},
+		"invalid and unknown attributes": {
+			attrRepo:   &mockAttributeRepository{schemas: schemas},
+			attributes: map[string]json.RawMessage{"founded": json.RawMessage(`"old"`), "vat_number": json.RawMessage(`"DE123"`), "website": json.RawMessage(`"acme.com"`)},
+			expErr:     "invalid company: attributes.website is not a defined attribute; attributes.founded must be an integer",
+		},
+		"attribute repo error": {
+			attrRepo: &mockAttributeRepository{err: errors.New("attribute repo error")},
+			expErr:   "failed to list attribute schemas: attribute repo error",
+		},
+		"no attributes defined": {
+			attrRepo: &mockAttributeRepository{},
+		},
+		"success": {
+			attrRepo:      &mockAttributeRepository{schemas: schemas},
+			attributes:    map[string]json.RawMessage{"vat_number": json.RawMessage(`"DE123"`), "founded": json.RawMessage(`null`)},
+			expAttributes: []string{"vat_number"},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			companyRepo := &mockCompanyRepository{}
+			s, err := NewCompanyService(companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, tt.attrRepo)
+			assert.NoError(t, err)
+			userID := uuid.New()
+			_, err = s.Create(context.TODO(), userID, CreateUpdateCompanyPayload{Name: "acme", EmployeesAmount: 3, Type: common.Corporations, Attributes: tt.attributes})
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Empty(t, companyRepo.saved.Name, "the company is not saved")
+				return
+			}
+			assert.NoError(t, err)
+			var names []string
+			for _, attribute := range companyRepo.saved.Attributes {
+				names = append(names, attribute.Name)
+				assert.Equal(t, userID, attribute.UpdatedBy)
+			}
+			assert.Equal(t, tt.expAttributes, names)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// minAnnualizedDays is the shortest range whose growth is annualized, the annual rate of
+// shorter ones is dominated by noise.
+const minAnnualizedDays = 90
+
+// ErrInvalidHistoryQuery is returned when the range or interval of an employee history cannot be used.
+var ErrInvalidHistoryQuery = newError(ErrInvalidRequest, "invalid_history_query", "invalid history query")
+
+// EmployeeHistory defines the functionality related to how the headcount of companies evolved.
+type EmployeeHistory interface {
+	GetEmployeeHistory(ctx context.Context, userID, companyID uuid.UUID, query EmployeeHistoryQuery) (EmployeeHistoryReport, error)
+}
+
+// HistoryInterval is the period an employee history is downsampled to.
+type HistoryInterval string
+
+const (
+	IntervalNone      HistoryInterval = ""          // Every recorded change is returned.
+	IntervalMonthly   HistoryInterval = "monthly"   // One point per calendar month.
+	IntervalQuarterly HistoryInterval = "quarterly" // One point per calendar quarter.
+)
+
+// EmployeeHistoryQuery represents the days an employee history covers, both ends are included.
+// From defaults to the first recorded count and To to today.
+type EmployeeHistoryQuery struct {
+	From     *time.Time
+	To       *time.Time
+	Interval HistoryInterval
+}
+
+// EmployeeHistoryReport represents the headcount of a company over a range of days.
+type EmployeeHistoryReport struct {
+	From     time.Time
+	To       time.Time
+	Interval HistoryInterval
+	Points   []EmployeeHistoryPoint
+	Summary  EmployeeGrowth
+}
+
+// EmployeeHistoryPoint is the headcount on a day, or at the end of the period starting on it when
+// the history is downsampled. GrowthRate is relative to the previous point, nil for the first one
+// or when the previous headcount is zero.
+type EmployeeHistoryPoint struct {
+	Date       time.Time
+	Employees  int
+	GrowthRate *float64
+}
+
+// EmployeeGrowth represents how the headcount changed over the whole range. AnnualGrowthRate is the
+// compound annual growth rate, nil when the range is too short or either headcount is zero.
+type EmployeeGrowth struct {
+	Start            int
+	End              int
+	GrowthRate       *float64
+	AnnualGrowthRate *float64
+}
+
+// EmployeeHistoryService represents the employee history service.
+type EmployeeHistoryService struct {
+	employeeCountRepo repositories.EmployeeCountRepository
+	companyRepo       repositories.CompanyRepository
+	access            companyAccess
+}
+
+// NewEmployeeHistoryService creates a new employee history service, the history of a company is
+// read by its members.
+func NewEmployeeHistoryService(employeeCountRepo repositories.EmployeeCountRepository, companyRepo repositories.CompanyRepository, memberRepo repositories.MemberRepository) (*EmployeeHistoryService, error) {
+	if employeeCountRepo == nil {
+		return nil, errors.New("employee count repository is nil")
+	}
+
+	if companyRepo == nil {
+		return nil, errors.New("company repository is nil")
+	}
+
+	if memberRepo == nil {
+		return nil, errors.New("member repository is nil")
+	}
+
+	return &EmployeeHistoryService{
+		employeeCountRepo: employeeCountRepo,
+		companyRepo:       companyRepo,
+		access:            companyAccess{memberRepo: memberRepo},
+	}, nil
+}
+
+// GetEmployeeHistory returns the headcount of a live company the user can view over the range of the query.
+func (s *EmployeeHistoryService) GetEmployeeHistory(ctx context.Context, userID, companyID uuid.UUID, query EmployeeHistoryQuery) (EmployeeHistoryReport, error) {
+	if query.Interval != IntervalNone && query.Interval != IntervalMonthly && query.Interval != IntervalQuarterly {
+		return EmployeeHistoryReport{}, fmt.Errorf("%w: unknown interval %q", ErrInvalidHistoryQuery, query.Interval)
+	}
+	if query.From != nil && query.To != nil && models.Day(*query.From).After(models.Day(*query.To)) {
+		return EmployeeHistoryReport{}, fmt.Errorf("%w: from must not be after to", ErrInvalidHistoryQuery)
+	}
+
+	_, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return EmployeeHistoryReport{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return EmployeeHistoryReport{}, fmt.Errorf("failed to find company: %w", err)
+	}
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return EmployeeHistoryReport{}, err
+	}
+
+	to := models.Day(time.Now())
+	if query.To != nil && models.Day(*query.To).Before(to) {
+		to = models.Day(*query.To)
+	}
+	if query.From != nil && models.Day(*query.From).After(to) {
+		return EmployeeHistoryReport{}, fmt.Errorf("%w: from must not be in the future", ErrInvalidHistoryQuery)
+	}
+	counts, err := s.employeeCountRepo.List(ctx, companyID, to)
+	if err != nil {
+		return EmployeeHistoryReport{}, fmt.Errorf("failed to list employee counts: %w", err)
+	}
+
+	report := EmployeeHistoryReport{From: to, To: to, Interval: query.Interval, Points: []EmployeeHistoryPoint{}}
+	if query.From != nil {
+		report.From = models.Day(*query.From)
+	}
+	if len(counts) == 0 {
+		return report, nil
+	}
+	if query.From == nil || report.From.Before(counts[0].EffectiveOn) {
+		report.From = counts[0].EffectiveOn
+	}
+
+	if query.Interval == IntervalNone {
+		report.Points = changedPoints(counts, report.From, report.To)
+	} else {
+		report.Points = periodPoints(counts, report.From, report.To, query.Interval)
+	}
+	for i := 1; i < len(report.Points); i++ {
+		report.Points[i].GrowthRate = growthRate(report.Points[i-1].Employees, report.Points[i].Employees)
+	}
+	report.Summary = summarizeGrowth(report.Points, report.From, report.To)
+
+	return report, nil
+}
+
+// changedPoints returns the headcount on from followed by every day in the range it changed on.
+func changedPoints(counts []models.EmployeeCount, from, to time.Time) []EmployeeHistoryPoint {
+	points := []EmployeeHistoryPoint{{Date: from, Employees: employeesOn(counts, from)}}
+	for _, count := range counts {
+		if !count.EffectiveOn.After(from) || count.EffectiveOn.After(to) {
+			continue
+		}
+		// counts are ordered, the last one of a day wins
+		if last := &points[len(points)-1]; last.Date.Equal(count.EffectiveOn) {
+			last.Employees = count.Employees
+			continue
+		}
+		points = append(points, EmployeeHistoryPoint{Date: count.EffectiveOn, Employees: count.Employees})
+	}
+
+	return points
+}
+
+// periodPoints returns a point per period of the range dated on the first day of the period,
+// its headcount is the one at the end of the period or on to for the last one.
+func periodPoints(counts []models.EmployeeCount, from, to time.Time, interval HistoryInterval) []EmployeeHistoryPoint {
+	months := 1
+	if interval == IntervalQuarterly {
+		months = 3
+	}
+	start := time.Date(from.Year(), from.Month()-(from.Month()-1)%time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+
+	var points []EmployeeHistoryPoint
+	for ; !start.After(to); start = start.AddDate(0, months, 0) {
+		end := start.AddDate(0, months, -1)
+		if end.After(to) {
+			end = to
+		}
+		points = append(points, EmployeeHistoryPoint{Date: start, Employees: employeesOn(counts, end)})
+	}
+
+	return points
+}
+
+// employeesOn returns the headcount on a day, counts are ordered oldest first.
+func employeesOn(counts []models.EmployeeCount, day time.Time) int {
+	employees := 0
+	for _, count := range counts {
+		if count.EffectiveOn.After(day) {
+			break
+		}
+		employees = count.Employees
+	}
+
+	return employees
+}
+
+// summarizeGrowth returns the growth between the first and the last point.
+func summarizeGrowth(points []EmployeeHistoryPoint, from, to time.Time) EmployeeGrowth {
+	if len(points) == 0 {
+		return EmployeeGrowth{}
+	}
+
+	growth := EmployeeGrowth{Start: points[0].Employees, End: points[len(points)-1].Employees}
+	growth.GrowthRate = growthRate(growth.Start, growth.End)
+
+	days := to.Sub(from).Hours() / 24
+	if days >= minAnnualizedDays && growth.Start > 0 && growth.End > 0 {
+		annual := math.Pow(float64(growth.End)/float64(growth.Start), 365.25/days) - 1
+		if !math.IsInf(annual, 0) {
+			growth.AnnualGrowthRate = rate(annual)
+		}
+	}
+
+	return growth
+}
+
+// growthRate returns the relative change between two headcounts, nil when there is nothing to compare to.
+func growthRate(previous, current int) *float64 {
+	if previous == 0 {
+		return nil
+	}
+
+	return rate(float64(current-previous) / float64(previous))
+}
+
+// rate rounds a rate to four decimals, a hundredth of a percent.
+func rate(r float64) *float64 {
+	r = math.Round(r*10000) / 10000
+	return &r
+}
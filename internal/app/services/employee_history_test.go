@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEmployeeHistoryService(t *testing.T) {
+	t.Parallel()
+	s, err := NewEmployeeHistoryService(nil, &mockCompanyRepository{}, &mockMemberRepository{})
+	assert.EqualError(t, err, "employee count repository is nil")
+	assert.Nil(t, s)
+
+	s, err = NewEmployeeHistoryService(&mockEmployeeCountRepository{}, nil, &mockMemberRepository{})
+	assert.EqualError(t, err, "company repository is nil")
+	assert.Nil(t, s)
+
+	s, err = NewEmployeeHistoryService(&mockEmployeeCountRepository{}, &mockCompanyRepository{}, nil)
+	assert.EqualError(t, err, "member repository is nil")
+	assert.Nil(t, s)
+
+	s, err = NewEmployeeHistoryService(&mockEmployeeCountRepository{}, &mockCompanyRepository{}, &mockMemberRepository{})
+	assert.NoError(t, err)
+	assert.NotNil(t, s)
+}
+
+func TestEmployeeHistoryService_GetEmployeeHistory(t *testing.T) {
+	t.Parallel()
+	day := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	ptr := func(s string) *time.Time {
+		d := day(s)
+		return &d
+	}
+	rate := func(r float64) *float64 { return &r }
+	future := time.Now().AddDate(0, 1, 0)
+	counts := []models.EmployeeCount{
+		{EffectiveOn: day("2023-01-10"), Employees: 10},
+		{EffectiveOn: day("2023-03-10"), Employees: 12},
+		{EffectiveOn: day("2023-03-10"), Employees: 14},
+		{EffectiveOn: day("2023-04-10"), Employees: 20},
+	}
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		memberRepo  *mockMemberRepository
+		query       EmployeeHistoryQuery
+		expReport   EmployeeHistoryReport
+		expErr      string
+	}{
+		"unknown interval": {
+			companyRepo: &mockCompanyRepository{},
+			query:       EmployeeHistoryQuery{Interval: "weekly"},
+			expErr:      "invalid history query: unknown interval \"weekly\"",
+		},
+		"from after to": {
+			companyRepo: &mockCompanyRepository{},
+			query:       EmployeeHistoryQuery{From: ptr("2023-05-01"), To: ptr("2023-04-30")},
+			expErr:      "invalid history query: from must not be after to",
+		},
+		"future from": {
+			companyRepo: &mockCompanyRepository{},
+			query:       EmployeeHistoryQuery{From: &future, Interval: IntervalMonthly},
+			expErr:      "invalid history query: from must not be in the future",
+		},
+		"company not found": {
+			companyRepo: &mockCompanyRepository{err: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound)},
+			expErr:      "company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+		},
+		"not a member": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{err: fmt.Errorf("failed to find member: %w", repositories.ErrNotFound)},
+			expErr:      "access to the company denied: the viewer role is required",
+		},
+		"every change": {
+			companyRepo: &mockCompanyRepository{},
+			query:       EmployeeHistoryQuery{From: ptr("2023-01-01"), To: ptr("2023-06-30")},
+			expReport: EmployeeHistoryReport{
+				From: day("2023-01-10"),
+				To:   day("2023-06-30"),
+				Points: []EmployeeHistoryPoint{
+					{Date: day("2023-01-10"), Employees: 10},
+					{Date: day("2023-03-10"), Employees: 14, GrowthRate: rate(0.4)},
+					{Date: day("2023-04-10"), Employees: 20, GrowthRate: rate(0.4286)},
+				},
+				Summary: EmployeeGrowth{Start: 10, End: 20, GrowthRate: rate(1), AnnualGrowthRate: rate(3.3953)},
+			},
+		},
+		"starting after the first change": {
+			companyRepo: &mockCompanyRepository{},
+			query:       EmployeeHistoryQuery{From: ptr("2023-02-01"), To: ptr("2023-03-31")},
+			expReport: EmployeeHistoryReport{
+				From: day("2023-02-01"),
+				To:   day("2023-03-31"),
+				Points: []EmployeeHistoryPoint{
+					{Date: day("2023-02-01"), Employees: 10},
+					{Date: day("2023-03-10"), Employees: 14, GrowthRate: rate(0.4)},
+				},
+				Summary: EmployeeGrowth{Start: 10, End: 14, GrowthRate: rate(0.4)},
+			},
+		},
+		"quarterly": {
+			companyRepo: &mockCompanyRepository{},
+			query:       EmployeeHistoryQuery{To: ptr("2023-06-30"), Interval: IntervalQuarterly},
+			expReport: EmployeeHistoryReport{
+				From:     day("2023-01-10"),
+				To:       day("2023-06-30"),
+				Interval: IntervalQuarterly,
+				Points: []EmployeeHistoryPoint{
+					{Date: day("2023-01-01"), Employees: 14},
+					{Date: day("2023-04-01"), Employees: 20, GrowthRate: rate(0.4286)},
+				},
+				Summary: EmployeeGrowth{Start: 14, End: 20, GrowthRate: rate(0.4286), AnnualGrowthRate: rate(1.1422)},
+			},
+		},
+		"monthly, too short to annualize": {
+			companyRepo: &mockCompanyRepository{},
+			query:       EmployeeHistoryQuery{From: ptr("2023-02-15"), To: ptr("2023-05-15"), Interval: IntervalMonthly},
+			expReport: EmployeeHistoryReport{
+				From:     day("2023-02-15"),
+				To:       day("2023-05-15"),
+				Interval: IntervalMonthly,
+				Points: []EmployeeHistoryPoint{
+					{Date: day("2023-02-01"), Employees: 10},
+					{Date: day("2023-03-01"), Employees: 14, GrowthRate: rate(0.4)},
+					{Date: day("2023-04-01"), Employees: 20, GrowthRate: rate(0.4286)},
+					{Date: day("2023-05-01"), Employees: 20, GrowthRate: rate(0)},
+				},
+				Summary: EmployeeGrowth{Start: 10, End: 20, GrowthRate: rate(1)},
+			},
+		},
+		"before the first count": {
+			companyRepo: &mockCompanyRepository{},
+			query:       EmployeeHistoryQuery{From: ptr("2022-01-01"), To: ptr("2022-12-31")},
+			expReport: EmployeeHistoryReport{
+				From:   day("2022-01-01"),
+				To:     day("2022-12-31"),
+				Points: []EmployeeHistoryPoint{},
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewEmployeeHistoryService(&mockEmployeeCountRepository{counts: counts}, tt.companyRepo, memberRepo)
+			assert.NoError(t, err)
+			report, err := s.GetEmployeeHistory(context.TODO(), uuid.New(), uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), tt.query)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expReport, report)
+		})
+	}
+}
+
+func TestSummarizeGrowth_NoPoints(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, EmployeeGrowth{}, summarizeGrowth(nil, time.Time{}, time.Time{}))
+}
+
+func TestCompanyService_Update_EmployeesEffectiveOn(t *testing.T) {
+	t.Parallel()
+	stored := models.Company{Version: 1, Name: "Acme", EmployeesAmount: 4, Type: common.Corporations}
+	employees := 6
+	yesterday := time.Now().AddDate(0, 0, -1)
+	tomorrow := time.Now().AddDate(0, 0, 1)
+
+	companyRepo := &mockCompanyRepository{singleCompany: stored}
+	s, err := NewCompanyService(companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+	assert.NoError(t, err)
+	_, err = s.Update(context.TODO(), uuid.New(), uuid.New(), 0, UpdateCompanyPayload{EmployeesAmount: &employees, EmployeesEffectiveOn: &yesterday})
+	assert.NoError(t, err)
+	assert.Equal(t, yesterday, companyRepo.employeesOn)
+
+	_, err = s.Update(context.TODO(), uuid.New(), uuid.New(), 0, UpdateCompanyPayload{EmployeesAmount: &employees, EmployeesEffectiveOn: &tomorrow})
+	assert.EqualError(t, err, "invalid company: employees_effective_on must not be in the future")
+
+	companyRepo = &mockCompanyRepository{singleCompany: stored, updateErr: fmt.Errorf("failed to update company: %w", repositories.ErrEmployeeCountOutOfOrder)}
+	s, err = NewCompanyService(companyRepo, &mockCompanyTypeRepository{}, &mockMemberRepository{}, &mockAttributeRepository{})
+	assert.NoError(t, err)
+	_, err = s.Patch(context.TODO(), uuid.New(), uuid.New(), 0, []PatchOperation{{Op: "replace", Path: "/employees_amount", Value: []byte("6")}}, &yesterday)
+	assert.EqualError(t, err, "invalid company: employees_effective_on must not be before the day of the latest employee count")
+}
+
+// mockEmployeeCountRepository for testing, it only returns the counts effective by the requested day.
+type mockEmployeeCountRepository struct {
+	counts []models.EmployeeCount
+	err    error
+}
+
+func (m *mockEmployeeCountRepository) List(_ context.Context, _ uuid.UUID, until time.Time) ([]models.EmployeeCount, error) {
+	var counts []models.EmployeeCount
+	for _, count := range m.counts {
+		if !count.EffectiveOn.After(until) {
+			counts = append(counts, count)
+		}
+	}
+	return counts, m.err
+}
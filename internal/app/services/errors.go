@@ -0,0 +1,52 @@
+package services
+
+import "errors"
+
+// The kinds of errors returned by the services. Errors of no kind are internal failures.
+var (
+	ErrInvalidRequest  = errors.New("invalid request")
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrValidation      = errors.New("validation failed")
+)
+
+// Error is a domain error. Code identifies the error for clients and never changes,
+// Kind is one of the error kinds above and tells how the error is reported.
+// Validation errors list the fields at fault in Violations.
+type Error struct {
+	Kind       error
+	Code       string
+	Message    string
+	Violations []Violation
+}
+
+// Violation is a validation rule broken by a field, Field is the JSON path of the field.
+type Violation struct {
+	Field   string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Field + " " + v.Message
+}
+
+func newError(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap makes errors.Is match the kind of the error.
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// Is matches errors with the same code, so a detailed error matches its sentinel.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// IdempotencyKeys defines the functionality related to idempotent requests.
+type IdempotencyKeys interface {
+	Begin(ctx context.Context, userID uuid.UUID, key, fingerprint string) (*StoredResponse, error)
+	Complete(ctx context.Context, userID uuid.UUID, key string, response StoredResponse) error
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+}
+
+var (
+	// ErrInvalidIdempotencyKey is returned when an idempotency key cannot be stored.
+	ErrInvalidIdempotencyKey = newError(ErrInvalidRequest, "invalid_idempotency_key", "invalid idempotency key")
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a different request.
+	ErrIdempotencyKeyReused = newError(ErrValidation, "idempotency_key_reused", "idempotency key reused with a different request")
+	// ErrIdempotencyKeyInProgress is returned when the first request with a key has not finished yet.
+	ErrIdempotencyKeyInProgress = newError(ErrConflict, "idempotency_key_in_progress", "request with the same idempotency key is in progress")
+)
+
+const maxIdempotencyKeyLength = 255
+
+// StoredResponse represents the response given to the first request made with an idempotency key.
+type StoredResponse struct {
+	StatusCode int
+	Header     map[string]string
+	Body       []byte
+}
+
+// IdempotencyService represents the idempotency key service.
+type IdempotencyService struct {
+	idempotencyRepo repositories.IdempotencyRepository
+	ttl             time.Duration
+}
+
+// NewIdempotencyService creates a new idempotency service, keys are kept for the ttl.
+func NewIdempotencyService(idempotencyRepo repositories.IdempotencyRepository, ttl time.Duration) (*IdempotencyService, error) {
+	if idempotencyRepo == nil {
+		return nil, errors.New("idempotency repository is nil")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("idempotency ttl must be positive")
+	}
+
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+	}, nil
+}
+
+// Begin reserves a key of the user for the request with the given fingerprint.
+// It returns the stored response when the same request was already answered and
+// nil when the request is new and has to be processed.
+func (s *IdempotencyService) Begin(ctx context.Context, userID uuid.UUID, key, fingerprint string) (*StoredResponse, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%w: must be between 1 and %d characters", ErrInvalidIdempotencyKey, maxIdempotencyKeyLength)
+	}
+
+	now := time.Now()
+	record, reserved, err := s.idempotencyRepo.Reserve(ctx, models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin idempotent request: %w", err)
+	}
+
+	switch {
+	case reserved:
+		return nil, nil
+	case record.Fingerprint != fingerprint:
+		return nil, ErrIdempotencyKeyReused
+	case record.StatusCode == 0:
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return &StoredResponse{
+		StatusCode: record.StatusCode,
+		Header:     record.Header,
+		Body:       record.Body,
+	}, nil
+}
+
+// Complete stores the response of a request reserved by Begin so it can be replayed.
+func (s *IdempotencyService) Complete(ctx context.Context, userID uuid.UUID, key string, response StoredResponse) error {
+	err := s.idempotencyRepo.Complete(ctx, models.IdempotencyKey{
+		UserID:     userID,
+		Key:        key,
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+		Body:       response.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotent request: %w", err)
+	}
+
+	return nil
+}
+
+// Release drops a key reserved by Begin without a response, the request can then be retried.
+func (s *IdempotencyService) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	if err := s.idempotencyRepo.Release(ctx, userID, key); err != nil {
+		return fmt.Errorf("failed to release idempotent request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIdempotencyService(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		idempotencyRepo repositories.IdempotencyRepository
+		ttl             time.Duration
+		expErr          string
+	}{
+		"idempotency repo is nil": {
+			ttl:    time.Hour,
+			expErr: "idempotency repository is nil",
+		},
+		"no ttl": {
+			idempotencyRepo: &mockIdempotencyRepository{},
+			expErr:          "idempotency ttl must be positive",
+		},
+		"success": {
+			idempotencyRepo: &mockIdempotencyRepository{},
+			ttl:             time.Hour,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewIdempotencyService(tt.idempotencyRepo, tt.ttl)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		idempotencyRepo *mockIdempotencyRepository
+		key             string
+		expResponse     *StoredResponse
+		expErr          string
+	}{
+		"empty key": {
+			idempotencyRepo: &mockIdempotencyRepository{},
+			expErr:          "invalid idempotency key: must be between 1 and 255 characters",
+		},
+		"idempotency repo error": {
+			idempotencyRepo: &mockIdempotencyRepository{err: errors.New("idempotency repo error")},
+			key:             "key",
+			expErr:          "failed to begin idempotent request: idempotency repo error",
+		},
+		"new request": {
+			idempotencyRepo: &mockIdempotencyRepository{reserved: true},
+			key:             "key",
+		},
+		"key reused": {
+			idempotencyRepo: &mockIdempotencyRepository{record: models.IdempotencyKey{Fingerprint: "other", StatusCode: 201}},
+			key:             "key",
+			expErr:          "idempotency key reused with a different request",
+		},
+		"in progress": {
+			idempotencyRepo: &mockIdempotencyRepository{record: models.IdempotencyKey{Fingerprint: "fingerprint"}},
+			key:             "key",
+			expErr:          "request with the same idempotency key is in progress",
+		},
+		"replay": {
+			idempotencyRepo: &mockIdempotencyRepository{record: models.IdempotencyKey{
+				Fingerprint: "fingerprint",
+				StatusCode:  201,
+				Header:      map[string]string{"ETag": `"1"`},
+				Body:        []byte("{}"),
+			}},
+			key:         "key",
+			expResponse: &StoredResponse{StatusCode: 201, Header: map[string]string{"ETag": `"1"`}, Body: []byte("{}")},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewIdempotencyService(tt.idempotencyRepo, time.Hour)
+			assert.NoError(t, err)
+
+			response, err := s.Begin(context.TODO(), uuid.New(), tt.key, "fingerprint")
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expResponse, response)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), tt.idempotencyRepo.reservedRecord.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+type mockIdempotencyRepository struct {
+	record         models.IdempotencyKey
+	reserved       bool
+	reservedRecord models.IdempotencyKey
+	err            error
+}
+
+func (m *mockIdempotencyRepository) Reserve(_ context.Context, record models.IdempotencyKey) (models.IdempotencyKey, bool, error) {
+	m.reservedRecord = record
+	if m.reserved {
+		return record, true, m.err
+	}
+	return m.record, false, m.err
+}
+
+func (m *mockIdempotencyRepository) Complete(_ context.Context, _ models.IdempotencyKey) error {
+	return m.err
+}
+
+func (m *mockIdempotencyRepository) Release(_ context.Context, _ uuid.UUID, _ string) error {
+	return m.err
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/countries"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// limits of company locations, the lengths match the size of the database columns.
+const (
+	maxLocationNameLength  = 128
+	maxStreetLength        = 255
+	maxPostalCodeLength    = 32
+	maxCityLength          = 128
+	maxRegionLength        = 128
+	maxLocationsPerCompany = 100
+)
+
+var (
+	// ErrInvalidLocation is returned when a location breaks one or more validation rules,
+	// the returned error lists every violation.
+	ErrInvalidLocation = newError(ErrValidation, "invalid_location", "invalid location")
+	// ErrLocationNotFound is returned when a company has no location with the requested id.
+	ErrLocationNotFound = newError(ErrNotFound, "location_not_found", "location not found")
+)
+
+// CompanyLocations defines the functionality related to the offices of companies. Changing the
+// locations of a company changes the company, the updated company is returned along.
+type CompanyLocations interface {
+	ListLocations(ctx context.Context, userID, companyID uuid.UUID) ([]models.CompanyLocation, error)
+	GetLocation(ctx context.Context, userID, companyID, locationID uuid.UUID) (models.CompanyLocation, error)
+	AddLocation(ctx context.Context, userID, companyID uuid.UUID, payload LocationPayload) (models.CompanyLocation, models.Company, error)
+	UpdateLocation(ctx context.Context, userID, companyID, locationID uuid.UUID, payload LocationPayload) (models.CompanyLocation, models.Company, error)
+	RemoveLocation(ctx context.Context, userID, companyID, locationID uuid.UUID) (models.Company, error)
+}
+
+// LocationPayload is the address of a location. Country is an ISO 3166-1 alpha-2 code and
+// the coordinates are optional, but given together.
+type LocationPayload struct {
+	Name         string
+	Headquarters bool
+	Street       string
+	PostalCode   string
+	City         string
+	Region       string
+	Country      string
+	Latitude     *float64
+	Longitude    *float64
+}
+
+// LocationService represents the company location service.
+type LocationService struct {
+	locationRepo repositories.LocationRepository
+	companyRepo  repositories.CompanyRepository
+	access       companyAccess
+}
+
+// NewLocationService creates a new location service, the locations of a company are changed by its editors.
+func NewLocationService(locationRepo repositories.LocationRepository, companyRepo repositories.CompanyRepository, memberRepo repositories.MemberRepository) (*LocationService, error) {
+	if locationRepo == nil {
+		return nil, errors.New("location repository is nil")
+	}
+
+	if companyRepo == nil {
+		return nil, errors.New("company repository is nil")
+	}
+
+	if memberRepo == nil {
+		return nil, errors.New("member repository is nil")
+	}
+
+	return &LocationService{
+		locationRepo: locationRepo,
+		companyRepo:  companyRepo,
+		access:       companyAccess{memberRepo: memberRepo},
+	}, nil
+}
+
+// ListLocations lists the locations of a company the user can view, headquarters first.
+func (s *LocationService) ListLocations(ctx context.Context, userID, companyID uuid.UUID) ([]models.CompanyLocation, error) {
+	if err := s.findViewable(ctx, userID, companyID); err != nil {
+		return nil, err
+	}
+
+	locations, err := s.locationRepo.List(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locations: %w", err)
+	}
+
+	return locations, nil
+}
+
+// GetLocation returns a single location of a company the user can view.
+func (s *LocationService) GetLocation(ctx context.Context, userID, companyID, locationID uuid.UUID) (models.CompanyLocation, error) {
+	if err := s.findViewable(ctx, userID, companyID); err != nil {
+		return models.CompanyLocation{}, err
+	}
+
+	location, err := s.locationRepo.Find(ctx, companyID, locationID)
+	if err != nil {
+		return models.CompanyLocation{}, locationError(err, companyID, locationID)
+	}
+
+	return location, nil
+}
+
+// AddLocation adds a location to a company on behalf of an editor of it. A new headquarters
+// turns the previous one into a branch.
+func (s *LocationService) AddLocation(ctx context.Context, userID, companyID uuid.UUID, payload LocationPayload) (models.CompanyLocation, models.Company, error) {
+	if err := s.findEditable(ctx, userID, companyID); err != nil {
+		return models.CompanyLocation{}, models.Company{}, err
+	}
+
+	location, err := newLocation(payload)
+	if err != nil {
+		return models.CompanyLocation{}, models.Company{}, err
+	}
+
+	locations, err := s.locationRepo.List(ctx, companyID)
+	if err != nil {
+		return models.CompanyLocation{}, models.Company{}, fmt.Errorf("failed to list locations: %w", err)
+	}
+	if len(locations) >= maxLocationsPerCompany {
+		return models.CompanyLocation{}, models.Company{}, fmt.Errorf("%w: a company has at most %d locations", ErrInvalidLocation, maxLocationsPerCompany)
+	}
+
+	location.CompanyID = companyID
+	location, comp, err := s.locationRepo.Save(ctx, userID, location)
+	if err != nil {
+		return models.CompanyLocation{}, models.Company{}, locationError(err, companyID, uuid.Nil)
+	}
+
+	return location, comp, nil
+}
+
+// UpdateLocation replaces the address of a location of a company on behalf of an editor of it.
+func (s *LocationService) UpdateLocation(ctx context.Context, userID, companyID, locationID uuid.UUID, payload LocationPayload) (models.CompanyLocation, models.Company, error) {
+	if err := s.findEditable(ctx, userID, companyID); err != nil {
+		return models.CompanyLocation{}, models.Company{}, err
+	}
+
+	location, err := newLocation(payload)
+	if err != nil {
+		return models.CompanyLocation{}, models.Company{}, err
+	}
+
+	location.ID = locationID
+	location.CompanyID = companyID
+	location, comp, err := s.locationRepo.Update(ctx, userID, location)
+	if err != nil {
+		return models.CompanyLocation{}, models.Company{}, locationError(err, companyID, locationID)
+	}
+
+	return location, comp, nil
+}
+
+// RemoveLocation removes a location of a company on behalf of an editor of it.
+func (s *LocationService) RemoveLocation(ctx context.Context, userID, companyID, locationID uuid.UUID) (models.Company, error) {
+	if err := s.findEditable(ctx, userID, companyID); err != nil {
+		return models.Company{}, err
+	}
+
+	comp, err := s.locationRepo.Delete(ctx, userID, companyID, locationID)
+	if err != nil {
+		return models.Company{}, locationError(err, companyID, locationID)
+	}
+
+	return comp, nil
+}
+
+// newLocation validates the payload against every rule and returns the location it describes.
+// Text is trimmed and the country code is upper-cased.
+func newLocation(payload LocationPayload) (models.CompanyLocation, error) {
+	location := models.CompanyLocation{
+		Name:         strings.TrimSpace(payload.Name),
+		Headquarters: payload.Headquarters,
+		Street:       strings.TrimSpace(payload.Street),
+		PostalCode:   strings.TrimSpace(payload.PostalCode),
+		City:         strings.TrimSpace(payload.City),
+		Region:       strings.TrimSpace(payload.Region),
+		Country:      countries.Normalize(payload.Country),
+		Latitude:     payload.Latitude,
+		Longitude:    payload.Longitude,
+	}
+
+	var violations []Violation
+	lengths := []struct {
+		field string
+		value string
+		max   int
+	}{
+		{"name", location.Name, maxLocationNameLength},
+		{"street", location.Street, maxStreetLength},
+		{"postal_code", location.PostalCode, maxPostalCodeLength},
+		{"city", location.City, maxCityLength},
+		{"region", location.Region, maxRegionLength},
+	}
+	for _, l := range lengths {
+		if utf8.RuneCountInString(l.value) > l.max {
+			violations = append(violations, Violation{Field: l.field, Message: fmt.Sprintf("must be at most %d characters long", l.max)})
+		}
+	}
+	if location.Name == "" {
+		violations = append(violations, Violation{Field: "name", Message: "must not be empty"})
+	}
+	if location.City == "" {
+		violations = append(violations, Violation{Field: "city", Message: "must not be empty"})
+	}
+
+	switch _, ok := countries.Lookup(location.Country); {
+	case location.Country == "":
+		violations = append(violations, Violation{Field: "country", Message: "must not be empty"})
+	case !ok:
+		violations = append(violations, Violation{Field: "country", Message: "must be an ISO 3166-1 alpha-2 country code"})
+	}
+
+	switch {
+	case (location.Latitude == nil) != (location.Longitude == nil):
+		violations = append(violations, Violation{Field: "latitude", Message: "must be given together with longitude"})
+	case location.Latitude != nil:
+		if *location.Latitude < -90 || *location.Latitude > 90 {
+			violations = append(violations, Violation{Field: "latitude", Message: "must be between -90 and 90"})
+		}
+		if *location.Longitude < -180 || *location.Longitude > 180 {
+			violations = append(violations, Violation{Field: "longitude", Message: "must be between -180 and 180"})
+		}
+	}
+
+	if err := newValidationError(ErrInvalidLocation, violations); err != nil {
+		return models.CompanyLocation{}, err
+	}
+
+	return location, nil
+}
+
+// locationError converts the repository errors of reading and changing a location.
+func locationError(err error, companyID, locationID uuid.UUID) error {
+	switch {
+	case errors.Is(err, repositories.ErrLocationNotFound):
+		return fmt.Errorf("%w: %s", ErrLocationNotFound, locationID)
+	case errors.Is(err, repositories.ErrNotFound):
+		return fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	case errors.Is(err, repositories.ErrVersionConflict):
+		return fmt.Errorf("%w: company was modified concurrently", ErrVersionMismatch)
+	}
+
+	return fmt.Errorf("failed to change location: %w", err)
+}
+
+// findEditable makes sure the company exists and the user is an editor of it.
+func (s *LocationService) findEditable(ctx context.Context, userID, companyID uuid.UUID) error {
+	if err := s.findCompany(ctx, companyID); err != nil {
+		return err
+	}
+
+	return s.access.authorize(ctx, userID, companyID, models.RoleEditor)
+}
+
+// findViewable makes sure the company exists and the user can view it.
+func (s *LocationService) findViewable(ctx context.Context, userID, companyID uuid.UUID) error {
+	if err := s.findCompany(ctx, companyID); err != nil {
+		return err
+	}
+
+	return s.access.authorize(ctx, userID, companyID, models.RoleViewer)
+}
+
+// findCompany makes sure the company exists.
+func (s *LocationService) findCompany(ctx context.Context, companyID uuid.UUID) error {
+	_, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLocationService(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		locationRepo repositories.LocationRepository
+		companyRepo  repositories.CompanyRepository
+		memberRepo   repositories.MemberRepository
+		expErr       string
+	}{
+		"location repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+			expErr:      "location repository is nil",
+		},
+		"company repo is nil": {
+			locationRepo: &mockLocationRepository{},
+			memberRepo:   &mockMemberRepository{},
+			expErr:       "company repository is nil",
+		},
+		"member repo is nil": {
+			locationRepo: &mockLocationRepository{},
+			companyRepo:  &mockCompanyRepository{},
+			expErr:       "member repository is nil",
+		},
+		"success": {
+			locationRepo: &mockLocationRepository{},
+			companyRepo:  &mockCompanyRepository{},
+			memberRepo:   &mockMemberRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewLocationService(tt.locationRepo, tt.companyRepo, tt.memberRepo)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestLocationService_AddLocation(t *testing.T) {
+	t.Parallel()
+	lat, long, far := 52.52, 13.405, 200.0
+	berlin := LocationPayload{Name: "Berlin office", City: "Berlin", Country: "DE"}
+	full := make([]models.CompanyLocation, maxLocationsPerCompany)
+	cases := map[string]struct {
+		companyRepo  *mockCompanyRepository
+		memberRepo   *mockMemberRepository
+		locationRepo *mockLocationRepository
+		payload      LocationPayload
+		expSaved     models.CompanyLocation
+		expErr       string
+	}{
+		"company not found": {
+			companyRepo:  &mockCompanyRepository{err: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound)},
+			locationRepo: &mockLocationRepository{},
+			payload:      berlin,
+			expErr:       "company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+		},
+		"viewers cannot add locations": {
+			memberRepo:   &mockMemberRepository{role: models.RoleViewer},
+			locationRepo: &mockLocationRepository{},
+			payload:      berlin,
+			expErr:       "access to the company denied: the editor role is required, you are viewer",
+		},
+		"empty": {
+			locationRepo: &mockLocationRepository{},
+			expErr:       "invalid location: name must not be empty; city must not be empty; country must not be empty",
+		},
+		"too long": {
+			locationRepo: &mockLocationRepository{},
+			payload:      LocationPayload{Name: "Berlin", City: "Berlin", Country: "DE", PostalCode: strings.Repeat("1", maxPostalCodeLength+1)},
+			expErr:       "invalid location: postal_code must be at most 32 characters long",
+		},
+		"unknown country": {
+			locationRepo: &mockLocationRepository{},
+			payload:      LocationPayload{Name: "Berlin", City: "Berlin", Country: "DEU"},
+			expErr:       "invalid location: country must be an ISO 3166-1 alpha-2 country code",
+		},
+		"latitude without longitude": {
+			locationRepo: &mockLocationRepository{},
+			payload:      LocationPayload{Name: "Berlin", City: "Berlin", Country: "DE", Latitude: &lat},
+			expErr:       "invalid location: latitude must be given together with longitude",
+		},
+		"coordinates out of range": {
+			locationRepo: &mockLocationRepository{},
+			payload:      LocationPayload{Name: "Berlin", City: "Berlin", Country: "DE", Latitude: &far, Longitude: &far},
+			expErr:       "invalid location: latitude must be between -90 and 90; longitude must be between -180 and 180",
+		},
+		"too many locations": {
+			locationRepo: &mockLocationRepository{locations: full},
+			payload:      berlin,
+			expErr:       "invalid location: a company has at most 100 locations",
+		},
+		"company deleted meanwhile": {
+			locationRepo: &mockLocationRepository{err: fmt.Errorf("failed to save location: %w", repositories.ErrNotFound)},
+			payload:      berlin,
+			expSaved:     models.CompanyLocation{CompanyID: uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), Name: "Berlin office", City: "Berlin", Country: "DE"},
+			expErr:       "company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+		},
+		"success": {
+			locationRepo: &mockLocationRepository{},
+			payload:      LocationPayload{Name: " Berlin office ", Headquarters: true, City: "Berlin", Country: " de", Latitude: &lat, Longitude: &long},
+			expSaved: models.CompanyLocation{
+				CompanyID:    uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+				Name:         "Berlin office",
+				Headquarters: true,
+				City:         "Berlin",
+				Country:      "DE",
+				Latitude:     &lat,
+				Longitude:    &long,
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			companyRepo, memberRepo := tt.companyRepo, tt.memberRepo
+			if companyRepo == nil {
+				companyRepo = &mockCompanyRepository{}
+			}
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewLocationService(tt.locationRepo, companyRepo, memberRepo)
+			assert.NoError(t, err)
+			_, _, err = s.AddLocation(context.TODO(), uuid.New(), uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), tt.payload)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expSaved, tt.locationRepo.saved)
+		})
+	}
+}
+
+func TestLocationService_UpdateLocation(t *testing.T) {
+	t.Parallel()
+	locationID := uuid.MustParse("3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50")
+	locationRepo := &mockLocationRepository{err: fmt.Errorf("failed to update location: %w", repositories.ErrLocationNotFound)}
+	s, err := NewLocationService(locationRepo, &mockCompanyRepository{}, &mockMemberRepository{})
+	assert.NoError(t, err)
+	_, _, err = s.UpdateLocation(context.TODO(), uuid.New(), uuid.New(), locationID, LocationPayload{Name: "Paris", City: "Paris", Country: "FR"})
+	assert.EqualError(t, err, "location not found: 3f1c2a9e-7b5d-4d8e-9a6f-0c1b2d3e4f50")
+	assert.Equal(t, locationID, locationRepo.saved.ID)
+}
+
+func TestLocationService_RemoveLocation(t *testing.T) {
+	t.Parallel()
+	locationRepo := &mockLocationRepository{err: fmt.Errorf("failed to delete location: %w", repositories.ErrVersionConflict)}
+	s, err := NewLocationService(locationRepo, &mockCompanyRepository{}, &mockMemberRepository{})
+	assert.NoError(t, err)
+	_, err = s.RemoveLocation(context.TODO(), uuid.New(), uuid.New(), uuid.New())
+	assert.EqualError(t, err, "company version mismatch: company was modified concurrently")
+}
+
+// mockLocationRepository for testing.
+type mockLocationRepository struct {
+	locations []models.CompanyLocation
+	saved     models.CompanyLocation
+	err       error
+}
+
+func (m *mockLocationRepository) List(_ context.Context, _ uuid.UUID) ([]models.CompanyLocation, error) {
+	return m.locations, nil
+}
+
+func (m *mockLocationRepository) Find(_ context.Context, _, _ uuid.UUID) (models.CompanyLocation, error) {
+	return m.saved, m.err
+}
+
+func (m *mockLocationRepository) Save(_ context.Context, _ uuid.UUID, location models.CompanyLocation) (models.CompanyLocation, models.Company, error) {
+	m.saved = location
+	return location, models.Company{ID: location.CompanyID, Locations: []models.CompanyLocation{location}}, m.err
+}
+
+func (m *mockLocationRepository) Update(_ context.Context, _ uuid.UUID, location models.CompanyLocation) (models.CompanyLocation, models.Company, error) {
+	m.saved = location
+	return location, models.Company{ID: location.CompanyID, Locations: []models.CompanyLocation{location}}, m.err
+}
+
+func (m *mockLocationRepository) Delete(_ context.Context, _, companyID, _ uuid.UUID) (models.Company, error) {
+	return models.Company{ID: companyID}, m.err
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// CompanyMembers defines the functionality related to the access control lists of companies.
+type CompanyMembers interface {
+	ListMembers(ctx context.Context, userID, companyID uuid.UUID) ([]models.CompanyMember, error)
+	GrantMember(ctx context.Context, userID, companyID uuid.UUID, username string, role models.MemberRole) (models.CompanyMember, error)
+	RevokeMember(ctx context.Context, userID, companyID, memberID uuid.UUID) error
+}
+
+var (
+	// ErrMemberNotFound is returned when a user is not a member of a company.
+	ErrMemberNotFound = newError(ErrNotFound, "member_not_found", "member not found")
+	// ErrInvalidMember is returned when a user cannot be granted the requested access.
+	ErrInvalidMember = newError(ErrValidation, "invalid_member", "invalid member")
+)
+
+// MemberService represents the company access control list service.
+type MemberService struct {
+	companyRepo repositories.CompanyRepository
+	memberRepo  repositories.MemberRepository
+	userRepo    repositories.UserRepository
+	access      companyAccess
+}
+
+// NewMemberService creates a new member service.
+func NewMemberService(companyRepo repositories.CompanyRepository, memberRepo repositories.MemberRepository, userRepo repositories.UserRepository) (*MemberService, error) {
+	if companyRepo == nil {
+		return nil, errors.New("company repository is nil")
+	}
+
+	if memberRepo == nil {
+		return nil, errors.New("member repository is nil")
+	}
+
+	if userRepo == nil {
+		return nil, errors.New("user repository is nil")
+	}
+
+	return &MemberService{
+		companyRepo: companyRepo,
+		memberRepo:  memberRepo,
+		userRepo:    userRepo,
+		access:      companyAccess{memberRepo: memberRepo},
+	}, nil
+}
+
+// ListMembers lists the members of a company to a viewer of it.
+func (s *MemberService) ListMembers(ctx context.Context, userID, companyID uuid.UUID) ([]models.CompanyMember, error) {
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := s.memberRepo.List(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	return members, nil
+}
+
+// GrantMember gives the user with the username a role on a company, replacing the role they had.
+// Only owners manage members, and the role of the user the company belongs to never changes.
+func (s *MemberService) GrantMember(ctx context.Context, userID, companyID uuid.UUID, username string, role models.MemberRole) (models.CompanyMember, error) {
+	comp, err := s.findManaged(ctx, userID, companyID)
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+
+	if !role.Valid() {
+		return models.CompanyMember{}, fmt.Errorf("%w: role must be one of %s, %s or %s", ErrInvalidMember, models.RoleOwner, models.RoleEditor, models.RoleViewer)
+	}
+	user, err := s.userRepo.FindByUserName(ctx, username)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CompanyMember{}, fmt.Errorf("%w: user %q does not exist", ErrInvalidMember, username)
+	}
+	if err != nil {
+		return models.CompanyMember{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.ID == comp.UserID {
+		return models.CompanyMember{}, fmt.Errorf("%w: the company belongs to %q, transfer it to change their role", ErrInvalidMember, username)
+	}
+
+	member, err := s.memberRepo.Save(ctx, models.CompanyMember{
+		CompanyID: companyID,
+		UserID:    user.ID,
+		Role:      role,
+		GrantedBy: userID,
+	})
+	if err != nil {
+		return models.CompanyMember{}, fmt.Errorf("failed to grant member: %w", err)
+	}
+
+	return member, nil
+}
+
+// RevokeMember removes a member of a company. Owners revoke any member but the user the company
+// belongs to, other members can only leave.
+func (s *MemberService) RevokeMember(ctx context.Context, userID, companyID, memberID uuid.UUID) error {
+	var comp models.Company
+	var err error
+	if userID == memberID {
+		comp, err = s.findCompany(ctx, companyID)
+	} else {
+		comp, err = s.findManaged(ctx, userID, companyID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if memberID == comp.UserID {
+		return fmt.Errorf("%w: the company belongs to %s, transfer it before revoking their access", ErrInvalidMember, memberID)
+	}
+
+	err = s.memberRepo.Delete(ctx, companyID, memberID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrMemberNotFound, memberID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke member: %w", err)
+	}
+
+	return nil
+}
+
+// findManaged returns a company the user is an owner of.
+func (s *MemberService) findManaged(ctx context.Context, userID, companyID uuid.UUID) (models.Company, error) {
+	comp, err := s.findCompany(ctx, companyID)
+	if err != nil {
+		return models.Company{}, err
+	}
+
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleOwner); err != nil {
+		return models.Company{}, err
+	}
+
+	return comp, nil
+}
+
+// findCompany gets a company by id.
+func (s *MemberService) findCompany(ctx context.Context, companyID uuid.UUID) (models.Company, error) {
+	comp, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.Company{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return models.Company{}, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return comp, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMemberService(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		companyRepo repositories.CompanyRepository
+		memberRepo  repositories.MemberRepository
+		userRepo    repositories.UserRepository
+		expErr      string
+	}{
+		"company repo is nil": {
+			memberRepo: &mockMemberRepository{},
+			userRepo:   &mockTransferUserRepository{},
+			expErr:     "company repository is nil",
+		},
+		"member repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			userRepo:    &mockTransferUserRepository{},
+			expErr:      "member repository is nil",
+		},
+		"user repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+			expErr:      "user repository is nil",
+		},
+		"success": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+			userRepo:    &mockTransferUserRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewMemberService(tt.companyRepo, tt.memberRepo, tt.userRepo)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestMemberService_GrantMember(t *testing.T) {
+	t.Parallel()
+	company := models.Company{ID: uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), UserID: transferOwner.ID}
+	cases := map[string]struct {
+		memberRepo *mockMemberRepository
+		username   string
+		role       models.MemberRole
+		expErr     string
+		expSaved   models.CompanyMember
+	}{
+		"editors cannot manage members": {
+			memberRepo: &mockMemberRepository{role: models.RoleEditor},
+			username:   "recipient",
+			role:       models.RoleViewer,
+			expErr:     "access to the company denied: the owner role is required, you are editor",
+		},
+		"unknown role": {
+			memberRepo: &mockMemberRepository{},
+			username:   "recipient",
+			role:       "admin",
+			expErr:     "invalid member: role must be one of owner, editor or viewer",
+		},
+		"unknown user": {
+			memberRepo: &mockMemberRepository{},
+			username:   "nobody",
+			role:       models.RoleViewer,
+			expErr:     "invalid member: user \"nobody\" does not exist",
+		},
+		"the company owner": {
+			memberRepo: &mockMemberRepository{},
+			username:   "owner",
+			role:       models.RoleViewer,
+			expErr:     "invalid member: the company belongs to \"owner\", transfer it to change their role",
+		},
+		"success": {
+			memberRepo: &mockMemberRepository{},
+			username:   "recipient",
+			role:       models.RoleEditor,
+			expSaved:   models.CompanyMember{CompanyID: company.ID, UserID: transferRecipient.ID, Role: models.RoleEditor, GrantedBy: transferOwner.ID},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewMemberService(&mockCompanyRepository{singleCompany: company}, tt.memberRepo, newMockTransferUserRepository())
+			assert.NoError(t, err)
+			_, err = s.GrantMember(context.TODO(), transferOwner.ID, company.ID, tt.username, tt.role)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expSaved, tt.memberRepo.saved)
+			}
+		})
+	}
+}
+
+func TestMemberService_RevokeMember(t *testing.T) {
+	t.Parallel()
+	company := models.Company{ID: uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), UserID: transferOwner.ID}
+	cases := map[string]struct {
+		memberRepo *mockMemberRepository
+		userID     uuid.UUID
+		memberID   uuid.UUID
+		expErr     string
+	}{
+		"viewers cannot revoke others": {
+			memberRepo: &mockMemberRepository{role: models.RoleViewer},
+			userID:     transferStranger.ID,
+			memberID:   transferRecipient.ID,
+			expErr:     "access to the company denied: the owner role is required, you are viewer",
+		},
+		"members can leave": {
+			memberRepo: &mockMemberRepository{role: models.RoleViewer},
+			userID:     transferRecipient.ID,
+			memberID:   transferRecipient.ID,
+		},
+		"the company owner stays": {
+			memberRepo: &mockMemberRepository{},
+			userID:     transferOwner.ID,
+			memberID:   transferOwner.ID,
+			expErr:     "invalid member: the company belongs to b6000e46-809f-4684-abd9-dc8f445b5ca9, transfer it before revoking their access",
+		},
+		"not a member": {
+			memberRepo: &mockMemberRepository{deleteErr: fmt.Errorf("failed to delete member: %w", repositories.ErrNotFound)},
+			userID:     transferOwner.ID,
+			memberID:   transferStranger.ID,
+			expErr:     "member not found: 9a1d5c2e-33b7-4c4e-8f0c-1d6b2a7e9f33",
+		},
+		"owner revokes": {
+			memberRepo: &mockMemberRepository{},
+			userID:     transferOwner.ID,
+			memberID:   transferRecipient.ID,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewMemberService(&mockCompanyRepository{singleCompany: company}, tt.memberRepo, newMockTransferUserRepository())
+			assert.NoError(t, err)
+			err = s.RevokeMember(context.TODO(), tt.userID, company.ID, tt.memberID)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMemberService_ListMembers(t *testing.T) {
+	t.Parallel()
+	s, err := NewMemberService(&mockCompanyRepository{}, &mockMemberRepository{err: errors.New("member repo error")}, newMockTransferUserRepository())
+	assert.NoError(t, err)
+	_, err = s.ListMembers(context.TODO(), uuid.New(), uuid.New())
+	assert.EqualError(t, err, "failed to check access: member repo error")
+
+	s, err = NewMemberService(&mockCompanyRepository{}, &mockMemberRepository{role: models.RoleViewer}, newMockTransferUserRepository())
+	assert.NoError(t, err)
+	members, err := s.ListMembers(context.TODO(), uuid.New(), uuid.New())
+	assert.NoError(t, err)
+	assert.Len(t, members, 1)
+}
+
+// mockMemberRepository for testing, every user has the role it is given, owner when it has none.
+type mockMemberRepository struct {
+	role      models.MemberRole
+	saved     models.CompanyMember
+	err       error
+	deleteErr error
+	hidden    []uuid.UUID // companies the user is not a member of, every other one is visible
+}
+
+func (m *mockMemberRepository) List(ctx context.Context, companyID uuid.UUID) ([]models.CompanyMember, error) {
+	member, err := m.Find(ctx, companyID, uuid.Nil)
+	return []models.CompanyMember{member}, err
+}
+
+func (m *mockMemberRepository) Find(_ context.Context, companyID, userID uuid.UUID) (models.CompanyMember, error) {
+	if m.err != nil {
+		return models.CompanyMember{}, m.err
+	}
+	role := m.role
+	if role == "" {
+		role = models.RoleOwner
+	}
+	return models.CompanyMember{CompanyID: companyID, UserID: userID, Role: role}, nil
+}
+
+func (m *mockMemberRepository) MemberOf(_ context.Context, _ uuid.UUID, companyIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	ids := []uuid.UUID{}
+	for _, id := range companyIDs {
+		hidden := false
+		for _, h := range m.hidden {
+			hidden = hidden || h == id
+		}
+		if !hidden {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (m *mockMemberRepository) Save(_ context.Context, member models.CompanyMember) (models.CompanyMember, error) {
+	m.saved = member
+	return member, nil
+}
+
+func (m *mockMemberRepository) Delete(_ context.Context, _, _ uuid.UUID) error {
+	return m.deleteErr
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// CompanyRevisions defines the functionality related to the revision history of companies.
+type CompanyRevisions interface {
+	ListRevisions(ctx context.Context, userID, companyID uuid.UUID) ([]Revision, error)
+	GetRevision(ctx context.Context, userID, companyID uuid.UUID, revision int) (Revision, error)
+}
+
+// ErrRevisionNotFound is returned when a company has no revision with the requested number.
+var ErrRevisionNotFound = newError(ErrNotFound, "revision_not_found", "revision not found")
+
+// Revision represents a recorded change of a company and how it differs from the revision before it.
+type Revision struct {
+	Number    int
+	Action    models.RevisionAction
+	ActorID   uuid.UUID
+	CreatedAt time.Time
+	Company   models.Company
+	Changes   []FieldChange
+}
+
+// FieldChange represents a field whose value differs from the previous revision.
+// From is null for the first revision of a company.
+type FieldChange struct {
+	Field string
+	From  json.RawMessage
+	To    json.RawMessage
+}
+
+// revisionFields lists the fields compared between revisions, bookkeeping fields
+// like the version and timestamps change every time and are left out.
+var revisionFields = []struct {
+	name  string
+	value func(models.Company) any
+}{
+	{"Name", func(c models.Company) any { return c.Name }},
+	{"Description", func(c models.Company) any { return c.Description }},
+	{"EmployeesAmount", func(c models.Company) any { return c.EmployeesAmount }},
+	{"Registered", func(c models.Company) any { return c.Registered }},
+	{"Status", func(c models.Company) any { return c.Status }},
+	{"Type", func(c models.Company) any { return c.Type }},
+	{"UserID", func(c models.Company) any { return c.UserID }},
+	{"ParentID", func(c models.Company) any { return c.ParentID }},
+	{"DeletedAt", func(c models.Company) any { return c.DeletedAt }},
+}
+
+// RevisionService represents the company revision history service.
+type RevisionService struct {
+	revisionRepo repositories.RevisionRepository
+	access       companyAccess
+}
+
+// NewRevisionService creates a new revision service, the history of a company is only shown to its members.
+func NewRevisionService(revisionRepo repositories.RevisionRepository, memberRepo repositories.MemberRepository) (*RevisionService, error) {
+	if revisionRepo == nil {
+		return nil, errors.New("revision repository is nil")
+	}
+
+	if memberRepo == nil {
+		return nil, errors.New("member repository is nil")
+	}
+
+	return &RevisionService{
+		revisionRepo: revisionRepo,
+		access:       companyAccess{memberRepo: memberRepo},
+	}, nil
+}
+
+// ListRevisions returns the history of a company to a viewer of it, oldest first.
+func (s *RevisionService) ListRevisions(ctx context.Context, userID, companyID uuid.UUID) ([]Revision, error) {
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	records, err := s.revisionRepo.List(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	revisions := make([]Revision, 0, len(records))
+	for i, record := range records {
+		var previous *models.CompanyRevision
+		if i > 0 {
+			previous = &records[i-1]
+		}
+
+		revision, err := newRevision(record, previous)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns a single revision of a company to a viewer of it.
+func (s *RevisionService) GetRevision(ctx context.Context, userID, companyID uuid.UUID, revision int) (Revision, error) {
+	if err := s.access.authorize(ctx, userID, companyID, models.RoleViewer); err != nil {
+		return Revision{}, err
+	}
+
+	record, previous, err := s.revisionRepo.Find(ctx, companyID, revision)
+	if errors.Is(err, repositories.ErrRevisionNotFound) {
+		return Revision{}, fmt.Errorf("%w: company %s has no revision %d", ErrRevisionNotFound, companyID, revision)
+	}
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return newRevision(record, previous)
+}
+
+// newRevision converts a stored revision and computes its changes against the previous one.
+func newRevision(record models.CompanyRevision, previous *models.CompanyRevision) (Revision, error) {
+	changes, err := diffCompanies(previous, record.Snapshot)
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to diff revision %d: %w", record.Revision, err)
+	}
+
+	return Revision{
+		Number:    record.Revision,
+		Action:    record.Action,
+		ActorID:   record.ActorID,
+		CreatedAt: record.CreatedAt,
+		Company:   record.Snapshot,
+		Changes:   changes,
+	}, nil
+}
+
+// diffCompanies returns the fields of current that differ from the previous revision,
+// every field is reported when there is no previous revision.
+func diffCompanies(previous *models.CompanyRevision, current models.Company) ([]FieldChange, error) {
+	changes := []FieldChange{}
+	for _, field := range revisionFields {
+		to, err := json.Marshal(field.value(current))
+		if err != nil {
+			return nil, err
+		}
+
+		from := json.RawMessage("null")
+		if previous != nil {
+			from, err = json.Marshal(field.value(previous.Snapshot))
+			if err != nil {
+				return nil, err
+			}
+			if string(from) == string(to) {
+				continue
+			}
+		}
+
+		changes = append(changes, FieldChange{Field: field.name, From: from, To: to})
+	}
+
+	return changes, nil
+}
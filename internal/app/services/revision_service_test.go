@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/common"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRevisionService(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		revisionRepo repositories.RevisionRepository
+		memberRepo   repositories.MemberRepository
+		expErr       string
+	}{
+		"revision repo is nil": {
+			memberRepo: &mockMemberRepository{},
+			expErr:     "revision repository is nil",
+		},
+		"member repo is nil": {
+			revisionRepo: &mockRevisionRepository{},
+			expErr:       "member repository is nil",
+		},
+		"success": {
+			revisionRepo: &mockRevisionRepository{},
+			memberRepo:   &mockMemberRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewRevisionService(tt.revisionRepo, tt.memberRepo)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestRevisionService_ListRevisions(t *testing.T) {
+	t.Parallel()
+	created := models.CompanyRevision{
+		Revision: 1,
+		Action:   models.RevisionCreated,
+		Snapshot: models.Company{Name: "Acme", EmployeesAmount: 10, Type: common.Corporations},
+	}
+	updated := models.CompanyRevision{
+		Revision: 2,
+		Action:   models.RevisionUpdated,
+		Snapshot: models.Company{Name: "Acme", Description: "Anvils", EmployeesAmount: 12, Type: common.Corporations, Version: 2},
+	}
+	cases := map[string]struct {
+		revisionRepo *mockRevisionRepository
+		memberRepo   *mockMemberRepository
+		expChanges   [][]FieldChange
+		expErr       string
+	}{
+		"not a member": {
+			revisionRepo: &mockRevisionRepository{revisions: []models.CompanyRevision{created, updated}},
+			memberRepo:   &mockMemberRepository{err: fmt.Errorf("failed to find member: %w", repositories.ErrNotFound)},
+			expErr:       "access to the company denied: the viewer role is required",
+		},
+		"revision repo error": {
+			revisionRepo: &mockRevisionRepository{err: errors.New("revision repo error")},
+			expErr:       "failed to list revisions: revision repo error",
+		},
+		"no history": {
+			revisionRepo: &mockRevisionRepository{},
+			expChanges:   [][]FieldChange{},
+		},
+		"success": {
+			revisionRepo: &mockRevisionRepository{revisions: []models.CompanyRevision{created, updated}},
+			expChanges: [][]FieldChange{
+				{
+					{Field: "Name", From: json.RawMessage(`null`), To: json.RawMessage(`"Acme"`)},
+					{Field: "Description", From: json.RawMessage(`null`), To: json.RawMessage(`""`)},
+					{Field: "EmployeesAmount", From: json.RawMessage(`null`), To: json.RawMessage(`10`)},
+					{Field: "Registered", From: json.RawMessage(`null`), To: json.RawMessage(`false`)},
+					{Field: "Status", From: json.RawMessage(`null`), To: json.RawMessage(`""`)},
+					{Field: "Type", From: json.RawMessage(`null`), To: json.RawMessage(`"Corporations"`)},
+					{Field: "UserID", From: json.RawMessage(`null`), To: json.RawMessage(`"00000000-0000-0000-0000-000000000000"`)},
+					{Field: "ParentID", From: json.RawMessage(`null`), To: json.RawMessage(`null`)},
+					{Field: "DeletedAt", From: json.RawMessage(`null`), To: json.RawMessage(`null`)},
+				},
+				{
+					{Field: "Description", From: json.RawMessage(`""`), To: json.RawMessage(`"Anvils"`)},
+					{Field: "EmployeesAmount", From: json.RawMessage(`10`), To: json.RawMessage(`12`)},
+				},
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memberRepo := tt.memberRepo
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{role: models.RoleViewer}
+			}
+			s, err := NewRevisionService(tt.revisionRepo, memberRepo)
+			assert.NoError(t, err)
+
+			revisions, err := s.ListRevisions(context.TODO(), uuid.New(), uuid.New())
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			changes := make([][]FieldChange, 0, len(revisions))
+			for _, revision := range revisions {
+				changes = append(changes, revision.Changes)
+			}
+			assert.Equal(t, tt.expChanges, changes)
+		})
+	}
+}
+
+func TestRevisionService_GetRevision(t *testing.T) {
+	t.Parallel()
+	companyID := uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9")
+	cases := map[string]struct {
+		revisionRepo *mockRevisionRepository
+		expRevision  Revision
+		expErr       string
+	}{
+		"unknown revision": {
+			revisionRepo: &mockRevisionRepository{err: repositories.ErrRevisionNotFound},
+			expErr:       "revision not found: company ca8fc620-509a-40ac-8cc0-525c37c9c4b9 has no revision 3",
+		},
+		"revision repo error": {
+			revisionRepo: &mockRevisionRepository{err: errors.New("revision repo error")},
+			expErr:       "failed to get revision: revision repo error",
+		},
+		"success": {
+			revisionRepo: &mockRevisionRepository{revisions: []models.CompanyRevision{
+				{Revision: 2, Action: models.RevisionUpdated, Snapshot: models.Company{Name: "Acme", Registered: true}},
+				{Revision: 3, Action: models.RevisionUpdated, Snapshot: models.Company{Name: "Acme Corp", Registered: true}},
+			}},
+			expRevision: Revision{
+				Number:  3,
+				Action:  models.RevisionUpdated,
+				Company: models.Company{Name: "Acme Corp", Registered: true},
+				Changes: []FieldChange{{Field: "Name", From: json.RawMessage(`"Acme"`), To: json.RawMessage(`"Acme Corp"`)}},
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewRevisionService(tt.revisionRepo, &mockMemberRepository{})
+			assert.NoError(t, err)
+
+			revision, err := s.GetRevision(context.TODO(), uuid.New(), companyID, 3)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expRevision, revision)
+		})
+	}
+}
+
+type mockRevisionRepository struct {
+	revisions []models.CompanyRevision
+	err       error
+}
+
+func (m *mockRevisionRepository) List(_ context.Context, _ uuid.UUID) ([]models.CompanyRevision, error) {
+	return m.revisions, m.err
+}
+
+// Find returns the last revision of the mock and the one before it.
+func (m *mockRevisionRepository) Find(_ context.Context, _ uuid.UUID, _ int) (models.CompanyRevision, *models.CompanyRevision, error) {
+	if m.err != nil {
+		return models.CompanyRevision{}, nil, m.err
+	}
+
+	last := len(m.revisions) - 1
+	if last == 0 {
+		return m.revisions[0], nil, nil
+	}
+	return m.revisions[last], &m.revisions[last-1], nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/iNDicat0r/company/internal/app/search"
+)
+
+// snippetWords is the length of the description excerpt returned with a search hit.
+const snippetWords = 30
+
+// ErrInvalidSearchQuery is returned when a search cannot be run with the given parameters.
+var ErrInvalidSearchQuery = newError(ErrInvalidRequest, "invalid_search_query", "invalid search query")
+
+// CompanySearcher defines the functionality of the company search.
+type CompanySearcher interface {
+	Search(ctx context.Context, userID uuid.UUID, query string, limit, offset int) (SearchResult, error)
+}
+
+// SearchResult represents a page of search hits and the total number of matching companies.
+type SearchResult struct {
+	Hits  []SearchHit
+	Total int
+}
+
+// SearchHit represents a company matched by a search.
+type SearchHit struct {
+	Company    models.Company
+	Score      float64
+	Highlights SearchHighlights
+}
+
+// SearchHighlights holds the HTML-escaped matched fields with the matching words wrapped in <em> tags.
+type SearchHighlights struct {
+	Name        string
+	Description string
+}
+
+// SearchService represents the company search service.
+type SearchService struct {
+	searchRepo repositories.SearchRepository
+}
+
+// NewSearchService creates a new search service.
+func NewSearchService(searchRepo repositories.SearchRepository) (*SearchService, error) {
+	if searchRepo == nil {
+		return nil, errors.New("search repository is nil")
+	}
+
+	return &SearchService{
+		searchRepo: searchRepo,
+	}, nil
+}
+
+// Search the companies the user is a member of by words of their name and description.
+func (s *SearchService) Search(ctx context.Context, userID uuid.UUID, query string, limit, offset int) (SearchResult, error) {
+	terms := uniqueTerms(search.Tokenize(query))
+	if len(terms) == 0 {
+		return SearchResult{}, fmt.Errorf("%w: no searchable terms", ErrInvalidSearchQuery)
+	}
+
+	if offset < 0 {
+		return SearchResult{}, fmt.Errorf("%w: negative offset", ErrInvalidSearchQuery)
+	}
+
+	switch {
+	case limit < 0:
+		return SearchResult{}, fmt.Errorf("%w: negative limit", ErrInvalidSearchQuery)
+	case limit == 0:
+		limit = defaultPageSize
+	case limit > maxPageSize:
+		limit = maxPageSize
+	}
+
+	hits, total, err := s.searchRepo.Search(ctx, userID, terms, limit, offset)
+	if err != nil {
+		return SearchResult{}, fmt.Errorf("failed to search companies: %w", err)
+	}
+
+	result := SearchResult{
+		Hits:  make([]SearchHit, 0, len(hits)),
+		Total: total,
+	}
+	for _, hit := range hits {
+		result.Hits = append(result.Hits, SearchHit{
+			Company: hit.Company,
+			Score:   hit.Score,
+			Highlights: SearchHighlights{
+				Name:        search.Highlight(hit.Company.Name, terms),
+				Description: search.Snippet(hit.Company.Description, terms, snippetWords),
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// uniqueTerms removes the duplicated terms keeping their order.
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]struct{}, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+		unique = append(unique, term)
+	}
+
+	return unique
+}
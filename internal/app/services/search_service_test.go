@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSearchService(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		searchRepo repositories.SearchRepository
+		expErr     string
+	}{
+		"search repo is nil": {
+			expErr: "search repository is nil",
+		},
+		"success": {
+			searchRepo: &mockSearchRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewSearchService(tt.searchRepo)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestSearchService_Search(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		searchRepo    *mockSearchRepository
+		query         string
+		limit         int
+		offset        int
+		expTerms      []string
+		expLimit      int
+		expHighlights SearchHighlights
+		expErr        string
+	}{
+		"no terms": {
+			searchRepo: &mockSearchRepository{},
+			query:      " ?! ",
+			expErr:     "invalid search query: no searchable terms",
+		},
+		"negative offset": {
+			searchRepo: &mockSearchRepository{},
+			query:      "acme",
+			offset:     -1,
+			expErr:     "invalid search query: negative offset",
+		},
+		"search repo error": {
+			searchRepo: &mockSearchRepository{err: errors.New("search repo error")},
+			query:      "acme",
+			expErr:     "failed to search companies: search repo error",
+		},
+		"success": {
+			searchRepo: &mockSearchRepository{
+				hits: []repositories.SearchHit{{
+					Company: models.Company{Name: "Acme Corp", Description: "Anvils for coyotes"},
+					Score:   1.5,
+				}},
+				total: 1,
+			},
+			query:    "ACME anvil acme",
+			limit:    500,
+			expTerms: []string{"acme", "anvil"},
+			expLimit: maxPageSize,
+			expHighlights: SearchHighlights{
+				Name:        "<em>Acme</em> Corp",
+				Description: "<em>Anvils</em> for coyotes",
+			},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewSearchService(tt.searchRepo)
+			assert.NoError(t, err)
+			result, err := s.Search(context.TODO(), uuid.New(), tt.query, tt.limit, tt.offset)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expTerms, tt.searchRepo.terms)
+				assert.Equal(t, tt.expLimit, tt.searchRepo.limit)
+				assert.Equal(t, 1, result.Total)
+				assert.Equal(t, tt.expHighlights, result.Hits[0].Highlights)
+			}
+		})
+	}
+}
+
+// mockSearchRepository for testing
+type mockSearchRepository struct {
+	hits  []repositories.SearchHit
+	total int
+	err   error
+	terms []string
+	limit int
+}
+
+func (m *mockSearchRepository) Search(_ context.Context, _ uuid.UUID, terms []string, limit, _ int) ([]repositories.SearchHit, int, error) {
+	m.terms = terms
+	m.limit = limit
+	return m.hits, m.total, m.err
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/naming"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// limits of company tags.
+const (
+	maxTagLength       = 32
+	maxTagsPerCompany  = 20
+	defaultSuggestions = 10
+	maxSuggestions     = 50
+)
+
+var (
+	// ErrInvalidTag is returned when a tag cannot be given to a company.
+	ErrInvalidTag = newError(ErrValidation, "invalid_tag", "invalid tag")
+	// ErrTagNotFound is returned when removing a tag a company does not have.
+	ErrTagNotFound = newError(ErrNotFound, "tag_not_found", "tag not found")
+)
+
+// CompanyTags defines the functionality related to tagging companies.
+type CompanyTags interface {
+	ListTags(ctx context.Context, userID, companyID uuid.UUID) ([]models.Tag, error)
+	AddTag(ctx context.Context, userID, companyID uuid.UUID, label string) ([]models.Tag, error)
+	RemoveTag(ctx context.Context, userID, companyID uuid.UUID, label string) error
+	SuggestTags(ctx context.Context, userID uuid.UUID, prefix string, limit int) ([]TagUsage, error)
+}
+
+// TagUsage represents a tag and the number of companies using it.
+type TagUsage struct {
+	Tag       models.Tag
+	Companies int
+}
+
+// TagService represents the company tag service.
+type TagService struct {
+	tagRepo     repositories.TagRepository
+	companyRepo repositories.CompanyRepository
+	access      companyAccess
+}
+
+// NewTagService creates a new tag service, the tags of a company are changed by its editors.
+func NewTagService(tagRepo repositories.TagRepository, companyRepo repositories.CompanyRepository, memberRepo repositories.MemberRepository) (*TagService, error) {
+	if tagRepo == nil {
+		return nil, errors.New("tag repository is nil")
+	}
+
+	if companyRepo == nil {
+		return nil, errors.New("company repository is nil")
+	}
+
+	if memberRepo == nil {
+		return nil, errors.New("member repository is nil")
+	}
+
+	return &TagService{
+		tagRepo:     tagRepo,
+		companyRepo: companyRepo,
+		access:      companyAccess{memberRepo: memberRepo},
+	}, nil
+}
+
+// ListTags lists the tags of a company the user can view.
+func (s *TagService) ListTags(ctx context.Context, userID, companyID uuid.UUID) ([]models.Tag, error) {
+	if err := s.findViewable(ctx, userID, companyID); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.ListByCompany(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// AddTag tags a company on behalf of an editor of it and returns the tags the company has.
+// Tags are matched ignoring case, adding a tag the company already has changes nothing.
+func (s *TagService) AddTag(ctx context.Context, userID, companyID uuid.UUID, label string) ([]models.Tag, error) {
+	if err := s.findEditable(ctx, userID, companyID); err != nil {
+		return nil, err
+	}
+
+	tag, err := newTag(label)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.ListByCompany(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	for _, t := range tags {
+		if t.Name == tag.Name {
+			return tags, nil
+		}
+	}
+	if len(tags) >= maxTagsPerCompany {
+		return nil, fmt.Errorf("%w: a company has at most %d tags", ErrInvalidTag, maxTagsPerCompany)
+	}
+
+	if err := s.tagRepo.Attach(ctx, userID, companyID, tag); err != nil {
+		return nil, fmt.Errorf("failed to add tag: %w", err)
+	}
+
+	return s.ListTags(ctx, userID, companyID)
+}
+
+// RemoveTag removes a tag from a company on behalf of an editor of it.
+func (s *TagService) RemoveTag(ctx context.Context, userID, companyID uuid.UUID, label string) error {
+	if err := s.findEditable(ctx, userID, companyID); err != nil {
+		return err
+	}
+
+	err := s.tagRepo.Detach(ctx, companyID, naming.Key(strings.TrimSpace(label)))
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: the company is not tagged %q", ErrTagNotFound, label)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove tag: %w", err)
+	}
+
+	return nil
+}
+
+// SuggestTags returns the tags starting with the prefix, ignoring case, the most used first.
+// Only the companies the user is a member of are looked at.
+func (s *TagService) SuggestTags(ctx context.Context, userID uuid.UUID, prefix string, limit int) ([]TagUsage, error) {
+	switch {
+	case limit < 0:
+		return nil, fmt.Errorf("%w: negative limit", ErrInvalidListQuery)
+	case limit == 0:
+		limit = defaultSuggestions
+	case limit > maxSuggestions:
+		limit = maxSuggestions
+	}
+
+	usage, err := s.tagRepo.Usage(ctx, userID, naming.Key(strings.TrimSpace(prefix)), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest tags: %w", err)
+	}
+
+	suggestions := make([]TagUsage, 0, len(usage))
+	for _, u := range usage {
+		suggestions = append(suggestions, TagUsage(u))
+	}
+
+	return suggestions, nil
+}
+
+// newTag validates a label and returns the tag it stands for.
+func newTag(label string) (models.Tag, error) {
+	label = strings.TrimSpace(label)
+	switch {
+	case label == "":
+		return models.Tag{}, fmt.Errorf("%w: must not be empty", ErrInvalidTag)
+	case utf8.RuneCountInString(label) > maxTagLength:
+		return models.Tag{}, fmt.Errorf("%w: must be at most %d characters long", ErrInvalidTag, maxTagLength)
+	case strings.IndexFunc(label, func(r rune) bool { return r == ',' || unicode.IsControl(r) }) >= 0:
+		// commas separate the tags of listing filters
+		return models.Tag{}, fmt.Errorf("%w: must not contain commas or control characters", ErrInvalidTag)
+	}
+
+	return models.Tag{Name: naming.Key(label), Label: label}, nil
+}
+
+// findEditable makes sure the company exists and the user is an editor of it.
+func (s *TagService) findEditable(ctx context.Context, userID, companyID uuid.UUID) error {
+	if err := s.findCompany(ctx, companyID); err != nil {
+		return err
+	}
+
+	return s.access.authorize(ctx, userID, companyID, models.RoleEditor)
+}
+
+// findViewable makes sure the company exists and the user can view it.
+func (s *TagService) findViewable(ctx context.Context, userID, companyID uuid.UUID) error {
+	if err := s.findCompany(ctx, companyID); err != nil {
+		return err
+	}
+
+	return s.access.authorize(ctx, userID, companyID, models.RoleViewer)
+}
+
+// findCompany makes sure the company exists.
+func (s *TagService) findCompany(ctx context.Context, companyID uuid.UUID) error {
+	_, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTagService(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		tagRepo     repositories.TagRepository
+		companyRepo repositories.CompanyRepository
+		memberRepo  repositories.MemberRepository
+		expErr      string
+	}{
+		"tag repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+			expErr:      "tag repository is nil",
+		},
+		"company repo is nil": {
+			tagRepo:    &mockTagRepository{},
+			memberRepo: &mockMemberRepository{},
+			expErr:     "company repository is nil",
+		},
+		"member repo is nil": {
+			tagRepo:     &mockTagRepository{},
+			companyRepo: &mockCompanyRepository{},
+			expErr:      "member repository is nil",
+		},
+		"success": {
+			tagRepo:     &mockTagRepository{},
+			companyRepo: &mockCompanyRepository{},
+			memberRepo:  &mockMemberRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewTagService(tt.tagRepo, tt.companyRepo, tt.memberRepo)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestTagService_AddTag(t *testing.T) {
+	t.Parallel()
+	eu := models.Tag{Name: "eu", Label: "EU"}
+	full := make([]models.Tag, maxTagsPerCompany)
+	for i := range full {
+		full[i] = models.Tag{Name: fmt.Sprintf("tag %d", i)}
+	}
+	cases := map[string]struct {
+		companyRepo *mockCompanyRepository
+		memberRepo  *mockMemberRepository
+		tagRepo     *mockTagRepository
+		label       string
+		expAttached models.Tag
+		expErr      string
+	}{
+		"company not found": {
+			companyRepo: &mockCompanyRepository{err: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound)},
+			tagRepo:     &mockTagRepository{},
+			label:       "EU",
+			expErr:      "company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+		},
+		"viewers cannot tag": {
+			memberRepo: &mockMemberRepository{role: models.RoleViewer},
+			tagRepo:    &mockTagRepository{},
+			label:      "EU",
+			expErr:     "access to the company denied: the editor role is required, you are viewer",
+		},
+		"empty": {
+			tagRepo: &mockTagRepository{},
+			label:   "  ",
+			expErr:  "invalid tag: must not be empty",
+		},
+		"too long": {
+			tagRepo: &mockTagRepository{},
+			label:   strings.Repeat("a", maxTagLength+1),
+			expErr:  "invalid tag: must be at most 32 characters long",
+		},
+		"comma": {
+			tagRepo: &mockTagRepository{},
+			label:   "EU,US",
+			expErr:  "invalid tag: must not contain commas or control characters",
+		},
+		"too many tags": {
+			tagRepo: &mockTagRepository{tags: full},
+			label:   "EU",
+			expErr:  "invalid tag: a company has at most 20 tags",
+		},
+		"already tagged ignoring case": {
+			tagRepo: &mockTagRepository{tags: []models.Tag{eu}},
+			label:   "eu",
+		},
+		"success": {
+			tagRepo:     &mockTagRepository{},
+			label:       " EU ",
+			expAttached: eu,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			companyRepo, memberRepo := tt.companyRepo, tt.memberRepo
+			if companyRepo == nil {
+				companyRepo = &mockCompanyRepository{}
+			}
+			if memberRepo == nil {
+				memberRepo = &mockMemberRepository{}
+			}
+			s, err := NewTagService(tt.tagRepo, companyRepo, memberRepo)
+			assert.NoError(t, err)
+			_, err = s.AddTag(context.TODO(), uuid.New(), uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), tt.label)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expAttached, tt.tagRepo.attached)
+		})
+	}
+}
+
+func TestTagService_RemoveTag(t *testing.T) {
+	t.Parallel()
+	tagRepo := &mockTagRepository{detachErr: fmt.Errorf("failed to detach tag: %w", repositories.ErrNotFound)}
+	s, err := NewTagService(tagRepo, &mockCompanyRepository{}, &mockMemberRepository{})
+	assert.NoError(t, err)
+	err = s.RemoveTag(context.TODO(), uuid.New(), uuid.New(), "EU")
+	assert.EqualError(t, err, "tag not found: the company is not tagged \"EU\"")
+	assert.Equal(t, "eu", tagRepo.detached, "tags are removed ignoring case")
+}
+
+func TestTagService_SuggestTags(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		tagRepo  *mockTagRepository
+		prefix   string
+		limit    int
+		expLimit int
+		expErr   string
+	}{
+		"negative limit": {
+			tagRepo: &mockTagRepository{},
+			limit:   -1,
+			expErr:  "invalid list query: negative limit",
+		},
+		"tag repo error": {
+			tagRepo: &mockTagRepository{err: errors.New("tag repo error")},
+			expErr:  "failed to suggest tags: tag repo error",
+		},
+		"default limit": {
+			tagRepo:  &mockTagRepository{},
+			prefix:   "Pri",
+			expLimit: defaultSuggestions,
+		},
+		"limit is capped": {
+			tagRepo:  &mockTagRepository{},
+			limit:    1000,
+			expLimit: maxSuggestions,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewTagService(tt.tagRepo, &mockCompanyRepository{}, &mockMemberRepository{})
+			assert.NoError(t, err)
+			_, err = s.SuggestTags(context.TODO(), uuid.New(), tt.prefix, tt.limit)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, strings.ToLower(tt.prefix), tt.tagRepo.prefix)
+				assert.Equal(t, tt.expLimit, tt.tagRepo.limit)
+			}
+		})
+	}
+}
+
+// mockTagRepository for testing.
+type mockTagRepository struct {
+	tags      []models.Tag
+	attached  models.Tag
+	detached  string
+	detachErr error
+	prefix    string
+	limit     int
+	err       error
+}
+
+func (m *mockTagRepository) ListByCompany(_ context.Context, _ uuid.UUID) ([]models.Tag, error) {
+	return m.tags, m.err
+}
+
+func (m *mockTagRepository) Attach(_ context.Context, _, _ uuid.UUID, tag models.Tag) error {
+	m.attached = tag
+	return m.err
+}
+
+func (m *mockTagRepository) Detach(_ context.Context, _ uuid.UUID, name string) error {
+	m.detached = name
+	return m.detachErr
+}
+
+func (m *mockTagRepository) Usage(_ context.Context, _ uuid.UUID, prefix string, limit int) ([]repositories.TagUsage, error) {
+	m.prefix, m.limit = prefix, limit
+	return nil, m.err
+}
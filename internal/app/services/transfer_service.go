@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+)
+
+// CompanyTransfers defines the functionality related to company ownership transfers.
+type CompanyTransfers interface {
+	InitiateTransfer(ctx context.Context, userID, companyID uuid.UUID, recipient string) (models.CompanyTransfer, error)
+	ListTransfers(ctx context.Context, userID uuid.UUID) ([]models.CompanyTransfer, error)
+	AcceptTransfer(ctx context.Context, userID, transferID uuid.UUID) (models.CompanyTransfer, models.Company, error)
+	CancelTransfer(ctx context.Context, userID, transferID uuid.UUID) (models.CompanyTransfer, error)
+}
+
+var (
+	// ErrTransferNotFound is returned when a transfer does not exist.
+	ErrTransferNotFound = newError(ErrNotFound, "transfer_not_found", "transfer not found")
+	// ErrTransferPending is returned when transferring a company that already has a pending transfer.
+	ErrTransferPending = newError(ErrConflict, "transfer_pending", "company has a pending transfer")
+	// ErrTransferResolved is returned when accepting or cancelling a transfer that is no longer pending.
+	ErrTransferResolved = newError(ErrConflict, "transfer_resolved", "transfer is no longer pending")
+	// ErrTransferForbidden is returned when a user takes part in a transfer they have no say in.
+	ErrTransferForbidden = newError(ErrForbidden, "transfer_forbidden", "transfer not allowed")
+	// ErrInvalidTransfer is returned when a transfer cannot be made to the recipient.
+	ErrInvalidTransfer = newError(ErrValidation, "invalid_transfer", "invalid transfer")
+)
+
+// TransferService represents the company ownership transfer service.
+type TransferService struct {
+	transferRepo repositories.TransferRepository
+	companyRepo  repositories.CompanyRepository
+	userRepo     repositories.UserRepository
+}
+
+// NewTransferService creates a new transfer service.
+func NewTransferService(transferRepo repositories.TransferRepository, companyRepo repositories.CompanyRepository, userRepo repositories.UserRepository) (*TransferService, error) {
+	if transferRepo == nil {
+		return nil, errors.New("transfer repository is nil")
+	}
+
+	if companyRepo == nil {
+		return nil, errors.New("company repository is nil")
+	}
+
+	if userRepo == nil {
+		return nil, errors.New("user repository is nil")
+	}
+
+	return &TransferService{
+		transferRepo: transferRepo,
+		companyRepo:  companyRepo,
+		userRepo:     userRepo,
+	}, nil
+}
+
+// InitiateTransfer offers a company to the user with the recipient username. Only the owner
+// of the company or an admin can initiate a transfer, and a company has a single pending one.
+func (s *TransferService) InitiateTransfer(ctx context.Context, userID, companyID uuid.UUID, recipient string) (models.CompanyTransfer, error) {
+	comp, err := s.companyRepo.FindByID(ctx, companyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CompanyTransfer{}, fmt.Errorf("%w: %s", ErrCompanyNotFound, companyID)
+	}
+	if err != nil {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	if comp.UserID != userID {
+		admin, err := s.isAdmin(ctx, userID)
+		if err != nil {
+			return models.CompanyTransfer{}, err
+		}
+		if !admin {
+			return models.CompanyTransfer{}, fmt.Errorf("%w: only the owner of the company or an admin can transfer it", ErrTransferForbidden)
+		}
+	}
+
+	if recipient == "" {
+		return models.CompanyTransfer{}, fmt.Errorf("%w: recipient must not be empty", ErrInvalidTransfer)
+	}
+	to, err := s.userRepo.FindByUserName(ctx, recipient)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CompanyTransfer{}, fmt.Errorf("%w: user %q does not exist", ErrInvalidTransfer, recipient)
+	}
+	if err != nil {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to get recipient: %w", err)
+	}
+	if to.ID == comp.UserID {
+		return models.CompanyTransfer{}, fmt.Errorf("%w: user %q already owns the company", ErrInvalidTransfer, recipient)
+	}
+
+	transfer, err := s.transferRepo.Save(ctx, models.CompanyTransfer{
+		CompanyID:   comp.ID,
+		FromUserID:  comp.UserID,
+		ToUserID:    to.ID,
+		InitiatorID: userID,
+	})
+	if errors.Is(err, repositories.ErrTransferPending) {
+		return models.CompanyTransfer{}, fmt.Errorf("%w: cancel it before starting another one", ErrTransferPending)
+	}
+	if err != nil {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to initiate transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// ListTransfers lists the pending transfers the user gives or receives, admins see every pending transfer.
+func (s *TransferService) ListTransfers(ctx context.Context, userID uuid.UUID) ([]models.CompanyTransfer, error) {
+	admin, err := s.isAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var party *uuid.UUID
+	if !admin {
+		party = &userID
+	}
+	transfers, err := s.transferRepo.ListPending(ctx, party)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// AcceptTransfer makes the recipient of a pending transfer the owner of its company.
+// Only the recipient can accept, admins cannot accept on their behalf.
+func (s *TransferService) AcceptTransfer(ctx context.Context, userID, transferID uuid.UUID) (models.CompanyTransfer, models.Company, error) {
+	transfer, err := s.findTransfer(ctx, transferID)
+	if err != nil {
+		return models.CompanyTransfer{}, models.Company{}, err
+	}
+	if transfer.ToUserID != userID {
+		return models.CompanyTransfer{}, models.Company{}, fmt.Errorf("%w: only the recipient can accept a transfer", ErrTransferForbidden)
+	}
+
+	accepted, comp, err := s.transferRepo.Accept(ctx, transferID)
+	if err != nil {
+		return models.CompanyTransfer{}, models.Company{}, transferError(err, transfer)
+	}
+
+	return accepted, comp, nil
+}
+
+// CancelTransfer withdraws a pending transfer. The owner, the initiator and the recipient
+// of the transfer can cancel it, and so can admins.
+func (s *TransferService) CancelTransfer(ctx context.Context, userID, transferID uuid.UUID) (models.CompanyTransfer, error) {
+	transfer, err := s.findTransfer(ctx, transferID)
+	if err != nil {
+		return models.CompanyTransfer{}, err
+	}
+
+	if userID != transfer.FromUserID && userID != transfer.InitiatorID && userID != transfer.ToUserID {
+		admin, err := s.isAdmin(ctx, userID)
+		if err != nil {
+			return models.CompanyTransfer{}, err
+		}
+		if !admin {
+			return models.CompanyTransfer{}, fmt.Errorf("%w: only the parties of a transfer or an admin can cancel it", ErrTransferForbidden)
+		}
+	}
+
+	cancelled, err := s.transferRepo.Cancel(ctx, transferID, userID)
+	if err != nil {
+		return models.CompanyTransfer{}, transferError(err, transfer)
+	}
+
+	return cancelled, nil
+}
+
+// findTransfer gets a transfer by id.
+func (s *TransferService) findTransfer(ctx context.Context, transferID uuid.UUID) (models.CompanyTransfer, error) {
+	transfer, err := s.transferRepo.FindByID(ctx, transferID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.CompanyTransfer{}, fmt.Errorf("%w: %s", ErrTransferNotFound, transferID)
+	}
+	if err != nil {
+		return models.CompanyTransfer{}, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// isAdmin reports whether the user has admin privileges.
+func (s *TransferService) isAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user.Admin, nil
+}
+
+// transferError converts the repository errors of resolving a transfer.
+func transferError(err error, transfer models.CompanyTransfer) error {
+	switch {
+	case errors.Is(err, repositories.ErrTransferResolved):
+		return fmt.Errorf("%w: %s", ErrTransferResolved, transfer.ID)
+	case errors.Is(err, repositories.ErrNotFound):
+		return fmt.Errorf("%w: %s", ErrCompanyNotFound, transfer.CompanyID)
+	case errors.Is(err, repositories.ErrVersionConflict):
+		return fmt.Errorf("%w: the company changed while it was transferred, try again", ErrVersionMismatch)
+	}
+
+	return fmt.Errorf("failed to resolve transfer: %w", err)
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/iNDicat0r/company/internal/app/models"
+	"github.com/iNDicat0r/company/internal/app/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	transferOwner     = models.User{ID: uuid.MustParse("b6000e46-809f-4684-abd9-dc8f445b5ca9"), Username: "owner"}
+	transferRecipient = models.User{ID: uuid.MustParse("5b2a5d0e-4c0b-4e47-9d8e-4f9a1b4f3c11"), Username: "recipient"}
+	transferAdmin     = models.User{ID: uuid.MustParse("0f5d8a44-4b54-4bd4-9a39-34a1c3a5cf02"), Username: "admin", Admin: true}
+	transferStranger  = models.User{ID: uuid.MustParse("9a1d5c2e-33b7-4c4e-8f0c-1d6b2a7e9f33"), Username: "stranger"}
+)
+
+func TestNewTransferService(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		transferRepo repositories.TransferRepository
+		companyRepo  repositories.CompanyRepository
+		userRepo     repositories.UserRepository
+		expErr       string
+	}{
+		"transfer repo is nil": {
+			companyRepo: &mockCompanyRepository{},
+			userRepo:    &mockTransferUserRepository{},
+			expErr:      "transfer repository is nil",
+		},
+		"company repo is nil": {
+			transferRepo: &mockTransferRepository{},
+			userRepo:     &mockTransferUserRepository{},
+			expErr:       "company repository is nil",
+		},
+		"user repo is nil": {
+			transferRepo: &mockTransferRepository{},
+			companyRepo:  &mockCompanyRepository{},
+			expErr:       "user repository is nil",
+		},
+		"success": {
+			transferRepo: &mockTransferRepository{},
+			companyRepo:  &mockCompanyRepository{},
+			userRepo:     &mockTransferUserRepository{},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewTransferService(tt.transferRepo, tt.companyRepo, tt.userRepo)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.Nil(t, s)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestTransferService_InitiateTransfer(t *testing.T) {
+	t.Parallel()
+	company := models.Company{ID: uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"), UserID: transferOwner.ID}
+	cases := map[string]struct {
+		companyRepo  *mockCompanyRepository
+		transferRepo *mockTransferRepository
+		userID       uuid.UUID
+		recipient    string
+		expErr       string
+		expSaved     models.CompanyTransfer
+	}{
+		"company not found": {
+			companyRepo:  &mockCompanyRepository{err: fmt.Errorf("failed to find company: %w", repositories.ErrNotFound)},
+			transferRepo: &mockTransferRepository{},
+			userID:       transferOwner.ID,
+			recipient:    "recipient",
+			expErr:       "company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+		},
+		"not the owner": {
+			companyRepo:  &mockCompanyRepository{singleCompany: company},
+			transferRepo: &mockTransferRepository{},
+			userID:       transferStranger.ID,
+			recipient:    "recipient",
+			expErr:       "transfer not allowed: only the owner of the company or an admin can transfer it",
+		},
+		"unknown recipient": {
+			companyRepo:  &mockCompanyRepository{singleCompany: company},
+			transferRepo: &mockTransferRepository{},
+			userID:       transferOwner.ID,
+			recipient:    "nobody",
+			expErr:       "invalid transfer: user \"nobody\" does not exist",
+		},
+		"empty recipient": {
+			companyRepo:  &mockCompanyRepository{singleCompany: company},
+			transferRepo: &mockTransferRepository{},
+			userID:       transferOwner.ID,
+			expErr:       "invalid transfer: recipient must not be empty",
+		},
+		"recipient is the owner": {
+			companyRepo:  &mockCompanyRepository{singleCompany: company},
+			transferRepo: &mockTransferRepository{},
+			userID:       transferAdmin.ID,
+			recipient:    "owner",
+			expErr:       "invalid transfer: user \"owner\" already owns the company",
+		},
+		"pending transfer": {
+			companyRepo:  &mockCompanyRepository{singleCompany: company},
+			transferRepo: &mockTransferRepository{err: fmt.Errorf("failed to save transfer: %w", repositories.ErrTransferPending)},
+			userID:       transferOwner.ID,
+			recipient:    "recipient",
+			expErr:       "company has a pending transfer: cancel it before starting another one",
+		},
+		"owner": {
+			companyRepo:  &mockCompanyRepository{singleCompany: company},
+			transferRepo: &mockTransferRepository{},
+			userID:       transferOwner.ID,
+			recipient:    "recipient",
+			expSaved:     models.CompanyTransfer{CompanyID: company.ID, FromUserID: transferOwner.ID, ToUserID: transferRecipient.ID, InitiatorID: transferOwner.ID},
+		},
+		"admin on behalf of the owner": {
+			companyRepo:  &mockCompanyRepository{singleCompany: company},
+			transferRepo: &mockTransferRepository{},
+			userID:       transferAdmin.ID,
+			recipient:    "recipient",
+			expSaved:     models.CompanyTransfer{CompanyID: company.ID, FromUserID: transferOwner.ID, ToUserID: transferRecipient.ID, InitiatorID: transferAdmin.ID},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewTransferService(tt.transferRepo, tt.companyRepo, newMockTransferUserRepository())
+			assert.NoError(t, err)
+			_, err = s.InitiateTransfer(context.TODO(), tt.userID, company.ID, tt.recipient)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expSaved, tt.transferRepo.saved)
+			}
+		})
+	}
+}
+
+func TestTransferService_ListTransfers(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		userID   uuid.UUID
+		expParty *uuid.UUID
+	}{
+		"user sees their transfers": {userID: transferOwner.ID, expParty: &transferOwner.ID},
+		"admin sees every transfer": {userID: transferAdmin.ID},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			transferRepo := &mockTransferRepository{}
+			s, err := NewTransferService(transferRepo, &mockCompanyRepository{}, newMockTransferUserRepository())
+			assert.NoError(t, err)
+			_, err = s.ListTransfers(context.TODO(), tt.userID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expParty, transferRepo.listParty)
+		})
+	}
+}
+
+func TestTransferService_AcceptTransfer(t *testing.T) {
+	t.Parallel()
+	pending := models.CompanyTransfer{
+		ID:          uuid.MustParse("7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a"),
+		CompanyID:   uuid.MustParse("ca8fc620-509a-40ac-8cc0-525c37c9c4b9"),
+		FromUserID:  transferOwner.ID,
+		ToUserID:    transferRecipient.ID,
+		InitiatorID: transferOwner.ID,
+		Status:      models.TransferPending,
+	}
+	cases := map[string]struct {
+		transferRepo *mockTransferRepository
+		userID       uuid.UUID
+		expErr       string
+		expErrIs     error
+	}{
+		"transfer not found": {
+			transferRepo: &mockTransferRepository{findErr: fmt.Errorf("failed to find transfer: %w", repositories.ErrNotFound)},
+			userID:       transferRecipient.ID,
+			expErr:       "transfer not found: 7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a",
+			expErrIs:     ErrTransferNotFound,
+		},
+		"not the recipient": {
+			transferRepo: &mockTransferRepository{transfer: pending},
+			userID:       transferAdmin.ID,
+			expErr:       "transfer not allowed: only the recipient can accept a transfer",
+			expErrIs:     ErrTransferForbidden,
+		},
+		"already resolved": {
+			transferRepo: &mockTransferRepository{transfer: pending, err: fmt.Errorf("failed to accept transfer: %w", repositories.ErrTransferResolved)},
+			userID:       transferRecipient.ID,
+			expErr:       "transfer is no longer pending: 7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a",
+			expErrIs:     ErrTransferResolved,
+		},
+		"company was deleted": {
+			transferRepo: &mockTransferRepository{transfer: pending, err: fmt.Errorf("failed to accept transfer: %w", repositories.ErrNotFound)},
+			userID:       transferRecipient.ID,
+			expErr:       "company not found: ca8fc620-509a-40ac-8cc0-525c37c9c4b9",
+			expErrIs:     ErrCompanyNotFound,
+		},
+		"success": {
+			transferRepo: &mockTransferRepository{transfer: pending},
+			userID:       transferRecipient.ID,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewTransferService(tt.transferRepo, &mockCompanyRepository{}, newMockTransferUserRepository())
+			assert.NoError(t, err)
+			transfer, _, err := s.AcceptTransfer(context.TODO(), tt.userID, pending.ID)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				assert.ErrorIs(t, err, tt.expErrIs)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.TransferAccepted, transfer.Status)
+			}
+		})
+	}
+}
+
+func TestTransferService_CancelTransfer(t *testing.T) {
+	t.Parallel()
+	pending := models.CompanyTransfer{
+		ID:          uuid.MustParse("7d0e3f4a-2b1c-4d5e-8f9a-0b1c2d3e4f5a"),
+		FromUserID:  transferOwner.ID,
+		ToUserID:    transferRecipient.ID,
+		InitiatorID: transferOwner.ID,
+		Status:      models.TransferPending,
+	}
+	cases := map[string]struct {
+		userID uuid.UUID
+		expErr string
+	}{
+		"owner":     {userID: transferOwner.ID},
+		"recipient": {userID: transferRecipient.ID},
+		"admin":     {userID: transferAdmin.ID},
+		"stranger": {
+			userID: transferStranger.ID,
+			expErr: "transfer not allowed: only the parties of a transfer or an admin can cancel it",
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewTransferService(&mockTransferRepository{transfer: pending}, &mockCompanyRepository{}, newMockTransferUserRepository())
+			assert.NoError(t, err)
+			transfer, err := s.CancelTransfer(context.TODO(), tt.userID, pending.ID)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.TransferCancelled, transfer.Status)
+				assert.Equal(t, &tt.userID, transfer.ResolvedBy)
+			}
+		})
+	}
+}
+
+// mockTransferRepository for testing
+type mockTransferRepository struct {
+	transfer  models.CompanyTransfer
+	saved     models.CompanyTransfer
+	listParty *uuid.UUID
+	findErr   error
+	err       error
+}
+
+func (m *mockTransferRepository) Save(_ context.Context, transfer models.CompanyTransfer) (models.CompanyTransfer, error) {
+	m.saved = transfer
+	return transfer, m.err
+}
+
+func (m *mockTransferRepository) FindByID(_ context.Context, _ uuid.UUID) (models.CompanyTransfer, error) {
+	return m.transfer, m.findErr
+}
+
+func (m *mockTransferRepository) ListPending(_ context.Context, userID *uuid.UUID) ([]models.CompanyTransfer, error) {
+	m.listParty = userID
+	return nil, m.err
+}
+
+func (m *mockTransferRepository) Accept(_ context.Context, _ uuid.UUID) (models.CompanyTransfer, models.Company, error) {
+	if m.err != nil {
+		return models.CompanyTransfer{}, models.Company{}, m.err
+	}
+	transfer := m.transfer
+	transfer.Status = models.TransferAccepted
+	return transfer, models.Company{ID: transfer.CompanyID, UserID: transfer.ToUserID}, nil
+}
+
+func (m *mockTransferRepository) Cancel(_ context.Context, _, userID uuid.UUID) (models.CompanyTransfer, error) {
+	if m.err != nil {
+		return models.CompanyTransfer{}, m.err
+	}
+	transfer := m.transfer
+	transfer.Status = models.TransferCancelled
+	transfer.ResolvedBy = &userID
+	return transfer, nil
+}
+
+// mockTransferUserRepository knows a fixed set of users.
+type mockTransferUserRepository struct {
+	users []models.User
+}
+
+func newMockTransferUserRepository() *mockTransferUserRepository {
+	return &mockTransferUserRepository{users: []models.User{transferOwner, transferRecipient, transferAdmin, transferStranger}}
+}
+
+func (m *mockTransferUserRepository) Save(_ context.Context, _ models.User) (uuid.UUID, error) {
+	return uuid.UUID{}, errors.New("not supported")
+}
+
+func (m *mockTransferUserRepository) FindByUserName(_ context.Context, username string) (models.User, error) {
+	for _, user := range m.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return models.User{}, fmt.Errorf("failed to find user: %w", repositories.ErrNotFound)
+}
+
+func (m *mockTransferUserRepository) FindByID(_ context.Context, id uuid.UUID) (models.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return models.User{}, fmt.Errorf("failed to find user: %w", repositories.ErrNotFound)
+}
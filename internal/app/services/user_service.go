@@ -12,6 +12,13 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	// ErrInvalidCredentials is returned when a username and password do not match a user.
+	ErrInvalidCredentials = newError(ErrUnauthenticated, "invalid_credentials", "wrong username/password combination")
+	// ErrUserNotFound is returned when a user does not exist.
+	ErrUserNotFound = newError(ErrNotFound, "user_not_found", "user not found")
+)
+
 // User defines the behaviours of the user functionalities in this service.
 type User interface {
 	Save(ctx context.Context, name, username, password string) (uuid.UUID, error)
@@ -44,15 +51,15 @@ func NewUserService(userRepo repositories.UserRepository, jwtSecret string) (*Us
 // Save a user into db.
 func (us *UserService) Save(ctx context.Context, name, username, password string) (uuid.UUID, error) {
 	if name == "" {
-		return uuid.UUID{}, errors.New("name is empty")
+		return uuid.UUID{}, invalidUser("name is empty")
 	}
 
 	if username == "" {
-		return uuid.UUID{}, errors.New("username is empty")
+		return uuid.UUID{}, invalidUser("username is empty")
 	}
 
 	if password == "" {
-		return uuid.UUID{}, errors.New("password is empty")
+		return uuid.UUID{}, invalidUser("password is empty")
 	}
 
 	hashedPassword, err := utils.HashPassword(password)
@@ -77,13 +84,16 @@ func (us *UserService) Save(ctx context.Context, name, username, password string
 // Authenticate a user and returns a JWT token.
 func (us *UserService) Authenticate(ctx context.Context, username, password string) (string, error) {
 	user, err := us.userRepo.FindByUserName(ctx, username)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return "", ErrInvalidCredentials
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to authenticate: %w", err)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", errors.New("wrong username/password combination")
+		return "", ErrInvalidCredentials
 	}
 
 	token, err := utils.GenerateJWT(us.jwtSecret, user.ID.String())
@@ -97,9 +107,17 @@ func (us *UserService) Authenticate(ctx context.Context, username, password stri
 // Introspect a user with details.
 func (us *UserService) GetUser(ctx context.Context, userID uuid.UUID) (models.User, error) {
 	user, err := us.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.User{}, fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+	}
 	if err != nil {
 		return models.User{}, fmt.Errorf("user not found: %w", err)
 	}
 
 	return user, nil
 }
+
+// invalidUser returns the validation error of a user field.
+func invalidUser(message string) error {
+	return newError(ErrValidation, "invalid_user", message)
+}